@@ -3,6 +3,7 @@ package strava
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 )
 
@@ -15,19 +16,22 @@ type ActivityDetailed struct {
 	SplitsMetric   []*Split                `json:"splits_metric"`
 	SplitsStandard []*Split                `json:"splits_standard"`
 	BestEfforts    []*BestEffort           `json:"best_efforts"`
+	DeviceName     string                  `json:"device_name"` // e.g. "Garmin Edge 530", see Device
 }
 
 type ActivitySummary struct {
-	Id                 int64          `json:"id"`
+	RawJSON
+	Id                 int64          `json:"id" strava:"required"`
 	ExternalId         string         `json:"external_id"`
 	UploadId           int64          `json:"upload_id"`
 	Athlete            AthleteSummary `json:"athlete"`
 	Name               string         `json:"name"`
-	Distance           float64        `json:"distance"`
+	Distance           Distance       `json:"distance"`
 	MovingTime         int            `json:"moving_time"`
 	ElapsedTime        int            `json:"elapsed_time"`
-	TotalElevationGain float64        `json:"total_elevation_gain"`
-	Type               ActivityType   `json:"type"`
+	TotalElevationGain Elevation      `json:"total_elevation_gain"`
+	Type               ActivityType   `json:"type"`       // deprecated by Strava in favor of sport_type
+	SportType          ActivityType   `json:"sport_type"` // more specific than Type, e.g. TrailRun instead of Run
 
 	StartDate      time.Time `json:"start_date"`
 	StartDateLocal time.Time `json:"start_date_local"`
@@ -48,24 +52,26 @@ type ActivitySummary struct {
 		Polyline        Polyline `json:"polyline"`
 		SummaryPolyline Polyline `json:"summary_polyline"`
 	} `json:"map"`
-	Trainer              bool    `json:"trainer"`
-	Commute              bool    `json:"commute"`
-	Manual               bool    `json:"manual"`
-	Private              bool    `json:"private"`
-	Flagged              bool    `json:"flagged"`
-	GearId               string  `json:"gear_id"` // bike or pair of shoes
-	AverageSpeed         float64 `json:"average_speed"`
-	MaximunSpeed         float64 `json:"max_speed"`
-	AverageCadence       float64 `json:"average_cadence"`
-	AverageTemperature   float64 `json:"average_temp"`
-	AveragePower         float64 `json:"average_watts"`
-	WeightedAveragePower int     `json:"weighted_average_watts"`
-	Kilojoules           float64 `json:"kilojoules"`
-	DeviceWatts          bool    `json:"device_watts"`
-	AverageHeartrate     float64 `json:"average_heartrate"`
-	MaximumHeartrate     float64 `json:"max_heartrate"`
-	Truncated            int     `json:"truncated"` // only present if activity is owned by authenticated athlete, returns 0 if not truncated by privacy zones
-	HasKudoed            bool    `json:"has_kudoed"`
+	Trainer              bool        `json:"trainer"`
+	Commute              bool        `json:"commute"`
+	Manual               bool        `json:"manual"`
+	Private              bool        `json:"private"`
+	Visibility           Visibility  `json:"visibility"`
+	HideFromHome         bool        `json:"hide_from_home"` // muted, hidden from the home feeds of followers
+	Flagged              bool        `json:"flagged"`
+	GearId               string      `json:"gear_id"` // bike or pair of shoes
+	AverageSpeed         Speed       `json:"average_speed"`
+	MaximunSpeed         Speed       `json:"max_speed"`
+	AverageCadence       float64     `json:"average_cadence"`
+	AverageTemperature   Temperature `json:"average_temp"`
+	AveragePower         Power       `json:"average_watts"`
+	WeightedAveragePower Power       `json:"weighted_average_watts"`
+	Kilojoules           float64     `json:"kilojoules"`
+	DeviceWatts          bool        `json:"device_watts"`
+	AverageHeartrate     float64     `json:"average_heartrate"`
+	MaximumHeartrate     float64     `json:"max_heartrate"`
+	Truncated            int         `json:"truncated"` // only present if activity is owned by authenticated athlete, returns 0 if not truncated by privacy zones
+	HasKudoed            bool        `json:"has_kudoed"`
 }
 
 type BestEffort struct {
@@ -109,13 +115,41 @@ var ActivityTypes = struct {
 	Yoga               ActivityType
 	WinterSport        ActivityType
 	CrossCountrySkiing ActivityType
+
+	// sport types, only returned as SportType
+	MountainBikeRide  ActivityType
+	GravelRide        ActivityType
+	EMountainBikeRide ActivityType
+	TrailRun          ActivityType
+	VirtualRun        ActivityType
 }{"Ride", "AlpineSki", "BackcountrySki", "Hike", "IceSkate", "InlineSkate", "NordicSki", "RollerSki",
 	"Run", "Walk", "Workout", "Snowboard", "Snowshoe", "Kitesurf", "Windsurf", "Swim", "VirtualRide", "EBikeRide",
 
 	"WaterSport", "Canoeing", "Kayaking", "Rowing", "StandUpPaddling", "Surfing",
 	"Crossfit", "Elliptical", "RockClimbing", "StairStepper", "WeightTraining", "Yoga", "WinterSport", "CrossCountrySkiing",
+
+	"MountainBikeRide", "GravelRide", "EMountainBikeRide", "TrailRun", "VirtualRun",
+}
+
+// sportTypeBaseTypes maps the sport types that are more specific than the
+// corresponding (legacy) activity type onto that type.
+var sportTypeBaseTypes = map[ActivityType]ActivityType{
+	ActivityTypes.MountainBikeRide:  ActivityTypes.Ride,
+	ActivityTypes.GravelRide:        ActivityTypes.Ride,
+	ActivityTypes.EMountainBikeRide: ActivityTypes.EBikeRide,
+	ActivityTypes.TrailRun:          ActivityTypes.Run,
+	ActivityTypes.VirtualRun:        ActivityTypes.Run,
 }
 
+// Visibility is who can see an activity.
+type Visibility string
+
+var Visibilities = struct {
+	Everyone      Visibility
+	FollowersOnly Visibility
+	OnlyMe        Visibility
+}{"everyone", "followers_only", "only_me"}
+
 type Location [2]float64
 
 type ActivitiesService struct {
@@ -129,9 +163,13 @@ func NewActivitiesService(client *Client) *ActivitiesService {
 /*********************************************************/
 
 type ActivitiesGetCall struct {
-	service *ActivitiesService
-	id      int64
-	ops     map[string]interface{}
+	service      *ActivitiesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) Get(activityId int64) *ActivitiesGetCall {
@@ -147,27 +185,48 @@ func (c *ActivitiesGetCall) IncludeAllEfforts() *ActivitiesGetCall {
 	return c
 }
 
-func (c *ActivitiesGetCall) Do() (*ActivityDetailed, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/activities/%d", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivitiesGetCall) OnError(handler ErrorHandler) *ActivitiesGetCall {
+	c.errorHandler = handler
+	return c
+}
 
-	var activity ActivityDetailed
-	err = json.Unmarshal(data, &activity)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesGetCall) Response(resp *Response) *ActivitiesGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesGetCall) Timeout(d time.Duration) *ActivitiesGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesGetCall) Header(key, value string) *ActivitiesGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &activity, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesGetCall) Do() (*ActivityDetailed, error) {
+	activity, err := runAndDecode[*ActivityDetailed](c.service.client, "GET", fmt.Sprintf("/activities/%d", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return activity, wrapError(err, "activities.get id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ActivitiesDeleteCall struct {
-	service *ActivitiesService
-	id      int64
-	ops     map[string]interface{}
+	service      *ActivitiesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) Delete(activityId int64) *ActivitiesDeleteCall {
@@ -177,16 +236,47 @@ func (s *ActivitiesService) Delete(activityId int64) *ActivitiesDeleteCall {
 	}
 }
 
+func (c *ActivitiesDeleteCall) OnError(handler ErrorHandler) *ActivitiesDeleteCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesDeleteCall) Response(resp *Response) *ActivitiesDeleteCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesDeleteCall) Timeout(d time.Duration) *ActivitiesDeleteCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesDeleteCall) Header(key, value string) *ActivitiesDeleteCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *ActivitiesDeleteCall) Do() error {
-	_, err := c.service.client.run("DELETE", fmt.Sprintf("/activities/%d", c.id), nil)
-	return err
+	_, err := c.service.client.runWithErrorHandler("DELETE", fmt.Sprintf("/activities/%d", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return wrapError(err, "activities.delete id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ActivitiesPostCall struct {
-	service *ActivitiesService
-	ops     map[string]interface{}
+	service      *ActivitiesService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) Create(
@@ -213,32 +303,110 @@ func (c *ActivitiesPostCall) Description(description string) *ActivitiesPostCall
 	return c
 }
 
-func (c *ActivitiesPostCall) Distance(distance float64) *ActivitiesPostCall {
-	c.ops["distance"] = distance
+func (c *ActivitiesPostCall) Distance(distance Distance) *ActivitiesPostCall {
+	c.ops["distance"] = distance.Meters()
 	return c
 }
 
-func (c *ActivitiesPostCall) Do() (*ActivityDetailed, error) {
-	data, err := c.service.client.run("POST", "/activities", c.ops)
-	if err != nil {
-		return nil, err
+func (c *ActivitiesPostCall) Trainer(isTrainer bool) *ActivitiesPostCall {
+	c.ops["trainer"] = isTrainer
+	return c
+}
+
+func (c *ActivitiesPostCall) Commute(isCommute bool) *ActivitiesPostCall {
+	c.ops["commute"] = isCommute
+	return c
+}
+
+// Validate checks the parameters of the activity against the constraints of its type,
+// e.g. a swim needs a distance and a yoga session can't have one.
+// Do validates the call before sending it.
+func (c *ActivitiesPostCall) Validate() error {
+	activityType := ActivityType(c.ops["type"].(string))
+
+	if c.ops["name"] == "" {
+		return &ValidationError{"name", "is required"}
 	}
 
-	var activity ActivityDetailed
-	err = json.Unmarshal(data, &activity)
-	if err != nil {
-		return nil, err
+	if activityType == "" {
+		return &ValidationError{"type", "is required"}
 	}
 
-	return &activity, nil
+	if c.ops["elapsed_time"].(int) <= 0 {
+		return &ValidationError{"elapsed_time", "must be positive"}
+	}
+
+	distance, hasDistance := c.ops["distance"].(float64)
+	if hasDistance && distance < 0 {
+		return &ValidationError{"distance", "can not be negative"}
+	}
+
+	if hasDistance && distance > 0 && !activityType.HasDistance() {
+		return &ValidationError{"distance", fmt.Sprintf("not applicable to %s activities", activityType)}
+	}
+
+	if activityType == ActivityTypes.Swim && (!hasDistance || distance == 0) {
+		return &ValidationError{"distance", "is required for manual swims"}
+	}
+
+	trainer, _ := c.ops["trainer"].(bool)
+	if trainer && !activityType.SupportsTrainer() {
+		return &ValidationError{"trainer", fmt.Sprintf("not applicable to %s activities", activityType)}
+	}
+
+	if commute, _ := c.ops["commute"].(bool); commute && (trainer || activityType.IsVirtual()) {
+		return &ValidationError{"commute", "not applicable to trainer or virtual activities"}
+	}
+
+	return nil
+}
+
+func (c *ActivitiesPostCall) OnError(handler ErrorHandler) *ActivitiesPostCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesPostCall) Response(resp *Response) *ActivitiesPostCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesPostCall) Timeout(d time.Duration) *ActivitiesPostCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesPostCall) Header(key, value string) *ActivitiesPostCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesPostCall) Do() (*ActivityDetailed, error) {
+	if err := c.Validate(); err != nil {
+		return nil, wrapError(err, "activities.create")
+	}
+
+	activity, err := runAndDecode[*ActivityDetailed](c.service.client, "POST", "/activities", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return activity, wrapError(err, "activities.create")
 }
 
 /*********************************************************/
 
 type ActivitiesPutCall struct {
-	service *ActivitiesService
-	id      int64
-	ops     map[string]interface{}
+	service      *ActivitiesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) Update(activityId int64) *ActivitiesPutCall {
@@ -275,6 +443,17 @@ func (c *ActivitiesPutCall) Private(isPrivate bool) *ActivitiesPutCall {
 	return c
 }
 
+func (c *ActivitiesPutCall) Visibility(visibility Visibility) *ActivitiesPutCall {
+	c.ops["visibility"] = visibility
+	return c
+}
+
+// HideFromHome mutes the activity, hiding it from the home feeds of followers.
+func (c *ActivitiesPutCall) HideFromHome(hide bool) *ActivitiesPutCall {
+	c.ops["hide_from_home"] = hide
+	return c
+}
+
 func (c *ActivitiesPutCall) Commute(isCommute bool) *ActivitiesPutCall {
 	c.ops["commute"] = isCommute
 	return c
@@ -290,26 +469,47 @@ func (c *ActivitiesPutCall) Gear(gearId string) *ActivitiesPutCall {
 	return c
 }
 
-func (c *ActivitiesPutCall) Do() (*ActivityDetailed, error) {
-	data, err := c.service.client.run("PUT", fmt.Sprintf("/activities/%d", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivitiesPutCall) OnError(handler ErrorHandler) *ActivitiesPutCall {
+	c.errorHandler = handler
+	return c
+}
 
-	var activity ActivityDetailed
-	err = json.Unmarshal(data, &activity)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesPutCall) Response(resp *Response) *ActivitiesPutCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesPutCall) Timeout(d time.Duration) *ActivitiesPutCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesPutCall) Header(key, value string) *ActivitiesPutCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &activity, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesPutCall) Do() (*ActivityDetailed, error) {
+	activity, err := runAndDecode[*ActivityDetailed](c.service.client, "PUT", fmt.Sprintf("/activities/%d", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return activity, wrapError(err, "activities.update id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ActivitiesListPhotosCall struct {
-	service *ActivitiesService
-	id      int64
+	service      *ActivitiesService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) ListPhotos(activityId int64) *ActivitiesListPhotosCall {
@@ -319,26 +519,47 @@ func (s *ActivitiesService) ListPhotos(activityId int64) *ActivitiesListPhotosCa
 	}
 }
 
-func (c *ActivitiesListPhotosCall) Do() ([]*PhotoSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/activities/%d/photos", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivitiesListPhotosCall) OnError(handler ErrorHandler) *ActivitiesListPhotosCall {
+	c.errorHandler = handler
+	return c
+}
 
-	photos := make([]*PhotoSummary, 0)
-	err = json.Unmarshal(data, &photos)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesListPhotosCall) Response(resp *Response) *ActivitiesListPhotosCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesListPhotosCall) Timeout(d time.Duration) *ActivitiesListPhotosCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesListPhotosCall) Header(key, value string) *ActivitiesListPhotosCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return photos, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesListPhotosCall) Do() ([]*PhotoSummary, error) {
+	photos, err := runAndDecode[[]*PhotoSummary](c.service.client, "GET", fmt.Sprintf("/activities/%d/photos", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return photos, wrapError(err, "activities.list_photos id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ActivitiesListZonesCall struct {
-	service *ActivitiesService
-	id      int64
+	service      *ActivitiesService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) ListZones(activityId int64) *ActivitiesListZonesCall {
@@ -348,26 +569,47 @@ func (s *ActivitiesService) ListZones(activityId int64) *ActivitiesListZonesCall
 	}
 }
 
-func (c *ActivitiesListZonesCall) Do() ([]*ZonesSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/activities/%d/zones", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivitiesListZonesCall) OnError(handler ErrorHandler) *ActivitiesListZonesCall {
+	c.errorHandler = handler
+	return c
+}
 
-	zones := make([]*ZonesSummary, 0)
-	err = json.Unmarshal(data, &zones)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesListZonesCall) Response(resp *Response) *ActivitiesListZonesCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesListZonesCall) Timeout(d time.Duration) *ActivitiesListZonesCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesListZonesCall) Header(key, value string) *ActivitiesListZonesCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return zones, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesListZonesCall) Do() ([]*ZonesSummary, error) {
+	zones, err := runAndDecode[[]*ZonesSummary](c.service.client, "GET", fmt.Sprintf("/activities/%d/zones", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return zones, wrapError(err, "activities.list_zones id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ActivitiesListLapsCall struct {
-	service *ActivitiesService
-	id      int64
+	service      *ActivitiesService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivitiesService) ListLaps(activityId int64) *ActivitiesListLapsCall {
@@ -377,19 +619,36 @@ func (s *ActivitiesService) ListLaps(activityId int64) *ActivitiesListLapsCall {
 	}
 }
 
-func (c *ActivitiesListLapsCall) Do() ([]*LapEffortSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/activities/%d/laps", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivitiesListLapsCall) OnError(handler ErrorHandler) *ActivitiesListLapsCall {
+	c.errorHandler = handler
+	return c
+}
 
-	laps := make([]*LapEffortSummary, 0)
-	err = json.Unmarshal(data, &laps)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesListLapsCall) Response(resp *Response) *ActivitiesListLapsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesListLapsCall) Timeout(d time.Duration) *ActivitiesListLapsCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesListLapsCall) Header(key, value string) *ActivitiesListLapsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return laps, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesListLapsCall) Do() ([]*LapEffortSummary, error) {
+	laps, err := runAndDecode[[]*LapEffortSummary](c.service.client, "GET", fmt.Sprintf("/activities/%d/laps", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return laps, wrapError(err, "activities.list_laps id=%d", c.id)
 }
 
 /*********************************************************/
@@ -531,11 +790,133 @@ func (t ActivityType) String() string {
 		return "WinterSport"
 	case ActivityTypes.CrossCountrySkiing:
 		return "CrossCountrySkiing"
+	case ActivityTypes.MountainBikeRide:
+		return "Mountain Bike Ride"
+	case ActivityTypes.GravelRide:
+		return "Gravel Ride"
+	case ActivityTypes.EMountainBikeRide:
+		return "E-Mountain Bike Ride"
+	case ActivityTypes.TrailRun:
+		return "Trail Run"
+	case ActivityTypes.VirtualRun:
+		return "Virtual Run"
 	}
 
 	return "Activity"
 }
 
+// HasDistance returns false for activity types where a distance is meaningless, like yoga.
+func (t ActivityType) HasDistance() bool {
+	switch t {
+	case ActivityTypes.Crossfit,
+		ActivityTypes.RockClimbing,
+		ActivityTypes.WeightTraining,
+		ActivityTypes.Yoga,
+		ActivityTypes.Workout:
+		return false
+	}
+
+	return true
+}
+
+// SupportsTrainer returns true for activity types that can be done on a trainer,
+// such as a turbo trainer, treadmill or rowing machine.
+func (t ActivityType) SupportsTrainer() bool {
+	switch t.BaseType() {
+	case ActivityTypes.Ride,
+		ActivityTypes.VirtualRide,
+		ActivityTypes.EBikeRide,
+		ActivityTypes.Run,
+		ActivityTypes.Walk,
+		ActivityTypes.Rowing:
+		return true
+	}
+
+	return false
+}
+
+// cyclingTypes are the activity and sport types of rides, see IsCycling.
+var cyclingTypes = []ActivityType{
+	ActivityTypes.Ride,
+	ActivityTypes.VirtualRide,
+	ActivityTypes.EBikeRide,
+	ActivityTypes.MountainBikeRide,
+	ActivityTypes.GravelRide,
+	ActivityTypes.EMountainBikeRide,
+}
+
+// IsCycling returns true for rides, real or virtual and with or without a motor, the sports an FTP applies to.
+func (t ActivityType) IsCycling() bool {
+	for _, cycling := range cyclingTypes {
+		if t == cycling {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsVirtual returns true for activities done in a virtual world, which never have a real location.
+func (t ActivityType) IsVirtual() bool {
+	return t == ActivityTypes.VirtualRide || t == ActivityTypes.VirtualRun
+}
+
+// BaseType returns the activity type a sport type belongs to, e.g. Run for TrailRun.
+// Types that are not more specific sport types are returned as is.
+func (t ActivityType) BaseType() ActivityType {
+	if base, ok := sportTypeBaseTypes[t]; ok {
+		return base
+	}
+
+	return t
+}
+
+/*********************************************************/
+
+// UnmarshalJSON decodes the activity, populating Type and SportType
+// from each other if only one of them is present in the data.
+func (a *ActivitySummary) UnmarshalJSON(data []byte) error {
+	type activitySummary ActivitySummary
+	err := json.Unmarshal(data, (*activitySummary)(a))
+	if err != nil {
+		return err
+	}
+
+	a.resolveAliases()
+	return nil
+}
+
+// UnmarshalJSON decodes the activity, see ActivitySummary.UnmarshalJSON.
+func (a *ActivityDetailed) UnmarshalJSON(data []byte) error {
+	type activityDetailed ActivityDetailed
+	var detailed struct {
+		*activityDetailed
+
+		// hides the UnmarshalJSON promoted from ActivitySummary,
+		// which would only decode the summary fields
+		UnmarshalJSON struct{} `json:"-"`
+	}
+
+	detailed.activityDetailed = (*activityDetailed)(a)
+	err := json.Unmarshal(data, &detailed)
+	if err != nil {
+		return err
+	}
+
+	a.resolveAliases()
+	return nil
+}
+
+func (a *ActivitySummary) resolveAliases() {
+	if a.SportType == "" {
+		a.SportType = a.Type
+	}
+
+	if a.Type == "" {
+		a.Type = a.SportType.BaseType()
+	}
+}
+
 func (l Location) String() string {
 	return fmt.Sprintf("[%f, %f]", l[0], l[1])
 }
@@ -1,6 +1,8 @@
 package strava
 
 import (
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"reflect"
 	"testing"
@@ -8,7 +10,7 @@ import (
 )
 
 func TestActivitiesGet(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_get")
+	client := newFixtureClient(t, "activity_get")
 	activity, err := NewActivitiesService(client).Get(103221154).Do()
 
 	if err != nil {
@@ -38,6 +40,7 @@ func TestActivitiesGet(t *testing.T) {
 	expected.Name = "08/15/2010 Davis, CA"
 	expected.Description = "Something Special"
 	expected.Type = ActivityTypes.Ride
+	expected.SportType = ActivityTypes.Ride // sport_type is missing from older responses
 	expected.Distance = 20739.1
 	expected.MovingTime = 2836
 	expected.ElapsedTime = 3935
@@ -148,7 +151,7 @@ func TestActivitiesGet(t *testing.T) {
 	}
 
 	// run
-	client = newCassetteClient(testToken, "activity_get_run")
+	client = newFixtureClient(t, "activity_get_run")
 	activity, err = NewActivitiesService(client).Get(103359122).Do()
 
 	if err != nil {
@@ -217,7 +220,7 @@ func TestActivitiesGet(t *testing.T) {
 	}
 
 	// hidden efforts
-	client = newCassetteClient(testToken, "activity_get_ride_all_efforts")
+	client = newFixtureClient(t, "activity_get_ride_all_efforts")
 	activity, err = NewActivitiesService(client).Get(103221154).IncludeAllEfforts().Do()
 
 	if err != nil {
@@ -262,7 +265,7 @@ func TestActivitiesDelete(t *testing.T) {
 }
 
 func TestActivitiesCreate(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_post")
+	client := newFixtureClient(t, "activity_post")
 	activity, err := NewActivitiesService(client).Create("name", ActivityTypes.Ride, time.Now(), 100).Do()
 
 	if err != nil {
@@ -312,7 +315,7 @@ func TestActivitiesCreate(t *testing.T) {
 }
 
 func TestActivitiesUpdate(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_put")
+	client := newFixtureClient(t, "activity_put")
 	activity, err := NewActivitiesService(client).Update(141818870).Do()
 
 	if err != nil {
@@ -365,11 +368,17 @@ func TestActivitiesUpdate(t *testing.T) {
 	if transport.request.URL.RawQuery != "private=1" {
 		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
 	}
+
+	// parameters5
+	s.Update(123).Visibility(Visibilities.FollowersOnly).HideFromHome(true).Do()
+
+	if transport.request.URL.RawQuery != "hide_from_home=true&visibility=followers_only" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
 }
 
 func TestActivitiesListPhotos(t *testing.T) {
-	// token for 3545423, I wasn't able to post a test photo for the other account
-	client := newCassetteClient("f578367dbb2288fb9f91090fa676111fdc5e8698", "activity_list_photos")
+	client := newFixtureClient(t, "activity_list_photos")
 	photos, err := NewActivitiesService(client).ListPhotos(103374194).Do()
 
 	if err != nil {
@@ -412,7 +421,7 @@ func TestActivitiesListPhotos(t *testing.T) {
 }
 
 func TestActivitiesListZones(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_list_zones")
+	client := newFixtureClient(t, "activity_list_zones")
 	zones, err := NewActivitiesService(client).ListZones(103221154).Do()
 
 	if err != nil {
@@ -466,7 +475,7 @@ func TestActivitiesListZones(t *testing.T) {
 }
 
 func TestActivitiesListLaps(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_list_laps")
+	client := newFixtureClient(t, "activity_list_laps")
 	laps, err := NewActivitiesService(client).ListLaps(103373338).Do()
 
 	if err != nil {
@@ -820,7 +829,7 @@ func TestActivityType(t *testing.T) {
 	}
 
 	// other
-	ty := ActivityType(100)
+	ty := ActivityType("Unknown")
 	if id := ty.Id(); id != 0 {
 		t.Errorf("activity type id incorrect, got %v", id)
 	}
@@ -829,3 +838,99 @@ func TestActivityType(t *testing.T) {
 		t.Errorf("activity type string incorrect, got %v", s)
 	}
 }
+
+func TestActivitySportTypeAliases(t *testing.T) {
+	var summary ActivitySummary
+	json.Unmarshal([]byte(`{"id":1,"type":"Ride"}`), &summary)
+	if summary.Type != ActivityTypes.Ride || summary.SportType != ActivityTypes.Ride {
+		t.Errorf("sport type not populated from type, got %v %v", summary.Type, summary.SportType)
+	}
+
+	summary = ActivitySummary{}
+	json.Unmarshal([]byte(`{"id":1,"sport_type":"TrailRun"}`), &summary)
+	if summary.Type != ActivityTypes.Run || summary.SportType != ActivityTypes.TrailRun {
+		t.Errorf("type not populated from sport type, got %v %v", summary.Type, summary.SportType)
+	}
+
+	summary = ActivitySummary{}
+	json.Unmarshal([]byte(`{"id":1,"type":"Ride","sport_type":"GravelRide"}`), &summary)
+	if summary.Type != ActivityTypes.Ride || summary.SportType != ActivityTypes.GravelRide {
+		t.Errorf("both types should be kept, got %v %v", summary.Type, summary.SportType)
+	}
+
+	var detailed ActivityDetailed
+	err := json.Unmarshal([]byte(`{"id":2,"name":"Lunch Run","sport_type":"TrailRun","description":"muddy","calories":512.5}`), &detailed)
+	if err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+
+	if detailed.Id != 2 || detailed.Name != "Lunch Run" || detailed.Description != "muddy" || detailed.Calories != 512.5 {
+		t.Errorf("detailed activity not fully decoded, got %v", detailed)
+	}
+
+	if detailed.Type != ActivityTypes.Run || detailed.SportType != ActivityTypes.TrailRun {
+		t.Errorf("type not populated from sport type, got %v %v", detailed.Type, detailed.SportType)
+	}
+
+	activities := make([]*ActivitySummary, 0)
+	json.Unmarshal([]byte(`[{"id":3,"type":"Swim"}]`), &activities)
+	if activities[0].SportType != ActivityTypes.Swim {
+		t.Errorf("sport type not populated in list, got %v", activities[0].SportType)
+	}
+
+	if b := ActivityTypes.Hike.BaseType(); b != ActivityTypes.Hike {
+		t.Errorf("base type incorrect, got %v", b)
+	}
+}
+
+func TestActivitiesCreateValidate(t *testing.T) {
+	s := NewActivitiesService(newStoreRequestClient())
+	start := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		call  *ActivitiesPostCall
+		field string
+	}{
+		{s.Create("name", ActivityTypes.Ride, start, 100), ""},
+		{s.Create("name", ActivityTypes.Swim, start, 100).Distance(1500), ""},
+		{s.Create("name", ActivityTypes.Ride, start, 100).Trainer(true), ""},
+		{s.Create("name", ActivityTypes.MountainBikeRide, start, 100).Commute(true), ""},
+		{s.Create("", ActivityTypes.Ride, start, 100), "name"},
+		{s.Create("name", "", start, 100), "type"},
+		{s.Create("name", ActivityTypes.Ride, start, 0), "elapsed_time"},
+		{s.Create("name", ActivityTypes.Ride, start, 100).Distance(-1), "distance"},
+		{s.Create("name", ActivityTypes.Yoga, start, 100).Distance(100), "distance"},
+		{s.Create("name", ActivityTypes.Swim, start, 100), "distance"},
+		{s.Create("name", ActivityTypes.Swim, start, 100).Distance(1500).Trainer(true), "trainer"},
+		{s.Create("name", ActivityTypes.VirtualRide, start, 100).Commute(true), "commute"},
+		{s.Create("name", ActivityTypes.Ride, start, 100).Trainer(true).Commute(true), "commute"},
+	}
+
+	for i, c := range cases {
+		err := c.call.Validate()
+
+		var validationErr *ValidationError
+		if c.field == "" {
+			if err != nil {
+				t.Errorf("case %d: should be valid, got %v", i, err)
+			}
+		} else if !errors.As(err, &validationErr) || validationErr.Field != c.field {
+			t.Errorf("case %d: should be invalid %s, got %v", i, c.field, err)
+		}
+	}
+
+	// invalid calls are not sent
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	transport.request = nil
+
+	_, err := s.Create("name", ActivityTypes.Swim, start, 100).Do()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("should return validation error, got %v", err)
+	}
+
+	if transport.request != nil {
+		t.Error("invalid call should not be sent")
+	}
+}
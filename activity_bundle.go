@@ -0,0 +1,149 @@
+package strava
+
+import (
+	"context"
+	"sync"
+)
+
+// An ActivityBundle holds an activity together with its laps.
+// Streams are not fetched with the bundle, they are loaded on the first call
+// to Streams and cached afterwards, so code that only sometimes needs them
+// doesn't always pay for them.
+type ActivityBundle struct {
+	Activity *ActivityDetailed
+	Laps     []*LapEffortSummary
+
+	client       *Client
+	streamTypes  []StreamType
+	resolution   StreamResolution
+	errorHandler ErrorHandler
+
+	lock    sync.Mutex
+	streams *StreamSet
+}
+
+// DefaultBundleStreamTypes are the streams loaded by ActivityBundle.Streams
+// if no other stream types were requested.
+var DefaultBundleStreamTypes = []StreamType{
+	StreamTypes.Time,
+	StreamTypes.Location,
+	StreamTypes.Distance,
+	StreamTypes.Elevation,
+	StreamTypes.Speed,
+	StreamTypes.HeartRate,
+	StreamTypes.Cadence,
+	StreamTypes.Power,
+	StreamTypes.Moving,
+	StreamTypes.Grade,
+}
+
+/*********************************************************/
+
+type ActivitiesGetBundleCall struct {
+	service      *ActivitiesService
+	id           int64
+	streamTypes  []StreamType
+	resolution   StreamResolution
+	errorHandler ErrorHandler
+}
+
+// GetBundle defines a call fetching the activity and its laps.
+// The streams of the bundle are loaded lazily by ActivityBundle.Streams.
+func (s *ActivitiesService) GetBundle(activityId int64) *ActivitiesGetBundleCall {
+	return &ActivitiesGetBundleCall{
+		service: s,
+		id:      activityId,
+	}
+}
+
+// StreamTypes sets the streams loaded by ActivityBundle.Streams, defaults to DefaultBundleStreamTypes.
+func (c *ActivitiesGetBundleCall) StreamTypes(types []StreamType) *ActivitiesGetBundleCall {
+	c.streamTypes = make([]StreamType, len(types))
+	copy(c.streamTypes, types)
+	return c
+}
+
+// Resolution sets the resolution of the lazily loaded streams, e.g. the one picked by ResolutionForActivity.
+// By default all points are returned.
+func (c *ActivitiesGetBundleCall) Resolution(resolution StreamResolution) *ActivitiesGetBundleCall {
+	c.resolution = resolution
+	return c
+}
+
+// OnError sets the ErrorHandler of the call, it is also used when loading the streams.
+func (c *ActivitiesGetBundleCall) OnError(handler ErrorHandler) *ActivitiesGetBundleCall {
+	c.errorHandler = handler
+	return c
+}
+
+func (c *ActivitiesGetBundleCall) Do() (*ActivityBundle, error) {
+	activity, err := c.service.Get(c.id).OnError(c.errorHandler).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	laps, err := c.service.ListLaps(c.id).OnError(c.errorHandler).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &ActivityBundle{
+		Activity:     activity,
+		Laps:         laps,
+		client:       c.service.client,
+		streamTypes:  c.streamTypes,
+		resolution:   c.resolution,
+		errorHandler: c.errorHandler,
+	}
+
+	if len(bundle.streamTypes) == 0 {
+		bundle.streamTypes = DefaultBundleStreamTypes
+	}
+
+	return bundle, nil
+}
+
+/*********************************************************/
+
+// Streams returns the streams of the activity, fetching them on first use.
+// Successfully fetched streams are cached, a failed fetch is retried on the next call.
+// The fetch is cancelled with ctx. The bundle isn't locked during the fetch, concurrent
+// first calls may each fetch the streams, they all return the streams cached first.
+func (b *ActivityBundle) Streams(ctx context.Context) (*StreamSet, error) {
+	if streams := b.cachedStreams(); streams != nil {
+		return streams, nil
+	}
+
+	call := NewActivityStreamsService(b.client).
+		Get(b.Activity.Id, b.streamTypes).
+		OnError(b.errorHandler)
+	if b.resolution != "" {
+		call.Resolution(b.resolution)
+	}
+
+	streams, err := call.do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.streams == nil {
+		b.streams = streams
+	}
+
+	return b.streams, nil
+}
+
+func (b *ActivityBundle) cachedStreams() *StreamSet {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.streams
+}
+
+// StreamsLoaded returns true if the streams have already been fetched.
+func (b *ActivityBundle) StreamsLoaded() bool {
+	return b.cachedStreams() != nil
+}
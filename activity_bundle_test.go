@@ -0,0 +1,117 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestActivitiesGetBundle(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/activities/123":                    `{"id":123,"name":"Morning Ride","elapsed_time":600}`,
+		"/api/v3/activities/123/laps":               `[{"id":1,"lap_index":1},{"id":2,"lap_index":2}]`,
+		"/api/v3/activities/123/streams/time,watts": `[{"type":"time","series_type":"distance","original_size":3,"resolution":"medium","data":[0,1,2]},{"type":"watts","series_type":"distance","original_size":3,"resolution":"medium","data":[100,200,null]}]`,
+	})
+
+	bundle, err := NewActivitiesService(client).
+		GetBundle(123).
+		StreamTypes([]StreamType{StreamTypes.Time, StreamTypes.Power}).
+		Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if bundle.Activity.Name != "Morning Ride" {
+		t.Errorf("activity not parsed, got %v", bundle.Activity.Name)
+	}
+
+	if len(bundle.Laps) != 2 {
+		t.Errorf("laps not parsed, got %d", len(bundle.Laps))
+	}
+
+	if len(transport.requests) != 2 {
+		t.Errorf("streams should not be fetched with the bundle, got %d requests", len(transport.requests))
+	}
+
+	if bundle.StreamsLoaded() {
+		t.Error("streams should not be loaded yet")
+	}
+
+	streams, err := bundle.Streams(context.Background())
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(streams.Power.Data) != 3 || streams.Power.RawData[2] != nil {
+		t.Errorf("streams not parsed, got %v", streams.Power.Data)
+	}
+
+	if q := transport.requests[2].URL.RawQuery; q != "" {
+		t.Errorf("all points should be requested by default, got %v", q)
+	}
+
+	if _, err := bundle.Streams(context.Background()); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 3 {
+		t.Errorf("streams should be cached, got %d requests", len(transport.requests))
+	}
+
+	// the resolution is opted in
+	bundle, _ = NewActivitiesService(client).GetBundle(123).StreamTypes([]StreamType{StreamTypes.Time, StreamTypes.Power}).Resolution(StreamResolutions.Medium).Do()
+	if _, err := bundle.Streams(context.Background()); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if q := transport.requests[len(transport.requests)-1].URL.RawQuery; q != "resolution=medium" {
+		t.Errorf("request query incorrect, got %v", q)
+	}
+
+	// failed stream fetches are not cached
+	bundle, _ = NewActivitiesService(client).GetBundle(123).Do()
+	if _, err := bundle.Streams(context.Background()); err == nil {
+		t.Error("should have returned error")
+	}
+
+	if bundle.StreamsLoaded() {
+		t.Error("failed streams should not be cached")
+	}
+
+	// the fetch is made with the context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bundle.Streams(ctx)
+	if err := transport.requests[len(transport.requests)-1].Context().Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("streams should be fetched with the context, got %v", err)
+	}
+}
+
+func TestActivityBundleStreamsUnlocked(t *testing.T) {
+	bundle := &ActivityBundle{Activity: &ActivityDetailed{}, streamTypes: []StreamType{StreamTypes.Time}}
+	bundle.Activity.Id = 123
+
+	bundle.client = NewClient(newStubTokenSource())
+	bundle.client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// the bundle can be used while the streams are fetched
+		if bundle.StreamsLoaded() {
+			t.Error("streams should not be loaded yet")
+		}
+
+		body := `[{"type":"time","series_type":"distance","original_size":2,"resolution":"high","data":[0,1]}]`
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})}
+
+	streams, err := bundle.Streams(context.Background())
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(streams.Time.Data) != 2 || !bundle.StreamsLoaded() {
+		t.Errorf("streams should be loaded, got %v", streams.Time.Data)
+	}
+}
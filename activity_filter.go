@@ -0,0 +1,107 @@
+package strava
+
+import (
+	"time"
+)
+
+// An ActivityFilter selects activities, e.g. in the Where of the calls listing activities.
+// Outdoor rides over 50 km this year are:
+//
+//	activities, err := service.ListActivities().Where(
+//		strava.ActivityOfType(strava.ActivityTypes.Ride),
+//		strava.ActivityOnTrainer(false),
+//		strava.ActivityDistanceBetween(50000, 0),
+//		strava.ActivityStartedBetween(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}),
+//	).Do()
+type ActivityFilter func(activity *ActivitySummary) bool
+
+// FilterActivities returns the activities selected by all filters, in order.
+func FilterActivities(activities []*ActivitySummary, filters ...ActivityFilter) []*ActivitySummary {
+	if len(filters) == 0 || activities == nil {
+		return activities
+	}
+
+	selected := make([]*ActivitySummary, 0, len(activities))
+	for _, activity := range activities {
+		if matchActivity(activity, filters) {
+			selected = append(selected, activity)
+		}
+	}
+
+	return selected
+}
+
+// matchActivity returns if the activity is selected by all filters.
+func matchActivity(activity *ActivitySummary, filters []ActivityFilter) bool {
+	for _, filter := range filters {
+		if !filter(activity) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ActivityOfType selects activities of any of the types, by sport type or the (legacy) type,
+// so Ride selects gravel and mountain bike rides too.
+func ActivityOfType(types ...ActivityType) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		for _, t := range types {
+			if activity.SportType == t || activity.Type == t {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// ActivityWithVisibility selects activities with any of the visibilities.
+func ActivityWithVisibility(visibilities ...Visibility) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		for _, v := range visibilities {
+			if activity.Visibility == v {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// ActivityHiddenFromHome selects muted activities, or the ones that are not if hidden is false.
+func ActivityHiddenFromHome(hidden bool) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		return activity.HideFromHome == hidden
+	}
+}
+
+// ActivityOnTrainer selects activities recorded on a trainer, or the ones that are not if trainer is false.
+func ActivityOnTrainer(trainer bool) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		return activity.Trainer == trainer
+	}
+}
+
+// ActivityCommute selects commutes, or the activities that are not if commute is false.
+func ActivityCommute(commute bool) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		return activity.Commute == commute
+	}
+}
+
+// ActivityStartedBetween selects activities started at or after from and before to.
+// A zero time leaves that end of the window open.
+func ActivityStartedBetween(from, to time.Time) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		return (from.IsZero() || !activity.StartDate.Before(from)) && (to.IsZero() || activity.StartDate.Before(to))
+	}
+}
+
+// ActivityDistanceBetween selects activities with a distance from min up to and including max, in meters.
+// A zero max leaves the range open.
+func ActivityDistanceBetween(min, max Distance) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		return activity.Distance >= min && (max == 0 || activity.Distance <= max)
+	}
+}
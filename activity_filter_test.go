@@ -0,0 +1,84 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterActivities(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	activities := []*ActivitySummary{
+		{Id: 1, Type: ActivityTypes.Ride, SportType: ActivityTypes.GravelRide, Distance: 60000, StartDate: start},
+		{Id: 2, Type: ActivityTypes.Ride, SportType: ActivityTypes.Ride, Distance: 40000, StartDate: start},
+		{Id: 3, Type: ActivityTypes.Ride, SportType: ActivityTypes.Ride, Distance: 80000, StartDate: start.AddDate(-1, 0, 0)},
+		{Id: 4, Type: ActivityTypes.VirtualRide, SportType: ActivityTypes.VirtualRide, Distance: 70000, StartDate: start, Trainer: true},
+		{Id: 5, Type: ActivityTypes.Run, SportType: ActivityTypes.Run, Distance: 10000, StartDate: start, Commute: true,
+			Visibility: Visibilities.OnlyMe, HideFromHome: true},
+	}
+
+	ids := func(activities []*ActivitySummary) []int64 {
+		ids := make([]int64, 0)
+		for _, a := range activities {
+			ids = append(ids, a.Id)
+		}
+
+		return ids
+	}
+
+	cases := []struct {
+		filters  []ActivityFilter
+		expected []int64
+	}{
+		{nil, []int64{1, 2, 3, 4, 5}},
+		{[]ActivityFilter{ActivityOfType(ActivityTypes.Ride)}, []int64{1, 2, 3}},
+		{[]ActivityFilter{ActivityOfType(ActivityTypes.GravelRide, ActivityTypes.Run)}, []int64{1, 5}},
+		{[]ActivityFilter{ActivityOnTrainer(true)}, []int64{4}},
+		{[]ActivityFilter{ActivityCommute(true)}, []int64{5}},
+		{[]ActivityFilter{ActivityWithVisibility(Visibilities.OnlyMe, Visibilities.FollowersOnly)}, []int64{5}},
+		{[]ActivityFilter{ActivityHiddenFromHome(false)}, []int64{1, 2, 3, 4}},
+		{[]ActivityFilter{ActivityStartedBetween(start, time.Time{})}, []int64{1, 2, 4, 5}},
+		{[]ActivityFilter{ActivityStartedBetween(time.Time{}, start)}, []int64{3}},
+		{[]ActivityFilter{ActivityDistanceBetween(40000, 60000)}, []int64{1, 2}},
+		{[]ActivityFilter{ActivityOfType(ActivityTypes.Ride), ActivityOnTrainer(false), ActivityDistanceBetween(50000, 0),
+			ActivityStartedBetween(start, time.Time{})}, []int64{1}},
+	}
+
+	for i, c := range cases {
+		if got := ids(FilterActivities(activities, c.filters...)); !equalIds(got, c.expected) {
+			t.Errorf("case %d: incorrect activities, got %v, expected %v", i, got, c.expected)
+		}
+	}
+
+	if FilterActivities(nil, ActivityCommute(true)) != nil {
+		t.Error("nil should stay nil")
+	}
+}
+
+func TestClientListActivitiesWhere(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athlete/activities": `[{"id":1,"commute":true},{"id":2},{"id":3,"commute":true}]`,
+	})
+
+	activities, err := NewCurrentAthleteService(client).ListActivities().Where(ActivityCommute(true)).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 2 || activities[0].Id != 1 || activities[1].Id != 3 {
+		t.Errorf("incorrect activities, got %v", activities)
+	}
+}
+
+func equalIds(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,178 @@
+package strava
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ActivityIndex finds activities by the words of their name and description, e.g. "find my ride called X",
+// since Strava has no search endpoint. Add the activities as they are synced, e.g. from the pages of
+// CurrentAthleteService.ListActivities, and again when they are updated. Safe for concurrent use.
+type ActivityIndex struct {
+	lock         sync.Mutex
+	activities   map[int64]*ActivitySummary
+	descriptions map[int64]string         // of the activities added with AddDetailed
+	words        map[int64]map[string]int // of each activity, with their weight
+	index        map[string]map[int64]bool
+}
+
+// NewActivityIndex creates an empty index.
+func NewActivityIndex() *ActivityIndex {
+	return &ActivityIndex{
+		activities:   make(map[int64]*ActivitySummary),
+		descriptions: make(map[int64]string),
+		words:        make(map[int64]map[string]int),
+		index:        make(map[string]map[int64]bool),
+	}
+}
+
+// weights of the words of names and descriptions, names are a better match
+const (
+	nameWeight        = 2
+	descriptionWeight = 1
+)
+
+// Add indexes the names of the activities, replacing previous versions of them. Summaries have no description,
+// the description of an activity added with AddDetailed before stays indexed.
+func (x *ActivityIndex) Add(activities ...*ActivitySummary) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	for _, activity := range activities {
+		x.add(activity, x.descriptions[activity.Id])
+	}
+}
+
+// AddDetailed indexes the names and descriptions of the activities, replacing previous versions of them.
+func (x *ActivityIndex) AddDetailed(activities ...*ActivityDetailed) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	for _, activity := range activities {
+		x.add(&activity.ActivitySummary, activity.Description)
+	}
+}
+
+func (x *ActivityIndex) add(activity *ActivitySummary, description string) {
+	x.remove(activity.Id)
+
+	words := make(map[string]int)
+	for _, word := range searchWords(description) {
+		words[word] = descriptionWeight
+	}
+
+	for _, word := range searchWords(activity.Name) {
+		words[word] = nameWeight
+	}
+
+	for word := range words {
+		if x.index[word] == nil {
+			x.index[word] = make(map[int64]bool)
+		}
+		x.index[word][activity.Id] = true
+	}
+
+	x.activities[activity.Id] = activity
+	x.words[activity.Id] = words
+	if description != "" {
+		x.descriptions[activity.Id] = description
+	}
+}
+
+// Remove removes the activity from the index, e.g. after it was deleted.
+func (x *ActivityIndex) Remove(activityId int64) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	x.remove(activityId)
+}
+
+func (x *ActivityIndex) remove(activityId int64) {
+	for word := range x.words[activityId] {
+		delete(x.index[word], activityId)
+		if len(x.index[word]) == 0 {
+			delete(x.index, word)
+		}
+	}
+
+	delete(x.activities, activityId)
+	delete(x.descriptions, activityId)
+	delete(x.words, activityId)
+}
+
+// Len returns the number of activities in the index.
+func (x *ActivityIndex) Len() int {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	return len(x.activities)
+}
+
+// Search returns the activities having all words of the query, or words starting with them, in their name
+// or description, ignoring case. Activities matching by name come first, then the most recent.
+func (x *ActivityIndex) Search(query string) []*ActivitySummary {
+	terms := searchWords(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	var scores map[int64]int
+	for _, term := range terms {
+		matches := make(map[int64]int)
+		for word, ids := range x.index {
+			if !strings.HasPrefix(word, term) {
+				continue
+			}
+
+			for id := range ids {
+				if weight := x.words[id][word]; weight > matches[id] {
+					matches[id] = weight
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = matches
+			continue
+		}
+
+		// all terms have to match
+		for id := range scores {
+			if matches[id] == 0 {
+				delete(scores, id)
+			} else {
+				scores[id] += matches[id]
+			}
+		}
+	}
+
+	results := make([]*ActivitySummary, 0, len(scores))
+	for id := range scores {
+		results = append(results, x.activities[id])
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if scores[a.Id] != scores[b.Id] {
+			return scores[a.Id] > scores[b.Id]
+		}
+		if !a.StartDate.Equal(b.StartDate) {
+			return a.StartDate.After(b.StartDate)
+		}
+		return a.Id > b.Id
+	})
+
+	return results
+}
+
+// searchWords returns the lower case words of the text, split on anything but letters and digits.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,60 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityIndex(t *testing.T) {
+	day := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	newActivity := func(id int64, name string, days int) *ActivitySummary {
+		return &ActivitySummary{Id: id, Name: name, StartDate: day.AddDate(0, 0, days)}
+	}
+
+	x := NewActivityIndex()
+	x.Add(newActivity(1, "Morning Ride", 0), newActivity(2, "Evening Run", 1), newActivity(3, "Ride to Girona", 2))
+	x.AddDetailed(&ActivityDetailed{ActivitySummary: *newActivity(4, "Lunch Run", 3), Description: "Easy ride-along with the club, then a run"})
+
+	ids := func(activities []*ActivitySummary) []int64 {
+		var ids []int64
+		for _, activity := range activities {
+			ids = append(ids, activity.Id)
+		}
+		return ids
+	}
+
+	for query, expected := range map[string][]int64{
+		"ride":          {3, 1, 4}, // names first, then the most recent
+		"RIDE girona":   {3},
+		"run":           {4, 2},
+		"club run":      {4},
+		"mor":           {1},
+		"ride swimming": nil,
+		"  ":            nil,
+	} {
+		if got := ids(x.Search(query)); !equalIds(got, expected) {
+			t.Errorf("incorrect results for %q, got %v", query, got)
+		}
+	}
+
+	// updated and removed activities
+	x.Add(newActivity(1, "Gravel Loop", 0))
+	x.Remove(3)
+	if got := ids(x.Search("ride")); !equalIds(got, []int64{4}) || x.Len() != 3 {
+		t.Errorf("incorrect results after the update, got %v", got)
+	}
+
+	if got := ids(x.Search("gravel")); !equalIds(got, []int64{1}) {
+		t.Errorf("new name should be found, got %v", got)
+	}
+
+	// a summary keeps the description of the detailed activity
+	x.Add(newActivity(4, "Lunch Jog", 3))
+	if got := ids(x.Search("club")); !equalIds(got, []int64{4}) {
+		t.Errorf("description should still be found, got %v", got)
+	}
+
+	if got := ids(x.Search("jog")); !equalIds(got, []int64{4}) {
+		t.Errorf("new name should be found, got %v", got)
+	}
+}
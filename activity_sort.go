@@ -0,0 +1,85 @@
+package strava
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// SortActivitiesOldestFirst sorts the activities in place by start date, activities started at the same
+// time by id, e.g. for sync pipelines processing activities chronologically. Strava returns lists newest first.
+func SortActivitiesOldestFirst(activities []*ActivitySummary) {
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activityStartedBefore(activities[i], activities[j])
+	})
+}
+
+// SortActivitiesNewestFirst sorts the activities in place by start date, newest first, like Strava returns them.
+func SortActivitiesNewestFirst(activities []*ActivitySummary) {
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activityStartedBefore(activities[j], activities[i])
+	})
+}
+
+func activityStartedBefore(a, b *ActivitySummary) bool {
+	if !a.StartDate.Equal(b.StartDate) {
+		return a.StartDate.Before(b.StartDate)
+	}
+
+	return a.Id < b.Id
+}
+
+/*********************************************************/
+
+// ErrUnboundedWindow is returned by IterateOldestFirst for a zero after, which would load the whole history.
+var ErrUnboundedWindow = errors.New("the window has no start")
+
+// IterateOldestFirst returns an iterator over the activities started after after and before before,
+// oldest first, selected by the filters of Where. All activities of the window are fetched before the
+// first one is returned, the window bounds the memory used, so after is required: a zero after fails with
+// ErrUnboundedWindow, pass time.Unix(0, 0) to really load all activities. A zero before is now.
+// The call itself is not changed, it can be iterated over other windows.
+func (c *CurrentAthleteListActivitiesCall) IterateOldestFirst(after, before time.Time) *Iterator[*ActivitySummary] {
+	if after.IsZero() {
+		return NewIterator(func() (*ActivitySummary, bool, error) {
+			return nil, false, wrapError(ErrUnboundedWindow, "athlete.list_activities")
+		})
+	}
+
+	window := *c
+	window.ops = make(map[string]interface{}, len(c.ops)+2)
+	for k, v := range c.ops {
+		window.ops[k] = v
+	}
+
+	window.After(int(after.Unix()))
+	if !before.IsZero() {
+		window.Before(int(before.Unix()))
+	}
+
+	it := window.Iterate()
+
+	var activities []*ActivitySummary
+	loaded := false
+
+	return NewIterator(func() (*ActivitySummary, bool, error) {
+		if !loaded {
+			var err error
+			if activities, err = Collect(it); err != nil {
+				return nil, false, err
+			}
+
+			SortActivitiesOldestFirst(activities)
+			loaded = true
+		}
+
+		if len(activities) == 0 {
+			return nil, false, nil
+		}
+
+		activity := activities[0]
+		activities = activities[1:]
+
+		return activity, true, nil
+	})
+}
@@ -0,0 +1,85 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSortActivities(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	activities := []*ActivitySummary{
+		{Id: 3, StartDate: start.Add(time.Hour)},
+		{Id: 2, StartDate: start},
+		{Id: 4, StartDate: start.Add(2 * time.Hour)},
+		{Id: 1, StartDate: start},
+	}
+
+	SortActivitiesOldestFirst(activities)
+	if ids := activityIds(activities); ids != "[1 2 3 4]" {
+		t.Errorf("incorrect order, got %v", ids)
+	}
+
+	SortActivitiesNewestFirst(activities)
+	if ids := activityIds(activities); ids != "[4 3 2 1]" {
+		t.Errorf("incorrect order, got %v", ids)
+	}
+}
+
+func TestCurrentAthleteListActivitiesIterateOldestFirst(t *testing.T) {
+	transport := &pageTransport{pages: []string{
+		`[{"id":4,"start_date":"2024-01-04T00:00:00Z"},{"id":3,"start_date":"2024-01-03T00:00:00Z"}]`,
+		`[{"id":2,"start_date":"2024-01-02T00:00:00Z"},{"id":1,"start_date":"2024-01-01T00:00:00Z"}]`,
+	}}
+
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: transport}
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	it := NewCurrentAthleteService(client).ListActivities().PerPage(2).IterateOldestFirst(after, before)
+	activities, err := Collect(it)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if ids := activityIds(activities); ids != "[1 2 3 4]" {
+		t.Errorf("incorrect order, got %v", ids)
+	}
+
+	expected := fmt.Sprintf("after=%d&before=%d&page=1&per_page=2", after.Unix(), before.Unix())
+	if transport.queries[0] != expected {
+		t.Errorf("incorrect query, got %v", transport.queries[0])
+	}
+
+	// a zero before is left out, the call is not changed
+	transport.queries = nil
+	call := NewCurrentAthleteService(client).ListActivities().PerPage(2)
+	Collect(call.IterateOldestFirst(after, time.Time{}))
+
+	if transport.queries[0] != fmt.Sprintf("after=%d&page=1&per_page=2", after.Unix()) {
+		t.Errorf("zero before should be left out, got %v", transport.queries[0])
+	}
+
+	if len(call.ops) != 1 {
+		t.Errorf("the call should not be changed, got %v", call.ops)
+	}
+
+	// the whole history is not loaded by mistake
+	transport.queries = nil
+	if _, err := Collect(call.IterateOldestFirst(time.Time{}, before)); !errors.Is(err, ErrUnboundedWindow) || len(transport.queries) != 0 {
+		t.Errorf("zero after should be rejected, got %v", err)
+	}
+}
+
+func activityIds(activities []*ActivitySummary) string {
+	ids := make([]int64, len(activities))
+	for i, a := range activities {
+		ids[i] = a.Id
+	}
+
+	return fmt.Sprint(ids)
+}
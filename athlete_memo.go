@@ -0,0 +1,154 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// AthleteMemo memoizes the profiles and zones of athletes, which rarely change, so computing the metrics
+// of every synced activity doesn't fetch them again. They are kept until they are invalidated, e.g. by
+// passing the webhook events to InvalidateEvent, or until the max age set with MaxAge. Concurrent calls
+// for the same athlete share one request and failed requests are not kept. Safe for concurrent use.
+type AthleteMemo struct {
+	clientFor func(athleteId int64) (*Client, error)
+	maxAge    time.Duration
+	now       func() time.Time
+
+	lock     sync.Mutex
+	profiles map[int64]*memoEntry[AthleteDetailed]
+	zones    map[int64]*memoEntry[AthleteZones]
+}
+
+// memoEntry is a value of an athlete, being fetched until done is closed.
+type memoEntry[T any] struct {
+	done    chan struct{}
+	value   *T
+	err     error
+	expires time.Time // zero if the value doesn't expire
+}
+
+// NewAthleteMemo creates an empty memo fetching with the client returned by clientFor for the athlete,
+// like a WebhookDispatcher.
+func NewAthleteMemo(clientFor func(athleteId int64) (*Client, error)) *AthleteMemo {
+	return &AthleteMemo{
+		clientFor: clientFor,
+		now:       time.Now,
+		profiles:  make(map[int64]*memoEntry[AthleteDetailed]),
+		zones:     make(map[int64]*memoEntry[AthleteZones]),
+	}
+}
+
+// MaxAge makes the memo fetch the profiles and zones again once they are older than d, for changes made
+// without a webhook event, e.g. of the FTP or the zones. Zero, the default, keeps them until invalidated.
+func (m *AthleteMemo) MaxAge(d time.Duration) *AthleteMemo {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.maxAge = d
+	return m
+}
+
+// Profile returns the profile of the athlete, from /athlete.
+func (m *AthleteMemo) Profile(ctx context.Context, athleteId int64) (*AthleteDetailed, error) {
+	athlete, err := memoize(m, m.profiles, ctx, athleteId, "/athlete")
+	return athlete, wrapError(err, "memo.profile")
+}
+
+// Zones returns the heart rate and power zones of the athlete, from /athlete/zones,
+// which needs the profile:read_all scope.
+func (m *AthleteMemo) Zones(ctx context.Context, athleteId int64) (*AthleteZones, error) {
+	zones, err := memoize(m, m.zones, ctx, athleteId, "/athlete/zones")
+	return zones, wrapError(err, "memo.zones")
+}
+
+// errMemoPanic is the error of a fetch that panicked, returned to the calls waiting for it.
+var errMemoPanic = errors.New("fetch panicked")
+
+// memoize returns the value of the athlete in the entries, fetching it from the path if there is none
+// or it expired. Calls waiting for a fetch in flight get its result, also when it failed, unless it was
+// cancelled by the context of the call that made it, then they fetch it again with their own.
+func memoize[T any](m *AthleteMemo, entries map[int64]*memoEntry[T], ctx context.Context, athleteId int64, path string) (*T, error) {
+	for {
+		m.lock.Lock()
+		entry, ok := entries[athleteId]
+		if !ok || entry.expired(m.now()) {
+			break
+		}
+		m.lock.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if !errors.Is(entry.err, context.Canceled) && !errors.Is(entry.err, context.DeadlineExceeded) {
+			return entry.value, entry.err
+		}
+	}
+
+	entry := &memoEntry[T]{done: make(chan struct{})}
+	entries[athleteId] = entry
+	maxAge := m.maxAge
+	m.lock.Unlock()
+
+	// the waiting calls are released also if the fetch panics
+	fetched := false
+	defer func() {
+		if !fetched {
+			entry.value, entry.err = nil, errMemoPanic
+		}
+
+		m.lock.Lock()
+		if entry.err != nil && entries[athleteId] == entry {
+			delete(entries, athleteId)
+		}
+		if maxAge > 0 {
+			entry.expires = m.now().Add(maxAge)
+		}
+		close(entry.done)
+		m.lock.Unlock()
+	}()
+
+	client, err := m.clientFor(athleteId)
+	if err == nil {
+		entry.value, err = Call[T](ctx, client, "GET", path, nil)
+	}
+	entry.err = err
+	fetched = true
+
+	return entry.value, entry.err
+}
+
+// expired returns if the entry was fetched and has expired at now, called with the lock of the memo held.
+func (e *memoEntry[T]) expired(now time.Time) bool {
+	select {
+	case <-e.done:
+		return !e.expires.IsZero() && !now.Before(e.expires)
+	default:
+		return false
+	}
+}
+
+// Invalidate removes the profile and zones of the athlete, the next calls fetch them again.
+// Fetches in flight are not cancelled but their result isn't kept.
+func (m *AthleteMemo) Invalidate(athleteId int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.profiles, athleteId)
+	delete(m.zones, athleteId)
+}
+
+// InvalidateEvent invalidates the athlete of a webhook event about an athlete, an update of the profile
+// or a deauthorization, and returns if it did. Events about activities are ignored.
+func (m *AthleteMemo) InvalidateEvent(event *WebhookEvent) bool {
+	if event.ObjectType != WebhookObjectTypes.Athlete {
+		return false
+	}
+
+	m.Invalidate(event.ObjectId)
+	return true
+}
@@ -0,0 +1,218 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAthleteMemo(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/athlete":       `{"id":1,"firstname":"Jane","ftp":250}`,
+		"/api/v3/athlete/zones": `{"heart_rate":{"custom_zones":false,"zones":[{"min":0,"max":120},{"min":120,"max":-1}]}}`,
+	})
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	memo := NewAthleteMemo(func(athleteId int64) (*Client, error) { return client, nil })
+	memo.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		athlete, err := memo.Profile(context.Background(), 1)
+		if err != nil || athlete.FTP != 250 {
+			t.Fatalf("incorrect profile, got %v %v", athlete, err)
+		}
+
+		zones, err := memo.Zones(context.Background(), 1)
+		if err != nil || len(zones.HeartRate.Zones) != 2 {
+			t.Fatalf("incorrect zones, got %v %v", zones, err)
+		}
+	}
+
+	if len(transport.requests) != 2 {
+		t.Errorf("profile and zones should be fetched once, got %d requests", len(transport.requests))
+	}
+
+	// activity events keep the athlete, athlete events invalidate it
+	if memo.InvalidateEvent(&WebhookEvent{ObjectType: WebhookObjectTypes.Activity, ObjectId: 1, OwnerId: 1}) {
+		t.Error("activity events should not invalidate")
+	}
+
+	if !memo.InvalidateEvent(&WebhookEvent{ObjectType: WebhookObjectTypes.Athlete, AspectType: WebhookAspectTypes.Update, ObjectId: 1, OwnerId: 1}) {
+		t.Error("athlete events should invalidate")
+	}
+
+	memo.Profile(context.Background(), 1)
+	memo.Zones(context.Background(), 1)
+	if len(transport.requests) != 4 {
+		t.Errorf("invalidated athletes should be fetched again, got %d requests", len(transport.requests))
+	}
+
+	// max age
+	memo.MaxAge(time.Hour)
+	memo.Invalidate(1)
+	memo.Profile(context.Background(), 1)
+	now = now.Add(59 * time.Minute)
+	memo.Profile(context.Background(), 1)
+	if len(transport.requests) != 5 {
+		t.Errorf("profiles should be kept for the max age, got %d requests", len(transport.requests))
+	}
+
+	now = now.Add(time.Minute)
+	memo.Profile(context.Background(), 1)
+	if len(transport.requests) != 6 {
+		t.Errorf("expired profiles should be fetched again, got %d requests", len(transport.requests))
+	}
+
+	// failures are not kept
+	delete(transport.routes, "/api/v3/athlete/zones")
+	memo.Invalidate(1)
+	for i := 0; i < 2; i++ {
+		if _, err := memo.Zones(context.Background(), 1); err == nil {
+			t.Fatal("missing zones should fail")
+		}
+	}
+
+	if len(transport.requests) != 8 {
+		t.Errorf("failed fetches should be retried, got %d requests", len(transport.requests))
+	}
+}
+
+func TestAthleteMemoConcurrent(t *testing.T) {
+	transport := &blockingTransport{started: make(chan bool, 10), release: make(chan bool)}
+
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: transport}
+	memo := NewAthleteMemo(func(athleteId int64) (*Client, error) { return client, nil })
+
+	var wg sync.WaitGroup
+	athletes := make([]*AthleteDetailed, 5)
+
+	get := func(i int) {
+		defer wg.Done()
+		athletes[i], _ = memo.Profile(context.Background(), 1)
+	}
+
+	wg.Add(1)
+	go get(0)
+	<-transport.started
+
+	for i := 1; i < len(athletes); i++ {
+		wg.Add(1)
+		go get(i)
+	}
+
+	time.Sleep(50 * time.Millisecond) // for the calls to wait for the fetch in flight
+	close(transport.release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&transport.requests); n != 1 {
+		t.Errorf("calls should share one request, got %v", n)
+	}
+
+	for i, athlete := range athletes {
+		if athlete == nil || athlete.FirstName != "Jane" {
+			t.Fatalf("call %d should get the profile, got %v", i, athlete)
+		}
+	}
+}
+
+func TestAthleteMemoCancelledFetch(t *testing.T) {
+	var requests int32
+	started := make(chan bool, 10)
+
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			started <- true
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(`{"id":1,"firstname":"Jane"}`)), Request: req}, nil
+	})}
+	memo := NewAthleteMemo(func(athleteId int64) (*Client, error) { return client, nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := memo.Profile(ctx, 1)
+		errs <- err
+	}()
+	<-started
+
+	athletes := make(chan *AthleteDetailed, 1)
+	go func() {
+		athlete, _ := memo.Profile(context.Background(), 1)
+		athletes <- athlete
+	}()
+
+	time.Sleep(50 * time.Millisecond) // for the call to wait for the fetch in flight
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled call should fail, got %v", err)
+	}
+
+	// the waiting call fetches again with its own context
+	if athlete := <-athletes; athlete == nil || athlete.FirstName != "Jane" {
+		t.Errorf("waiting call should get the profile, got %v", athlete)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("profile should be fetched again, got %d requests", n)
+	}
+}
+
+func TestAthleteMemoPanic(t *testing.T) {
+	release := make(chan bool)
+	memo := NewAthleteMemo(func(athleteId int64) (*Client, error) {
+		<-release
+		panic("no client")
+	})
+
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		memo.Profile(context.Background(), 1)
+	}()
+
+	// waits for the fetch of the first call
+	for {
+		memo.lock.Lock()
+		_, fetching := memo.profiles[1]
+		memo.lock.Unlock()
+
+		if fetching {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := memo.Profile(context.Background(), 1)
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond) // for the call to wait for the fetch in flight
+	close(release)
+
+	if r := <-panicked; r != "no client" {
+		t.Errorf("the panic should be passed on, got %v", r)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, errMemoPanic) {
+			t.Errorf("waiting call should fail, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting call should be released")
+	}
+}
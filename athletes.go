@@ -1,8 +1,8 @@
 package strava
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 )
 
@@ -10,8 +10,8 @@ type AthleteDetailed struct {
 	AthleteSummary
 	Email                 string         `json:"email"`
 	FollowerCount         int            `json:"follower_count"`
-	FriendCount           int            `json:"friend_count"`
-	MutualFriendCount     int            `json:"mutual_friend_count"`
+	FriendCount           int            `json:"friend_count"`        // no longer returned by Strava
+	MutualFriendCount     int            `json:"mutual_friend_count"` // no longer returned by Strava
 	DatePreference        string         `json:"date_preference"`
 	MeasurementPreference string         `json:"measurement_preference"`
 	FTP                   int            `json:"ftp"`
@@ -22,6 +22,7 @@ type AthleteDetailed struct {
 }
 
 type AthleteSummary struct {
+	RawJSON
 	AthleteMeta
 	FirstName        string    `json:"firstname"`
 	LastName         string    `json:"lastname"`
@@ -33,7 +34,8 @@ type AthleteSummary struct {
 	Gender           Gender    `json:"sex"`
 	Friend           string    `json:"friend"`   // ‘pending’, ‘accepted’, ‘blocked’ or ‘null’, the authenticated athlete’s following status of this athlete
 	Follower         string    `json:"follower"` // this athlete’s following status of the authenticated athlete
-	Premium          bool      `json:"premium"`
+	Premium          bool      `json:"premium"`  // deprecated by Strava in favor of summit
+	Summit           bool      `json:"summit"`   // if the athlete has a subscription
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 	ApproveFollowers bool      `json:"approve_followers"` // if has enhanced privacy enabled
@@ -41,12 +43,13 @@ type AthleteSummary struct {
 }
 
 type AthleteMeta struct {
-	Id int64 `json:"id"`
+	Id int64 `json:"id" strava:"required"`
 }
 
 type AthleteStats struct {
-	BiggestRideDistance       float64       `json:"biggest_ride_distance"`
-	BiggestClimbElevationGain float64       `json:"biggest_climb_elevation_gain"`
+	RawJSON
+	BiggestRideDistance       Distance      `json:"biggest_ride_distance"`
+	BiggestClimbElevationGain Elevation     `json:"biggest_climb_elevation_gain"`
 	RecentRideTotals          AthleteTotals `json:"recent_ride_totals"`
 	RecentRunTotals           AthleteTotals `json:"recent_run_totals"`
 	YTDRideTotals             AthleteTotals `json:"ytd_ride_totals"`
@@ -56,11 +59,11 @@ type AthleteStats struct {
 }
 
 type AthleteTotals struct {
-	Count         int     `json:"count"`
-	Distance      float64 `json:"distance"`
-	MovingTime    int     `json:"moving_time"`
-	ElapsedTime   int     `json:"elapsed_time"`
-	ElevationGain float64 `json:"elevation_gain"`
+	Count         int       `json:"count"`
+	Distance      Distance  `json:"distance"`
+	MovingTime    int       `json:"moving_time"`
+	ElapsedTime   int       `json:"elapsed_time"`
+	ElevationGain Elevation `json:"elevation_gain"`
 
 	// only correct for recent totals, not ytd or all
 	AchievementCount int `json:"achievement_count"`
@@ -85,8 +88,12 @@ func NewAthletesService(client *Client) *AthletesService {
 /*********************************************************/
 
 type AthletesGetCall struct {
-	service *AthletesService
-	id      int64
+	service      *AthletesService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) Get(athleteId int64) *AthletesGetCall {
@@ -96,27 +103,48 @@ func (s *AthletesService) Get(athleteId int64) *AthletesGetCall {
 	}
 }
 
-func (c *AthletesGetCall) Do() (*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesGetCall) OnError(handler ErrorHandler) *AthletesGetCall {
+	c.errorHandler = handler
+	return c
+}
 
-	var athlete AthleteSummary
-	err = json.Unmarshal(data, &athlete)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesGetCall) Response(resp *Response) *AthletesGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesGetCall) Timeout(d time.Duration) *AthletesGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesGetCall) Header(key, value string) *AthletesGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &athlete, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesGetCall) Do() (*AthleteSummary, error) {
+	athlete, err := runAndDecode[*AthleteSummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return athlete, wrapError(err, "athletes.get id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesListStarredSegmentsCall struct {
-	service *AthletesService
-	id      int64
-	ops     map[string]interface{}
+	service      *AthletesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) ListStarredSegments(athleteId int64) *AthletesListStarredSegmentsCall {
@@ -137,27 +165,48 @@ func (c *AthletesListStarredSegmentsCall) PerPage(perPage int) *AthletesListStar
 	return c
 }
 
-func (c *AthletesListStarredSegmentsCall) Do() ([]*PersonalSegmentSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/segments/starred", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesListStarredSegmentsCall) OnError(handler ErrorHandler) *AthletesListStarredSegmentsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesListStarredSegmentsCall) Response(resp *Response) *AthletesListStarredSegmentsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesListStarredSegmentsCall) Timeout(d time.Duration) *AthletesListStarredSegmentsCall {
+	c.timeout = d
+	return c
+}
 
-	segments := make([]*PersonalSegmentSummary, 0)
-	err = json.Unmarshal(data, &segments)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesListStarredSegmentsCall) Header(key, value string) *AthletesListStarredSegmentsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return segments, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesListStarredSegmentsCall) Do() ([]*PersonalSegmentSummary, error) {
+	segments, err := runAndDecode[[]*PersonalSegmentSummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d/segments/starred", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return segments, wrapError(err, "athletes.list_starred_segments id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesListFriendsCall struct {
-	service *AthletesService
-	id      int64
-	ops     map[string]interface{}
+	service      *AthletesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) ListFriends(athleteId int64) *AthletesListFriendsCall {
@@ -178,27 +227,48 @@ func (c *AthletesListFriendsCall) PerPage(perPage int) *AthletesListFriendsCall
 	return c
 }
 
-func (c *AthletesListFriendsCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/friends", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesListFriendsCall) OnError(handler ErrorHandler) *AthletesListFriendsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesListFriendsCall) Response(resp *Response) *AthletesListFriendsCall {
+	c.response = resp
+	return c
+}
 
-	friends := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &friends)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesListFriendsCall) Timeout(d time.Duration) *AthletesListFriendsCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesListFriendsCall) Header(key, value string) *AthletesListFriendsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return friends, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesListFriendsCall) Do() ([]*AthleteSummary, error) {
+	friends, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d/friends", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return friends, wrapError(err, "athletes.list_friends id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesListFollowersCall struct {
-	service *AthletesService
-	id      int64
-	ops     map[string]interface{}
+	service      *AthletesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) ListFollowers(athleteId int64) *AthletesListFollowersCall {
@@ -219,27 +289,48 @@ func (c *AthletesListFollowersCall) PerPage(perPage int) *AthletesListFollowersC
 	return c
 }
 
-func (c *AthletesListFollowersCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/followers", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesListFollowersCall) OnError(handler ErrorHandler) *AthletesListFollowersCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesListFollowersCall) Response(resp *Response) *AthletesListFollowersCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesListFollowersCall) Timeout(d time.Duration) *AthletesListFollowersCall {
+	c.timeout = d
+	return c
+}
 
-	followers := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &followers)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesListFollowersCall) Header(key, value string) *AthletesListFollowersCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return followers, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesListFollowersCall) Do() ([]*AthleteSummary, error) {
+	followers, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d/followers", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return followers, wrapError(err, "athletes.list_followers id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesListBothFollowingCall struct {
-	service *AthletesService
-	id      int64
-	ops     map[string]interface{}
+	service      *AthletesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) ListBothFollowing(athleteId int64) *AthletesListBothFollowingCall {
@@ -260,26 +351,47 @@ func (c *AthletesListBothFollowingCall) PerPage(perPage int) *AthletesListBothFo
 	return c
 }
 
-func (c *AthletesListBothFollowingCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/both-following", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesListBothFollowingCall) OnError(handler ErrorHandler) *AthletesListBothFollowingCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesListBothFollowingCall) Response(resp *Response) *AthletesListBothFollowingCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesListBothFollowingCall) Timeout(d time.Duration) *AthletesListBothFollowingCall {
+	c.timeout = d
+	return c
+}
 
-	athletes := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &athletes)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesListBothFollowingCall) Header(key, value string) *AthletesListBothFollowingCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return athletes, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesListBothFollowingCall) Do() ([]*AthleteSummary, error) {
+	athletes, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d/both-following", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return athletes, wrapError(err, "athletes.list_both_following id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesStatsCall struct {
-	service *AthletesService
-	id      int64
+	service      *AthletesService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) Stats(athleteId int64) *AthletesStatsCall {
@@ -289,27 +401,48 @@ func (s *AthletesService) Stats(athleteId int64) *AthletesStatsCall {
 	}
 }
 
-func (c *AthletesStatsCall) Do() (*AthleteStats, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/stats", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesStatsCall) OnError(handler ErrorHandler) *AthletesStatsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesStatsCall) Response(resp *Response) *AthletesStatsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesStatsCall) Timeout(d time.Duration) *AthletesStatsCall {
+	c.timeout = d
+	return c
+}
 
-	stats := &AthleteStats{}
-	err = json.Unmarshal(data, &stats)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesStatsCall) Header(key, value string) *AthletesStatsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return stats, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesStatsCall) Do() (*AthleteStats, error) {
+	stats, err := runAndDecode[*AthleteStats](c.service.client, "GET", fmt.Sprintf("/athletes/%d/stats", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return stats, wrapError(err, "athletes.stats id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesListKOMsCall struct {
-	service *AthletesService
-	id      int64
-	ops     map[string]interface{}
+	service      *AthletesService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) ListKOMs(athleteId int64) *AthletesListKOMsCall {
@@ -330,27 +463,49 @@ func (c *AthletesListKOMsCall) PerPage(perPage int) *AthletesListKOMsCall {
 	return c
 }
 
-func (c *AthletesListKOMsCall) Do() ([]*SegmentEffortSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/koms", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *AthletesListKOMsCall) OnError(handler ErrorHandler) *AthletesListKOMsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesListKOMsCall) Response(resp *Response) *AthletesListKOMsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesListKOMsCall) Timeout(d time.Duration) *AthletesListKOMsCall {
+	c.timeout = d
+	return c
+}
 
-	efforts := make([]*SegmentEffortSummary, 0)
-	err = json.Unmarshal(data, &efforts)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesListKOMsCall) Header(key, value string) *AthletesListKOMsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return efforts, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesListKOMsCall) Do() ([]*SegmentEffortSummary, error) {
+	efforts, err := runAndDecode[[]*SegmentEffortSummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d/koms", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return efforts, wrapError(err, "athletes.list_koms id=%d", c.id)
 }
 
 /*********************************************************/
 
 type AthletesListActivitiesCall struct {
-	service *AthletesService
-	id      int64
-	ops     map[string]interface{}
+	service      *AthletesService
+	id           int64
+	ops          map[string]interface{}
+	filters      []ActivityFilter
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *AthletesService) ListActivities(athleteId int64) *AthletesListActivitiesCall {
@@ -381,17 +536,41 @@ func (c *AthletesListActivitiesCall) PerPage(perPage int) *AthletesListActivitie
 	return c
 }
 
-func (c *AthletesListActivitiesCall) Do() ([]*ActivitySummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/activities", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+// Where only returns the activities of the page selected by all filters, see ActivityFilter.
+// Pages are requested as usual, so they may hold fewer activities than requested.
+func (c *AthletesListActivitiesCall) Where(filters ...ActivityFilter) *AthletesListActivitiesCall {
+	c.filters = append(c.filters, filters...)
+	return c
+}
+
+func (c *AthletesListActivitiesCall) OnError(handler ErrorHandler) *AthletesListActivitiesCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *AthletesListActivitiesCall) Response(resp *Response) *AthletesListActivitiesCall {
+	c.response = resp
+	return c
+}
 
-	activities := make([]*ActivitySummary, 0)
-	err = json.Unmarshal(data, &activities)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *AthletesListActivitiesCall) Timeout(d time.Duration) *AthletesListActivitiesCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *AthletesListActivitiesCall) Header(key, value string) *AthletesListActivitiesCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return activities, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *AthletesListActivitiesCall) Do() ([]*ActivitySummary, error) {
+	activities, err := runAndDecode[[]*ActivitySummary](c.service.client, "GET", fmt.Sprintf("/athletes/%d/activities", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return FilterActivities(activities, c.filters...), wrapError(err, "athletes.list_activities id=%d", c.id)
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func TestAthletesGet(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_get")
+	client := newFixtureClient(t, "athlete_get")
 	athlete, err := NewAthletesService(client).Get(3545423).Do()
 
 	if err != nil {
@@ -51,7 +51,7 @@ func TestAthletesGet(t *testing.T) {
 }
 
 func TestCAthletesListStarredSegments(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_list_starred_segments")
+	client := newFixtureClient(t, "athlete_list_starred_segments")
 	segments, err := NewAthletesService(client).ListStarredSegments(3545423).Do()
 
 	if err != nil {
@@ -107,7 +107,7 @@ func TestCAthletesListStarredSegments(t *testing.T) {
 }
 
 func TestAthletesListFriends(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_list_friends")
+	client := newFixtureClient(t, "athlete_list_friends")
 	friends, err := NewAthletesService(client).ListFriends(3545423).Do()
 
 	if err != nil {
@@ -139,7 +139,7 @@ func TestAthletesListFriends(t *testing.T) {
 }
 
 func TestAthletesListFollowers(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_list_followers")
+	client := newFixtureClient(t, "athlete_list_followers")
 	followers, err := NewAthletesService(client).ListFollowers(3545423).Do()
 
 	if err != nil {
@@ -171,7 +171,7 @@ func TestAthletesListFollowers(t *testing.T) {
 }
 
 func TestAthletesListBothFollowing(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_list_both_following")
+	client := newFixtureClient(t, "athlete_list_both_following")
 	followers, err := NewAthletesService(client).ListBothFollowing(3545423).Do()
 
 	if err != nil {
@@ -203,7 +203,7 @@ func TestAthletesListBothFollowing(t *testing.T) {
 }
 
 func TestAthletesStats(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_stats")
+	client := newFixtureClient(t, "athlete_stats")
 	stats, err := NewAthletesService(client).Stats(123).Do()
 
 	if err != nil {
@@ -258,7 +258,7 @@ func TestAthletesStats(t *testing.T) {
 }
 
 func TestAthletesListKOMs(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_list_koms")
+	client := newFixtureClient(t, "athlete_list_koms")
 	efforts, err := NewAthletesService(client).ListKOMs(3776).Do()
 
 	if err != nil {
@@ -290,7 +290,7 @@ func TestAthletesListKOMs(t *testing.T) {
 }
 
 func TestAthletesListActivities(t *testing.T) {
-	client := newCassetteClient(testToken, "athlete_list_activies")
+	client := newFixtureClient(t, "athlete_list_activies")
 	activities, err := NewAthletesService(client).ListActivities(14507).Do()
 
 	if err != nil {
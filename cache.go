@@ -0,0 +1,148 @@
+package strava
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A Cache keeps the responses of GET requests of a client created WithCache, which returns them
+// without a request until they expire, e.g. for dashboards rendering the same athlete and activity
+// data again and again. Caches are used from several goroutines when clients are.
+type Cache interface {
+	// Get returns the response stored for the key, ok is false if there is none or it expired.
+	Get(key string) (value []byte, ok bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// WithCache makes the client return the responses of GET requests from the cache for ttl after
+// they were made. Responses are cached per athlete, or per access token if the AuthorizationResponse
+// of the TokenSource doesn't hold the athlete, so caches can be shared by clients. Updates made with
+// the client don't invalidate cached responses, the ttl should be short enough for that.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// LRUCache is a Cache keeping a limited number of responses in memory,
+// evicting the least recently used ones first.
+type LRUCache struct {
+	lock    sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns a cache keeping up to size responses.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := &lruEntry{key: key, value: value, expires: c.now().Add(ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached responses, including expired ones not evicted yet.
+func (c *LRUCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+/*********************************************************/
+
+// storeKey returns the key of the GET request in caches and ETag stores, the url prefixed with the id
+// of the athlete, or a hash of the access token if the athlete is unknown, so clients sharing a store never
+// get the responses of other athletes. It is empty for other requests, downloads of a range, requests
+// without either and requests of the application without a token, which are not stored.
+func storeKey(req *http.Request, auth *AuthorizationResponse) string {
+	if auth == nil || req.Method != "GET" || req.Header.Get("Range") != "" {
+		return ""
+	}
+
+	switch {
+	case auth.Athlete != nil && auth.Athlete.Id != 0:
+		return strconv.FormatInt(auth.Athlete.Id, 10) + " " + req.URL.String()
+	case auth.AccessToken != "":
+		hash := sha256.Sum256([]byte(auth.AccessToken))
+		return "token:" + hex.EncodeToString(hash[:16]) + " " + req.URL.String()
+	}
+
+	return ""
+}
+
+// cached returns the cached response to the request, if any, and the key to cache its response with,
+// empty if it is not cached.
+func (client *Client) cached(req *http.Request, auth *AuthorizationResponse) (string, []byte, bool) {
+	if client.cache == nil {
+		return "", nil, false
+	}
+
+	key := storeKey(req, auth)
+	if key == "" {
+		return "", nil, false
+	}
+
+	data, ok := client.cache.Get(key)
+	return key, data, ok
+}
+
+func (client *Client) storeCache(key string, data []byte) {
+	if key != "" {
+		client.cache.Set(key, data, client.cacheTTL)
+	}
+}
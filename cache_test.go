@@ -0,0 +1,109 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	cache := NewLRUCache(2)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+
+	if value, ok := cache.Get("a"); !ok || string(value) != "1" {
+		t.Errorf("value should be cached, got %s %v", value, ok)
+	}
+
+	// b is the least recently used
+	cache.Set("c", []byte("3"), time.Minute)
+	if _, ok := cache.Get("b"); ok || cache.Len() != 2 {
+		t.Errorf("least recently used value should be evicted, got %v entries", cache.Len())
+	}
+
+	cache.Set("a", []byte("4"), time.Second)
+	if value, _ := cache.Get("a"); string(value) != "4" || cache.Len() != 2 {
+		t.Errorf("value should be replaced, got %s", value)
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := cache.Get("a"); ok || cache.Len() != 1 {
+		t.Errorf("expired value should be removed, got %v entries", cache.Len())
+	}
+
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("value should not be expired")
+	}
+}
+
+func TestClientWithCache(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/athlete":    `{"id":1,"firstname":"Jane"}`,
+		"/api/v3/gear/b1234": `{"id":"b1234"}`,
+	})
+	WithCache(NewLRUCache(10), time.Minute)(client)
+
+	for i := 0; i < 2; i++ {
+		athlete, err := NewCurrentAthleteService(client).Get().Do()
+		if err != nil || athlete.FirstName != "Jane" {
+			t.Fatalf("incorrect athlete, got %v %v", athlete, err)
+		}
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("response should be cached, got %v requests", len(transport.requests))
+	}
+
+	// other urls, errors and other methods are not cached
+	NewGearService(client).Get("b1234").Do()
+	NewCurrentAthleteService(client).Update().City("Utrecht").Do()
+	NewCurrentAthleteService(client).Update().City("Utrecht").Do()
+	NewActivitiesService(client).Get(1).Do()
+	NewActivitiesService(client).Get(1).Do()
+
+	if len(transport.requests) != 6 {
+		t.Errorf("responses should not be cached, got %v requests", len(transport.requests))
+	}
+
+	if options := client.ClientOptionsSnapshot(); !options.Cache || options.CacheTTL != time.Minute {
+		t.Errorf("incorrect options, got %+v", options)
+	}
+}
+
+func TestClientWithCacheExpired(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{"/api/v3/athlete": `{"id":1}`})
+	WithCache(NewLRUCache(10), 0)(client)
+
+	NewCurrentAthleteService(client).Get().Do()
+	NewCurrentAthleteService(client).Get().Do()
+
+	if len(transport.requests) != 2 {
+		t.Errorf("expired responses should be requested again, got %v requests", len(transport.requests))
+	}
+}
+
+func TestClientWithCacheSharedPerAthlete(t *testing.T) {
+	cache := NewLRUCache(10)
+	newClient := func(accessToken, firstName string) (*Client, *routeTransport) {
+		client, transport := newRouteClient(map[string]string{"/api/v3/athlete": `{"id":1,"firstname":"` + firstName + `"}`})
+		client.tokenSource.(*stubTokenSource).response.AccessToken = accessToken
+		WithCache(cache, time.Minute)(client)
+		return client, transport
+	}
+
+	// neither token holds the athlete
+	jane, _ := newClient("jane-token", "Jane")
+	john, transport := newClient("john-token", "John")
+
+	NewCurrentAthleteService(jane).Get().Do()
+	athlete, err := NewCurrentAthleteService(john).Get().Do()
+	if err != nil || athlete.FirstName != "John" || len(transport.requests) != 1 {
+		t.Errorf("athletes should not share cached responses, got %v %v", athlete, err)
+	}
+
+	if key := storeKey(transport.requests[0], &AuthorizationResponse{}); key != "" {
+		t.Errorf("requests without athlete and token should not be stored, got %v", key)
+	}
+}
@@ -0,0 +1,76 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Call calls an endpoint of the api and decodes the response into a new T, like Client.Do,
+// e.g. for endpoints that have no call in this package:
+//
+//	zones, err := strava.Call[map[string]interface{}](ctx, client, "GET", "/athlete/zones", nil)
+func Call[T any](ctx context.Context, client *Client, method, path string, params map[string]interface{}) (*T, error) {
+	var v T
+	if err := client.Do(ctx, method, path, params, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// runAndDecode runs the request with the ErrorHandler, Response, timeout and header, see Client.runWithErrorHandler,
+// and decodes the response into a T. Errors are returned as is, for the call to wrap them.
+func runAndDecode[T any](client *Client, method, path string, params map[string]interface{}, errorHandler ErrorHandler, response *Response, timeout time.Duration, header http.Header) (T, error) {
+	return runAndDecodeContext[T](context.Background(), client, method, path, params, errorHandler, response, timeout, header)
+}
+
+// runAndDecodeContext is runAndDecode with a context, for calls made on behalf of a caller with one.
+func runAndDecodeContext[T any](ctx context.Context, client *Client, method, path string, params map[string]interface{}, errorHandler ErrorHandler, response *Response, timeout time.Duration, header http.Header) (T, error) {
+	var v T
+
+	ctx, cancel := client.contextWithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := client.newRequest(ctx, method, path, params)
+	if err != nil {
+		return v, err
+	}
+	addHeader(req, header)
+
+	if _, err := client.execute(req, errorHandler, response, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// callContext returns the context of a call with the timeout, if it is positive,
+// or the timeout of the client, see WithTimeout.
+func (client *Client) callContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return client.contextWithTimeout(context.Background(), timeout)
+}
+
+// contextWithTimeout returns ctx with the timeout of a call, see callContext.
+func (client *Client) contextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = client.timeout
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// addHeader adds the header of a call to the request. The client sets the authorization
+// and user agent of the request after, so the header can't override them.
+func addHeader(req *http.Request, header http.Header) {
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
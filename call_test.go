@@ -0,0 +1,92 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCallTimeout(t *testing.T) {
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: stallingTransport{}}
+
+	start := time.Now()
+	_, err := NewCurrentAthleteService(client).Get().Timeout(10 * time.Millisecond).Do()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("call should time out, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call should end at its timeout, took %v", elapsed)
+	}
+
+	err = NewActivitiesService(client).Delete(1).Timeout(10 * time.Millisecond).Do()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("call should time out, got %v", err)
+	}
+}
+
+func TestCallHeader(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{"/api/v3/athlete": `{"id":1}`, "/api/v3/activities/1": ``})
+
+	_, err := NewCurrentAthleteService(client).Get().
+		Header("X-Experiment", "a").
+		Header("X-Experiment", "b").
+		Header("Authorization", "Bearer other").
+		Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	header := transport.requests[0].Header
+	if values := header.Values("X-Experiment"); len(values) != 2 || values[0] != "a" || values[1] != "b" {
+		t.Errorf("request should have the header, got %v", values)
+	}
+
+	if auth := header.Values("Authorization"); len(auth) != 1 || auth[0] == "Bearer other" {
+		t.Errorf("header should not override the authorization, got %v", auth)
+	}
+
+	if err := NewActivitiesService(client).Delete(1).Header("X-Trace", "1").Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if v := transport.requests[1].Header.Get("X-Trace"); v != "1" {
+		t.Errorf("request should have the header, got %q", v)
+	}
+
+	if v := transport.requests[1].Header.Get("X-Experiment"); v != "" {
+		t.Errorf("headers should not leak between calls, got %q", v)
+	}
+}
+
+func TestClientWithTimeout(t *testing.T) {
+	client := NewClient(newStubTokenSource(), WithTimeout(10*time.Millisecond))
+	client.httpClient = &http.Client{Transport: stallingTransport{}}
+
+	_, err := NewCurrentAthleteService(client).Get().Do()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("call should time out, got %v", err)
+	}
+
+	if err := client.Do(context.Background(), "GET", "/athlete/zones", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("call should time out, got %v", err)
+	}
+
+	// the timeout of a call takes precedence
+	start := time.Now()
+	NewCurrentAthleteService(client).Get().Timeout(50 * time.Millisecond).Do()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("call should use its own timeout, took %v", elapsed)
+	}
+}
+
+// stallingTransport answers no request until it is cancelled.
+type stallingTransport struct{}
+
+func (stallingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
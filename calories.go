@@ -0,0 +1,90 @@
+package strava
+
+import (
+	"time"
+)
+
+// CalorieSource is where the calories of an EnergyEstimate come from.
+type CalorieSource string
+
+var CalorieSources = struct {
+	Strava    CalorieSource
+	Power     CalorieSource
+	Heartrate CalorieSource
+	None      CalorieSource
+}{"strava", "power", "heartrate", ""}
+
+// An EnergyProfile holds the details of the athlete needed for heart rate based calorie estimates.
+// The weight, in kilograms, can be taken from AthleteSettings.Weight. Strava doesn't return the age.
+type EnergyProfile struct {
+	Weight float64
+	Gender Gender
+	Age    int
+}
+
+// An EnergyEstimate holds the energy spent in an activity. Calories are only exact if the Source is
+// CalorieSources.Strava, all other values are estimates.
+type EnergyEstimate struct {
+	Calories   float64 // kilocalories
+	Kilojoules float64 // mechanical work, 0 if the activity has no power
+	Source     CalorieSource
+}
+
+// Estimated returns true if the calories are estimated rather than returned by Strava.
+func (e EnergyEstimate) Estimated() bool {
+	return e.Source != CalorieSources.Strava
+}
+
+// EstimateEnergy returns the calories of the activity, falling back to an estimate if Strava didn't
+// return them, e.g. for the activities of other athletes.
+//
+// With power, the kilojoules of work are used as calories, the efficiency of the human body of about 24%
+// about cancels out the 4.184 kilojoules per kilocalorie. Otherwise the calories are estimated from the
+// average heart rate, weight, age and gender using the formula of Keytel et al. (2005), averaging both genders
+// if unspecified. If neither is possible the Source is CalorieSources.None.
+func EstimateEnergy(activity *ActivityDetailed, profile EnergyProfile) EnergyEstimate {
+	estimate := EnergyEstimate{
+		Kilojoules: activity.Kilojoules,
+	}
+
+	duration := time.Duration(activity.MovingTime) * time.Second
+	if estimate.Kilojoules == 0 && activity.AveragePower > 0 {
+		estimate.Kilojoules = activity.AveragePower.Kilojoules(duration)
+	}
+
+	switch {
+	case activity.Calories > 0:
+		estimate.Calories = activity.Calories
+		estimate.Source = CalorieSources.Strava
+	case estimate.Kilojoules > 0:
+		estimate.Calories = estimate.Kilojoules
+		estimate.Source = CalorieSources.Power
+	case activity.AverageHeartrate > 0 && profile.Weight > 0 && profile.Age > 0:
+		estimate.Calories = heartrateCaloriesPerMinute(activity.AverageHeartrate, profile) * duration.Minutes()
+		estimate.Source = CalorieSources.Heartrate
+	}
+
+	if estimate.Calories < 0 {
+		// the formula isn't valid for very low heart rates
+		estimate.Calories = 0
+	}
+
+	return estimate
+}
+
+// heartrateCaloriesPerMinute returns the kilocalories per minute at the heart rate, according to Keytel et al.
+func heartrateCaloriesPerMinute(heartrate float64, profile EnergyProfile) float64 {
+	weight, age := profile.Weight, float64(profile.Age)
+
+	male := (-55.0969 + 0.6309*heartrate + 0.1988*weight + 0.2017*age) / 4.184
+	female := (-20.4022 + 0.4472*heartrate - 0.1263*weight + 0.074*age) / 4.184
+
+	switch profile.Gender {
+	case Genders.Male:
+		return male
+	case Genders.Female:
+		return female
+	}
+
+	return (male + female) / 2
+}
@@ -0,0 +1,47 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateEnergy(t *testing.T) {
+	profile := EnergyProfile{Weight: 70, Gender: Genders.Male, Age: 35}
+
+	activity := &ActivityDetailed{Calories: 500}
+	activity.MovingTime = 3600
+	activity.Kilojoules = 700
+
+	if e := EstimateEnergy(activity, profile); e.Calories != 500 || e.Kilojoules != 700 || e.Source != CalorieSources.Strava || e.Estimated() {
+		t.Errorf("incorrect strava energy, got %+v", e)
+	}
+
+	activity.Calories = 0
+	if e := EstimateEnergy(activity, profile); e.Calories != 700 || e.Source != CalorieSources.Power || !e.Estimated() {
+		t.Errorf("incorrect power energy, got %+v", e)
+	}
+
+	activity.Kilojoules = 0
+	activity.AveragePower = 200
+	if e := EstimateEnergy(activity, profile); e.Calories != 720 || e.Kilojoules != 720 {
+		t.Errorf("incorrect average power energy, got %+v", e)
+	}
+
+	activity.AveragePower = 0
+	activity.AverageHeartrate = 150
+	e := EstimateEnergy(activity, profile)
+	expected := (-55.0969 + 0.6309*150 + 0.1988*70 + 0.2017*35) / 4.184 * 60
+	if math.Abs(e.Calories-expected) > 1e-9 || e.Source != CalorieSources.Heartrate || e.Kilojoules != 0 {
+		t.Errorf("incorrect heart rate energy, got %+v", e)
+	}
+
+	profile.Gender = Genders.Female
+	if female := EstimateEnergy(activity, profile); female.Calories >= e.Calories {
+		t.Errorf("incorrect female estimate, got %+v", female)
+	}
+
+	profile.Age = 0
+	if e := EstimateEnergy(activity, profile); e.Calories != 0 || e.Source != CalorieSources.None {
+		t.Errorf("should not estimate without age, got %+v", e)
+	}
+}
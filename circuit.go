@@ -0,0 +1,83 @@
+package strava
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by the calls of a client created WithCircuitBreaker, without sending the request,
+// while Strava keeps failing.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// WithCircuitBreaker makes the client stop sending requests after failures requests failed in a row, without
+// a response or with a 5xx response, and fail its calls with ErrCircuitOpen instead. After the cooldown a single
+// request is sent to try whether Strava recovered, the circuit closes again when it succeeds. Requests cancelled
+// by their context don't count. Copies made with Client.With share the circuit. Zero failures, the default, turns it off.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = nil
+		if failures > 0 {
+			c.breaker = &circuitBreaker{threshold: failures, cooldown: cooldown}
+		}
+	}
+}
+
+// circuitBreaker counts the failed requests of a client in a row.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	lock     sync.Mutex
+	failures int       // in a row
+	until    time.Time // end of the cooldown, once the circuit opened
+	trying   bool      // a request is sent to try whether Strava recovered
+}
+
+// allow returns ErrCircuitOpen if the circuit is open, during the cooldown or while a request is trying,
+// otherwise the request is sent and its outcome must be passed to done.
+func (b *circuitBreaker) allow(now time.Time) error {
+	if b == nil {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if b.trying || now.Before(b.until) {
+		return ErrCircuitOpen
+	}
+
+	b.trying = true
+	return nil
+}
+
+// done records the outcome of a request that was allowed, opening the circuit once enough requests failed in a row.
+// A cancelled request is no outcome, a request trying whether Strava recovered leaves the circuit open for the next.
+func (b *circuitBreaker) done(failed, cancelled bool, now time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.trying = false
+	if cancelled {
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.until = now.Add(b.cooldown)
+	}
+}
@@ -0,0 +1,111 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientWithCircuitBreaker(t *testing.T) {
+	requests := 0
+	status := http.StatusInternalServerError
+
+	clock := newFakeClock()
+	client := NewClient(newStubTokenSource(), WithClock(clock), WithCircuitBreaker(2, time.Minute))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(`{"id":1}`)), Request: req}, nil
+	})}
+
+	for i := 0; i < 2; i++ {
+		if _, err := NewClubsService(client).Get(1).Do(); !errors.Is(err, ErrServerError) {
+			t.Fatalf("failures should be returned, got %v", err)
+		}
+	}
+
+	// the circuit opened, requests are not sent
+	_, err := NewClubsService(client).Get(1).Do()
+	if !errors.Is(err, ErrCircuitOpen) || requests != 2 {
+		t.Fatalf("circuit should be open, got %v after %d requests", err, requests)
+	}
+
+	// after the cooldown a request tries, and fails again
+	clock.Sleep(time.Minute)
+	NewClubsService(client).Get(1).Do()
+	if _, err := NewClubsService(client).Get(1).Do(); !errors.Is(err, ErrCircuitOpen) || requests != 3 {
+		t.Fatalf("failed try should open the circuit again, got %v after %d requests", err, requests)
+	}
+
+	// a successful try closes the circuit
+	clock.Sleep(time.Minute)
+	status = http.StatusOK
+	for i := 0; i < 2; i++ {
+		if _, err := NewClubsService(client).Get(1).Do(); err != nil {
+			t.Fatalf("circuit should be closed, got %v", err)
+		}
+	}
+
+	if requests != 5 {
+		t.Errorf("requests should be sent again, got %d", requests)
+	}
+
+	// client errors are not failures of Strava
+	status = http.StatusNotFound
+	for i := 0; i < 3; i++ {
+		NewClubsService(client).Get(1).Do()
+	}
+
+	if _, err := NewClubsService(client).Get(1).Do(); errors.Is(err, ErrCircuitOpen) {
+		t.Error("client errors should not open the circuit")
+	}
+
+	if !client.ClientOptionsSnapshot().CircuitBreaker || NewClient(nil).ClientOptionsSnapshot().CircuitBreaker {
+		t.Error("circuit breaker should only be on when enabled")
+	}
+}
+
+func TestClientWithCircuitBreakerCancelled(t *testing.T) {
+	requests := 0
+	var cancelRequest context.CancelFunc
+
+	clock := newFakeClock()
+	client := NewClient(newStubTokenSource(), WithClock(clock), WithCircuitBreaker(2, time.Minute))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if cancelRequest != nil {
+			cancelRequest()
+			return nil, req.Context().Err()
+		}
+		return &http.Response{StatusCode: http.StatusInternalServerError, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(`{}`)), Request: req}, nil
+	})}
+
+	// doCancelled sends a request cancelled before its response
+	doCancelled := func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cancelRequest = cancel
+		client.Do(ctx, "GET", "/clubs/1", nil, nil)
+		cancelRequest = nil
+	}
+
+	// a cancelled request between failures doesn't reset them
+	client.Do(context.Background(), "GET", "/clubs/1", nil, nil)
+	doCancelled()
+	client.Do(context.Background(), "GET", "/clubs/1", nil, nil)
+	if err := client.Do(context.Background(), "GET", "/clubs/1", nil, nil); !errors.Is(err, ErrCircuitOpen) || requests != 3 {
+		t.Fatalf("circuit should be open, got %v after %d requests", err, requests)
+	}
+
+	// a cancelled try doesn't close the circuit, the next request tries again
+	clock.Sleep(time.Minute)
+	doCancelled()
+	client.Do(context.Background(), "GET", "/clubs/1", nil, nil)
+	if err := client.Do(context.Background(), "GET", "/clubs/1", nil, nil); !errors.Is(err, ErrCircuitOpen) || requests != 5 {
+		t.Fatalf("cancelled try should leave the circuit open, got %v after %d requests", err, requests)
+	}
+}
@@ -0,0 +1,129 @@
+package strava
+
+import (
+	"time"
+)
+
+// maxAdjustedGrade is the steepest grade, as a fraction, the grade adjustment is defined for.
+// Steeper grades are treated as this grade.
+const maxAdjustedGrade = 0.45
+
+// GradeAdjustedSpeed returns the speed on flat ground that takes the same effort as running
+// at speed on a grade, in percent. Uphill the adjusted speed is higher, downhill lower.
+//
+// The adjustment uses the energy cost of running on a grade measured by Minetti et al. (2002),
+// which Strava's original grade adjusted pace was based on. Strava's current heart rate based
+// model isn't published, so results may differ slightly from the values on strava.com.
+func GradeAdjustedSpeed(speed Speed, grade float64) Speed {
+	return Speed(float64(speed) * runningCost(grade/100) / runningCost(0))
+}
+
+// runningCost returns the energy cost of running, in J/kg/m, on a grade given as a fraction.
+func runningCost(i float64) float64 {
+	if i > maxAdjustedGrade {
+		i = maxAdjustedGrade
+	}
+
+	if i < -maxAdjustedGrade {
+		i = -maxAdjustedGrade
+	}
+
+	return ((((155.4*i-30.4)*i-43.3)*i+46.3)*i+19.5)*i + 3.6
+}
+
+// GradeAdjustedSpeeds returns the grade adjusted speed of every sample.
+// Requires the Speed and Grade streams, returns nil otherwise.
+func (s *StreamSet) GradeAdjustedSpeeds() []Speed {
+	if s.Speed == nil || s.Grade == nil {
+		return nil
+	}
+
+	n := len(s.Speed.Data)
+	if len(s.Grade.Data) < n {
+		n = len(s.Grade.Data)
+	}
+
+	speeds := make([]Speed, n)
+	for i := 0; i < n; i++ {
+		speeds[i] = GradeAdjustedSpeed(Speed(s.Speed.Data[i]), s.Grade.Data[i])
+	}
+
+	return speeds
+}
+
+// AverageGradeAdjustedSpeed returns the grade adjusted speed averaged over the time the athlete was moving,
+// the pace of which is the grade adjusted pace of the activity. Gaps longer than threshold are left out,
+// if threshold is 0 DefaultGapThreshold is used. Requires the Time, Speed and Grade streams.
+func (s *StreamSet) AverageGradeAdjustedSpeed(threshold time.Duration) Speed {
+	speeds := s.GradeAdjustedSpeeds()
+	if speeds == nil {
+		return 0
+	}
+
+	var total float64
+	var seconds int
+	s.eachMoving(threshold, func(i int) {
+		if i < len(speeds) {
+			dt := s.Time.Data[i] - s.Time.Data[i-1]
+			total += float64(speeds[i]) * float64(dt)
+			seconds += dt
+		}
+	})
+
+	if seconds == 0 {
+		return 0
+	}
+
+	return Speed(total / float64(seconds))
+}
+
+/*********************************************************/
+
+// climbScores are the minimum scores, distance in meters times the average grade in percent,
+// of the climb categories as used by Strava, from hors categorie down to category 4.
+var climbScores = []struct {
+	score    float64
+	category ClimbCategory
+}{
+	{80000, ClimbCategories.HorsCategorie},
+	{64000, ClimbCategories.Category1},
+	{32000, ClimbCategories.Category2},
+	{16000, ClimbCategories.Category3},
+	{8000, ClimbCategories.Category4},
+}
+
+// ClimbCategoryFor categorizes a climb by its distance and average grade in percent,
+// the way Strava categorizes segments. The climb is scored by multiplying the distance in meters
+// by the grade, a score of 8000 is category 4 and the score doubles for every category up to 64000
+// for category 1. Climbs scoring 80000 or more are hors categorie.
+func ClimbCategoryFor(distance Distance, averageGrade float64) ClimbCategory {
+	score := distance.Meters() * averageGrade
+	for _, c := range climbScores {
+		if score >= c.score {
+			return c.category
+		}
+	}
+
+	return ClimbCategories.NotCategorized
+}
+
+// ClimbCategory categorizes the part of the activity in the interval, using the difference
+// in the Distance and Elevation streams between its first and last sample.
+// Requires the Distance and Elevation streams.
+func (s *StreamSet) ClimbCategory(interval Interval) ClimbCategory {
+	if s.Distance == nil || s.Elevation == nil {
+		return ClimbCategories.NotCategorized
+	}
+
+	if interval.StartIndex < 0 || interval.EndIndex >= len(s.Distance.Data) || interval.EndIndex >= len(s.Elevation.Data) {
+		return ClimbCategories.NotCategorized
+	}
+
+	distance := s.Distance.Data[interval.EndIndex] - s.Distance.Data[interval.StartIndex]
+	elevation := s.Elevation.Data[interval.EndIndex] - s.Elevation.Data[interval.StartIndex]
+	if distance <= 0 {
+		return ClimbCategories.NotCategorized
+	}
+
+	return ClimbCategoryFor(Distance(distance), elevation/distance*100)
+}
@@ -0,0 +1,98 @@
+package strava
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGradeAdjustedSpeed(t *testing.T) {
+	if s := GradeAdjustedSpeed(3, 0); s != 3 {
+		t.Errorf("flat ground should not be adjusted, got %v", s)
+	}
+
+	up := GradeAdjustedSpeed(3, 10)
+	down := GradeAdjustedSpeed(3, -10)
+	if up <= 3 || down >= 3 {
+		t.Errorf("incorrect adjustment, got %v up and %v down", up, down)
+	}
+
+	// 10% uphill is about two thirds more effort
+	if math.Abs(float64(up)/3-1.66) > 0.01 {
+		t.Errorf("incorrect uphill adjustment, got %v", up)
+	}
+
+	// grades are capped
+	if GradeAdjustedSpeed(3, 100) != GradeAdjustedSpeed(3, 45) {
+		t.Error("grade should be capped")
+	}
+}
+
+func TestStreamSetGradeAdjustedSpeed(t *testing.T) {
+	s := &StreamSet{
+		Time:  &IntegerStream{Data: []int{0, 10, 20, 30}},
+		Speed: &DecimalStream{Data: []float64{3, 3, 3, 3}},
+		Grade: &DecimalStream{Data: []float64{0, 0, 10, 10}},
+	}
+
+	speeds := s.GradeAdjustedSpeeds()
+	if len(speeds) != 4 || speeds[1] != 3 || speeds[2] != GradeAdjustedSpeed(3, 10) {
+		t.Errorf("incorrect speeds, got %v", speeds)
+	}
+
+	expected := (3 + 2*GradeAdjustedSpeed(3, 10)) / 3
+	if a := s.AverageGradeAdjustedSpeed(0); math.Abs(float64(a-expected)) > 1e-9 {
+		t.Errorf("incorrect average, got %v, expected %v", a, expected)
+	}
+
+	// gaps are left out
+	if a := s.AverageGradeAdjustedSpeed(5 * time.Second); a != 0 {
+		t.Errorf("incorrect average, got %v", a)
+	}
+
+	s.Grade = nil
+	if s.GradeAdjustedSpeeds() != nil || s.AverageGradeAdjustedSpeed(0) != 0 {
+		t.Error("should require grade stream")
+	}
+}
+
+func TestClimbCategoryFor(t *testing.T) {
+	cases := []struct {
+		distance Distance
+		grade    float64
+		category ClimbCategory
+	}{
+		{1000, 5, ClimbCategories.NotCategorized},
+		{2000, 4, ClimbCategories.Category4},
+		{4000, 4, ClimbCategories.Category3},
+		{8000, 4, ClimbCategories.Category2},
+		{10000, 7, ClimbCategories.Category1},
+		{20000, 7, ClimbCategories.HorsCategorie},
+		{20000, -7, ClimbCategories.NotCategorized},
+	}
+
+	for _, c := range cases {
+		if category := ClimbCategoryFor(c.distance, c.grade); category != c.category {
+			t.Errorf("incorrect category for %v at %v%%, got %v", c.distance, c.grade, category)
+		}
+	}
+}
+
+func TestStreamSetClimbCategory(t *testing.T) {
+	s := &StreamSet{
+		Distance:  &DecimalStream{Data: []float64{0, 1000, 5000}},
+		Elevation: &DecimalStream{Data: []float64{100, 110, 450}},
+	}
+
+	if c := s.ClimbCategory(Interval{StartIndex: 0, EndIndex: 2}); c != ClimbCategories.Category2 {
+		t.Errorf("incorrect category, got %v", c)
+	}
+
+	if c := s.ClimbCategory(Interval{StartIndex: 0, EndIndex: 1}); c != ClimbCategories.NotCategorized {
+		t.Errorf("incorrect category, got %v", c)
+	}
+
+	if c := s.ClimbCategory(Interval{StartIndex: 0, EndIndex: 3}); c != ClimbCategories.NotCategorized {
+		t.Errorf("out of range interval should not be categorized, got %v", c)
+	}
+}
@@ -0,0 +1,33 @@
+package strava
+
+import "time"
+
+// A Clock tells the time and waits for the rate limiting and token expiry of a client, see WithClock.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// WithClock makes the client tell the time with the clock, e.g. a stravatest.Clock, so tests and simulations
+// control when tokens expire and the request delays of WithRequestDelay pass without really waiting.
+// The durations of calls, see CallTiming, are still measured in real time. Nil restores the system clock.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		if clock == nil {
+			clock = systemClock{}
+		}
+
+		c.clock = clock
+	}
+}
+
+// systemClock is the real time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
@@ -0,0 +1,66 @@
+package strava
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock starts at the current time and only moves when it sleeps.
+type fakeClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Now()}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+}
+
+func TestClientWithClock(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/athlete":     `{"id":1}`,
+		"/api/v3/oauth/token": `{"access_token":"refreshed","refresh_token":"refresh","expires_at":0}`,
+	})
+
+	clock := newFakeClock()
+	WithClock(clock)(client)
+
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("token should not be refreshed, got %d requests", len(transport.requests))
+	}
+
+	// the token expires within an hour
+	clock.Sleep(2 * time.Hour)
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 3 || transport.requests[1].URL.Path != "/api/v3/oauth/token" {
+		t.Errorf("token should be refreshed, got %d requests", len(transport.requests))
+	}
+
+	WithClock(nil)(client)
+	if _, ok := client.clock.(systemClock); !ok {
+		t.Error("nil should restore the system clock")
+	}
+}
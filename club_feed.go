@@ -0,0 +1,176 @@
+package strava
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A ClubFeed merges the activity feeds of several clubs, e.g. for a dashboard of an athlete in multiple clubs.
+// The athlete's own activities show up in the feed of every club they are a member of,
+// they are only included once in the merged feed, like the activities of other athletes listed
+// with their id in several clubs.
+type ClubFeed struct {
+	service      *ClubsService
+	clubIds      []int64
+	athlete      *AthleteSummary
+	perPage      int
+	errorHandler ErrorHandler
+}
+
+// A ClubFeedActivity is an activity of the merged feed with the clubs it was listed in.
+type ClubFeedActivity struct {
+	*ActivitySummary
+	ClubIds []int64
+	Own     bool // if the activity is of the athlete set with ClubFeed.Athlete
+}
+
+// ClubComparison holds the totals of the feed of one club, to compare clubs with each other.
+// AthleteCount counts the distinct athletes in the feed, not the members of the club.
+type ClubComparison struct {
+	ClubId       int64
+	Totals       AthleteTotals
+	AthleteCount int
+	Own          AthleteTotals // totals of the athlete's own activities in the feed
+	OwnRank      int           // by distance among the athletes in the feed, 0 if the athlete has no activities in it
+}
+
+// OwnShare returns the fraction of the distance of the club feed that is covered by the athlete.
+func (c *ClubComparison) OwnShare() float64 {
+	if c.Totals.Distance == 0 {
+		return 0
+	}
+
+	return float64(c.Own.Distance / c.Totals.Distance)
+}
+
+// A ClubFeedResult is the merged feed, most recent activities first, and a comparison per club
+// in the order the clubs were given.
+type ClubFeedResult struct {
+	Activities  []*ClubFeedActivity
+	Comparisons []*ClubComparison
+}
+
+// NewClubFeed creates a feed merging the most recent activities of the clubs.
+func NewClubFeed(client *Client, clubIds ...int64) *ClubFeed {
+	return &ClubFeed{
+		service: NewClubsService(client),
+		clubIds: clubIds,
+		perPage: 200,
+	}
+}
+
+// Athlete sets the athlete whose own activities are deduplicated and compared, usually the authenticated athlete.
+// Club feeds often leave out the athlete id, so activities are matched on the name of the athlete if it is missing.
+func (f *ClubFeed) Athlete(athlete *AthleteSummary) *ClubFeed {
+	f.athlete = athlete
+	return f
+}
+
+// PerPage sets the number of activities fetched per club, defaults to 200, the maximum allowed by Strava.
+func (f *ClubFeed) PerPage(perPage int) *ClubFeed {
+	f.perPage = perPage
+	return f
+}
+
+func (f *ClubFeed) OnError(handler ErrorHandler) *ClubFeed {
+	f.errorHandler = handler
+	return f
+}
+
+func (f *ClubFeed) Do() (*ClubFeedResult, error) {
+	result := &ClubFeedResult{}
+	merged := make(map[string]*ClubFeedActivity)
+
+	for _, clubId := range f.clubIds {
+		activities, err := f.service.ListActivities(clubId).
+			PerPage(f.perPage).
+			OnError(f.errorHandler).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+
+		comparison := &ClubComparison{ClubId: clubId}
+		perAthlete := make(map[string]Distance)
+
+		for _, a := range activities {
+			own := f.isOwn(a)
+			addClubFeedTotals(&comparison.Totals, a)
+			if own {
+				addClubFeedTotals(&comparison.Own, a)
+			}
+
+			athleteKey := clubFeedAthleteKey(&a.Athlete)
+			perAthlete[athleteKey] += a.Distance
+
+			// activities of others without an id may be different activities alike
+			key := clubFeedActivityKey(a)
+			if m, ok := merged[key]; ok && (own || a.Id != 0) {
+				m.ClubIds = append(m.ClubIds, clubId)
+				continue
+			}
+
+			m := &ClubFeedActivity{ActivitySummary: a, ClubIds: []int64{clubId}, Own: own}
+			merged[key] = m
+			result.Activities = append(result.Activities, m)
+		}
+
+		comparison.AthleteCount = len(perAthlete)
+		if comparison.Own.Count > 0 {
+			comparison.OwnRank = 1
+			for _, d := range perAthlete {
+				if d > comparison.Own.Distance {
+					comparison.OwnRank++
+				}
+			}
+		}
+
+		result.Comparisons = append(result.Comparisons, comparison)
+	}
+
+	sort.SliceStable(result.Activities, func(i, j int) bool {
+		return result.Activities[i].StartDate.After(result.Activities[j].StartDate)
+	})
+
+	return result, nil
+}
+
+func (f *ClubFeed) isOwn(a *ActivitySummary) bool {
+	if f.athlete == nil {
+		return false
+	}
+
+	if f.athlete.Id != 0 && a.Athlete.Id != 0 {
+		return f.athlete.Id == a.Athlete.Id
+	}
+
+	// club feeds abbreviate the last name to its initial
+	return a.Athlete.FirstName == f.athlete.FirstName &&
+		len(f.athlete.LastName) > 0 && len(a.Athlete.LastName) > 0 &&
+		a.Athlete.LastName[0] == f.athlete.LastName[0]
+}
+
+func addClubFeedTotals(totals *AthleteTotals, a *ActivitySummary) {
+	totals.Count++
+	totals.Distance += a.Distance
+	totals.MovingTime += a.MovingTime
+	totals.ElapsedTime += a.ElapsedTime
+	totals.ElevationGain += a.TotalElevationGain
+}
+
+func clubFeedAthleteKey(a *AthleteSummary) string {
+	if a.Id != 0 {
+		return fmt.Sprintf("%d", a.Id)
+	}
+
+	return a.FirstName + " " + a.LastName
+}
+
+// clubFeedActivityKey identifies an activity across feeds, club feeds often leave out the activity id.
+func clubFeedActivityKey(a *ActivitySummary) string {
+	if a.Id != 0 {
+		return fmt.Sprintf("%d", a.Id)
+	}
+
+	return fmt.Sprintf("%s|%s|%s|%.1f|%d", clubFeedAthleteKey(&a.Athlete), a.Name, a.Type, float64(a.Distance), a.ElapsedTime)
+}
@@ -0,0 +1,75 @@
+package strava
+
+import (
+	"testing"
+)
+
+func TestClubFeed(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/clubs/1/activities": `[
+			{"athlete":{"firstname":"Jane","lastname":"D."},"name":"Morning Ride","type":"Ride","distance":30000,"elapsed_time":3600,"start_date":"2024-05-02T08:00:00Z"},
+			{"athlete":{"firstname":"John","lastname":"S."},"name":"Lunch Ride","type":"Ride","distance":20000,"elapsed_time":2400,"start_date":"2024-05-01T12:00:00Z"},
+			{"athlete":{"firstname":"Anna","lastname":"K."},"name":"Long Ride","type":"Ride","distance":80000,"elapsed_time":10800,"start_date":"2024-04-30T08:00:00Z"}
+		]`,
+		"/api/v3/clubs/2/activities": `[
+			{"athlete":{"firstname":"Jane","lastname":"D."},"name":"Morning Ride","type":"Ride","distance":30000,"elapsed_time":3600,"start_date":"2024-05-02T08:00:00Z"},
+			{"athlete":{"firstname":"Piet","lastname":"J."},"name":"Evening Run","type":"Run","distance":10000,"elapsed_time":3000,"start_date":"2024-05-03T18:00:00Z"}
+		]`,
+		"/api/v3/clubs/4/activities": `[
+			{"id":7,"athlete":{"id":3,"firstname":"Piet","lastname":"J."},"name":"Evening Run","type":"Run","distance":10000,"elapsed_time":3000,"start_date":"2024-05-03T18:00:00Z"}
+		]`,
+		"/api/v3/clubs/5/activities": `[
+			{"id":7,"athlete":{"id":3,"firstname":"Piet","lastname":"J."},"name":"Evening Run","type":"Run","distance":10000,"elapsed_time":3000,"start_date":"2024-05-03T18:00:00Z"}
+		]`,
+	})
+
+	athlete := &AthleteSummary{FirstName: "Jane", LastName: "Doe"}
+	result, err := NewClubFeed(client, 1, 2).Athlete(athlete).Do()
+	if err != nil {
+		t.Fatalf("feed error: %v", err)
+	}
+
+	if len(result.Activities) != 4 {
+		t.Fatalf("incorrect number of activities, got %v", len(result.Activities))
+	}
+
+	if a := result.Activities[0]; a.Name != "Evening Run" || len(a.ClubIds) != 1 || a.ClubIds[0] != 2 {
+		t.Errorf("incorrect first activity, got %v", a)
+	}
+
+	if a := result.Activities[1]; a.Name != "Morning Ride" || !a.Own || len(a.ClubIds) != 2 {
+		t.Errorf("own activity not deduplicated, got %v", a)
+	}
+
+	if len(result.Comparisons) != 2 {
+		t.Fatalf("incorrect number of comparisons, got %v", len(result.Comparisons))
+	}
+
+	c := result.Comparisons[0]
+	if c.ClubId != 1 || c.Totals.Count != 3 || c.Totals.Distance != 130000 || c.AthleteCount != 3 {
+		t.Errorf("incorrect comparison, got %v", c)
+	}
+
+	if c.Own.Count != 1 || c.OwnRank != 2 {
+		t.Errorf("incorrect own totals, got %v rank %v", c.Own, c.OwnRank)
+	}
+
+	if s := result.Comparisons[1].OwnShare(); s != 0.75 {
+		t.Errorf("incorrect own share, got %v", s)
+	}
+
+	// activities of others are deduplicated by id
+	result, err = NewClubFeed(client, 4, 5).Athlete(athlete).Do()
+	if err != nil {
+		t.Fatalf("feed error: %v", err)
+	}
+
+	if len(result.Activities) != 1 || len(result.Activities[0].ClubIds) != 2 || result.Activities[0].Own {
+		t.Errorf("activity of another athlete not deduplicated, got %v", result.Activities)
+	}
+
+	// errors of any club are returned
+	if _, err := NewClubFeed(client, 1, 3).Do(); err == nil {
+		t.Error("should return error")
+	}
+}
@@ -0,0 +1,145 @@
+package strava
+
+import (
+	"sort"
+	"sync"
+)
+
+// ClubMemberEventType is the kind of change in a club roster found by ClubSync.
+type ClubMemberEventType string
+
+var ClubMemberEventTypes = struct {
+	Joined ClubMemberEventType
+	Left   ClubMemberEventType
+}{"joined", "left"}
+
+// A ClubMemberEvent is a member that joined or left the club since the previous sync.
+type ClubMemberEvent struct {
+	Type    ClubMemberEventType
+	Athlete *AthleteSummary
+}
+
+// ClubSync maintains a local copy of the members of a club and reports who joined and left between syncs,
+// e.g. for a bot welcoming new members. Strava has no webhooks for club membership, so Sync should be called
+// periodically. To detect changes across restarts, save the Members and pass them to Restore on start.
+// Safe for concurrent use.
+type ClubSync struct {
+	service      *ClubsService
+	clubId       int64
+	perPage      int
+	errorHandler ErrorHandler
+
+	lock    sync.Mutex
+	members map[int64]*AthleteSummary
+}
+
+// NewClubSync creates a sync of the club members. The first Sync reports all members as joined,
+// unless a previous roster was restored.
+func NewClubSync(client *Client, clubId int64) *ClubSync {
+	return &ClubSync{
+		service: NewClubsService(client),
+		clubId:  clubId,
+		perPage: 200,
+		members: make(map[int64]*AthleteSummary),
+	}
+}
+
+// PerPage sets the page size used to list the members, defaults to 200, the maximum allowed by Strava.
+func (s *ClubSync) PerPage(perPage int) *ClubSync {
+	s.perPage = perPage
+	return s
+}
+
+func (s *ClubSync) OnError(handler ErrorHandler) *ClubSync {
+	s.errorHandler = handler
+	return s
+}
+
+// Restore sets the roster of a previous run, so the next Sync only reports the changes since then.
+func (s *ClubSync) Restore(members []*AthleteSummary) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.members = make(map[int64]*AthleteSummary, len(members))
+	for _, m := range members {
+		s.members[m.Id] = m
+	}
+}
+
+// Members returns the roster as of the last sync, ordered by athlete id.
+func (s *ClubSync) Members() []*AthleteSummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	members := make([]*AthleteSummary, 0, len(s.members))
+	for _, m := range s.members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Id < members[j].Id })
+
+	return members
+}
+
+// Sync lists all members of the club and returns who joined and left since the previous sync,
+// joined members first, each ordered by athlete id. If listing fails the roster is left unchanged,
+// so the changes are reported by the next successful sync.
+func (s *ClubSync) Sync() ([]*ClubMemberEvent, error) {
+	current, err := s.listMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var joined, left []*ClubMemberEvent
+	for id, m := range current {
+		if _, ok := s.members[id]; !ok {
+			joined = append(joined, &ClubMemberEvent{ClubMemberEventTypes.Joined, m})
+		}
+	}
+
+	for id, m := range s.members {
+		if _, ok := current[id]; !ok {
+			left = append(left, &ClubMemberEvent{ClubMemberEventTypes.Left, m})
+		}
+	}
+
+	sortClubMemberEvents(joined)
+	sortClubMemberEvents(left)
+
+	s.members = current
+
+	return append(joined, left...), nil
+}
+
+func (s *ClubSync) listMembers() (map[int64]*AthleteSummary, error) {
+	members := make(map[int64]*AthleteSummary)
+	for page := 1; ; page++ {
+		list, err := s.service.ListMembers(s.clubId).
+			Page(page).
+			PerPage(s.perPage).
+			OnError(s.errorHandler).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, m := range list {
+			if _, ok := members[m.Id]; !ok {
+				added++
+			}
+			members[m.Id] = m
+		}
+
+		// a page without new members means the end was passed, even if it was full
+		if len(list) < s.perPage || added == 0 {
+			return members, nil
+		}
+	}
+}
+
+func sortClubMemberEvents(events []*ClubMemberEvent) {
+	sort.Slice(events, func(i, j int) bool { return events[i].Athlete.Id < events[j].Athlete.Id })
+}
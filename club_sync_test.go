@@ -0,0 +1,77 @@
+package strava
+
+import (
+	"testing"
+)
+
+func TestClubSync(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/clubs/1/members": `[{"id":1,"firstname":"a"},{"id":2,"firstname":"b"}]`,
+	})
+
+	s := NewClubSync(client, 1)
+
+	events, err := s.Sync()
+	if err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+
+	if len(events) != 2 || events[0].Type != ClubMemberEventTypes.Joined || events[0].Athlete.Id != 1 || events[1].Athlete.Id != 2 {
+		t.Errorf("incorrect events, got %v", events)
+	}
+
+	if q := transport.requests[0].URL.Query(); q.Get("page") != "1" || q.Get("per_page") != "200" {
+		t.Errorf("incorrect query, got %v", q)
+	}
+
+	// one left, one joined
+	transport.routes["/api/v3/clubs/1/members"] = `[{"id":2,"firstname":"b"},{"id":3,"firstname":"c"}]`
+
+	events, err = s.Sync()
+	if err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+
+	if len(events) != 2 ||
+		events[0].Type != ClubMemberEventTypes.Joined || events[0].Athlete.Id != 3 ||
+		events[1].Type != ClubMemberEventTypes.Left || events[1].Athlete.Id != 1 {
+		t.Errorf("incorrect events, got %v", events)
+	}
+
+	if members := s.Members(); len(members) != 2 || members[0].Id != 2 || members[1].Id != 3 {
+		t.Errorf("incorrect members, got %v", members)
+	}
+
+	// failed syncs leave the roster unchanged
+	delete(transport.routes, "/api/v3/clubs/1/members")
+	if _, err := s.Sync(); err == nil {
+		t.Error("should return error")
+	}
+
+	if members := s.Members(); len(members) != 2 {
+		t.Errorf("incorrect members, got %v", members)
+	}
+}
+
+func TestClubSyncRestore(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/clubs/1/members": `[{"id":1},{"id":2}]`,
+	})
+
+	s := NewClubSync(client, 1).PerPage(2)
+	s.Restore([]*AthleteSummary{{AthleteMeta: AthleteMeta{Id: 1}}})
+
+	events, err := s.Sync()
+	if err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+
+	// a full page is followed by the next page, which returns the same members here
+	if len(transport.requests) != 2 {
+		t.Errorf("incorrect number of requests, got %v", len(transport.requests))
+	}
+
+	if len(events) != 1 || events[0].Type != ClubMemberEventTypes.Joined || events[0].Athlete.Id != 2 {
+		t.Errorf("incorrect events, got %v", events)
+	}
+}
@@ -1,8 +1,9 @@
 package strava
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 type ClubDetailed struct {
@@ -18,7 +19,8 @@ type ClubDetailed struct {
 }
 
 type ClubSummary struct {
-	Id            int64  `json:"id"`
+	RawJSON
+	Id            int64  `json:"id" strava:"required"`
 	Name          string `json:"name"`
 	ProfileMedium string `json:"profile_medium"` // URL to a 62x62 pixel profile picture
 	Profile       string `json:"profile"`        // URL to a 124x124 pixel profile picture
@@ -54,8 +56,12 @@ func NewClubsService(client *Client) *ClubsService {
 /*********************************************************/
 
 type ClubsGetCall struct {
-	service *ClubsService
-	id      int64
+	service      *ClubsService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ClubsService) Get(clubId int64) *ClubsGetCall {
@@ -65,27 +71,48 @@ func (s *ClubsService) Get(clubId int64) *ClubsGetCall {
 	}
 }
 
-func (c *ClubsGetCall) Do() (*ClubDetailed, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/clubs/%d", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *ClubsGetCall) OnError(handler ErrorHandler) *ClubsGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ClubsGetCall) Response(resp *Response) *ClubsGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ClubsGetCall) Timeout(d time.Duration) *ClubsGetCall {
+	c.timeout = d
+	return c
+}
 
-	var club ClubDetailed
-	err = json.Unmarshal(data, &club)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ClubsGetCall) Header(key, value string) *ClubsGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &club, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ClubsGetCall) Do() (*ClubDetailed, error) {
+	club, err := runAndDecode[*ClubDetailed](c.service.client, "GET", fmt.Sprintf("/clubs/%d", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return club, wrapError(err, "clubs.get id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ClubListMembersCall struct {
-	service *ClubsService
-	id      int64
-	ops     map[string]interface{}
+	service      *ClubsService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ClubsService) ListMembers(clubId int64) *ClubListMembersCall {
@@ -106,27 +133,49 @@ func (c *ClubListMembersCall) PerPage(perPage int) *ClubListMembersCall {
 	return c
 }
 
-func (c *ClubListMembersCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/clubs/%d/members", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *ClubListMembersCall) OnError(handler ErrorHandler) *ClubListMembersCall {
+	c.errorHandler = handler
+	return c
+}
 
-	members := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &members)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ClubListMembersCall) Response(resp *Response) *ClubListMembersCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ClubListMembersCall) Timeout(d time.Duration) *ClubListMembersCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ClubListMembersCall) Header(key, value string) *ClubListMembersCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return members, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ClubListMembersCall) Do() ([]*AthleteSummary, error) {
+	members, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", fmt.Sprintf("/clubs/%d/members", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return members, wrapError(err, "clubs.list_members id=%d", c.id)
 }
 
 /*********************************************************/
 
 type ClubListActivitiesCall struct {
-	service *ClubsService
-	id      int64
-	ops     map[string]interface{}
+	service      *ClubsService
+	id           int64
+	ops          map[string]interface{}
+	filters      []ActivityFilter
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ClubsService) ListActivities(clubId int64) *ClubListActivitiesCall {
@@ -147,17 +196,41 @@ func (c *ClubListActivitiesCall) PerPage(perPage int) *ClubListActivitiesCall {
 	return c
 }
 
-func (c *ClubListActivitiesCall) Do() ([]*ActivitySummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/clubs/%d/activities", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+// Where only returns the activities of the page selected by all filters, see ActivityFilter.
+// Pages are requested as usual, so they may hold fewer activities than requested.
+func (c *ClubListActivitiesCall) Where(filters ...ActivityFilter) *ClubListActivitiesCall {
+	c.filters = append(c.filters, filters...)
+	return c
+}
+
+func (c *ClubListActivitiesCall) OnError(handler ErrorHandler) *ClubListActivitiesCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ClubListActivitiesCall) Response(resp *Response) *ClubListActivitiesCall {
+	c.response = resp
+	return c
+}
 
-	activities := make([]*ActivitySummary, 0)
-	err = json.Unmarshal(data, &activities)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ClubListActivitiesCall) Timeout(d time.Duration) *ClubListActivitiesCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ClubListActivitiesCall) Header(key, value string) *ClubListActivitiesCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return activities, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ClubListActivitiesCall) Do() ([]*ActivitySummary, error) {
+	activities, err := runAndDecode[[]*ActivitySummary](c.service.client, "GET", fmt.Sprintf("/clubs/%d/activities", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return FilterActivities(activities, c.filters...), wrapError(err, "clubs.list_activities id=%d", c.id)
 }
@@ -6,7 +6,7 @@ import (
 )
 
 func TestClubsGet(t *testing.T) {
-	client := newCassetteClient(testToken, "club_get")
+	client := newFixtureClient(t, "club_get")
 	club, err := NewClubsService(client).Get(45255).Do()
 
 	if err != nil {
@@ -48,7 +48,7 @@ func TestClubsGet(t *testing.T) {
 }
 
 func TestClubsListMembers(t *testing.T) {
-	client := newCassetteClient(testToken, "club_list_members")
+	client := newFixtureClient(t, "club_list_members")
 	members, err := NewClubsService(client).ListMembers(45255).Do()
 
 	if err != nil {
@@ -84,7 +84,7 @@ func TestClubsListMembers(t *testing.T) {
 }
 
 func TestClubsListActivities(t *testing.T) {
-	client := newCassetteClient(testToken, "club_list_activities")
+	client := newFixtureClient(t, "club_list_activities")
 	activities, err := NewClubsService(client).ListActivities(45255).Do()
 
 	if err != nil {
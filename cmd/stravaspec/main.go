@@ -0,0 +1,66 @@
+// stravaspec reports the changes of the swagger spec of the Strava api since its last snapshot,
+// the new and removed endpoints and fields, so upstream changes are seen early.
+//
+// usage:
+//
+//	> go run ./cmd/stravaspec -snapshot=strava-spec.json -update   # take the first snapshot
+//	> go run ./cmd/stravaspec -snapshot=strava-spec.json           # report the changes since
+//
+// It exits with status 1 if the spec changed, e.g. to fail a scheduled CI job, and 2 on errors.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/caselongo/strava-go/stravaspec"
+)
+
+func main() {
+	var snapshotPath, specURL string
+	var update bool
+
+	flag.StringVar(&snapshotPath, "snapshot", "strava-spec.json", "file of the last snapshot of the spec")
+	flag.StringVar(&specURL, "url", stravaspec.SpecURL, "url of the swagger spec")
+	flag.BoolVar(&update, "update", false, "save the current spec as the snapshot")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	current, err := stravaspec.Fetch(ctx, http.DefaultClient, specURL)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	previous, err := stravaspec.ReadSnapshot(snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		previous, err = &stravaspec.Snapshot{}, nil
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	changes := stravaspec.Diff(previous, current)
+	fmt.Print(changes)
+
+	if update {
+		if err := current.Save(snapshotPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	if !changes.Empty() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package strava
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithCoalescing makes concurrent calls of the client to the same GET url share one request and its result,
+// e.g. when many handlers of a web app need the same athlete profile at once, saving rate limit quota.
+// Only calls with the same Header share a request, calls with an ErrorHandler of their own are not coalesced.
+// Calls sharing a request are cancelled with the context of the first call, and only that call is logged,
+// observed and gets a Response.
+func WithCoalescing() Option {
+	return func(c *Client) {
+		c.flights = &flightGroup{flights: make(map[string]*flight)}
+	}
+}
+
+// flightGroup tracks the requests in flight by key.
+type flightGroup struct {
+	lock    sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// do calls f, unless a call with the same key is in flight, of which the result is returned instead.
+func (g *flightGroup) do(key string, f func() ([]byte, error)) ([]byte, error) {
+	g.lock.Lock()
+	if fl, ok := g.flights[key]; ok {
+		g.lock.Unlock()
+		<-fl.done
+		return fl.data, fl.err
+	}
+
+	fl := &flight{done: make(chan struct{})}
+	g.flights[key] = fl
+	g.lock.Unlock()
+
+	fl.data, fl.err = f()
+
+	g.lock.Lock()
+	delete(g.flights, key)
+	g.lock.Unlock()
+	close(fl.done)
+
+	return fl.data, fl.err
+}
+
+// coalesceKey returns the key of the request in the flights of the client, empty if it is not coalesced.
+func (client *Client) coalesceKey(req *http.Request, errorHandler ErrorHandler) string {
+	if client.flights == nil || req.Method != "GET" || req.Header.Get("Range") != "" || errorHandler != nil {
+		return ""
+	}
+
+	// the header of the call is part of the key, e.g. calls of different If-None-Match get different responses
+	var key strings.Builder
+	key.WriteString(req.URL.String())
+	req.Header.Write(&key) // sorted by key
+
+	return key.String()
+}
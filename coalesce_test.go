@@ -0,0 +1,109 @@
+package strava
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientWithCoalescing(t *testing.T) {
+	transport := &blockingTransport{started: make(chan bool, 10), release: make(chan bool)}
+
+	client := NewClient(newStubTokenSource(), WithCoalescing())
+	client.httpClient = &http.Client{Transport: transport}
+
+	var wg sync.WaitGroup
+	athletes := make([]*AthleteDetailed, 5)
+
+	get := func(i int) {
+		defer wg.Done()
+		athletes[i], _ = NewCurrentAthleteService(client).Get().Do()
+	}
+
+	wg.Add(1)
+	go get(0)
+	<-transport.started
+
+	for i := 1; i < len(athletes); i++ {
+		wg.Add(1)
+		go get(i)
+	}
+
+	time.Sleep(50 * time.Millisecond) // for the calls to join the request in flight
+	close(transport.release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&transport.requests); n != 1 {
+		t.Errorf("calls should share one request, got %v", n)
+	}
+
+	for i, athlete := range athletes {
+		if athlete == nil || athlete.FirstName != "Jane" {
+			t.Fatalf("call %d should get the result, got %v", i, athlete)
+		}
+	}
+
+	if athletes[0] == athletes[1] {
+		t.Error("every call should decode its own model")
+	}
+
+	// later calls make a new request
+	NewCurrentAthleteService(client).Get().Do()
+	if n := atomic.LoadInt32(&transport.requests); n != 2 {
+		t.Errorf("finished requests should not be shared, got %v", n)
+	}
+}
+
+func TestClientCoalesceKey(t *testing.T) {
+	client := NewClient(newStubTokenSource(), WithCoalescing())
+
+	get, _ := client.newRequest(context.Background(), "GET", "/athlete", nil)
+	put, _ := client.newRequest(context.Background(), "PUT", "/athlete", nil)
+
+	if client.coalesceKey(get, nil) == "" {
+		t.Error("get requests should be coalesced")
+	}
+
+	if client.coalesceKey(put, nil) != "" || client.coalesceKey(get, defaultErrorHandler) != "" {
+		t.Error("other requests and calls with an error handler should not be coalesced")
+	}
+
+	other, _ := client.newRequest(context.Background(), "GET", "/athlete", nil)
+	if client.coalesceKey(other, nil) != client.coalesceKey(get, nil) {
+		t.Error("same requests should have the same key")
+	}
+
+	addHeader(other, http.Header{"If-None-Match": {`"abc"`}})
+	if client.coalesceKey(other, nil) == client.coalesceKey(get, nil) {
+		t.Error("requests with other headers should not be coalesced")
+	}
+
+	if NewClient(newStubTokenSource()).coalesceKey(get, nil) != "" {
+		t.Error("requests should only be coalesced WithCoalescing")
+	}
+}
+
+// blockingTransport signals each request and answers once released.
+type blockingTransport struct {
+	requests int32
+	started  chan bool
+	release  chan bool
+}
+
+func (t *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	t.started <- true
+	<-t.release
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"id":1,"firstname":"Jane"}`)),
+		Request:    req,
+	}, nil
+}
@@ -1,8 +1,8 @@
 package strava
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 )
 
@@ -11,7 +11,8 @@ type CommentDetailed struct {
 }
 
 type CommentSummary struct {
-	Id         int64          `json:"id"`
+	RawJSON
+	Id         int64          `json:"id" strava:"required"`
 	ActivityId int64          `json:"activity_id"`
 	Text       string         `json:"text"`
 	Athlete    AthleteSummary `json:"athlete"`
@@ -30,8 +31,12 @@ func NewActivityCommentsService(client *Client, activityId int64) *ActivityComme
 /*********************************************************/
 
 type ActivitiesCommentsListCall struct {
-	service *ActivityCommentsService
-	ops     map[string]interface{}
+	service      *ActivityCommentsService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivityCommentsService) List() *ActivitiesCommentsListCall {
@@ -56,26 +61,47 @@ func (c *ActivitiesCommentsListCall) PerPage(perPage int) *ActivitiesCommentsLis
 	return c
 }
 
-func (c *ActivitiesCommentsListCall) Do() ([]*CommentSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/activities/%d/comments", c.service.activityId), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivitiesCommentsListCall) OnError(handler ErrorHandler) *ActivitiesCommentsListCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivitiesCommentsListCall) Response(resp *Response) *ActivitiesCommentsListCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivitiesCommentsListCall) Timeout(d time.Duration) *ActivitiesCommentsListCall {
+	c.timeout = d
+	return c
+}
 
-	comments := make([]*CommentSummary, 0)
-	err = json.Unmarshal(data, &comments)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivitiesCommentsListCall) Header(key, value string) *ActivitiesCommentsListCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return comments, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivitiesCommentsListCall) Do() ([]*CommentSummary, error) {
+	comments, err := runAndDecode[[]*CommentSummary](c.service.client, "GET", fmt.Sprintf("/activities/%d/comments", c.service.activityId), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return comments, wrapError(err, "activity_comments.list activity_id=%d", c.service.activityId)
 }
 
 /*********************************************************/
 
 type ActivityCommentsPostCall struct {
-	service *ActivityCommentsService
-	text    string
+	service      *ActivityCommentsService
+	text         string
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivityCommentsService) Create(text string) *ActivityCommentsPostCall {
@@ -85,31 +111,57 @@ func (s *ActivityCommentsService) Create(text string) *ActivityCommentsPostCall
 	}
 }
 
+func (c *ActivityCommentsPostCall) OnError(handler ErrorHandler) *ActivityCommentsPostCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivityCommentsPostCall) Response(resp *Response) *ActivityCommentsPostCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivityCommentsPostCall) Timeout(d time.Duration) *ActivityCommentsPostCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivityCommentsPostCall) Header(key, value string) *ActivityCommentsPostCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *ActivityCommentsPostCall) Do() (*CommentDetailed, error) {
-	data, err := c.service.client.run(
+	comment, err := runAndDecode[*CommentDetailed](
+		c.service.client,
 		"POST",
 		fmt.Sprintf("/activities/%d/comments", c.service.activityId),
 		map[string]interface{}{"text": c.text},
+		c.errorHandler,
+		c.response,
+		c.timeout,
+		c.header,
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	var comment CommentDetailed
-	err = json.Unmarshal(data, &comment)
-	if err != nil {
-		return nil, err
-	}
-
-	return &comment, nil
+	return comment, wrapError(err, "activity_comments.create activity_id=%d", c.service.activityId)
 }
 
 /*********************************************************/
 
 type ActivityCommentsDeleteCall struct {
-	service    *ActivityCommentsService
-	activityId int64
-	commentId  int64
+	service      *ActivityCommentsService
+	activityId   int64
+	commentId    int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivityCommentsService) Delete(commentId int64) *ActivityCommentsDeleteCall {
@@ -119,11 +171,42 @@ func (s *ActivityCommentsService) Delete(commentId int64) *ActivityCommentsDelet
 	}
 }
 
+func (c *ActivityCommentsDeleteCall) OnError(handler ErrorHandler) *ActivityCommentsDeleteCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivityCommentsDeleteCall) Response(resp *Response) *ActivityCommentsDeleteCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivityCommentsDeleteCall) Timeout(d time.Duration) *ActivityCommentsDeleteCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivityCommentsDeleteCall) Header(key, value string) *ActivityCommentsDeleteCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *ActivityCommentsDeleteCall) Do() error {
-	_, err := c.service.client.run(
+	_, err := c.service.client.runWithErrorHandler(
 		"DELETE",
 		fmt.Sprintf("/activities/%d/comments/%d", c.service.activityId, c.commentId),
 		nil,
+		c.errorHandler,
+		c.response,
+		c.timeout,
+		c.header,
 	)
-	return err
+	return wrapError(err, "activity_comments.delete activity_id=%d id=%d", c.service.activityId, c.commentId)
 }
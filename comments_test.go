@@ -1,11 +1,12 @@
 package strava
 
 import (
+	"net/http"
 	"testing"
 )
 
 func TestActivityCommentsList(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_comments_list")
+	client := newFixtureClient(t, "activity_comments_list")
 	comments, err := NewActivityCommentsService(client, 103221154).List().Do()
 
 	if err != nil {
@@ -77,7 +78,7 @@ func TestActivityCommentsList(t *testing.T) {
 }
 
 func TestActivityCommentsCreate(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_comments_post")
+	client := newFixtureClient(t, "activity_comments_post")
 	comment, err := NewActivityCommentsService(client, 118293263).Create("test comment").Do()
 
 	if err != nil {
@@ -121,7 +122,7 @@ func TestActivityCommentsCreate(t *testing.T) {
 }
 
 func TestActivityCommentsDelete(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_comments_delete")
+	client := NewStubResponseClient("", http.StatusNoContent)
 	err := NewActivityCommentsService(client, 118293263).Delete(30043520).Do()
 
 	if err != nil {
@@ -0,0 +1,50 @@
+package strava
+
+import (
+	"context"
+	"time"
+)
+
+// WithMaxConcurrency caps the requests of the client in flight at once to n, so a burst of goroutines,
+// e.g. fetching the activities of a club, doesn't use up the 15 minute rate limit in a few seconds.
+// Other requests wait for one to finish, or fail with the error of their context. Copies made with
+// Client.With share the cap. Zero or less means no cap, which is the default.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		c.limiter = nil
+		if n > 0 {
+			c.limiter = make(concurrencyLimiter, n)
+		}
+	}
+}
+
+// concurrencyLimiter holds a slot for each request in flight.
+type concurrencyLimiter chan struct{}
+
+// acquire blocks until a slot is free and returns how long it waited, or the error of ctx if it is done first.
+func (l concurrencyLimiter) acquire(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	select {
+	case l <- struct{}{}:
+		return 0, nil
+	default:
+	}
+
+	start := time.Now()
+	select {
+	case l <- struct{}{}:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		return time.Since(start), ctx.Err()
+	}
+}
+
+// release frees the slot of a request.
+func (l concurrencyLimiter) release() {
+	if l != nil {
+		<-l
+	}
+}
@@ -0,0 +1,78 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientWithMaxConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client := NewClient(newStubTokenSource(), WithMaxConcurrency(2))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":1}`)), Request: req}, nil
+	})}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := NewClubsService(client).Get(1).Do(); err != nil {
+				t.Errorf("service error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("requests in flight should be capped, got %d", maxInFlight)
+	}
+
+	if options := client.ClientOptionsSnapshot(); options.Concurrency != 2 {
+		t.Errorf("incorrect concurrency, got %d", options.Concurrency)
+	}
+}
+
+func TestClientWithMaxConcurrencyTimeout(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{"/api/v3/clubs/1": `{"id":1}`})
+	WithMaxConcurrency(1)(client)
+
+	// a request in flight, for a copy of the client too
+	client.limiter <- struct{}{}
+
+	_, err := NewClubsService(client.With()).Get(1).Timeout(10 * time.Millisecond).Do()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("call should time out waiting, got %v", err)
+	}
+
+	if len(transport.requests) != 0 {
+		t.Errorf("request should not be made, got %d", len(transport.requests))
+	}
+
+	client.limiter.release()
+	if _, err := NewClubsService(client).Get(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(client.limiter) != 0 {
+		t.Error("slot should be released")
+	}
+}
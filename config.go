@@ -0,0 +1,159 @@
+package strava
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// Config holds the settings of a Strava application, loaded at startup with ConfigFromEnv or ConfigFromFile,
+// so the credentials don't have to be set in the deprecated package level ClientId and ClientSecret.
+type Config struct {
+	ClientId     int    `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+
+	// WebhookVerifyToken is the token Strava echoes when a webhook subscription is validated, optional.
+	WebhookVerifyToken string `json:"webhook_verify_token"`
+
+	// CallbackURL is the url users are redirected to after authorizing the application, optional.
+	CallbackURL string `json:"callback_url"`
+
+	// OAuthBaseURL overrides the base of the oauth urls, see OAuthAuthenticator.SetOAuthBaseURL, optional.
+	OAuthBaseURL string `json:"oauth_base_url"`
+
+	// BaseURL overrides the base of the api, see WithBaseURL, optional.
+	BaseURL string `json:"base_url"`
+}
+
+// The environment variables read by ConfigFromEnv.
+const (
+	EnvClientId           = "STRAVA_CLIENT_ID"
+	EnvClientSecret       = "STRAVA_CLIENT_SECRET"
+	EnvWebhookVerifyToken = "STRAVA_WEBHOOK_VERIFY_TOKEN"
+	EnvCallbackURL        = "STRAVA_CALLBACK_URL"
+	EnvOAuthBaseURL       = "STRAVA_OAUTH_BASE_URL"
+	EnvBaseURL            = "STRAVA_BASE_URL"
+)
+
+// ConfigFromEnv loads the config from the STRAVA_* environment variables, e.g. STRAVA_CLIENT_ID.
+// Returns a *ValidationError naming the variable if a required one is missing or one has an invalid value.
+func ConfigFromEnv() (*Config, error) {
+	config := &Config{
+		ClientSecret:       os.Getenv(EnvClientSecret),
+		WebhookVerifyToken: os.Getenv(EnvWebhookVerifyToken),
+		CallbackURL:        os.Getenv(EnvCallbackURL),
+		OAuthBaseURL:       os.Getenv(EnvOAuthBaseURL),
+		BaseURL:            os.Getenv(EnvBaseURL),
+	}
+
+	if id := os.Getenv(EnvClientId); id != "" {
+		clientId, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, &ValidationError{EnvClientId, fmt.Sprintf("%q is not a number", id)}
+		}
+		config.ClientId = clientId
+	}
+
+	err := config.validate(map[string]string{
+		"client_id":      EnvClientId,
+		"client_secret":  EnvClientSecret,
+		"callback_url":   EnvCallbackURL,
+		"oauth_base_url": EnvOAuthBaseURL,
+		"base_url":       EnvBaseURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ConfigFromFile loads the config from a json file with the fields of Config, for example:
+//
+//	{"client_id":123,"client_secret":"...","webhook_verify_token":"..."}
+//
+// Returns a *ValidationError naming the field if a required one is missing or one has an invalid value.
+func ConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil, &ValidationError{typeErr.Field, fmt.Sprintf("should be a %s", typeErr.Type)}
+		}
+		return nil, fmt.Errorf("strava: config %s: %w", path, err)
+	}
+
+	if err := config.validate(nil); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// validate checks the config, names maps the json field names to the names used in the errors.
+func (c *Config) validate(names map[string]string) error {
+	name := func(field string) string {
+		if n, ok := names[field]; ok {
+			return n
+		}
+		return field
+	}
+
+	if c.ClientId <= 0 {
+		return &ValidationError{name("client_id"), "is required and should be positive"}
+	}
+
+	if c.ClientSecret == "" {
+		return &ValidationError{name("client_secret"), "is required"}
+	}
+
+	urls := []struct{ field, value string }{
+		{"callback_url", c.CallbackURL},
+		{"oauth_base_url", c.OAuthBaseURL},
+		{"base_url", c.BaseURL},
+	}
+
+	for _, f := range urls {
+		if f.value == "" {
+			continue
+		}
+
+		if u, err := url.Parse(f.value); err != nil || u.Scheme == "" || u.Host == "" {
+			return &ValidationError{name(f.field), fmt.Sprintf("%q is not an absolute url", f.value)}
+		}
+	}
+
+	return nil
+}
+
+// Client creates a client with the credentials and base url of the config, the options are applied after them.
+func (c *Config) Client(tokenSource TokenSource, options ...Option) *Client {
+	defaults := []Option{WithCredentials(c.ClientId, c.ClientSecret)}
+	if c.BaseURL != "" {
+		defaults = append(defaults, WithBaseURL(c.BaseURL))
+	}
+
+	return NewClient(tokenSource, append(defaults, options...)...)
+}
+
+// Authenticator creates an authenticator with the credentials, callback url and base urls of the config.
+func (c *Config) Authenticator(tokenSource TokenSource) (*OAuthAuthenticator, error) {
+	auth, err := NewOAuthAuthenticator(tokenSource, c.CallbackURL)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.SetCredentials(c.ClientId, c.ClientSecret)
+	auth.SetBaseURL(c.BaseURL)
+	if c.OAuthBaseURL != "" {
+		auth.SetOAuthBaseURL(c.OAuthBaseURL)
+	}
+
+	return auth, nil
+}
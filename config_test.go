@@ -0,0 +1,96 @@
+package strava
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvClientId, "123")
+	t.Setenv(EnvClientSecret, "secret")
+	t.Setenv(EnvWebhookVerifyToken, "verify")
+	t.Setenv(EnvCallbackURL, "https://example.com/strava/callback")
+	t.Setenv(EnvOAuthBaseURL, "")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("config error: %v", err)
+	}
+
+	if config.ClientId != 123 || config.ClientSecret != "secret" || config.WebhookVerifyToken != "verify" || config.CallbackURL != "https://example.com/strava/callback" {
+		t.Errorf("incorrect config, got %+v", config)
+	}
+
+	if id, secret := config.Client(newStubTokenSource()).credentials(); id != 123 || secret != "secret" {
+		t.Errorf("incorrect client credentials, got %v %v", id, secret)
+	}
+
+	auth, _ := config.Authenticator(newStubTokenSource())
+	if path, _ := auth.CallbackPath(); path != "/strava/callback" {
+		t.Errorf("incorrect callback path, got %v", path)
+	}
+
+	// invalid values name the variable
+	var ve *ValidationError
+
+	t.Setenv(EnvClientId, "abc")
+	if _, err := ConfigFromEnv(); !errors.As(err, &ve) || ve.Field != EnvClientId {
+		t.Errorf("should return validation error, got %v", err)
+	}
+
+	t.Setenv(EnvClientId, "123")
+	t.Setenv(EnvClientSecret, "")
+	if _, err := ConfigFromEnv(); !errors.As(err, &ve) || ve.Field != EnvClientSecret {
+		t.Errorf("should return validation error, got %v", err)
+	}
+
+	t.Setenv(EnvClientSecret, "secret")
+	t.Setenv(EnvOAuthBaseURL, "localhost")
+	if _, err := ConfigFromEnv(); !errors.As(err, &ve) || ve.Field != EnvOAuthBaseURL {
+		t.Errorf("should return validation error, got %v", err)
+	}
+}
+
+func TestConfigFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "strava.json")
+	os.WriteFile(path, []byte(`{"client_id":123,"client_secret":"secret","oauth_base_url":"http://localhost:8080/oauth","base_url":"http://localhost:8080/api/v3"}`), 0600)
+
+	config, err := ConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("config error: %v", err)
+	}
+
+	if config.ClientId != 123 || config.ClientSecret != "secret" || config.OAuthBaseURL != "http://localhost:8080/oauth" {
+		t.Errorf("incorrect config, got %+v", config)
+	}
+
+	auth, _ := config.Authenticator(newStubTokenSource())
+	if u := auth.AuthorizationURL("", nil, false); !strings.HasPrefix(u, "http://localhost:8080/oauth/authorize") {
+		t.Errorf("oauth base url not set, got %v", u)
+	}
+
+	if c := config.Client(newStubTokenSource()); c.baseURL != "http://localhost:8080/api/v3" {
+		t.Errorf("base url not set, got %v", c.baseURL)
+	}
+
+	// incorrect types name the field
+	var ve *ValidationError
+	os.WriteFile(path, []byte(`{"client_id":"123","client_secret":"secret"}`), 0600)
+	if _, err := ConfigFromFile(path); !errors.As(err, &ve) || ve.Field != "client_id" {
+		t.Errorf("should return validation error, got %v", err)
+	}
+
+	os.WriteFile(path, []byte(`{"client_secret":"secret"}`), 0600)
+	if _, err := ConfigFromFile(path); !errors.As(err, &ve) || ve.Field != "client_id" {
+		t.Errorf("should return validation error, got %v", err)
+	}
+
+	if _, err := ConfigFromFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("should return error for missing file")
+	}
+}
@@ -1,7 +1,8 @@
 package strava
 
 import (
-	"encoding/json"
+	"net/http"
+	"time"
 )
 
 type CurrentAthleteService struct {
@@ -15,7 +16,11 @@ func NewCurrentAthleteService(client *Client) *CurrentAthleteService {
 /*********************************************************/
 
 type CurrentAthleteGetCall struct {
-	service *CurrentAthleteService
+	service      *CurrentAthleteService
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) Get() *CurrentAthleteGetCall {
@@ -24,26 +29,47 @@ func (s *CurrentAthleteService) Get() *CurrentAthleteGetCall {
 	}
 }
 
-func (c *CurrentAthleteGetCall) Do() (*AthleteDetailed, error) {
-	data, err := c.service.client.run("GET", "/athlete", nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *CurrentAthleteGetCall) OnError(handler ErrorHandler) *CurrentAthleteGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteGetCall) Response(resp *Response) *CurrentAthleteGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteGetCall) Timeout(d time.Duration) *CurrentAthleteGetCall {
+	c.timeout = d
+	return c
+}
 
-	var athlete AthleteDetailed
-	err = json.Unmarshal(data, &athlete)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteGetCall) Header(key, value string) *CurrentAthleteGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &athlete, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthleteGetCall) Do() (*AthleteDetailed, error) {
+	athlete, err := runAndDecode[*AthleteDetailed](c.service.client, "GET", "/athlete", nil, c.errorHandler, c.response, c.timeout, c.header)
+	return athlete, wrapError(err, "athlete.get")
 }
 
 /*********************************************************/
 
 type CurrentAthletePutCall struct {
-	service *CurrentAthleteService
-	ops     map[string]interface{}
+	service      *CurrentAthleteService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) Update() *CurrentAthletePutCall {
@@ -78,26 +104,49 @@ func (c *CurrentAthletePutCall) Weight(weight float64) *CurrentAthletePutCall {
 	return c
 }
 
-func (c *CurrentAthletePutCall) Do() (*AthleteDetailed, error) {
-	data, err := c.service.client.run("PUT", "/athlete", c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *CurrentAthletePutCall) OnError(handler ErrorHandler) *CurrentAthletePutCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthletePutCall) Response(resp *Response) *CurrentAthletePutCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthletePutCall) Timeout(d time.Duration) *CurrentAthletePutCall {
+	c.timeout = d
+	return c
+}
 
-	var athlete AthleteDetailed
-	err = json.Unmarshal(data, &athlete)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthletePutCall) Header(key, value string) *CurrentAthletePutCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &athlete, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthletePutCall) Do() (*AthleteDetailed, error) {
+	athlete, err := runAndDecode[*AthleteDetailed](c.service.client, "PUT", "/athlete", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return athlete, wrapError(err, "athlete.update")
 }
 
 /*********************************************************/
 
 type CurrentAthleteListActivitiesCall struct {
-	service *CurrentAthleteService
-	ops     map[string]interface{}
+	service      *CurrentAthleteService
+	ops          map[string]interface{}
+	filters      []ActivityFilter
+	prefetch     bool
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) ListActivities() *CurrentAthleteListActivitiesCall {
@@ -127,26 +176,99 @@ func (c *CurrentAthleteListActivitiesCall) PerPage(perPage int) *CurrentAthleteL
 	return c
 }
 
+// Where only returns the activities of the page selected by all filters, see ActivityFilter.
+// Pages are requested as usual, so they may hold fewer activities than requested.
+func (c *CurrentAthleteListActivitiesCall) Where(filters ...ActivityFilter) *CurrentAthleteListActivitiesCall {
+	c.filters = append(c.filters, filters...)
+	return c
+}
+
+func (c *CurrentAthleteListActivitiesCall) OnError(handler ErrorHandler) *CurrentAthleteListActivitiesCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteListActivitiesCall) Response(resp *Response) *CurrentAthleteListActivitiesCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteListActivitiesCall) Timeout(d time.Duration) *CurrentAthleteListActivitiesCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteListActivitiesCall) Header(key, value string) *CurrentAthleteListActivitiesCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *CurrentAthleteListActivitiesCall) Do() ([]*ActivitySummary, error) {
-	data, err := c.service.client.run("GET", "/athlete/activities", c.ops)
-	if err != nil {
-		return nil, err
+	activities, err := runAndDecode[[]*ActivitySummary](c.service.client, "GET", "/athlete/activities", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return FilterActivities(activities, c.filters...), wrapError(err, "athlete.list_activities")
+}
+
+// Prefetch makes the iterator returned by Iterate fetch the next page while the activities of a page
+// are iterated, hiding the latency of long listings. Pages are not fetched ahead once the rate limit
+// is reached, and the prefetching requests wait for their turn like others, see WithRequestDelay.
+// A Response of the call holds the metadata of the page being iterated.
+func (c *CurrentAthleteListActivitiesCall) Prefetch() *CurrentAthleteListActivitiesCall {
+	c.prefetch = true
+	return c
+}
+
+// Iterate returns an iterator over the activities of all pages, starting at the page of the call,
+// selected by the filters of Where. Pages hold PerPage activities, 30 by default.
+func (c *CurrentAthleteListActivitiesCall) Iterate() *Iterator[*ActivitySummary] {
+	perPage, ok := c.ops["per_page"].(int)
+	if !ok {
+		perPage = defaultPerPage
+	}
+
+	first, ok := c.ops["page"].(int)
+	if !ok {
+		first = 1
 	}
 
-	activities := make([]*ActivitySummary, 0)
-	err = json.Unmarshal(data, &activities)
-	if err != nil {
-		return nil, err
+	var prefetch func() bool
+	if c.prefetch {
+		prefetch = c.service.client.mayPrefetch
 	}
 
-	return activities, nil
+	activities := paginate(perPage, func(page int, response *Response) ([]*ActivitySummary, error) {
+		ops := make(map[string]interface{})
+		for k, v := range c.ops {
+			ops[k] = v
+		}
+		ops["page"] = first + page - 1
+		ops["per_page"] = perPage
+
+		activities, err := runAndDecode[[]*ActivitySummary](c.service.client, "GET", "/athlete/activities", ops, c.errorHandler, response, c.timeout, c.header)
+		return activities, wrapError(err, "athlete.list_activities")
+	}, prefetch, c.response)
+
+	return Filter(activities, func(activity *ActivitySummary) bool {
+		return matchActivity(activity, c.filters)
+	})
 }
 
 /*********************************************************/
 
 type CurrentAthleteListFriendsActivitiesCall struct {
-	service *CurrentAthleteService
-	ops     map[string]interface{}
+	service      *CurrentAthleteService
+	ops          map[string]interface{}
+	filters      []ActivityFilter
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) ListFriendsActivities() *CurrentAthleteListFriendsActivitiesCall {
@@ -171,26 +293,54 @@ func (c *CurrentAthleteListFriendsActivitiesCall) PerPage(perPage int) *CurrentA
 	return c
 }
 
-func (c *CurrentAthleteListFriendsActivitiesCall) Do() ([]*ActivitySummary, error) {
-	data, err := c.service.client.run("GET", "/activities/following", c.ops)
-	if err != nil {
-		return nil, err
-	}
+// Where only returns the activities of the page selected by all filters, see ActivityFilter.
+// Pages are requested as usual, so they may hold fewer activities than requested.
+func (c *CurrentAthleteListFriendsActivitiesCall) Where(filters ...ActivityFilter) *CurrentAthleteListFriendsActivitiesCall {
+	c.filters = append(c.filters, filters...)
+	return c
+}
+
+func (c *CurrentAthleteListFriendsActivitiesCall) OnError(handler ErrorHandler) *CurrentAthleteListFriendsActivitiesCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteListFriendsActivitiesCall) Response(resp *Response) *CurrentAthleteListFriendsActivitiesCall {
+	c.response = resp
+	return c
+}
 
-	activities := make([]*ActivitySummary, 0)
-	err = json.Unmarshal(data, &activities)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteListFriendsActivitiesCall) Timeout(d time.Duration) *CurrentAthleteListFriendsActivitiesCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteListFriendsActivitiesCall) Header(key, value string) *CurrentAthleteListFriendsActivitiesCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return activities, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthleteListFriendsActivitiesCall) Do() ([]*ActivitySummary, error) {
+	activities, err := runAndDecode[[]*ActivitySummary](c.service.client, "GET", "/activities/following", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return FilterActivities(activities, c.filters...), wrapError(err, "athlete.list_friends_activities")
 }
 
 /*********************************************************/
 
 type CurrentAthleteListFriendsCall struct {
-	service *CurrentAthleteService
-	ops     map[string]interface{}
+	service      *CurrentAthleteService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) ListFriends() *CurrentAthleteListFriendsCall {
@@ -210,26 +360,47 @@ func (c *CurrentAthleteListFriendsCall) PerPage(perPage int) *CurrentAthleteList
 	return c
 }
 
-func (c *CurrentAthleteListFriendsCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", "/athlete/friends", c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *CurrentAthleteListFriendsCall) OnError(handler ErrorHandler) *CurrentAthleteListFriendsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteListFriendsCall) Response(resp *Response) *CurrentAthleteListFriendsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteListFriendsCall) Timeout(d time.Duration) *CurrentAthleteListFriendsCall {
+	c.timeout = d
+	return c
+}
 
-	friends := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &friends)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteListFriendsCall) Header(key, value string) *CurrentAthleteListFriendsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return friends, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthleteListFriendsCall) Do() ([]*AthleteSummary, error) {
+	friends, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", "/athlete/friends", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return friends, wrapError(err, "athlete.list_friends")
 }
 
 /*********************************************************/
 
 type CurrentAthleteListFollowersCall struct {
-	service *CurrentAthleteService
-	ops     map[string]interface{}
+	service      *CurrentAthleteService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) ListFollowers() *CurrentAthleteListFollowersCall {
@@ -249,25 +420,46 @@ func (c *CurrentAthleteListFollowersCall) PerPage(perPage int) *CurrentAthleteLi
 	return c
 }
 
-func (c *CurrentAthleteListFollowersCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", "/athlete/followers", c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *CurrentAthleteListFollowersCall) OnError(handler ErrorHandler) *CurrentAthleteListFollowersCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteListFollowersCall) Response(resp *Response) *CurrentAthleteListFollowersCall {
+	c.response = resp
+	return c
+}
 
-	followers := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &followers)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteListFollowersCall) Timeout(d time.Duration) *CurrentAthleteListFollowersCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteListFollowersCall) Header(key, value string) *CurrentAthleteListFollowersCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return followers, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthleteListFollowersCall) Do() ([]*AthleteSummary, error) {
+	followers, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", "/athlete/followers", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return followers, wrapError(err, "athlete.list_followers")
 }
 
 /*********************************************************/
 
 type CurrentAthleteListClubsCall struct {
-	service *CurrentAthleteService
+	service      *CurrentAthleteService
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) ListClubs() *CurrentAthleteListClubsCall {
@@ -276,26 +468,47 @@ func (s *CurrentAthleteService) ListClubs() *CurrentAthleteListClubsCall {
 	}
 }
 
-func (c *CurrentAthleteListClubsCall) Do() ([]*ClubSummary, error) {
-	data, err := c.service.client.run("GET", "/athlete/clubs", nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *CurrentAthleteListClubsCall) OnError(handler ErrorHandler) *CurrentAthleteListClubsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteListClubsCall) Response(resp *Response) *CurrentAthleteListClubsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteListClubsCall) Timeout(d time.Duration) *CurrentAthleteListClubsCall {
+	c.timeout = d
+	return c
+}
 
-	clubs := make([]*ClubSummary, 0)
-	err = json.Unmarshal(data, &clubs)
-	if err != nil {
-		return nil, err
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteListClubsCall) Header(key, value string) *CurrentAthleteListClubsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return clubs, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthleteListClubsCall) Do() ([]*ClubSummary, error) {
+	clubs, err := runAndDecode[[]*ClubSummary](c.service.client, "GET", "/athlete/clubs", nil, c.errorHandler, c.response, c.timeout, c.header)
+	return clubs, wrapError(err, "athlete.list_clubs")
 }
 
 /*********************************************************/
 
 type CurrentAthleteListStarredSegmentsCall struct {
-	service *CurrentAthleteService
-	ops     map[string]interface{}
+	service      *CurrentAthleteService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *CurrentAthleteService) ListStarredSegments() *CurrentAthleteListStarredSegmentsCall {
@@ -315,17 +528,34 @@ func (c *CurrentAthleteListStarredSegmentsCall) PerPage(perPage int) *CurrentAth
 	return c
 }
 
-func (c *CurrentAthleteListStarredSegmentsCall) Do() ([]*PersonalSegmentSummary, error) {
-	data, err := c.service.client.run("GET", "/segments/starred", c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *CurrentAthleteListStarredSegmentsCall) OnError(handler ErrorHandler) *CurrentAthleteListStarredSegmentsCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *CurrentAthleteListStarredSegmentsCall) Response(resp *Response) *CurrentAthleteListStarredSegmentsCall {
+	c.response = resp
+	return c
+}
 
-	segments := make([]*PersonalSegmentSummary, 0)
-	err = json.Unmarshal(data, &segments)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *CurrentAthleteListStarredSegmentsCall) Timeout(d time.Duration) *CurrentAthleteListStarredSegmentsCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *CurrentAthleteListStarredSegmentsCall) Header(key, value string) *CurrentAthleteListStarredSegmentsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return segments, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *CurrentAthleteListStarredSegmentsCall) Do() ([]*PersonalSegmentSummary, error) {
+	segments, err := runAndDecode[[]*PersonalSegmentSummary](c.service.client, "GET", "/segments/starred", c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return segments, wrapError(err, "athlete.list_starred_segments")
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func TestCurrentAthleteGet(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_get")
+	client := newFixtureClient(t, "current_athlete_get")
 	athlete, err := NewCurrentAthleteService(client).Get().Do()
 
 	if err != nil {
@@ -79,7 +79,7 @@ func TestCurrentAthleteGet(t *testing.T) {
 }
 
 func TestCurrentAthleteUpdate(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_put")
+	client := newFixtureClient(t, "current_athlete_put")
 	athlete, err := NewCurrentAthleteService(client).Update().Do()
 
 	if err != nil {
@@ -127,7 +127,7 @@ func TestCurrentAthleteUpdate(t *testing.T) {
 }
 
 func TestCurrentAthleteListActivities(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_list_activities")
+	client := newFixtureClient(t, "current_athlete_list_activities")
 	activities, err := NewCurrentAthleteService(client).ListActivities().Do()
 
 	if err != nil {
@@ -171,7 +171,7 @@ func TestCurrentAthleteListActivities(t *testing.T) {
 }
 
 func TestCurrentAthleteListFriendsActivities(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_list_friends_activities")
+	client := newFixtureClient(t, "current_athlete_list_friends_activities")
 	activities, err := NewCurrentAthleteService(client).ListFriendsActivities().Do()
 
 	if err != nil {
@@ -215,7 +215,7 @@ func TestCurrentAthleteListFriendsActivities(t *testing.T) {
 }
 
 func TestCurrentAthleteListFriends(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_list_friends")
+	client := newFixtureClient(t, "current_athlete_list_friends")
 	friends, err := NewCurrentAthleteService(client).ListFriends().Do()
 
 	if err != nil {
@@ -247,7 +247,7 @@ func TestCurrentAthleteListFriends(t *testing.T) {
 }
 
 func TestCurrentAthleteListFollowers(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_list_followers")
+	client := newFixtureClient(t, "current_athlete_list_followers")
 	followers, err := NewCurrentAthleteService(client).ListFollowers().Do()
 
 	if err != nil {
@@ -279,7 +279,7 @@ func TestCurrentAthleteListFollowers(t *testing.T) {
 }
 
 func TestCurrentAthleteListClubs(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_list_clubs")
+	client := newFixtureClient(t, "current_athlete_list_clubs")
 	clubs, err := NewCurrentAthleteService(client).ListClubs().Do()
 
 	if err != nil {
@@ -307,7 +307,7 @@ func TestCurrentAthleteListClubs(t *testing.T) {
 }
 
 func TestCurrentAthleteListStarredSegments(t *testing.T) {
-	client := newCassetteClient(testToken, "current_athlete_list_starred_segments")
+	client := newFixtureClient(t, "current_athlete_list_starred_segments")
 	segments, err := NewCurrentAthleteService(client).ListStarredSegments().Do()
 
 	if err != nil {
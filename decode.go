@@ -0,0 +1,83 @@
+package strava
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+)
+
+// decodeResponse decodes the data of a response into v, see decode, and records the time it took.
+// Empty bodies and empty lists, returned by some endpoints when there is nothing to return,
+// leave v empty, see setEmpty, instead of failing.
+func (client *Client) decodeResponse(record *callRecord, data []byte, v interface{}) error {
+	start := time.Now()
+	defer func() { record.timing.Decode += time.Since(start) }()
+
+	if emptyJSON(data, true) {
+		record.setEmpty(v)
+		return nil
+	}
+
+	return client.decode(data, v)
+}
+
+// decodeStream decodes the body of a response into v while it is read, see decodeResponse.
+func (client *Client) decodeStream(record *callRecord, body io.Reader, v interface{}) error {
+	start := time.Now()
+	defer func() { record.timing.Decode += time.Since(start) }()
+
+	reader := bufio.NewReader(body)
+	prefix, err := reader.Peek(64)
+	if emptyJSON(prefix, err != nil) {
+		record.setEmpty(v)
+		return nil
+	}
+
+	err = json.NewDecoder(reader).Decode(v)
+	io.Copy(io.Discard, reader) // so the connection can be reused
+
+	return err
+}
+
+// emptyJSON returns if the data, the complete body or its start, is empty or starts an empty list.
+func emptyJSON(data []byte, complete bool) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return complete
+	}
+
+	if data[0] != '[' {
+		return false
+	}
+
+	rest := bytes.TrimSpace(data[1:])
+	return len(rest) > 0 && rest[0] == ']'
+}
+
+// setEmpty sets the value v points to to an empty slice, map or struct, so calls return an empty
+// result rather than nil, and flags the Response of the call as empty.
+func (record *callRecord) setEmpty(v interface{}) {
+	if record.response != nil {
+		record.response.Empty = true
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return
+	}
+
+	value = value.Elem()
+	switch value.Kind() {
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+	case reflect.Map:
+		value.Set(reflect.MakeMap(value.Type()))
+	case reflect.Ptr:
+		value.Set(reflect.New(value.Type().Elem()))
+	default:
+		value.Set(reflect.Zero(value.Type()))
+	}
+}
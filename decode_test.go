@@ -0,0 +1,137 @@
+package strava
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEmptyJSON(t *testing.T) {
+	cases := []struct {
+		data     string
+		complete bool
+		expected bool
+	}{
+		{"", true, true},
+		{" \n", true, true},
+		{"", false, false},
+		{"[]", true, true},
+		{"[ \n]", false, true},
+		{"[", false, false},
+		{`[{"id":1}]`, true, false},
+		{"{}", true, false},
+		{"null", true, false},
+	}
+
+	for _, c := range cases {
+		if empty := emptyJSON([]byte(c.data), c.complete); empty != c.expected {
+			t.Errorf("%q: incorrect empty, got %v", c.data, empty)
+		}
+	}
+}
+
+// TestEmptyResponses checks that every call decoding a response returns an empty result,
+// rather than an error, for empty bodies and empty lists.
+func TestEmptyResponses(t *testing.T) {
+	calls := map[string]func(c *Client) (interface{}, error){
+		"activities.get":    func(c *Client) (interface{}, error) { return NewActivitiesService(c).Get(1).Do() },
+		"activities.update": func(c *Client) (interface{}, error) { return NewActivitiesService(c).Update(1).Name("name").Do() },
+		"activities.photos": func(c *Client) (interface{}, error) { return NewActivitiesService(c).ListPhotos(1).Do() },
+		"activities.zones":  func(c *Client) (interface{}, error) { return NewActivitiesService(c).ListZones(1).Do() },
+		"activities.laps":   func(c *Client) (interface{}, error) { return NewActivitiesService(c).ListLaps(1).Do() },
+		"activities.streams": func(c *Client) (interface{}, error) {
+			return NewActivityStreamsService(c).Get(1, []StreamType{StreamTypes.Time}).Do()
+		},
+		"activity_comments.list":   func(c *Client) (interface{}, error) { return NewActivityCommentsService(c, 1).List().Do() },
+		"activity_comments.create": func(c *Client) (interface{}, error) { return NewActivityCommentsService(c, 1).Create("text").Do() },
+		"activity_kudos.list":      func(c *Client) (interface{}, error) { return NewActivityKudosService(c, 1).List().Do() },
+		"athlete.get":              func(c *Client) (interface{}, error) { return NewCurrentAthleteService(c).Get().Do() },
+		"athlete.update":           func(c *Client) (interface{}, error) { return NewCurrentAthleteService(c).Update().City("city").Do() },
+		"athlete.activities":       func(c *Client) (interface{}, error) { return NewCurrentAthleteService(c).ListActivities().Do() },
+		"athlete.friends_activities": func(c *Client) (interface{}, error) {
+			return NewCurrentAthleteService(c).ListFriendsActivities().Do()
+		},
+		"athlete.friends":   func(c *Client) (interface{}, error) { return NewCurrentAthleteService(c).ListFriends().Do() },
+		"athlete.followers": func(c *Client) (interface{}, error) { return NewCurrentAthleteService(c).ListFollowers().Do() },
+		"athlete.clubs":     func(c *Client) (interface{}, error) { return NewCurrentAthleteService(c).ListClubs().Do() },
+		"athlete.starred_segments": func(c *Client) (interface{}, error) {
+			return NewCurrentAthleteService(c).ListStarredSegments().Do()
+		},
+		"athletes.get":              func(c *Client) (interface{}, error) { return NewAthletesService(c).Get(1).Do() },
+		"athletes.starred_segments": func(c *Client) (interface{}, error) { return NewAthletesService(c).ListStarredSegments(1).Do() },
+		"athletes.friends":          func(c *Client) (interface{}, error) { return NewAthletesService(c).ListFriends(1).Do() },
+		"athletes.followers":        func(c *Client) (interface{}, error) { return NewAthletesService(c).ListFollowers(1).Do() },
+		"athletes.both_following":   func(c *Client) (interface{}, error) { return NewAthletesService(c).ListBothFollowing(1).Do() },
+		"athletes.stats":            func(c *Client) (interface{}, error) { return NewAthletesService(c).Stats(1).Do() },
+		"athletes.koms":             func(c *Client) (interface{}, error) { return NewAthletesService(c).ListKOMs(1).Do() },
+		"athletes.activities":       func(c *Client) (interface{}, error) { return NewAthletesService(c).ListActivities(1).Do() },
+		"clubs.get":                 func(c *Client) (interface{}, error) { return NewClubsService(c).Get(1).Do() },
+		"clubs.members":             func(c *Client) (interface{}, error) { return NewClubsService(c).ListMembers(1).Do() },
+		"clubs.activities":          func(c *Client) (interface{}, error) { return NewClubsService(c).ListActivities(1).Do() },
+		"gear.get":                  func(c *Client) (interface{}, error) { return NewGearService(c).Get("b1").Do() },
+		"segment_efforts.get":       func(c *Client) (interface{}, error) { return NewSegmentEffortsService(c).Get(1).Do() },
+		"segments.get":              func(c *Client) (interface{}, error) { return NewSegmentsService(c).Get(1).Do() },
+		"segments.efforts":          func(c *Client) (interface{}, error) { return NewSegmentsService(c).ListEfforts(1).Do() },
+		"segments.leaderboard":      func(c *Client) (interface{}, error) { return NewSegmentsService(c).GetLeaderboard(1).Do() },
+		"segments.explore":          func(c *Client) (interface{}, error) { return NewSegmentsService(c).Explore(1, 2, 3, 4).Do() },
+		"uploads.get":               func(c *Client) (interface{}, error) { return NewUploadsService(c).Get(1).Do() },
+	}
+
+	for _, body := range []string{"", "[]"} {
+		for _, rawJSON := range []bool{false, true} {
+			client := NewClient(newStubTokenSource())
+			client.httpClient = &http.Client{Transport: &contentTransport{body}}
+			client.rawJSON = rawJSON
+
+			for name, call := range calls {
+				result, err := call(client)
+				if err != nil {
+					t.Errorf("%s %q: empty response should not fail, got %v", name, body, err)
+					continue
+				}
+
+				if v := reflect.ValueOf(result); v.IsNil() {
+					t.Errorf("%s %q: empty result should not be nil", name, body)
+				}
+			}
+		}
+	}
+}
+
+func TestEmptyResponseFlag(t *testing.T) {
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: &contentTransport{"[]"}}
+
+	var resp Response
+	athlete, err := NewAthletesService(client).Get(1).Response(&resp).Do()
+	if err != nil || athlete == nil || athlete.Id != 0 {
+		t.Fatalf("zero athlete expected, got %v %v", athlete, err)
+	}
+
+	if !resp.Empty {
+		t.Error("response should be flagged empty")
+	}
+
+	client.httpClient = &http.Client{Transport: &contentTransport{`{"id":1}`}}
+	NewAthletesService(client).Get(1).Response(&resp).Do()
+
+	if resp.Empty {
+		t.Error("response should not be flagged empty")
+	}
+}
+
+// contentTransport answers every request with the content.
+type contentTransport struct {
+	content string
+}
+
+func (t *contentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(t.content)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package strava
+
+import (
+	"strings"
+)
+
+// A Device is the recording device of an activity, with its name split into manufacturer and model.
+type Device struct {
+	Manufacturer string
+	Model        string
+}
+
+func (d Device) String() string {
+	return strings.TrimSpace(d.Manufacturer + " " + d.Model)
+}
+
+// deviceManufacturers are the manufacturers as they are written in device names,
+// lower case name to preferred spelling.
+var deviceManufacturers = map[string]string{
+	"apple":      "Apple",
+	"bryton":     "Bryton",
+	"coros":      "COROS",
+	"fitbit":     "Fitbit",
+	"garmin":     "Garmin",
+	"hammerhead": "Hammerhead",
+	"lezyne":     "Lezyne",
+	"polar":      "Polar",
+	"samsung":    "Samsung",
+	"sigma":      "Sigma",
+	"stages":     "Stages",
+	"strava":     "Strava",
+	"suunto":     "Suunto",
+	"wahoo":      "Wahoo",
+	"zwift":      "Zwift",
+}
+
+// deviceModels are the model names that are also used without the manufacturer,
+// lower case model prefix to manufacturer and preferred spelling.
+var deviceModels = []struct {
+	prefix       string
+	manufacturer string
+	model        string
+}{
+	{"edge", "Garmin", "Edge"},
+	{"forerunner", "Garmin", "Forerunner"},
+	{"fenix", "Garmin", "fenix"},
+	{"fēnix", "Garmin", "fenix"},
+	{"epix", "Garmin", "epix"},
+	{"venu", "Garmin", "Venu"},
+	{"elemnt", "Wahoo", "ELEMNT"},
+	{"kickr", "Wahoo", "KICKR"},
+	{"karoo", "Hammerhead", "Karoo"},
+	{"watch", "Apple", "Watch"},
+}
+
+// ParseDevice splits a device name, as found in ActivityDetailed.DeviceName, into manufacturer and model,
+// normalizing the different spellings of the same device, e.g. "Garmin fēnix 6" and "fenix 6"
+// both become Garmin "fenix 6", and "Wahoo Elemnt Bolt" becomes Wahoo "ELEMNT Bolt".
+// Unknown names are returned as the model, without manufacturer.
+func ParseDevice(name string) Device {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return Device{}
+	}
+
+	var device Device
+	if manufacturer, ok := deviceManufacturers[strings.ToLower(words[0])]; ok {
+		device.Manufacturer = manufacturer
+		words = words[1:]
+	}
+
+	if len(words) > 0 {
+		first := strings.ToLower(words[0])
+		for _, m := range deviceModels {
+			if first != m.prefix || (device.Manufacturer != "" && device.Manufacturer != m.manufacturer) {
+				continue
+			}
+
+			device.Manufacturer = m.manufacturer
+			words[0] = m.model
+			break
+		}
+	}
+
+	device.Model = strings.Join(words, " ")
+	return device
+}
+
+// Device returns the parsed DeviceName of the activity.
+func (a *ActivityDetailed) Device() Device {
+	return ParseDevice(a.DeviceName)
+}
+
+// Equipment is the recording device and the gear of an activity, for equipment analytics.
+type Equipment struct {
+	Device Device
+	Gear   *GearSummary // nil if the activity has no gear
+}
+
+// String returns the device and the name of the gear, e.g. "Garmin Edge 530, Canyon Aeroad".
+func (e Equipment) String() string {
+	var parts []string
+	if device := e.Device.String(); device != "" {
+		parts = append(parts, device)
+	}
+
+	if e.Gear != nil && e.Gear.Name != "" {
+		parts = append(parts, e.Gear.Name)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Equipment returns the parsed device and the gear of the activity.
+func (a *ActivityDetailed) Equipment() Equipment {
+	equipment := Equipment{Device: a.Device()}
+	if a.Gear.Id != "" {
+		gear := a.Gear
+		equipment.Gear = &gear
+	}
+
+	return equipment
+}
@@ -0,0 +1,49 @@
+package strava
+
+import (
+	"testing"
+)
+
+func TestParseDevice(t *testing.T) {
+	cases := map[string]Device{
+		"Garmin Edge 530":      {"Garmin", "Edge 530"},
+		"garmin  fēnix 6X Pro": {"Garmin", "fenix 6X Pro"},
+		"Forerunner 945":       {"Garmin", "Forerunner 945"},
+		"Wahoo Elemnt Bolt":    {"Wahoo", "ELEMNT Bolt"},
+		"ELEMNT ROAM":          {"Wahoo", "ELEMNT ROAM"},
+		"Hammerhead Karoo 2":   {"Hammerhead", "Karoo 2"},
+		"Apple Watch Series 6": {"Apple", "Watch Series 6"},
+		"Strava iPhone App":    {"Strava", "iPhone App"},
+		"Zwift":                {"Zwift", ""},
+		"Some Bike Computer":   {"", "Some Bike Computer"},
+		"":                     {},
+	}
+
+	for name, expected := range cases {
+		if d := ParseDevice(name); d != expected {
+			t.Errorf("incorrect device for %q, got %+v", name, d)
+		}
+	}
+
+	activity := &ActivityDetailed{DeviceName: "Garmin Edge 1030"}
+	if d := activity.Device(); d.String() != "Garmin Edge 1030" {
+		t.Errorf("incorrect device, got %v", d)
+	}
+}
+
+func TestActivityEquipment(t *testing.T) {
+	activity := &ActivityDetailed{DeviceName: "wahoo elemnt BOLT"}
+	if e := activity.Equipment(); e.Gear != nil || e.String() != "Wahoo ELEMNT BOLT" {
+		t.Errorf("activities without gear should only have a device, got %v", e)
+	}
+
+	activity.Gear = GearSummary{Id: "b1", Name: "Canyon Aeroad", Distance: 1200000}
+	e := activity.Equipment()
+	if e.Gear == nil || e.Gear.Id != "b1" || e.String() != "Wahoo ELEMNT BOLT, Canyon Aeroad" {
+		t.Errorf("incorrect equipment, got %v", e)
+	}
+
+	if e := (&ActivityDetailed{Gear: GearSummary{Id: "g2", Name: "Pegasus"}}).Equipment(); e.String() != "Pegasus" {
+		t.Errorf("incorrect equipment without device, got %v", e)
+	}
+}
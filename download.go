@@ -0,0 +1,170 @@
+package strava
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadPolicy tells what a download does when its file already exists, see RoutesExportCall.Download.
+// Interrupted downloads are always resumed, whatever the policy.
+type DownloadPolicy string
+
+var DownloadPolicies = struct {
+	Skip      DownloadPolicy // keep the existing file, without a request
+	Overwrite DownloadPolicy // download the file again and replace it
+	Fail      DownloadPolicy // fail with an error wrapping os.ErrExist
+}{"skip", "overwrite", "fail"}
+
+// ErrIncompleteDownload is wrapped by the error of a download that ended before the whole file was received,
+// or whose file doesn't match the expected checksum. Downloading again resumes an incomplete file.
+var ErrIncompleteDownload = errors.New("incomplete download")
+
+// A DownloadedFile is the result of a download.
+type DownloadedFile struct {
+	Path    string
+	Size    int64
+	SHA256  string // hex encoded checksum of the file
+	Resumed bool   // the download continued an interrupted one
+	Skipped bool   // the file existed and was kept, see DownloadPolicies.Skip
+}
+
+// partSuffix is appended to the name of a file while it is downloaded.
+const partSuffix = ".part"
+
+// download writes the body of a response to the file of a download, see doRequest.
+type download struct {
+	part   *os.File
+	offset int64 // bytes of the file received before
+	total  int64 // size of the file, -1 if unknown
+}
+
+// downloader is implemented by the values of requests whose body is written while it is read,
+// instead of decoded.
+type downloader interface {
+	download(resp *http.Response) error
+}
+
+func (d *download) download(resp *http.Response) error {
+	d.total = -1
+	if resp.StatusCode == http.StatusPartialContent {
+		// Content-Range: bytes 100-999/1000
+		if i := strings.LastIndex(resp.Header.Get("Content-Range"), "/"); i >= 0 {
+			if total, err := strconv.ParseInt(resp.Header.Get("Content-Range")[i+1:], 10, 64); err == nil {
+				d.total = total
+			}
+		}
+	} else {
+		// the range was ignored, the whole file is sent
+		d.offset = 0
+		if resp.ContentLength >= 0 {
+			d.total = resp.ContentLength
+		}
+	}
+
+	if err := d.part.Truncate(d.offset); err != nil {
+		return err
+	}
+
+	if _, err := d.part.Seek(d.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(d.part, resp.Body)
+	return err
+}
+
+// downloadFile downloads the GET request of the path, relative to the base url, to the file, resuming
+// the part of an interrupted download, and checks its size and, if not empty, its SHA-256 checksum.
+func (client *Client) downloadFile(path, filename string, policy DownloadPolicy, checksum string, errorHandler ErrorHandler, response *Response, timeout time.Duration, header http.Header) (*DownloadedFile, error) {
+	if info, err := os.Stat(filename); err == nil {
+		switch policy {
+		case DownloadPolicies.Skip:
+			return &DownloadedFile{Path: filename, Size: info.Size(), Skipped: true}, nil
+		case DownloadPolicies.Fail:
+			return nil, fmt.Errorf("%s: %w", filename, os.ErrExist)
+		}
+	}
+
+	part, err := os.OpenFile(filename+partSuffix, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	info, err := part.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &download{part: part, offset: info.Size()}
+	err = client.requestRange(path, d, errorHandler, response, timeout, header)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// the part can't be resumed, e.g. the file changed, download it again
+		d.offset = 0
+		err = client.requestRange(path, d, errorHandler, response, timeout, header)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	file := &DownloadedFile{Path: filename, Resumed: d.offset > 0}
+	if file.Size, file.SHA256, err = fileChecksum(part); err != nil {
+		return nil, err
+	}
+
+	if d.total >= 0 && file.Size != d.total {
+		return nil, fmt.Errorf("%w: received %d of %d bytes", ErrIncompleteDownload, file.Size, d.total)
+	}
+
+	if checksum != "" && !strings.EqualFold(checksum, file.SHA256) {
+		// the part is corrupt, start over next time
+		part.Truncate(0)
+		return nil, fmt.Errorf("%w: checksum %s, expected %s", ErrIncompleteDownload, file.SHA256, checksum)
+	}
+
+	if err := part.Close(); err != nil {
+		return nil, err
+	}
+
+	return file, os.Rename(filename+partSuffix, filename)
+}
+
+// requestRange requests the part of the file the download misses.
+func (client *Client) requestRange(path string, d *download, errorHandler ErrorHandler, response *Response, timeout time.Duration, header http.Header) error {
+	ctx, cancel := client.callContext(timeout)
+	defer cancel()
+
+	req, err := client.newRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+	addHeader(req, header)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.offset))
+	// the offsets of a gzip encoded range are of the compressed file, so the file isn't compressed
+	req.Header.Set("Accept-Encoding", "identity")
+
+	_, err = client.execute(req, errorHandler, response, d)
+	return err
+}
+
+// fileChecksum returns the size and hex encoded SHA-256 checksum of the file.
+func fileChecksum(file *os.File) (int64, string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, "", err
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	return size, hex.EncodeToString(h.Sum(nil)), err
+}
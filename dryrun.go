@@ -0,0 +1,52 @@
+package strava
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// WithDryRun makes the write calls of the client, the POST, PUT and DELETE requests such as activity updates,
+// uploads and deauthorizations, succeed without being sent, e.g. to test a sync pipeline with production tokens.
+// The calls are still checked, for the token and its scopes, logged and dumped with the body that would
+// have been sent, see Client.Dump. They return models with only the id of the path, if any, and a Response
+// with DryRun set. Reads are sent as usual.
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.dryRun = true
+	}
+}
+
+// dryRunning returns if the request is a write not to be sent.
+func (client *Client) dryRunning(method string) bool {
+	return client.dryRun && method != "GET" && method != "HEAD"
+}
+
+// doDryRun answers the write request without sending it, decoding the synthetic response into v, if not nil.
+func (client *Client) doDryRun(req *http.Request, record *callRecord, v interface{}) ([]byte, error) {
+	client.dump.dumpRequest(req)
+	client.logf("strava: dry run %s %s, not sent", req.Method, req.URL)
+
+	if record.response != nil {
+		record.response.StatusCode = http.StatusOK
+		record.response.DryRun = true
+	}
+
+	data := dryRunResponse(req.URL.Path)
+	if v == nil {
+		return data, nil
+	}
+
+	return data, json.Unmarshal(data, v)
+}
+
+// dryRunResponse returns the synthetic response to a write to the path, an object with the id
+// the path ends with, e.g. {"id":123} for /activities/123, or an empty object.
+func dryRunResponse(urlPath string) []byte {
+	if id, err := strconv.ParseInt(path.Base(urlPath), 10, 64); err == nil {
+		return []byte(`{"id":` + strconv.FormatInt(id, 10) + `}`)
+	}
+
+	return []byte(`{}`)
+}
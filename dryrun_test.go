@@ -0,0 +1,69 @@
+package strava
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClientWithDryRun(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{"/api/v3/activities/123": `{"id":123,"name":"Morning Run"}`})
+	WithDryRun()(client)
+
+	var dump bytes.Buffer
+	client.Dump(&dump)
+
+	var response Response
+	activity, err := NewActivitiesService(client).Update(123).Name("Evening Run").Response(&response).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 0 {
+		t.Fatalf("write should not be sent, got %d requests", len(transport.requests))
+	}
+
+	if activity.Id != 123 || !response.DryRun || response.StatusCode != 200 {
+		t.Errorf("incorrect synthetic result, got %+v and %+v", activity, response)
+	}
+
+	if !strings.Contains(dump.String(), "> PUT") || !strings.Contains(dump.String(), "name=Evening+Run") {
+		t.Errorf("write should be dumped, got %s", dump.String())
+	}
+
+	if _, err := NewUploadsService(client).Create(FileDataTypes.GPX, "run.gpx", strings.NewReader("<gpx/>")).Do(); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+
+	if err := NewOAuthService(client).Deauthorize().Do(); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 0 {
+		t.Errorf("writes should not be sent, got %d requests", len(transport.requests))
+	}
+
+	// reads are sent
+	activity, err = NewActivitiesService(client).Get(123).Response(&response).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 1 || activity.Name != "Morning Run" || response.DryRun {
+		t.Errorf("read should be sent, got %d requests", len(transport.requests))
+	}
+
+	// writes are still checked
+	WithScopeCheck(GrantedScopes{ScopeRead})(client)
+	_, err = NewActivitiesService(client).Update(123).Name("Evening Run").Do()
+
+	var missing *ErrMissingScope
+	if !errors.As(err, &missing) {
+		t.Errorf("write should need its scope, got %v", err)
+	}
+
+	if !client.ClientOptionsSnapshot().DryRun {
+		t.Error("dry run should be reported")
+	}
+}
@@ -0,0 +1,166 @@
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithDump makes the client dump every request and its response to w, see Client.Dump.
+func WithDump(w io.Writer) Option {
+	return func(c *Client) {
+		c.dump.setWriter(w)
+	}
+}
+
+// Dump makes the client write every request and its response to w, with their headers and pretty printed
+// json bodies, e.g. to find out why Strava rejects a request. Tokens, codes and email addresses are redacted.
+// Passing nil stops dumping, which is the default. Dumping can be turned on and off while calls are made.
+func (client *Client) Dump(w io.Writer) *Client {
+	client.dump.setWriter(w)
+	return client
+}
+
+// dumper writes the dumps of a client, one at a time.
+type dumper struct {
+	lock      sync.Mutex
+	w         io.Writer
+	redaction *redaction // of the client, see WithRedaction
+}
+
+func (d *dumper) setWriter(w io.Writer) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.w = w
+}
+
+func (d *dumper) setRedaction(r *redaction) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.redaction = r
+}
+
+func (d *dumper) writer() io.Writer {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.w
+}
+
+func (d *dumper) enabled() bool {
+	return d.writer() != nil
+}
+
+// write writes the redacted dump, if dumping is on.
+func (d *dumper) write(dump string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.w != nil {
+		io.WriteString(d.w, d.redaction.redact(dump))
+	}
+}
+
+/*********************************************************/
+
+// dumpRequest dumps the request, leaving its body to be sent.
+func (d *dumper) dumpRequest(req *http.Request) {
+	if !d.enabled() {
+		return
+	}
+
+	var body []byte
+	if req.Body != nil && req.GetBody != nil {
+		if b, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(b)
+			b.Close()
+		}
+	}
+
+	var dump strings.Builder
+	fmt.Fprintf(&dump, "> %s %s\n", req.Method, req.URL)
+	writeDumpHeader(&dump, "> ", req.Header)
+	writeDumpBody(&dump, req.Header, body)
+
+	d.write(dump.String())
+}
+
+// dumpResponse dumps the response to the request, reading its body and leaving it to be read again.
+func (d *dumper) dumpResponse(req *http.Request, resp *http.Response) {
+	if !d.enabled() {
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errorReader{err}))
+
+	var dump strings.Builder
+	fmt.Fprintf(&dump, "< %d %s %s\n", resp.StatusCode, req.Method, req.URL)
+	writeDumpHeader(&dump, "< ", resp.Header)
+	writeDumpBody(&dump, resp.Header, body)
+
+	d.write(dump.String())
+}
+
+// dumpError dumps the error of a request that got no response.
+func (d *dumper) dumpError(req *http.Request, err error) {
+	if d.enabled() {
+		d.write(fmt.Sprintf("< %s %s failed: %v\n\n", req.Method, req.URL, err))
+	}
+}
+
+// writeDumpHeader writes the header sorted by key, each line prefixed.
+func writeDumpHeader(dump *strings.Builder, prefix string, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(dump, "%s%s: %s\n", prefix, key, value)
+		}
+	}
+}
+
+// writeDumpBody writes json bodies indented and text bodies as they are, only the length of others, e.g. uploaded files.
+func writeDumpBody(dump *strings.Builder, header http.Header, body []byte) {
+	dump.WriteString("\n")
+	if len(body) == 0 {
+		return
+	}
+
+	contentType := header.Get("Content-Type")
+	var indented bytes.Buffer
+	switch {
+	case json.Indent(&indented, body, "", "  ") == nil:
+		dump.Write(indented.Bytes())
+	case contentType == "" || strings.HasPrefix(contentType, "text/") || strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		dump.Write(body)
+	default:
+		fmt.Fprintf(dump, "[%d bytes of %s]", len(body), contentType)
+	}
+
+	dump.WriteString("\n\n")
+}
+
+// errorReader returns the error that ended reading a dumped body, if any.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return 0, io.EOF
+}
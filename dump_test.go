@@ -0,0 +1,64 @@
+package strava
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestClientDump(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athlete":               `{"id":1,"firstname":"Jane","email":"jane@example.com"}`,
+		"/api/v3/activities/1/comments": `{"id":2,"text":"nice"}`,
+	})
+
+	var dump bytes.Buffer
+	WithDump(&dump)(client)
+
+	athlete, err := NewCurrentAthleteService(client).Get().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.Id != 1 || athlete.FirstName != "Jane" {
+		t.Errorf("response should be decoded after the dump, got %+v", athlete)
+	}
+
+	for _, expected := range []string{
+		"> GET " + basePath + "/athlete?\n",
+		"> Authorization: Bearer [REDACTED]\n",
+		"< 200 GET " + basePath + "/athlete?\n",
+		"{\n  \"id\": 1,\n  \"firstname\": \"Jane\",\n  \"email\": \"[REDACTED]\"\n}",
+	} {
+		if !strings.Contains(dump.String(), expected) {
+			t.Errorf("dump should contain %q, got %s", expected, dump.String())
+		}
+	}
+
+	if strings.Contains(dump.String(), "token") || strings.Contains(dump.String(), "jane@example.com") {
+		t.Errorf("dump should be redacted, got %s", dump.String())
+	}
+
+	dump.Reset()
+	if _, err := NewActivityCommentsService(client, 1).Create("nice").Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !strings.Contains(dump.String(), "\ntext=nice\n") {
+		t.Errorf("dump should contain the form, got %s", dump.String())
+	}
+
+	dump.Reset()
+	client.Dump(nil)
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if dump.Len() != 0 {
+		t.Errorf("dumping should be off, got %s", dump.String())
+	}
+
+	if client.ClientOptionsSnapshot().Dump {
+		t.Error("options should show dumping is off")
+	}
+}
@@ -6,7 +6,8 @@ import (
 
 // EffortSummary is the base object for BestEfforts, SegmentEfforts and LapEfforts
 type EffortSummary struct {
-	Id       int64  `json:"id"`
+	RawJSON
+	Id       int64  `json:"id" strava:"required"`
 	Name     string `json:"name"`
 	Activity struct {
 		Id int64 `json:"id"`
@@ -14,7 +15,7 @@ type EffortSummary struct {
 	Athlete struct {
 		Id int64 `json:"id"`
 	} `json:"athlete"`
-	Distance       float64   `json:"distance"`
+	Distance       Distance  `json:"distance"`
 	MovingTime     int       `json:"moving_time"`
 	ElapsedTime    int       `json:"elapsed_time"`
 	StartIndex     int       `json:"start_index"`
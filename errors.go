@@ -2,6 +2,12 @@ package strava
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
 )
 
 type Error struct {
@@ -17,7 +23,161 @@ type ErrorDetailed struct {
 
 func (e Error) Error() string {
 	b, _ := json.Marshal(e)
-	return string(b)
+	return Redact(string(b))
+}
+
+// An APIError is returned by the default ErrorHandler for failed calls. It keeps the response,
+// for logging and for errors this package doesn't know about. It wraps the decoded Error of Strava,
+// if the body is one, and the status error, such as ErrNotFound, so both errors.As(err, &strava.Error{})
+// and errors.Is(err, strava.ErrNotFound) work on returned errors.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string // of the request, e.g. /api/v3/activities/123
+	Body       []byte // of the response
+	Payload    *Error // decoded from the body, nil if it is not an error of Strava
+
+	RetryAfter time.Duration // of 503 responses, from the Retry-After header, see ErrMaintenance
+}
+
+// newAPIError returns the error of the response, received at now.
+func newAPIError(resp *http.Response, now time.Time) *APIError {
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(resp.Body)
+	}
+
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		e.RetryAfter = retryAfter(resp.Header, now)
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+
+	var payload Error
+	if json.Unmarshal(body, &payload) == nil && (payload.Message != "" || payload.Errors != nil) {
+		e.Payload = &payload
+	}
+
+	return e
+}
+
+// Error returns the error of Strava, or the status of the response if there is none.
+func (e *APIError) Error() string {
+	if e.Payload != nil {
+		return e.Payload.Error()
+	}
+
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *APIError) Unwrap() []error {
+	var errs []error
+	if e.Payload != nil {
+		errs = append(errs, *e.Payload)
+	}
+
+	if statusErr := statusError(e.StatusCode); statusErr != nil {
+		errs = append(errs, statusErr)
+	}
+
+	if e.StatusCode == http.StatusServiceUnavailable {
+		errs = append(errs, &ErrMaintenance{RetryAfter: e.RetryAfter})
+	}
+
+	return errs
+}
+
+// Errors wrapped by the APIError of common failures, check for them with errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized") // 401, the token is invalid or was revoked
+	ErrForbidden    = errors.New("forbidden")    // 403, e.g. a scope is missing or the object is private
+	ErrNotFound     = errors.New("not found")    // 404
+	ErrRateLimited  = errors.New("rate limited") // 429, see RateLimiting
+	ErrServerError  = errors.New("server error") // 5xx
+)
+
+// ErrTokenRevoked is wrapped by the error of a token refresh rejected because of the refresh token,
+// when it was revoked or rotated by another process that didn't save the new tokens in the TokenSource.
+var ErrTokenRevoked = errors.New("token revoked")
+
+// ErrNoTokenSource is returned by the calls of clients created without a TokenSource, such as the base client
+// of a ClientFactory.
+var ErrNoTokenSource = errors.New("no token source")
+
+// ErrReauthorizationRequired is wrapped, along with ErrTokenRevoked, by the errors of calls whose token can't
+// be refreshed any more, when Strava rejects the refresh token with invalid_grant, e.g. because the athlete
+// revoked the access of the application. Retrying doesn't help, the athlete has to connect the application again.
+var ErrReauthorizationRequired = errors.New("reauthorization required")
+
+// ErrMaintenance is wrapped by the APIError of a 503 response, returned while Strava is down for maintenance.
+// RetryAfter is how long Strava asks clients to wait, zero if it didn't say. See WithMaintenanceRetry.
+type ErrMaintenance struct {
+	RetryAfter time.Duration
+}
+
+func (e *ErrMaintenance) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("under maintenance, retry after %v", e.RetryAfter)
+	}
+
+	return "under maintenance"
+}
+
+// retryAfter returns the wait of the Retry-After header, in seconds or an http date, zero if there is none.
+// Dates are relative to the Date header of the response, if any, so clocks that are off don't matter.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		now = date
+	}
+
+	if wait := at.Sub(now); wait > 0 {
+		return wait
+	}
+
+	return 0
+}
+
+// statusError returns the error of the status code, nil if there is none.
+func statusError(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode/100 == 5:
+		return ErrServerError
+	}
+
+	return nil
 }
 
 // returned during oauth if there was a user caused problem
@@ -36,3 +196,26 @@ var (
 	OAuthInvalidCodeErr         = &OAuthError{"unrecognized code"}
 	OAuthServerErr              = &OAuthError{"server error"}
 )
+
+// A ValidationError is returned when a call is rejected before it is sent to Strava,
+// because one of its parameters is missing or doesn't make sense for the activity type.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// wrapError adds the failed operation to err, so logs show which call failed,
+// for example "strava: activities.get id=123: ...". The original error stays in
+// the chain and can still be inspected with errors.Is and errors.As. The message is redacted,
+// see Redact. Returns nil if err is nil.
+func wrapError(err error, op string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return redactError(fmt.Errorf("strava: %s: %w", fmt.Sprintf(op, args...), err))
+}
@@ -0,0 +1,97 @@
+package strava
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestDefaultErrorHandlerStatusErrors(t *testing.T) {
+	statuses := map[int]error{
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: ErrServerError,
+		http.StatusServiceUnavailable:  ErrServerError,
+	}
+
+	for status, expected := range statuses {
+		client := NewStubResponseClient(`{"message":"failed","errors":[]}`, status)
+		client.tokenSource = newStubTokenSource()
+
+		_, err := NewClubsService(client).Get(1).Do()
+		if !errors.Is(err, expected) {
+			t.Errorf("%d: should return %v, got %v", status, expected, err)
+		}
+
+		for _, other := range statuses {
+			if other != expected && errors.Is(err, other) {
+				t.Errorf("%d: should not be %v", status, other)
+			}
+		}
+
+		if status/100 == 4 && !errors.As(err, &Error{}) {
+			t.Errorf("%d: should still return the strava error, got %v", status, err)
+		}
+	}
+
+	// other errors have no status error
+	client := NewStubResponseClient(`{"message":"Bad Request","errors":[]}`, http.StatusBadRequest)
+	client.tokenSource = newStubTokenSource()
+
+	_, err := NewClubsService(client).Get(1).Do()
+	for _, statusErr := range statuses {
+		if errors.Is(err, statusErr) {
+			t.Errorf("bad request should not be %v", statusErr)
+		}
+	}
+
+	// responses that are not json
+	client = NewStubResponseClient(`<html>Not Found</html>`, http.StatusNotFound)
+	client.tokenSource = newStubTokenSource()
+
+	if _, err := NewClubsService(client).Get(1).Do(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("should return not found, got %v", err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{})
+
+	_, err := NewClubsService(client).Get(1).Do()
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("should return an api error, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Method != "GET" || apiErr.Path != "/api/v3/clubs/1" {
+		t.Errorf("incorrect api error, got %+v", apiErr)
+	}
+
+	if string(apiErr.Body) != `{"message":"Record Not Found","errors":[]}` || apiErr.Payload == nil || apiErr.Payload.Message != "Record Not Found" {
+		t.Errorf("incorrect body, got %s", apiErr.Body)
+	}
+
+	if err.Error() != `strava: clubs.get id=1: {"message":"Record Not Found","errors":[]}` {
+		t.Errorf("incorrect message, got %v", err)
+	}
+
+	// responses that are not errors of Strava
+	client = NewStubResponseClient(`<html>Bad Gateway</html>`, http.StatusBadGateway)
+	client.tokenSource = newStubTokenSource()
+
+	_, err = NewClubsService(client).Get(1).Do()
+	if !errors.As(err, &apiErr) || apiErr.Payload != nil || string(apiErr.Body) != `<html>Bad Gateway</html>` {
+		t.Errorf("should return an api error without payload, got %v", err)
+	}
+
+	if errors.As(err, &Error{}) {
+		t.Error("should not be an error of Strava")
+	}
+
+	if err.Error() != "strava: clubs.get id=1: GET /api/v3/clubs/1: 502 Bad Gateway" {
+		t.Errorf("incorrect message, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package strava
+
+import (
+	"net/http"
+	"sync"
+)
+
+// An ETagStore keeps the ETags and bodies of responses, so a client created WithETags can make
+// conditional requests and reuse the stored body when Strava answers 304 Not Modified.
+// Stores are used from several goroutines when clients are.
+type ETagStore interface {
+	// GetETag returns the ETag and body stored for the key, ok is false if there are none.
+	GetETag(key string) (etag string, body []byte, ok bool)
+	SetETag(key string, etag string, body []byte)
+}
+
+// WithETags makes the client store the ETags of GET responses in the store and send them with
+// If-None-Match when requesting the same url again, returning the stored result if it didn't change.
+// This saves latency and, as far as Strava doesn't count not modified responses, rate limit quota
+// for data that rarely changes, such as segments and gear. Responses are stored per athlete,
+// or per access token if the AuthorizationResponse of the TokenSource doesn't hold the athlete,
+// so stores can be shared by clients.
+func WithETags(store ETagStore) Option {
+	return func(c *Client) {
+		c.etags = store
+	}
+}
+
+// MemoryETagStore is an ETagStore keeping the ETags in memory, without a limit.
+type MemoryETagStore struct {
+	lock    sync.RWMutex
+	entries map[string]etagEntry
+}
+
+type etagEntry struct {
+	etag string
+	body []byte
+}
+
+func NewMemoryETagStore() *MemoryETagStore {
+	return &MemoryETagStore{entries: make(map[string]etagEntry)}
+}
+
+func (s *MemoryETagStore) GetETag(key string) (string, []byte, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	entry, ok := s.entries[key]
+	return entry.etag, entry.body, ok
+}
+
+func (s *MemoryETagStore) SetETag(key string, etag string, body []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.entries[key] = etagEntry{etag, body}
+}
+
+/*********************************************************/
+
+// conditional makes a GET request conditional on the stored ETag of its url, if any.
+// It returns the key of the request in the ETagStore, empty if the request is not stored,
+// and the stored body.
+func (client *Client) conditional(req *http.Request, auth *AuthorizationResponse) (string, []byte) {
+	if client.etags == nil {
+		return "", nil
+	}
+
+	key := storeKey(req, auth)
+	if key == "" {
+		return "", nil
+	}
+
+	etag, body, ok := client.etags.GetETag(key)
+	if !ok {
+		return key, nil
+	}
+
+	req.Header.Set("If-None-Match", etag)
+	return key, body
+}
+
+// storeETag stores the ETag and data of the response, if it has one.
+func (client *Client) storeETag(key string, resp *http.Response, data []byte) {
+	if etag := resp.Header.Get("ETag"); key != "" && etag != "" {
+		client.etags.SetETag(key, etag, data)
+	}
+}
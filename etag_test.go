@@ -0,0 +1,104 @@
+package strava
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClientWithETags(t *testing.T) {
+	transport := &etagTransport{etag: `"v1"`, body: `{"id":1,"name":"Hill"}`}
+	store := NewMemoryETagStore()
+
+	client := NewClient(newStubTokenSource(), WithETags(store))
+	client.httpClient = &http.Client{Transport: transport}
+
+	segment, err := NewSegmentsService(client).Get(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if transport.ifNoneMatch != "" || segment.Name != "Hill" {
+		t.Errorf("first request should not be conditional, got %v %v", transport.ifNoneMatch, segment.Name)
+	}
+
+	// not modified
+	var resp Response
+	segment, err = NewSegmentsService(client).Get(1).Response(&resp).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if transport.ifNoneMatch != `"v1"` || segment.Name != "Hill" || resp.StatusCode != http.StatusNotModified {
+		t.Errorf("stored result should be returned, got %v %v %v", transport.ifNoneMatch, segment.Name, resp.StatusCode)
+	}
+
+	// modified
+	transport.etag, transport.body = `"v2"`, `{"id":1,"name":"Mountain"}`
+
+	segment, err = NewSegmentsService(client).Get(1).Do()
+	if err != nil || segment.Name != "Mountain" {
+		t.Fatalf("new result should be returned, got %v %v", segment, err)
+	}
+
+	req, _ := http.NewRequest("GET", transport.url, nil)
+	if etag, _, _ := store.GetETag(storeKey(req, client.tokenSource.(*stubTokenSource).response)); etag != `"v2"` {
+		t.Errorf("new etag should be stored, got %v", etag)
+	}
+
+	// other urls and methods are not conditional
+	NewSegmentsService(client).Get(2).Do()
+	if transport.ifNoneMatch != "" {
+		t.Errorf("request should not be conditional, got %v", transport.ifNoneMatch)
+	}
+
+	NewCurrentAthleteService(client).Update().City("Utrecht").Do()
+	if transport.ifNoneMatch != "" {
+		t.Errorf("request should not be conditional, got %v", transport.ifNoneMatch)
+	}
+}
+
+func TestClientWithETagsPerAthlete(t *testing.T) {
+	store := NewMemoryETagStore()
+
+	ts := newStubTokenSource()
+	ts.response.Athlete = &AthleteDetailed{}
+	ts.response.Athlete.Id = 42
+
+	client := NewClient(ts, WithETags(store))
+	client.httpClient = &http.Client{Transport: &etagTransport{etag: `"v1"`, body: `{"id":1}`}}
+
+	NewSegmentsService(client).Get(1).Do()
+
+	if _, _, ok := store.GetETag("42 " + basePath + "/segments/1?"); !ok {
+		t.Errorf("etag should be stored for the athlete, got %v", store.entries)
+	}
+}
+
+type etagTransport struct {
+	etag        string
+	body        string
+	url         string
+	ifNoneMatch string
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.url = req.URL.String()
+	t.ifNoneMatch = req.Header.Get("If-None-Match")
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    req,
+	}
+	resp.Header.Set("ETag", t.etag)
+
+	if t.ifNoneMatch == t.etag {
+		resp.StatusCode = http.StatusNotModified
+		resp.Body = io.NopCloser(strings.NewReader(""))
+	}
+
+	return resp, nil
+}
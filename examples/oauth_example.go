@@ -1,3 +1,5 @@
+//go:build ignore
+
 // oauth_example.go provides a simple example implementing Strava OAuth
 // using the go.strava library.
 //
@@ -5,7 +7,7 @@
 //
 //	> go get github.com/strava/go.strava
 //	> cd $GOPATH/github.com/strava/go.strava/examples
-//	> go run oauth_example.go -id=youappsid -secret=yourappsecret
+//	> go run oauth_example.go tokensource.go -id=youappsid -secret=yourappsecret
 //
 //	Visit http://localhost:8080 in your webbrowser
 //
@@ -28,14 +30,16 @@ func main() {
 	// setup the credentials for your app
 	// These need to be set to reflect your application
 	// and can be found at https://www.strava.com/settings/api
-	flag.IntVar(&strava.ClientId, "id", 0, "Strava Client ID")
-	flag.StringVar(&strava.ClientSecret, "secret", "", "Strava Client Secret")
+	var clientId int
+	var clientSecret string
+	flag.IntVar(&clientId, "id", 0, "Strava Client ID")
+	flag.StringVar(&clientSecret, "secret", "", "Strava Client Secret")
 
 	flag.Parse()
 
-	if strava.ClientId == 0 || strava.ClientSecret == "" {
+	if clientId == 0 || clientSecret == "" {
 		fmt.Println("\nPlease provide your application's client_id and client_secret.")
-		fmt.Println("For example: go run oauth_example.go -id=9 -secret=longrandomsecret")
+		fmt.Println("For example: go run oauth_example.go tokensource.go -id=9 -secret=longrandomsecret")
 		fmt.Println(" ")
 
 		flag.PrintDefaults()
@@ -46,12 +50,14 @@ func main() {
 	// The callback url is used to generate an AuthorizationURL.
 	// The requestClientGenerator can be used to generate an http.RequestClient.
 	// This is usually when running on the Google App Engine platform.
-	authenticator, err := strava.NewOAuthAuthenticator(&StaticTokenSource{}, fmt.Sprintf("http://localhost:%d/exchange_token", port))
+	var err error
+	authenticator, err = strava.NewOAuthAuthenticator(&StaticTokenSource{}, fmt.Sprintf("http://localhost:%d/exchange_token", port))
 	if err != nil {
 		// possibly that the callback url set above is invalid
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	authenticator.SetCredentials(clientId, clientSecret)
 
 	http.HandleFunc("/", indexHandler)
 
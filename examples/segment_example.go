@@ -1,3 +1,5 @@
+//go:build ignore
+
 // segment_example.go provides a simple example to fetch a segment details
 // and list the top 10 on the leaderboard.
 //
@@ -5,7 +7,7 @@
 //
 //	> go get github.com/strava/go.strava
 //	> cd $GOPATH/github.com/strava/go.strava/examples
-//	> go run segment_example.go -id=segment_id -token=access_token
+//	> go run segment_example.go tokensource.go -id=segment_id -token=access_token
 //
 //	You can find an access_token for your app at https://www.strava.com/settings/api
 package main
@@ -36,7 +38,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	client := strava.NewClient(StaticTokenSource{})
+	client := strava.NewClient(&StaticTokenSource{AccessToken: accessToken})
 
 	fmt.Printf("Fetching segment %d info...\n", segmentId)
 	segment, err := strava.NewSegmentsService(client).Get(segmentId).Do()
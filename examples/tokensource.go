@@ -1,12 +1,17 @@
+//go:build ignore
+
 package main
 
 import "github.com/caselongo/strava-go"
 
-type StaticTokenSource struct{}
+// StaticTokenSource is the access token passed to an example, it is never refreshed.
+type StaticTokenSource struct {
+	AccessToken string
+}
 
 func (tokenSource *StaticTokenSource) GetAuthorizationResponse() (*strava.AuthorizationResponse, error) {
 	return &strava.AuthorizationResponse{
-		AccessToken: "YOUR STATIC ACCESSTOKEN",
+		AccessToken: tokenSource.AccessToken,
 	}, nil
 }
 
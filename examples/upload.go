@@ -1,10 +1,12 @@
+//go:build ignore
+
 // upload.go provides a simple example of uploading a GPX file to Strava
 //
 // usage:
 //
 //	> go get github.com/strava/go.strava
 //	> cd $GOPATH/github.com/strava/go.strava/examples
-//	> go run upload.go -token=access_token
+//	> go run upload.go tokensource.go -token=access_token
 //
 //	You will need an access token with 'write' permissions. You'll
 //	need to complete the oauth flow to get one of those.
@@ -14,6 +16,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -39,7 +42,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	client := strava.NewClient(StaticTokenSource{})
+	client := strava.NewClient(&StaticTokenSource{AccessToken: accessToken})
 	service := strava.NewUploadsService(client)
 
 	fmt.Printf("Uploading data...\n")
@@ -49,7 +52,8 @@ func main() {
 		Private().
 		Do()
 	if err != nil {
-		if e, ok := err.(strava.Error); ok && e.Message == "Authorization Error" {
+		var e strava.Error
+		if errors.As(err, &e) && e.Message == "Authorization Error" {
 			log.Printf("Make sure your token has 'write' permissions. You'll need implement the oauth process to get one")
 		}
 
@@ -58,14 +62,14 @@ func main() {
 
 	log.Printf("Upload Complete...")
 	jsonForDisplay, _ := json.Marshal(upload)
-	log.Printf(string(jsonForDisplay))
+	log.Println(string(jsonForDisplay))
 
 	log.Printf("Waiting a 5 seconds so the upload will finish (might not)")
 	time.Sleep(5 * time.Second)
 
 	uploadSummary, err := service.Get(upload.Id).Do()
 	jsonForDisplay, _ = json.Marshal(uploadSummary)
-	log.Printf(string(jsonForDisplay))
+	log.Println(string(jsonForDisplay))
 
 	log.Printf("Your new activity is id %d", uploadSummary.ActivityId)
 	log.Printf("You can view it at http://www.strava.com/activities/%d", uploadSummary.ActivityId)
@@ -0,0 +1,176 @@
+//go:build js && wasm
+
+// wasm/main.go shows how browser-side tooling can use the client for read-only flows,
+// with a short-lived access token handed over by the page and requests made with fetch.
+//
+// usage:
+//
+//	> cd examples/wasm
+//	> GOOS=js GOARCH=wasm go build -o strava.wasm .
+//	> cp "$(go env GOROOT)/misc/wasm/wasm_exec.js" .
+//
+// and from the page, after loading strava.wasm with wasm_exec.js:
+//
+//	const activities = await stravaListActivities(accessToken, expiresAt) // expiresAt in seconds, as Strava returns it
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"syscall/js"
+
+	"github.com/caselongo/strava-go"
+)
+
+// shortLivedTokenSource holds a token of the page. There is no client secret in the browser,
+// so the token is never refreshed, calls fail once it expired.
+type shortLivedTokenSource struct {
+	accessToken string
+	expiresAt   int64
+}
+
+func (s *shortLivedTokenSource) GetAuthorizationResponse() (*strava.AuthorizationResponse, error) {
+	return &strava.AuthorizationResponse{AccessToken: s.accessToken, ExpiresAt: s.expiresAt}, nil
+}
+
+func (s *shortLivedTokenSource) SaveAuthorizationResponse(string, *strava.AuthorizationResponse) error {
+	return errors.New("tokens are not saved in the browser")
+}
+
+// fetchTransport makes requests with the fetch api of the browser, in cors mode and without cookies.
+type fetchTransport struct{}
+
+func (fetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers := js.Global().Get("Headers").New()
+	for key, values := range req.Header {
+		// the browser sets these itself
+		if key == "User-Agent" || key == "Accept-Encoding" {
+			continue
+		}
+
+		for _, value := range values {
+			headers.Call("append", key, value)
+		}
+	}
+
+	options := js.Global().Get("Object").New()
+	options.Set("method", req.Method)
+	options.Set("headers", headers)
+	options.Set("mode", "cors")
+	options.Set("credentials", "omit")
+
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		array := js.Global().Get("Uint8Array").New(len(body))
+		js.CopyBytesToJS(array, body)
+		options.Set("body", array)
+	}
+
+	response, err := await(js.Global().Call("fetch", req.URL.String(), options))
+	if err != nil {
+		return nil, err
+	}
+
+	buffer, err := await(response.Call("arrayBuffer"))
+	if err != nil {
+		return nil, err
+	}
+
+	array := js.Global().Get("Uint8Array").New(buffer)
+	body := make([]byte, array.Get("length").Int())
+	js.CopyBytesToGo(body, array)
+
+	resp := &http.Response{
+		Status:        response.Get("statusText").String(),
+		StatusCode:    response.Get("status").Int(),
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+
+	// the rate limit headers are only readable if Strava exposes them to the page
+	iterator := response.Get("headers").Call("entries")
+	for {
+		next := iterator.Call("next")
+		if next.Get("done").Bool() {
+			break
+		}
+
+		entry := next.Get("value")
+		resp.Header.Add(entry.Index(0).String(), entry.Index(1).String())
+	}
+
+	return resp, nil
+}
+
+// await waits for the promise, which must not be awaited on the event loop goroutine.
+func await(promise js.Value) (js.Value, error) {
+	done := make(chan struct{})
+	var result js.Value
+	var err error
+
+	resolve := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		result = args[0]
+		close(done)
+		return nil
+	})
+	defer resolve.Release()
+
+	reject := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		err = errors.New(args[0].Call("toString").String())
+		close(done)
+		return nil
+	})
+	defer reject.Release()
+
+	promise.Call("then", resolve, reject)
+	<-done
+
+	return result, err
+}
+
+// listActivities is exposed to the page as stravaListActivities(accessToken, expiresAt),
+// returning a promise of the json of the latest activities of the athlete.
+func listActivities(this js.Value, args []js.Value) interface{} {
+	tokenSource := &shortLivedTokenSource{accessToken: args[0].String(), expiresAt: int64(args[1].Float())}
+
+	return js.Global().Get("Promise").New(js.FuncOf(func(this js.Value, promise []js.Value) interface{} {
+		resolve, reject := promise[0], promise[1]
+
+		// calls block on fetch, so they can't run on the event loop
+		go func() {
+			client := strava.NewClient(tokenSource, strava.WithHTTPClient(&http.Client{Transport: fetchTransport{}}))
+
+			activities, err := strava.NewCurrentAthleteService(client).ListActivities().PerPage(30).Do()
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+				return
+			}
+
+			data, err := json.Marshal(activities)
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+				return
+			}
+
+			resolve.Invoke(js.Global().Get("JSON").Call("parse", string(data)))
+		}()
+
+		return nil
+	}))
+}
+
+func main() {
+	js.Global().Set("stravaListActivities", js.FuncOf(listActivities))
+
+	// keep the functions available to the page
+	select {}
+}
@@ -1,7 +1,8 @@
 package strava
 
 import (
-	"encoding/json"
+	"net/http"
+	"time"
 )
 
 type GearDetailed struct {
@@ -13,10 +14,11 @@ type GearDetailed struct {
 }
 
 type GearSummary struct {
-	Id       string  `json:"id"`
-	Name     string  `json:"name"`
-	Primary  bool    `json:"primary"`
-	Distance float64 `json:"distance"`
+	RawJSON
+	Id       string   `json:"id" strava:"required"`
+	Name     string   `json:"name"`
+	Primary  bool     `json:"primary"`
+	Distance Distance `json:"distance"`
 }
 
 type FrameType int
@@ -39,8 +41,12 @@ func NewGearService(client *Client) *GearService {
 /*********************************************************/
 
 type GearGetCall struct {
-	service *GearService
-	id      string
+	service      *GearService
+	id           string
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *GearService) Get(gearId string) *GearGetCall {
@@ -50,19 +56,36 @@ func (s *GearService) Get(gearId string) *GearGetCall {
 	}
 }
 
-func (c *GearGetCall) Do() (*GearDetailed, error) {
-	data, err := c.service.client.run("GET", "/gear/"+c.id, nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *GearGetCall) OnError(handler ErrorHandler) *GearGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *GearGetCall) Response(resp *Response) *GearGetCall {
+	c.response = resp
+	return c
+}
 
-	var gear GearDetailed
-	err = json.Unmarshal(data, &gear)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *GearGetCall) Timeout(d time.Duration) *GearGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *GearGetCall) Header(key, value string) *GearGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &gear, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *GearGetCall) Do() (*GearDetailed, error) {
+	gear, err := runAndDecode[*GearDetailed](c.service.client, "GET", "/gear/"+c.id, nil, c.errorHandler, c.response, c.timeout, c.header)
+	return gear, wrapError(err, "gear.get id=%s", c.id)
 }
 
 func (f FrameType) Id() int {
@@ -7,7 +7,7 @@ import (
 
 func TestGearGet(t *testing.T) {
 	// bike
-	client := newCassetteClient(testToken, "gear_get_bike")
+	client := newFixtureClient(t, "gear_get_bike")
 	gear, err := NewGearService(client).Get("b77076").Do()
 
 	expected := &GearDetailed{}
@@ -29,7 +29,7 @@ func TestGearGet(t *testing.T) {
 	}
 
 	// shoe
-	client = newCassetteClient(testToken, "gear_get_shoe")
+	client = newFixtureClient(t, "gear_get_shoe")
 	gear, err = NewGearService(client).Get("g5697").Do()
 
 	expected = &GearDetailed{}
@@ -1,3 +1,5 @@
 module github.com/caselongo/strava-go
 
 go 1.20
+
+require golang.org/x/oauth2 v0.24.0
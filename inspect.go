@@ -0,0 +1,105 @@
+package strava
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TokenInfo describes an access token, see Client.InspectToken.
+type TokenInfo struct {
+	Valid     bool  // Strava accepted the token
+	AthleteId int64 // of the athlete of a valid token
+
+	// The stored state of the token, known if it is the token of the TokenSource of the client.
+	Stored    bool
+	ExpiresAt time.Time // zero if not stored
+	Scopes    []Scope   // from the ScopeSource of the client, see WithScopeCheck, or the TokenSource if it is one
+}
+
+// Expired returns if the stored token expired, false if it is not stored.
+func (info *TokenInfo) Expired() bool {
+	return info.Stored && !info.ExpiresAt.After(time.Now())
+}
+
+// InspectToken checks the access token by requesting the athlete it belongs to, e.g. for admin tooling verifying
+// stored tokens. The token is used as is, it is not refreshed. A token that Strava rejects is not valid,
+// an error is only returned if the check fails. The expiry and scopes are only known for the token of the TokenSource,
+// clients without one fail with ErrNoTokenSource.
+func (client *Client) InspectToken(accessToken string) (*TokenInfo, error) {
+	if client.tokenSource == nil {
+		return nil, wrapError(ErrNoTokenSource, "inspect_token")
+	}
+
+	req, err := http.NewRequest("GET", client.baseURL+"/athlete", nil)
+	if err != nil {
+		return nil, wrapError(err, "inspect_token")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("User-Agent", client.userAgent)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		client.logf("strava: token inspection failed: %v", err)
+		return nil, wrapError(err, "inspect_token")
+	}
+	defer resp.Body.Close()
+
+	client.logf("strava: token inspection %d", resp.StatusCode)
+	client.rateLimit.updateRateLimits(resp, client.clock.Now())
+
+	info := &TokenInfo{}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		// the token is invalid, expired or revoked
+	case resp.StatusCode/100 == 2:
+		var athlete AthleteMeta
+		contents, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(contents, &athlete); err != nil {
+			return nil, wrapError(err, "inspect_token")
+		}
+
+		info.Valid = true
+		info.AthleteId = athlete.Id
+	default:
+		if resp.Request == nil {
+			resp.Request = req
+		}
+
+		return nil, wrapError(newAPIError(resp, client.clock.Now()), "inspect_token")
+	}
+
+	if err := client.storedTokenInfo(accessToken, info); err != nil {
+		return nil, wrapError(err, "inspect_token")
+	}
+
+	return info, nil
+}
+
+// storedTokenInfo sets the expiry and scopes of the token, if it is the token of the TokenSource.
+func (client *Client) storedTokenInfo(accessToken string, info *TokenInfo) error {
+	stored, err := client.tokenSource.GetAuthorizationResponse()
+	if err != nil {
+		return err
+	}
+
+	if stored == nil || stored.AccessToken != accessToken {
+		return nil
+	}
+
+	info.Stored = true
+	info.ExpiresAt = stored.ExpiresAtTime()
+
+	source := client.scopeSource
+	if source == nil {
+		source, _ = client.tokenSource.(ScopeSource)
+	}
+
+	if source != nil {
+		info.Scopes, err = source.GrantedScopes()
+	}
+
+	return err
+}
@@ -0,0 +1,70 @@
+package strava
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClientInspectToken(t *testing.T) {
+	ts := newStubTokenSource()
+	ts.response.AccessToken = "stored"
+
+	client := NewClient(ts, WithScopeCheck(GrantedScopes{ScopeRead, ScopeActivityRead}))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status, body := http.StatusUnauthorized, `{"message":"Authorization Error"}`
+		switch req.Header.Get("Authorization") {
+		case "Bearer stored", "Bearer other":
+			status, body = http.StatusOK, `{"id":42,"firstname":"Jane"}`
+		case "Bearer broken":
+			status, body = http.StatusInternalServerError, ``
+		}
+
+		return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})}
+
+	info, err := client.InspectToken("stored")
+	if err != nil {
+		t.Fatalf("inspection error: %v", err)
+	}
+
+	if !info.Valid || info.AthleteId != 42 || !info.Stored || info.Expired() || len(info.Scopes) != 2 {
+		t.Errorf("incorrect info for the stored token, got %+v", info)
+	}
+
+	info, err = client.InspectToken("other")
+	if err != nil {
+		t.Fatalf("inspection error: %v", err)
+	}
+
+	if !info.Valid || info.Stored || info.Scopes != nil || !info.ExpiresAt.IsZero() {
+		t.Errorf("only the stored token should have stored state, got %+v", info)
+	}
+
+	info, err = client.InspectToken("revoked")
+	if err != nil {
+		t.Fatalf("rejected tokens should not fail the inspection, got %v", err)
+	}
+
+	if info.Valid || info.AthleteId != 0 {
+		t.Errorf("rejected tokens should not be valid, got %+v", info)
+	}
+
+	if _, err := client.InspectToken("broken"); !errors.Is(err, ErrServerError) {
+		t.Errorf("failed inspections should return the error, got %v", err)
+	}
+}
+
+func TestClientInspectTokenWithoutTokenSource(t *testing.T) {
+	client := NewClient(nil)
+
+	if _, err := client.InspectToken("abc"); !errors.Is(err, ErrNoTokenSource) {
+		t.Errorf("clients without a token source should fail, got %v", err)
+	}
+
+	if _, err := NewCurrentAthleteService(client).Get().Do(); !errors.Is(err, ErrNoTokenSource) {
+		t.Errorf("calls of clients without a token source should fail, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package strava
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// A DetectedInterval is a part of an activity where the power or heart rate stayed at about the same level,
+// as found by DetectPowerIntervals or DetectHeartrateIntervals.
+type DetectedInterval struct {
+	Interval
+	Average float64
+	Maximum float64
+}
+
+// DetectPowerIntervals splits the activity into intervals of about constant power, e.g. the work and recovery
+// intervals of a structured workout, without relying on the athlete pressing lap. Intervals are at least
+// minDuration long. Requires the Time and Power streams, nil without them. Fails with ErrTimeNotIncreasing
+// for corrupted recordings.
+func (s *StreamSet) DetectPowerIntervals(minDuration time.Duration) ([]DetectedInterval, error) {
+	if s.Power == nil {
+		return nil, nil
+	}
+
+	return s.detectIntervals(s.Power.Data, minDuration)
+}
+
+// DetectHeartrateIntervals is like DetectPowerIntervals for the heart rate.
+// Heart rate lags behind the effort, so the intervals start and end later than the actual efforts.
+// Requires the Time and HeartRate streams.
+func (s *StreamSet) DetectHeartrateIntervals(minDuration time.Duration) ([]DetectedInterval, error) {
+	if s.HeartRate == nil {
+		return nil, nil
+	}
+
+	return s.detectIntervals(s.HeartRate.Data, minDuration)
+}
+
+// detectIntervals finds the changepoints in the data, resampled to one value per second, by binary segmentation.
+// A segment is split where it reduces the squared error the most, as long as the reduction is more
+// than can be expected from the noise in the data.
+func (s *StreamSet) detectIntervals(data []int, minDuration time.Duration) ([]DetectedInterval, error) {
+	values, err := s.perSecond(data)
+	if len(values) == 0 {
+		return nil, err
+	}
+
+	minLength := int(minDuration / time.Second)
+	if minLength < 1 {
+		minLength = 1
+	}
+
+	c := newChangepoints(values)
+	penalty := 2 * c.noiseVariance() * math.Log(float64(len(values)))
+
+	splits := c.split(0, len(values), minLength, penalty, nil)
+	sort.Ints(splits)
+
+	bounds := append(append([]int{0}, splits...), len(values))
+	intervals := make([]DetectedInterval, 0, len(bounds)-1)
+	for i := 1; i < len(bounds); i++ {
+		intervals = append(intervals, s.detectedInterval(values, bounds[i-1], bounds[i]))
+	}
+
+	return intervals, nil
+}
+
+// detectedInterval builds the interval of the seconds [start, end) of the resampled values.
+func (s *StreamSet) detectedInterval(values []float64, start, end int) DetectedInterval {
+	t0 := s.Time.Data[0]
+	interval := s.interval(
+		sort.SearchInts(s.Time.Data, t0+start),
+		sort.SearchInts(s.Time.Data, t0+end),
+	)
+
+	var sum, maximum float64
+	for _, v := range values[start:end] {
+		sum += v
+		if v > maximum {
+			maximum = v
+		}
+	}
+
+	return DetectedInterval{
+		Interval: interval,
+		Average:  sum / float64(end-start),
+		Maximum:  maximum,
+	}
+}
+
+/*********************************************************/
+
+// changepoints holds the cumulative sums of values, so the squared error of any segment is constant time.
+type changepoints struct {
+	values []float64
+	sum    []float64
+	sumSq  []float64
+}
+
+func newChangepoints(values []float64) *changepoints {
+	c := &changepoints{
+		values: values,
+		sum:    make([]float64, len(values)+1),
+		sumSq:  make([]float64, len(values)+1),
+	}
+
+	for i, v := range values {
+		c.sum[i+1] = c.sum[i] + v
+		c.sumSq[i+1] = c.sumSq[i] + v*v
+	}
+
+	return c
+}
+
+// cost returns the squared error of the segment [start, end) around its mean.
+func (c *changepoints) cost(start, end int) float64 {
+	n := float64(end - start)
+	sum := c.sum[end] - c.sum[start]
+	return c.sumSq[end] - c.sumSq[start] - sum*sum/n
+}
+
+// noiseVariance estimates the variance of the noise from the median absolute difference
+// of consecutive values, which isn't affected by the changepoints themselves.
+func (c *changepoints) noiseVariance() float64 {
+	if len(c.values) < 2 {
+		return 0
+	}
+
+	differences := make([]float64, len(c.values)-1)
+	for i := 1; i < len(c.values); i++ {
+		differences[i-1] = math.Abs(c.values[i] - c.values[i-1])
+	}
+	sort.Float64s(differences)
+
+	sigma := differences[len(differences)/2] / (0.6745 * math.Sqrt2)
+	if sigma < 1 {
+		// perfectly steady data, still require a meaningful change
+		sigma = 1
+	}
+
+	return sigma * sigma
+}
+
+// split appends the changepoints in [start, end) to splits.
+func (c *changepoints) split(start, end, minLength int, penalty float64, splits []int) []int {
+	if end-start < 2*minLength {
+		return splits
+	}
+
+	total := c.cost(start, end)
+
+	best, bestGain := -1, penalty
+	for i := start + minLength; i <= end-minLength; i++ {
+		if gain := total - c.cost(start, i) - c.cost(i, end); gain > bestGain {
+			best, bestGain = i, gain
+		}
+	}
+
+	if best == -1 {
+		return splits
+	}
+
+	splits = append(splits, best)
+	splits = c.split(start, best, minLength, penalty, splits)
+	return c.split(best, end, minLength, penalty, splits)
+}
@@ -0,0 +1,87 @@
+package strava
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStreamSetDetectPowerIntervals(t *testing.T) {
+	// 5 minutes easy, 3 minutes hard, 2 minutes easy, with some noise
+	s := &StreamSet{
+		Time:  &IntegerStream{},
+		Power: &IntegerStream{},
+	}
+
+	for i := 0; i <= 600; i++ {
+		power := 150
+		if i > 300 && i <= 480 {
+			power = 300
+		}
+
+		s.Time.Data = append(s.Time.Data, i)
+		s.Power.Data = append(s.Power.Data, power+(i%3-1)*10)
+	}
+
+	intervals, err := s.DetectPowerIntervals(30 * time.Second)
+	if err != nil || len(intervals) != 3 {
+		t.Fatalf("incorrect number of intervals, got %+v", intervals)
+	}
+
+	expected := []struct {
+		start, end int
+		average    float64
+	}{
+		{0, 300, 150},
+		{300, 480, 300},
+		{480, 600, 150},
+	}
+
+	for i, e := range expected {
+		interval := intervals[i]
+		if interval.Start != e.start || interval.End != e.end || interval.StartIndex != e.start || interval.EndIndex != e.end {
+			t.Errorf("incorrect interval %d, got %+v", i, interval)
+		}
+
+		if math.Abs(interval.Average-e.average) > 1 {
+			t.Errorf("incorrect average of interval %d, got %v", i, interval.Average)
+		}
+	}
+
+	if intervals[1].Maximum != 310 {
+		t.Errorf("incorrect maximum, got %v", intervals[1].Maximum)
+	}
+
+	// the activity is too short to split with the minimum duration
+	if intervals, _ := s.DetectPowerIntervals(6 * time.Minute); len(intervals) != 1 {
+		t.Errorf("incorrect number of intervals, got %+v", intervals)
+	}
+
+	if intervals, err := s.DetectHeartrateIntervals(time.Minute); intervals != nil || err != nil {
+		t.Error("should require heart rate stream")
+	}
+
+	// corrupted recordings
+	s.Time.Data[10] = 5
+	if _, err := s.DetectPowerIntervals(30 * time.Second); !errors.Is(err, ErrTimeNotIncreasing) {
+		t.Errorf("time going back should fail, got %v", err)
+	}
+}
+
+func TestStreamSetDetectHeartrateIntervals(t *testing.T) {
+	// samples every 2 seconds
+	s := &StreamSet{
+		Time:      &IntegerStream{Data: []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20}},
+		HeartRate: &IntegerStream{Data: []int{120, 120, 120, 120, 120, 120, 170, 170, 170, 170, 170}},
+	}
+
+	intervals, err := s.DetectHeartrateIntervals(4 * time.Second)
+	if err != nil || len(intervals) != 2 {
+		t.Fatalf("incorrect number of intervals, got %+v", intervals)
+	}
+
+	if intervals[0].Average != 120 || intervals[1].Average != 170 || intervals[1].Start != 10 || intervals[1].StartIndex != 5 || intervals[1].End != 20 {
+		t.Errorf("incorrect intervals, got %+v", intervals)
+	}
+}
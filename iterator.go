@@ -0,0 +1,176 @@
+package strava
+
+// defaultPerPage is the number of items in a page when the call doesn't set it.
+const defaultPerPage = 30
+
+// An Iterator yields the items of a paginated list one by one, fetching the pages as they are needed:
+//
+//	it := service.ListActivities().PerPage(100).Iterate()
+//	for it.Next() {
+//		activity := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+//
+// Map, Filter, Collect and Reduce compose transformations of the items without intermediate slices.
+type Iterator[T any] struct {
+	next func() (T, bool, error)
+	item T
+	err  error
+	done bool
+}
+
+// NewIterator returns an iterator yielding the items returned by next, until next returns false or an error.
+func NewIterator[T any](next func() (item T, ok bool, err error)) *Iterator[T] {
+	return &Iterator[T]{next: next}
+}
+
+// Paginate returns an iterator over the items of the pages returned by fetch, starting at page 1.
+// The pages are fetched until a page holds fewer than perPage items.
+func Paginate[T any](perPage int, fetch func(page int) ([]T, error)) *Iterator[T] {
+	return paginate(perPage, func(page int, _ *Response) ([]T, error) { return fetch(page) }, nil, nil)
+}
+
+// paginate is Paginate, fetching the next page while the items of a page are iterated
+// whenever prefetch, if not nil, returns true. Every page is fetched with a Response of its own,
+// which is copied to the response, if not nil, when its items are taken, so a page fetched ahead
+// doesn't write the response of the caller while it is read.
+func paginate[T any](perPage int, fetch func(page int, response *Response) ([]T, error), prefetch func() bool, response *Response) *Iterator[T] {
+	type result struct {
+		items    []T
+		response *Response
+		err      error
+	}
+
+	fetchPage := func(page int) result {
+		r := result{response: &Response{}}
+		r.items, r.err = fetch(page, r.response)
+		return r
+	}
+
+	var items []T
+	var ahead chan result // the prefetched page, if any
+	page := 0
+	last := false
+
+	return NewIterator(func() (T, bool, error) {
+		for len(items) == 0 {
+			var zero T
+			if last {
+				return zero, false, nil
+			}
+
+			page++
+
+			var r result
+			if ahead != nil {
+				r, ahead = <-ahead, nil
+			} else {
+				r = fetchPage(page)
+			}
+
+			if response != nil {
+				*response = *r.response
+			}
+
+			items = r.items
+			if r.err != nil {
+				return zero, false, r.err
+			}
+
+			last = len(items) < perPage
+			if !last && prefetch != nil && prefetch() {
+				ahead = make(chan result, 1)
+				go func(ahead chan<- result, page int) {
+					ahead <- fetchPage(page)
+				}(ahead, page+1)
+			}
+		}
+
+		item := items[0]
+		items = items[1:]
+
+		return item, true, nil
+	})
+}
+
+// Next advances to the next item, returning false when there are no more items or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+
+	var ok bool
+	it.item, ok, it.err = it.next()
+	if !ok || it.err != nil {
+		var zero T
+		it.item = zero
+		it.done = true
+		return false
+	}
+
+	return true
+}
+
+// Item returns the current item.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+/*********************************************************/
+
+// Map returns an iterator yielding the items of it transformed by f.
+func Map[T, U any](it *Iterator[T], f func(T) U) *Iterator[U] {
+	return NewIterator(func() (U, bool, error) {
+		if !it.Next() {
+			var zero U
+			return zero, false, it.Err()
+		}
+
+		return f(it.Item()), true, nil
+	})
+}
+
+// Filter returns an iterator yielding the items of it for which keep returns true.
+func Filter[T any](it *Iterator[T], keep func(T) bool) *Iterator[T] {
+	return NewIterator(func() (T, bool, error) {
+		for it.Next() {
+			if keep(it.Item()) {
+				return it.Item(), true, nil
+			}
+		}
+
+		var zero T
+		return zero, false, it.Err()
+	})
+}
+
+// Collect returns the remaining items of it, and the error that ended the iteration.
+func Collect[T any](it *Iterator[T]) ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+
+	return items, it.Err()
+}
+
+// Reduce aggregates the remaining items of it, starting with initial, e.g. to sum the distance of activities.
+func Reduce[T, A any](it *Iterator[T], initial A, f func(A, T) A) (A, error) {
+	result := initial
+	for it.Next() {
+		result = f(result, it.Item())
+	}
+
+	return result, it.Err()
+}
+
+// mayPrefetch returns if pages may be fetched ahead, which they may not once the rate limit is reached.
+func (client *Client) mayPrefetch() bool {
+	_, exceeded := client.rateLimit.exceededUsage()
+	return !exceeded
+}
@@ -0,0 +1,217 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaginate(t *testing.T) {
+	var pages []int
+	it := Paginate(2, func(page int) ([]int, error) {
+		pages = append(pages, page)
+		return [][]int{{1, 2}, {3, 4}, {5}}[page-1], nil
+	})
+
+	items, err := Collect(it)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(items) != "[1 2 3 4 5]" || fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("incorrect items, got %v from pages %v", items, pages)
+	}
+
+	if it.Next() {
+		t.Error("iterator should stay done")
+	}
+
+	// pages are fetched until one is short
+	pages = nil
+	Collect(Paginate(2, func(page int) ([]int, error) {
+		pages = append(pages, page)
+		return [][]int{{1, 2}, {}}[page-1], nil
+	}))
+
+	if fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("incorrect pages, got %v", pages)
+	}
+}
+
+func TestIteratorCombinators(t *testing.T) {
+	numbers := func() *Iterator[int] {
+		return Paginate(3, func(page int) ([]int, error) {
+			return [][]int{{1, 2, 3}, {4, 5, 6}, {7}}[page-1], nil
+		})
+	}
+
+	even := Filter(numbers(), func(n int) bool { return n%2 == 0 })
+	labels, err := Collect(Map(even, func(n int) string { return strconv.Itoa(n * 10) }))
+	if err != nil || strings.Join(labels, ",") != "20,40,60" {
+		t.Errorf("incorrect items, got %v %v", labels, err)
+	}
+
+	sum, err := Reduce(numbers(), 0, func(sum, n int) int { return sum + n })
+	if err != nil || sum != 28 {
+		t.Errorf("incorrect sum, got %v %v", sum, err)
+	}
+
+	// errors end the iteration
+	failure := errors.New("failure")
+	failing := Paginate(2, func(page int) ([]int, error) {
+		if page == 2 {
+			return nil, failure
+		}
+		return []int{1, 2}, nil
+	})
+
+	items, err := Collect(Map(failing, func(n int) int { return n }))
+	if err != failure || len(items) != 2 {
+		t.Errorf("error should be returned, got %v %v", items, err)
+	}
+}
+
+func TestCurrentAthleteListActivitiesIterate(t *testing.T) {
+	transport := &pageTransport{pages: []string{
+		`[{"id":1,"commute":true},{"id":2}]`,
+		`[{"id":3,"commute":true},{"id":4,"commute":true}]`,
+		`[{"id":5}]`,
+	}}
+
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: transport}
+
+	it := NewCurrentAthleteService(client).ListActivities().PerPage(2).Where(ActivityCommute(true)).Iterate()
+	ids, err := Collect(Map(it, func(a *ActivitySummary) int64 { return a.Id }))
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if fmt.Sprint(ids) != "[1 3 4]" || len(transport.queries) != 3 {
+		t.Errorf("incorrect activities, got %v from %v", ids, transport.queries)
+	}
+
+	if transport.queries[1] != "page=2&per_page=2" {
+		t.Errorf("incorrect query, got %v", transport.queries[1])
+	}
+
+	// starting at the page of the call
+	transport.queries = nil
+	Collect(NewCurrentAthleteService(client).ListActivities().Page(2).PerPage(2).Iterate())
+
+	if len(transport.queries) != 2 || transport.queries[0] != "page=2&per_page=2" {
+		t.Errorf("incorrect queries, got %v", transport.queries)
+	}
+}
+
+func TestCurrentAthleteListActivitiesPrefetchResponse(t *testing.T) {
+	pages := []string{`[{"id":1},{"id":2}]`, `[{"id":3},{"id":4}]`, `[{"id":5}]`}
+
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		resp := &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(pages[page-1])), Request: req}
+		resp.Header.Set("X-Page", strconv.Itoa(page))
+		return resp, nil
+	})}
+
+	// the response is of the page being iterated, not of the page fetched ahead
+	var resp Response
+	it := NewCurrentAthleteService(client).ListActivities().PerPage(2).Prefetch().Response(&resp).Iterate()
+	for _, expected := range []string{"1", "1", "2", "2", "3"} {
+		if !it.Next() {
+			t.Fatalf("iteration should go on, got %v", it.Err())
+		}
+
+		if page := resp.Header.Get("X-Page"); page != expected {
+			t.Errorf("response should be of page %s, got %s", expected, page)
+		}
+	}
+}
+
+// pageTransport answers with the pages, by the page parameter of the request.
+type pageTransport struct {
+	pages   []string
+	queries []string
+}
+
+func (t *pageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.queries = append(t.queries, req.URL.RawQuery)
+
+	content := "[]"
+	if page, _ := strconv.Atoi(req.URL.Query().Get("page")); page >= 1 && page <= len(t.pages) {
+		content = t.pages[page-1]
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(content)),
+		Request:    req,
+	}, nil
+}
+
+func TestPaginatePrefetch(t *testing.T) {
+	fetched := make(chan int, 10)
+	it := paginate(2, func(page int, _ *Response) ([]int, error) {
+		fetched <- page
+		return [][]int{{1, 2}, {3, 4}, {5}}[page-1], nil
+	}, func() bool { return true }, nil)
+
+	if !it.Next() || it.Item() != 1 {
+		t.Fatalf("incorrect item, got %v", it.Item())
+	}
+
+	// the second page is fetched while the first is iterated
+	for _, expected := range []int{1, 2} {
+		select {
+		case page := <-fetched:
+			if page != expected {
+				t.Errorf("incorrect page, got %v", page)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("page %d should be fetched", expected)
+		}
+	}
+
+	items, err := Collect(it)
+	if err != nil || fmt.Sprint(items) != "[2 3 4 5]" {
+		t.Errorf("incorrect items, got %v %v", items, err)
+	}
+
+	if len(fetched) != 1 {
+		t.Errorf("every page should be fetched once, got %v more", len(fetched))
+	}
+
+	// not ahead when not allowed
+	fetched = make(chan int, 10)
+	it = paginate(2, func(page int, _ *Response) ([]int, error) {
+		fetched <- page
+		return []int{1, 2}, nil
+	}, func() bool { return false }, nil)
+
+	it.Next()
+	it.Next()
+	if len(fetched) != 1 {
+		t.Errorf("next page should not be fetched, got %v pages", len(fetched))
+	}
+}
+
+func TestClientMayPrefetch(t *testing.T) {
+	client := NewClient(newStubTokenSource(), WithRateLimiter(&RateLimit{}))
+	if !client.mayPrefetch() {
+		t.Error("should prefetch without known usage")
+	}
+
+	client.rateLimit.RequestTime = time.Now()
+	client.rateLimit.LimitShort, client.rateLimit.UsageShort = 100, 100
+	client.rateLimit.LimitLong, client.rateLimit.UsageLong = 1000, 100
+	if client.mayPrefetch() {
+		t.Error("should not prefetch when the rate limit is reached")
+	}
+}
@@ -1,8 +1,9 @@
 package strava
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 type ActivityKudosService struct {
@@ -17,8 +18,12 @@ func NewActivityKudosService(client *Client, activityId int64) *ActivityKudosSer
 /*********************************************************/
 
 type ActivityKudosListCall struct {
-	service *ActivityKudosService
-	ops     map[string]interface{}
+	service      *ActivityKudosService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivityKudosService) List() *ActivityKudosListCall {
@@ -38,25 +43,46 @@ func (c *ActivityKudosListCall) PerPage(perPage int) *ActivityKudosListCall {
 	return c
 }
 
-func (c *ActivityKudosListCall) Do() ([]*AthleteSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/activities/%d/kudos", c.service.activityId), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *ActivityKudosListCall) OnError(handler ErrorHandler) *ActivityKudosListCall {
+	c.errorHandler = handler
+	return c
+}
 
-	kudoers := make([]*AthleteSummary, 0)
-	err = json.Unmarshal(data, &kudoers)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivityKudosListCall) Response(resp *Response) *ActivityKudosListCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivityKudosListCall) Timeout(d time.Duration) *ActivityKudosListCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivityKudosListCall) Header(key, value string) *ActivityKudosListCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return kudoers, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *ActivityKudosListCall) Do() ([]*AthleteSummary, error) {
+	kudoers, err := runAndDecode[[]*AthleteSummary](c.service.client, "GET", fmt.Sprintf("/activities/%d/kudos", c.service.activityId), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return kudoers, wrapError(err, "activity_kudos.list activity_id=%d", c.service.activityId)
 }
 
 /*********************************************************/
 
 type ActivityKudosPostCall struct {
-	service *ActivityKudosService
+	service      *ActivityKudosService
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivityKudosService) Create() *ActivityKudosPostCall {
@@ -65,15 +91,46 @@ func (s *ActivityKudosService) Create() *ActivityKudosPostCall {
 	}
 }
 
+func (c *ActivityKudosPostCall) OnError(handler ErrorHandler) *ActivityKudosPostCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivityKudosPostCall) Response(resp *Response) *ActivityKudosPostCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivityKudosPostCall) Timeout(d time.Duration) *ActivityKudosPostCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivityKudosPostCall) Header(key, value string) *ActivityKudosPostCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *ActivityKudosPostCall) Do() error {
-	_, err := c.service.client.run("POST", fmt.Sprintf("/activities/%d/kudos", c.service.activityId), nil)
-	return err
+	_, err := c.service.client.runWithErrorHandler("POST", fmt.Sprintf("/activities/%d/kudos", c.service.activityId), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return wrapError(err, "activity_kudos.create activity_id=%d", c.service.activityId)
 }
 
 /*********************************************************/
 
 type ActivityKudosDeleteCall struct {
-	service *ActivityKudosService
+	service      *ActivityKudosService
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *ActivityKudosService) Delete() *ActivityKudosDeleteCall {
@@ -82,7 +139,34 @@ func (s *ActivityKudosService) Delete() *ActivityKudosDeleteCall {
 	}
 }
 
+func (c *ActivityKudosDeleteCall) OnError(handler ErrorHandler) *ActivityKudosDeleteCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivityKudosDeleteCall) Response(resp *Response) *ActivityKudosDeleteCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivityKudosDeleteCall) Timeout(d time.Duration) *ActivityKudosDeleteCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivityKudosDeleteCall) Header(key, value string) *ActivityKudosDeleteCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *ActivityKudosDeleteCall) Do() error {
-	_, err := c.service.client.run("DELETE", fmt.Sprintf("/activities/%d/kudos", c.service.activityId), nil)
-	return err
+	_, err := c.service.client.runWithErrorHandler("DELETE", fmt.Sprintf("/activities/%d/kudos", c.service.activityId), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return wrapError(err, "activity_kudos.delete activity_id=%d", c.service.activityId)
 }
@@ -1,11 +1,12 @@
 package strava
 
 import (
+	"net/http"
 	"testing"
 )
 
 func TestActivitiesKudosList(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_kudos_list")
+	client := newFixtureClient(t, "activity_kudos_list")
 	athletes, err := NewActivityKudosService(client, 103221154).List().Do()
 
 	if err != nil {
@@ -49,7 +50,7 @@ func TestActivitiesKudosList(t *testing.T) {
 }
 
 func TestActivityKudosCreate(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_kudos_post")
+	client := NewStubResponseClient(`{}`)
 	err := NewActivityKudosService(client, 118229063).Create().Do()
 
 	if err != nil {
@@ -77,7 +78,7 @@ func TestActivityKudosCreate(t *testing.T) {
 }
 
 func TestActivityKudosDelete(t *testing.T) {
-	client := newCassetteClient(testToken, "activity_kudos_delete")
+	client := NewStubResponseClient("", http.StatusNoContent)
 	err := NewActivityKudosService(client, 118229063).Delete().Do()
 
 	if err != nil {
@@ -0,0 +1,103 @@
+package strava
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultMaintenanceWait is how long to wait after a 503 response without a Retry-After header.
+const defaultMaintenanceWait = 30 * time.Second
+
+// WithMaintenanceRetry retries calls that fail with a 503 response, while Strava is down for maintenance,
+// up to retries times, after the wait of the Retry-After header, see ErrMaintenance. Meanwhile the other
+// requests of the client wait too, instead of hammering Strava. Requests whose turn comes after the deadline
+// of their context fail right away with the ErrMaintenance. Copies made with Client.With share the wait.
+func WithMaintenanceRetry(retries int) Option {
+	return func(c *Client) {
+		c.maintenance = nil
+		if retries > 0 {
+			c.maintenance = &maintenance{retries: retries}
+		}
+	}
+}
+
+// maintenance holds the requests of a client until the end of a maintenance of Strava.
+type maintenance struct {
+	retries int
+
+	lock  sync.Mutex
+	until time.Time // end of the maintenance, as announced by the last 503 response
+}
+
+// hold makes requests wait for the maintenance announced by the 503 response.
+func (m *maintenance) hold(resp *http.Response, now time.Time) {
+	if m == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		return
+	}
+
+	wait := retryAfter(resp.Header, now)
+	if wait == 0 {
+		wait = defaultMaintenanceWait
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if until := now.Add(wait); until.After(m.until) {
+		m.until = until
+	}
+}
+
+// wait blocks until the end of the maintenance, by the clock, and returns how long it waited. If it ends after
+// the deadline, if not zero, it returns ErrMaintenance right away.
+func (m *maintenance) wait(clock Clock, deadline time.Time) (time.Duration, error) {
+	if m == nil {
+		return 0, nil
+	}
+
+	m.lock.Lock()
+	now := clock.Now()
+	wait := m.until.Sub(now)
+	m.lock.Unlock()
+
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	if !deadline.IsZero() && now.Add(wait).After(deadline) {
+		return 0, &ErrMaintenance{RetryAfter: wait}
+	}
+
+	clock.Sleep(wait)
+	return wait, nil
+}
+
+// doRequest makes the request with Client.doRequest, and again, while it fails with a 503 response,
+// if the client retries during maintenance, see WithMaintenanceRetry.
+func (m *maintenance) doRequest(client *Client, req *http.Request, path string, errorHandler ErrorHandler, record *callRecord, v interface{}) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		record.responded = false
+		data, err := client.doRequest(req, path, errorHandler, record, v)
+
+		if m == nil || attempt >= m.retries || !record.responded || record.StatusCode != http.StatusServiceUnavailable {
+			return data, err
+		}
+
+		// the body of the request is sent again
+		if req.Body != nil && req.GetBody == nil {
+			return data, err
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		client.logf("strava: %s %s under maintenance, retrying", req.Method, req.URL)
+		record.Retries++
+		req = retry
+	}
+}
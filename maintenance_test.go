@@ -0,0 +1,173 @@
+package strava
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIErrorMaintenance(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": {"120"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+
+	err := error(newAPIError(resp, time.Now()))
+
+	var maintenance *ErrMaintenance
+	if !errors.As(err, &maintenance) || maintenance.RetryAfter != 2*time.Minute {
+		t.Fatalf("should be a maintenance with a wait, got %v", err)
+	}
+
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("should still be a server error, got %v", err)
+	}
+
+	resp.StatusCode = http.StatusBadGateway
+	if errors.As(newAPIError(resp, time.Now()), &maintenance) {
+		t.Error("only 503 responses should be a maintenance")
+	}
+}
+
+func TestAPIErrorMaintenanceClock(t *testing.T) {
+	clock := newFakeClock()
+	clock.Sleep(24 * time.Hour)
+
+	// without a Date header the wait until the date is told by the clock of the client
+	client, _ := newRouteClient(nil)
+	WithClock(clock)(client)
+	client.tokenSource.(*stubTokenSource).response.ExpiresAt = time.Now().Add(48 * time.Hour).Unix()
+	client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Retry-After": {clock.Now().Add(10 * time.Minute).UTC().Format(http.TimeFormat)}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	_, err := NewClubsService(client).Get(1).Do()
+
+	var maintenance *ErrMaintenance
+	if !errors.As(err, &maintenance) || maintenance.RetryAfter <= 9*time.Minute || maintenance.RetryAfter > 10*time.Minute {
+		t.Errorf("should wait until the date by the clock, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		header   http.Header
+		expected time.Duration
+	}{
+		{http.Header{}, 0},
+		{http.Header{"Retry-After": {"30"}}, 30 * time.Second},
+		{http.Header{"Retry-After": {"-5"}}, 0},
+		{http.Header{"Retry-After": {"soon"}}, 0},
+		{http.Header{"Retry-After": {"Thu, 01 Jan 2026 12:10:00 GMT"}}, 10 * time.Minute},
+		{http.Header{"Retry-After": {"Thu, 01 Jan 2026 12:10:00 GMT"}, "Date": {"Thu, 01 Jan 2026 12:05:00 GMT"}}, 5 * time.Minute},
+		{http.Header{"Retry-After": {"Thu, 01 Jan 2026 11:00:00 GMT"}}, 0},
+	}
+
+	for _, c := range cases {
+		if wait := retryAfter(c.header, now); wait != c.expected {
+			t.Errorf("incorrect wait for %v, got %v", c.header, wait)
+		}
+	}
+}
+
+func TestClientWithMaintenanceRetry(t *testing.T) {
+	var requests int
+	client := NewClient(newStubTokenSource(), WithMaintenanceRetry(2))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if requests == 1 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": {"60"}},
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(`{"id":1}`))}, nil
+	})}
+
+	clock := newFakeClock()
+	tracer := &recordingTracer{}
+	WithClock(clock)(client)
+	WithTracer(tracer)(client)
+
+	club, err := NewClubsService(client).Get(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if club.Id != 1 || requests != 2 {
+		t.Errorf("call should be retried, got %d requests", requests)
+	}
+
+	if len(clock.sleeps) != 1 || clock.sleeps[0] != time.Minute {
+		t.Errorf("should wait for the end of the maintenance, got %v", clock.sleeps)
+	}
+
+	if attributes := tracer.spans[0].attributes; attributes.Retries != 1 || attributes.RateLimitWait != time.Minute {
+		t.Errorf("span should have the retries and the wait of all requests, got %+v", attributes)
+	}
+
+	if options := client.ClientOptionsSnapshot(); options.Retries != 2 {
+		t.Errorf("incorrect options, got %+v", options)
+	}
+}
+
+func TestClientMaintenanceRetryDeadline(t *testing.T) {
+	client, transport := newRouteClient(nil)
+	WithMaintenanceRetry(3)(client)
+	client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		transport.requests = append(transport.requests, req)
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Retry-After": {"3600"}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	clock := newFakeClock()
+	WithClock(clock)(client)
+
+	// the clock moves past the expiry of the token
+	client.tokenSource.(*stubTokenSource).response.ExpiresAt = time.Now().Add(24 * time.Hour).Unix()
+
+	// the maintenance ends after the deadline
+	_, err := NewClubsService(client).Get(1).Timeout(time.Minute).Do()
+
+	var maintenance *ErrMaintenance
+	if !errors.As(err, &maintenance) || maintenance.RetryAfter != time.Hour {
+		t.Fatalf("should fail with the maintenance, got %v", err)
+	}
+
+	if len(transport.requests) != 1 || len(clock.sleeps) != 0 {
+		t.Errorf("should not wait nor retry, got %d requests and waits %v", len(transport.requests), clock.sleeps)
+	}
+
+	// other requests are held too
+	if _, err := NewClubsService(client).Get(2).Timeout(time.Minute).Do(); !errors.As(err, &maintenance) {
+		t.Errorf("should fail with the maintenance, got %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("requests should not be made during the maintenance, got %d", len(transport.requests))
+	}
+
+	// retries stop after the last one
+	if _, err := NewClubsService(client).Get(3).Timeout(24 * time.Hour).Do(); !errors.Is(err, ErrServerError) {
+		t.Errorf("should fail with the last response, got %v", err)
+	}
+
+	if len(transport.requests) != 5 || len(clock.sleeps) != 4 {
+		t.Errorf("should make the request and 3 retries, got %d requests and waits %v", len(transport.requests), clock.sleeps)
+	}
+}
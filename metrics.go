@@ -0,0 +1,101 @@
+package strava
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Metrics observes the requests of a client, see WithMetrics. Implement it to update collectors
+// of a metrics package, e.g. for Prometheus:
+//
+//	func (m promMetrics) ObserveRequest(labels strava.MetricLabels, method, endpoint string, statusCode int, duration time.Duration) {
+//		m.requests.WithLabelValues(labels.Tenant, method, endpoint, strconv.Itoa(statusCode)).Inc()
+//		m.duration.WithLabelValues(labels.Tenant, method, endpoint).Observe(duration.Seconds())
+//	}
+//
+//	func (m promMetrics) ObserveRateLimit(labels strava.MetricLabels, usage strava.RateLimitUsage) {
+//		m.usage.WithLabelValues(labels.Tenant, "short").Set(float64(usage.UsageShort) / float64(usage.LimitShort))
+//		m.usage.WithLabelValues(labels.Tenant, "long").Set(float64(usage.UsageLong) / float64(usage.LimitLong))
+//	}
+//
+// The methods are called from the goroutines making the calls, they should be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called after every request with the endpoint template, e.g. /activities/{id},
+	// and the status code of the response, 0 if the request failed without one.
+	ObserveRequest(labels MetricLabels, method, endpoint string, statusCode int, duration time.Duration)
+
+	// ObserveRateLimit is called with the rate limits and usage returned with a response.
+	// Not called for responses without rate limit headers.
+	ObserveRateLimit(labels MetricLabels, usage RateLimitUsage)
+}
+
+// MetricLabels identify the client of an observation, so platforms running several applications
+// in one binary can attribute requests and quota usage, see WithMetricLabels.
+type MetricLabels struct {
+	Tenant  string // the application or tenant of the client, empty if not set
+	Athlete string // hash of the id of the athlete of the token, empty unless enabled or unknown
+}
+
+// RateLimitUsage are the rate limits of the application and the number of requests used,
+// for the short term (15 minutes) and long term (day) windows.
+type RateLimitUsage struct {
+	LimitShort int
+	LimitLong  int
+	UsageShort int
+	UsageLong  int
+}
+
+// WithMetrics makes the client report its requests and rate limit usage to the metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithMetricLabels sets the tenant label of the observations of the client, e.g. the name of the application.
+// With an athleteKey, observations are also labeled with an HMAC of the athlete id of the token with the key,
+// the athlete stays anonymous in the metrics while the requests of one athlete can be grouped. Keep the key
+// secret, athlete ids are easily guessed from a plain hash. A nil key doesn't label the athlete.
+// Mind the cardinality of the athlete label for platforms with many athletes.
+func WithMetricLabels(tenant string, athleteKey []byte) Option {
+	return func(c *Client) {
+		c.metricTenant = tenant
+		c.metricAthleteKey = athleteKey
+	}
+}
+
+// metricLabels returns the labels of an observation of a request with the token.
+func (client *Client) metricLabels(auth *AuthorizationResponse) MetricLabels {
+	labels := MetricLabels{Tenant: client.metricTenant}
+	if len(client.metricAthleteKey) > 0 && auth != nil && auth.Athlete != nil {
+		labels.Athlete = athleteHash(client.metricAthleteKey, client.metricTenant, auth.Athlete.Id)
+	}
+
+	return labels
+}
+
+// athleteHash returns a short HMAC-SHA256 of the athlete id of the tenant with the key.
+func athleteHash(key []byte, tenant string, athleteId int64) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(tenant + ":" + strconv.FormatInt(athleteId, 10)))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func (client *Client) observeRequest(labels MetricLabels, method, endpoint string, statusCode int, duration time.Duration) {
+	if client.metrics != nil {
+		client.metrics.ObserveRequest(labels, method, endpoint, statusCode, duration)
+	}
+}
+
+func (client *Client) observeRateLimit(labels MetricLabels) {
+	if client.metrics == nil {
+		return
+	}
+
+	if usage, ok := client.rateLimit.usage(); ok {
+		client.metrics.ObserveRateLimit(labels, usage)
+	}
+}
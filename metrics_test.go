@@ -0,0 +1,96 @@
+package strava
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	labels   []MetricLabels
+	requests []string
+	usages   []RateLimitUsage
+}
+
+func (m *recordingMetrics) ObserveRequest(labels MetricLabels, method, endpoint string, statusCode int, duration time.Duration) {
+	m.labels = append(m.labels, labels)
+	m.requests = append(m.requests, method+" "+endpoint+" "+http.StatusText(statusCode))
+}
+
+func (m *recordingMetrics) ObserveRateLimit(labels MetricLabels, usage RateLimitUsage) {
+	m.usages = append(m.usages, usage)
+}
+
+func TestClientWithMetrics(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	client, _ := newRouteClient(map[string]string{"/api/v3/clubs/1": `{"id":1}`})
+	WithMetrics(metrics)(client)
+	WithRateLimiter(&RateLimit{})(client)
+
+	NewClubsService(client).Get(1).Do()
+	NewClubsService(client).Get(2).Do()
+
+	if len(metrics.requests) != 2 || metrics.requests[0] != "GET /clubs/{id} OK" || metrics.requests[1] != "GET /clubs/{id} Not Found" {
+		t.Errorf("incorrect requests, got %v", metrics.requests)
+	}
+
+	if len(metrics.usages) != 0 {
+		t.Errorf("rate limit should not be observed without headers, got %v", metrics.usages)
+	}
+
+	client.httpClient = &http.Client{Transport: &rateLimitTransport{usage: "10,1000"}}
+	NewClubsService(client).Get(1).Do()
+
+	if len(metrics.usages) != 1 || metrics.usages[0] != (RateLimitUsage{LimitShort: 600, LimitLong: 30000, UsageShort: 10, UsageLong: 1000}) {
+		t.Errorf("incorrect rate limit usage, got %v", metrics.usages)
+	}
+
+	client.httpClient = &http.Client{Transport: &storeRequestTransport{}}
+	NewClubsService(client).Get(1).Do()
+
+	if len(metrics.requests) != 4 || metrics.requests[3] != "GET /clubs/{id} " {
+		t.Errorf("failed request should be observed without status, got %v", metrics.requests)
+	}
+}
+
+func TestClientWithMetricLabels(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	client, _ := newRouteClient(map[string]string{"/api/v3/clubs/1": `{"id":1}`})
+	WithMetrics(metrics)(client)
+	NewClubsService(client).Get(1).Do()
+
+	if metrics.labels[0] != (MetricLabels{}) {
+		t.Errorf("observations should not be labeled by default, got %+v", metrics.labels[0])
+	}
+
+	WithMetricLabels("app-a", nil)(client)
+	NewClubsService(client).Get(1).Do()
+
+	if metrics.labels[1] != (MetricLabels{Tenant: "app-a"}) {
+		t.Errorf("incorrect labels, got %+v", metrics.labels[1])
+	}
+
+	// the athlete is only labeled if known
+	WithMetricLabels("app-a", []byte("secret"))(client)
+	NewClubsService(client).Get(1).Do()
+
+	if metrics.labels[2].Athlete != "" {
+		t.Errorf("athlete should not be labeled without athlete, got %+v", metrics.labels[2])
+	}
+
+	client.tokenSource.(*stubTokenSource).response.Athlete = &AthleteDetailed{AthleteSummary: AthleteSummary{AthleteMeta: AthleteMeta{Id: 227615}}}
+	NewClubsService(client).Get(1).Do()
+
+	hash := metrics.labels[3].Athlete
+	if len(hash) != 16 || hash == athleteHash([]byte("secret"), "app-b", 227615) || hash == athleteHash([]byte("other"), "app-a", 227615) || strings.Contains(hash, "227615") {
+		t.Errorf("incorrect athlete hash, got %+v", metrics.labels[3])
+	}
+
+	NewClubsService(client).Get(1).Do()
+	if metrics.labels[4].Athlete != hash {
+		t.Errorf("hash should be stable, got %v and %v", hash, metrics.labels[4].Athlete)
+	}
+}
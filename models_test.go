@@ -0,0 +1,183 @@
+package strava
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// models are the types decoded from responses of Strava, which should survive a json round trip.
+// StreamSet is tested separately, its RawData refers to its Data.
+var models = []interface{}{
+	&ActivityDetailed{}, &ActivitySummary{}, &BestEffort{},
+	&AthleteDetailed{}, &AthleteSummary{}, &AthleteStats{}, &AthleteTotals{},
+	&ClubDetailed{}, &ClubSummary{},
+	&CommentDetailed{}, &CommentSummary{},
+	&EffortSummary{}, &LapEffortSummary{},
+	&GearDetailed{}, &GearSummary{},
+	&AuthorizationResponse{},
+	&PhotoSummary{},
+	&SegmentEffortDetailed{}, &SegmentEffortSummary{},
+	&SegmentDetailed{}, &SegmentSummary{}, &PersonalSegmentSummary{},
+	&SegmentLeaderboard{}, &SegmentLeaderboardEntry{}, &SegmentExplorerSegment{},
+	&Split{},
+	&UploadDetailed{}, &UploadSummary{},
+	&WebhookEvent{},
+	&ZonesSummary{}, &ZoneBucket{},
+}
+
+// fillModel sets every exported field of the value v points to, to a non zero value derived from seed.
+func fillModel(v reflect.Value, seed int) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		fillModel(v.Elem(), seed)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2024, 5, 1, 8, seed%60, 0, 0, time.UTC)))
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() && v.Type().Field(i).Tag.Get("json") != "-" {
+				fillModel(v.Field(i), seed+i+1)
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := 0; i < v.Len(); i++ {
+			fillModel(v.Index(i), seed+i)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillModel(v.Index(i), seed+i)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key, value := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fillModel(key, seed)
+		fillModel(value, seed+1)
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("value" + string(rune('a'+seed%26)))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(seed%100 + 1))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(seed%100) + 0.5)
+	}
+}
+
+func TestModelsJSONRoundTrip(t *testing.T) {
+	for _, model := range models {
+		original := reflect.New(reflect.TypeOf(model).Elem())
+		fillModel(original, 0)
+
+		data, err := json.Marshal(original.Interface())
+		if err != nil {
+			t.Errorf("%T: marshal error: %v", model, err)
+			continue
+		}
+
+		decoded := reflect.New(reflect.TypeOf(model).Elem())
+		if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+			t.Errorf("%T: unmarshal error: %v", model, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(original.Interface(), decoded.Interface()) {
+			t.Errorf("%T: not equal after round trip\n%s", model, data)
+		}
+	}
+}
+
+func TestModelsExportedFields(t *testing.T) {
+	// unexported fields would be lost when a model is persisted as json
+	var check func(typ reflect.Type, path string)
+	seen := make(map[reflect.Type]bool)
+	check = func(typ reflect.Type, path string) {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+
+		// RawJSON is deliberately not encoded, the raw json is the model itself
+		if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) || typ == reflect.TypeOf(RawJSON{}) || seen[typ] {
+			return
+		}
+		seen[typ] = true
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				t.Errorf("%s.%s is not encoded", path, field.Name)
+				continue
+			}
+			check(field.Type, path+"."+field.Name)
+		}
+	}
+
+	for _, model := range models {
+		check(reflect.TypeOf(model), reflect.TypeOf(model).Elem().Name())
+	}
+}
+
+func TestActivityJSONRoundTripAliases(t *testing.T) {
+	var original ActivityDetailed
+	json.Unmarshal([]byte(`{"id":1,"type":"Run","calories":500}`), &original)
+
+	data, _ := json.Marshal(&original)
+
+	var decoded ActivityDetailed
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, decoded) || decoded.SportType != ActivityTypes.Run || decoded.Calories != 500 {
+		t.Errorf("activity not equal after round trip, got %+v", decoded)
+	}
+}
+
+func TestStreamSetJSONRoundTrip(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/activities/1/streams/time,latlng,altitude,heartrate,moving": `[
+			{"type":"time","data":[0,1,2],"series_type":"distance","original_size":3,"resolution":"high"},
+			{"type":"latlng","data":[[52.1,5.1],[52.2,5.2],[52.3,5.3]],"series_type":"distance","original_size":3,"resolution":"high"},
+			{"type":"altitude","data":[1.5,null,2.5],"series_type":"distance","original_size":3,"resolution":"high"},
+			{"type":"heartrate","data":[120,null,130],"series_type":"distance","original_size":3,"resolution":"high"},
+			{"type":"moving","data":[false,true,true],"series_type":"distance","original_size":3,"resolution":"high"}
+		]`,
+	})
+
+	original, err := NewActivityStreamsService(client).
+		Get(1, []StreamType{StreamTypes.Time, StreamTypes.Location, StreamTypes.Elevation, StreamTypes.HeartRate, StreamTypes.Moving}).
+		Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	var decoded StreamSet
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, &decoded) {
+		t.Errorf("streams not equal after round trip\n%s", data)
+	}
+
+	if decoded.Elevation.RawData[1] != nil || decoded.HeartRate.RawData[1] != nil {
+		t.Error("missing values should stay missing")
+	}
+
+	if decoded.HeartRate.RawData[0] != &decoded.HeartRate.Data[0] {
+		t.Error("raw data should refer to the data")
+	}
+}
@@ -1,6 +1,7 @@
 package strava
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // An OAuthAuthenticator holds state about how OAuth requests should be authenticated.
@@ -22,8 +24,26 @@ type OAuthAuthenticator struct {
 	// can be used to create a client using the incoming request, for Example:
 	//    func(r *http.Request) { return urlfetch.Client(appengine.NewContext(r)) }
 	requestClientGenerator func(r *http.Request) *http.Client
+
+	// oauthBaseURL is the base of the authorization and token exchange urls,
+	// if empty the oauth urls of baseURL are used, or oauthBasePath if that is empty too.
+	oauthBaseURL string
+
+	// baseURL is the base of the api, as set on clients with WithBaseURL
+	baseURL string
+
+	// credentials of the application, if not set the package level ones are used
+	clientId     int
+	clientSecret string
+
+	// scopes the user must grant, see RequireScopes
+	requiredScopes []Scope
 }
 
+// oauthBasePath is where users log in and authorize applications,
+// this is the web host rather than the api host.
+const oauthBasePath = "https://www.strava.com/oauth"
+
 // NewOAuthAuthenticator creates a new OAuthAuthenticator instance.
 func NewOAuthAuthenticator(tokenSource TokenSource, callbackUrl string) (*OAuthAuthenticator, error) {
 	return &OAuthAuthenticator{
@@ -32,6 +52,44 @@ func NewOAuthAuthenticator(tokenSource TokenSource, callbackUrl string) (*OAuthA
 	}, nil
 }
 
+// SetOAuthBaseURL overrides the base url used to build the AuthorizationURL and to complete
+// the token exchange, for example to point them at a mock server. Defaults to https://www.strava.com/oauth.
+func (auth *OAuthAuthenticator) SetOAuthBaseURL(baseURL string) {
+	auth.oauthBaseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetBaseURL points the authenticator at the api base url also given to clients with WithBaseURL,
+// e.g. a mock server. The authorization and token exchange urls become baseURL/oauth/authorize and
+// baseURL/oauth/token, unless SetOAuthBaseURL was used.
+func (auth *OAuthAuthenticator) SetBaseURL(baseURL string) {
+	auth.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetCredentials sets the id and secret of the Strava application users authorize.
+// If not set, the deprecated package level ClientId and ClientSecret are used.
+func (auth *OAuthAuthenticator) SetCredentials(clientId int, clientSecret string) {
+	auth.clientId = clientId
+	auth.clientSecret = clientSecret
+}
+
+// credentials returns the credentials of the authenticator, or the package level ones if it has none.
+func (auth OAuthAuthenticator) credentials() (int, string) {
+	return credentialsOrDefault(auth.clientId, auth.clientSecret)
+}
+
+// oauthURL returns the url of the given oauth endpoint, e.g. "/authorize".
+func (auth OAuthAuthenticator) oauthURL(path string) string {
+	if auth.oauthBaseURL != "" {
+		return auth.oauthBaseURL + path
+	}
+
+	if auth.baseURL != "" {
+		return auth.baseURL + "/oauth" + path
+	}
+
+	return oauthBasePath + path
+}
+
 // Scope represents the access of an access_token.
 // The scope type is requested during the token exchange.
 type Scope string
@@ -49,11 +107,17 @@ const (
 // AuthorizationResponse is returned as a result of the token exchange
 type AuthorizationResponse struct {
 	TokenType    string           `json:"token_type"`
-	ExpiresAt    int64            `json:"expires_at"`
+	ExpiresAt    int64            `json:"expires_at"` // in seconds since the epoch, see ExpiresAtTime
 	ExpiresIn    int64            `json:"expires_in"`
 	RefreshToken string           `json:"refresh_token"`
 	AccessToken  string           `json:"access_token"`
 	Athlete      *AthleteDetailed `json:"athlete,omitempty"`
+	Scopes       []Scope          `json:"scopes,omitempty"` // granted by the user, see AuthorizeFromRequest
+}
+
+// ExpiresAtTime returns when the access token expires.
+func (r *AuthorizationResponse) ExpiresAtTime() time.Time {
+	return time.Unix(r.ExpiresAt, 0)
 }
 
 // CallbackPath returns the path portion of the callbackUrl.
@@ -74,10 +138,57 @@ func (auth OAuthAuthenticator) CallbackPath() (string, error) {
 // Authorize performs the second part of the OAuth exchange. The client has already been redirected to the
 // Strava authorization page, has granted authorization to the application and has been redirected back to the
 // defined URL. The code param was returned as a query string param in to the redirect_url.
-func (auth OAuthAuthenticator) Authorize(code string, state string, client *http.Client) error {
+// The tokens and athlete are saved in the TokenSource and returned. The exchange is canceled with ctx.
+func (auth OAuthAuthenticator) Authorize(ctx context.Context, code string, state string, client *http.Client) (*AuthorizationResponse, error) {
+	response, err := auth.exchange(ctx, code, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.tokenSource.SaveAuthorizationResponse(state, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// AuthorizeFromRequest completes the token exchange of the request Strava redirected the user back with
+// and returns the tokens, athlete and granted scopes, without saving them, for frameworks that don't use
+// HandlerFunc, e.g. with c.Request of gin or echo. The client of the exchange is the one of the
+// requestClientGenerator, if any. Users denying the authorization fail with OAuthAuthorizationDeniedErr,
+// users not granting the scopes of RequireScopes with an *ErrMissingScope, before the exchange.
+func (auth OAuthAuthenticator) AuthorizeFromRequest(r *http.Request) (*AuthorizationResponse, error) {
+	// user denied authorization
+	if r.FormValue("error") == "access_denied" {
+		return nil, OAuthAuthorizationDeniedErr
+	}
+
+	// users may uncheck the scopes they don't want to grant
+	scopes := ParseScopes(r.FormValue("scope"))
+	if err := RequireScopes(scopes, auth.requiredScopes...); err != nil {
+		return nil, err
+	}
+
+	// use the client generator if provided.
+	client := http.DefaultClient
+	if auth.requestClientGenerator != nil {
+		client = auth.requestClientGenerator(r)
+	}
+
+	response, err := auth.exchange(r.Context(), r.FormValue("code"), client)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Scopes = scopes
+	return response, nil
+}
+
+// exchange exchanges the code for the tokens, with the default client if client is nil.
+func (auth OAuthAuthenticator) exchange(ctx context.Context, code string, client *http.Client) (*AuthorizationResponse, error) {
 	// make sure a code was passed
 	if code == "" {
-		return OAuthInvalidCodeErr
+		return nil, OAuthInvalidCodeErr
 	}
 
 	// if a client wasn't passed use the default client
@@ -85,18 +196,31 @@ func (auth OAuthAuthenticator) Authorize(code string, state string, client *http
 		client = http.DefaultClient
 	}
 
-	resp, err := client.PostForm(basePath+"/oauth/token",
-		url.Values{"client_id": {fmt.Sprintf("%d", ClientId)}, "client_secret": {ClientSecret}, "code": {code}})
+	clientId, clientSecret := auth.credentials()
+
+	values := make(url.Values)
+	values.Set("client_id", fmt.Sprintf("%d", clientId))
+	values.Set("client_secret", clientSecret)
+	values.Set("code", code)
+	values.Set("grant_type", "authorization_code")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", auth.oauthURL("/token"), strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
 
 	// this was a poor request, maybe strava servers down?
 	if err != nil {
-		return err
+		return nil, redactError(err)
 	}
 	defer resp.Body.Close()
 
 	// check status code, could be 500, or most likely the client_secret is incorrect
 	if resp.StatusCode/100 == 5 {
-		return OAuthServerErr
+		return nil, OAuthServerErr
 	}
 
 	if resp.StatusCode/100 != 2 {
@@ -104,32 +228,32 @@ func (auth OAuthAuthenticator) Authorize(code string, state string, client *http
 		contents, _ := io.ReadAll(resp.Body)
 		err = json.Unmarshal(contents, &response)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if len(response.Errors) == 0 {
-			return OAuthServerErr
+			return nil, OAuthServerErr
 		}
 
 		if response.Errors[0].Resource == "Application" {
-			return OAuthInvalidCredentialsErr
+			return nil, OAuthInvalidCredentialsErr
 		}
 
 		if response.Errors[0].Resource == "RequestToken" {
-			return OAuthInvalidCodeErr
+			return nil, OAuthInvalidCodeErr
 		}
 
-		return &response
+		return nil, &response
 	}
 
 	var response AuthorizationResponse
 	contents, _ := io.ReadAll(resp.Body)
 	err = json.Unmarshal(contents, &response)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return auth.tokenSource.SaveAuthorizationResponse(state, &response)
+	return &response, nil
 }
 
 // HandlerFunc builds a http.HandlerFunc that will complete the token exchange
@@ -140,19 +264,11 @@ func (auth OAuthAuthenticator) HandlerFunc(
 	failure func(err error, w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		// user denied authorization
-		if r.FormValue("error") == "access_denied" {
-			failure(OAuthAuthorizationDeniedErr, w, r)
-			return
+		response, err := auth.AuthorizeFromRequest(r)
+		if err == nil {
+			err = auth.tokenSource.SaveAuthorizationResponse(r.FormValue("state"), response)
 		}
 
-		// use the client generator if provided.
-		client := http.DefaultClient
-		if auth.requestClientGenerator != nil {
-			client = auth.requestClientGenerator(r)
-		}
-
-		err := auth.Authorize(r.FormValue("code"), r.FormValue("state"), client)
 		if err != nil {
 			failure(err, w, r)
 			return
@@ -162,23 +278,37 @@ func (auth OAuthAuthenticator) HandlerFunc(
 }
 
 // AuthorizationURL constructs the url a user should use to authorize this specific application.
+// All parameters are query escaped, so callback urls with a query string and states
+// containing special characters survive the redirect intact.
 func (auth OAuthAuthenticator) AuthorizationURL(state string, scopes []Scope, force bool) string {
 	var s []string
 	for _, scope := range scopes {
 		s = append(s, string(scope))
 	}
 
-	path := fmt.Sprintf("%s/oauth/authorize?client_id=%d&response_type=code&redirect_uri=%s&scope=%v", basePath, ClientId, auth.callbackUrl, strings.Join(s, ","))
+	clientId, _ := auth.credentials()
+
+	values := make(url.Values)
+	values.Set("client_id", fmt.Sprintf("%d", clientId))
+	values.Set("response_type", "code")
+	values.Set("redirect_uri", auth.callbackUrl)
+	values.Set("scope", strings.Join(s, ","))
 
 	if state != "" {
-		path += "&state=" + state
+		values.Set("state", state)
 	}
 
 	if force {
-		path += "&approval_prompt=force"
+		values.Set("approval_prompt", "force")
 	}
 
-	return path
+	return auth.oauthURL("/authorize") + "?" + values.Encode()
+}
+
+// ParsedAuthorizationURL returns the AuthorizationURL as a *url.URL, for when the url needs
+// to be manipulated further. Only fails if the url set with SetOAuthBaseURL is invalid.
+func (auth OAuthAuthenticator) ParsedAuthorizationURL(state string, scopes []Scope, force bool) (*url.URL, error) {
+	return url.Parse(auth.AuthorizationURL(state, scopes, force))
 }
 
 /*********************************************************/
@@ -192,7 +322,11 @@ func NewOAuthService(client *Client) *OAuthService {
 }
 
 type OAuthDeauthorizeCall struct {
-	service *OAuthService
+	service      *OAuthService
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *OAuthService) Deauthorize() *OAuthDeauthorizeCall {
@@ -201,7 +335,34 @@ func (s *OAuthService) Deauthorize() *OAuthDeauthorizeCall {
 	}
 }
 
+func (c *OAuthDeauthorizeCall) OnError(handler ErrorHandler) *OAuthDeauthorizeCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *OAuthDeauthorizeCall) Response(resp *Response) *OAuthDeauthorizeCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *OAuthDeauthorizeCall) Timeout(d time.Duration) *OAuthDeauthorizeCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *OAuthDeauthorizeCall) Header(key, value string) *OAuthDeauthorizeCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *OAuthDeauthorizeCall) Do() error {
-	_, err := c.service.client.run("POST", "/oauth/deauthorize", nil)
-	return err
+	_, err := c.service.client.runWithErrorHandler("POST", "/oauth/deauthorize", nil, c.errorHandler, c.response, c.timeout, c.header)
+	return wrapError(err, "oauth.deauthorize")
 }
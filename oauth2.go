@@ -0,0 +1,152 @@
+package strava
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// OAuth2Endpoint is the authorization and token exchange urls of Strava, for golang.org/x/oauth2.
+// Strava expects the credentials of the application in the parameters of the requests.
+var OAuth2Endpoint = oauth2.Endpoint{
+	AuthURL:   oauthBasePath + "/authorize",
+	TokenURL:  oauthBasePath + "/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// OAuth2Config returns the golang.org/x/oauth2 config of the application, so the authorization and token
+// exchange integrate with the Go oauth2 ecosystem. The scopes are passed as one comma separated scope, the
+// separator Strava expects. Convert the token of config.Exchange with AuthorizationResponseFromToken.
+func OAuth2Config(clientId int, clientSecret, redirectURL string, scopes ...Scope) *oauth2.Config {
+	var s []string
+	for _, scope := range scopes {
+		s = append(s, string(scope))
+	}
+
+	config := &oauth2.Config{
+		ClientID:     strconv.Itoa(clientId),
+		ClientSecret: clientSecret,
+		Endpoint:     OAuth2Endpoint,
+		RedirectURL:  redirectURL,
+	}
+
+	if len(s) > 0 {
+		config.Scopes = []string{strings.Join(s, ",")}
+	}
+
+	return config
+}
+
+// OAuth2Token returns the tokens as an oauth2.Token, with the athlete, if any, in its extra "athlete" field.
+func (r *AuthorizationResponse) OAuth2Token() *oauth2.Token {
+	token := &oauth2.Token{
+		AccessToken:  r.AccessToken,
+		TokenType:    r.TokenType,
+		RefreshToken: r.RefreshToken,
+		Expiry:       r.ExpiresAtTime(),
+	}
+
+	if token.TokenType == "" {
+		token.TokenType = "Bearer"
+	}
+
+	if r.Athlete != nil {
+		token = token.WithExtra(map[string]interface{}{"athlete": r.Athlete})
+	}
+
+	return token
+}
+
+// AuthorizationResponseFromToken returns the oauth2.Token as tokens of this package, with the athlete
+// if the token holds it, as the tokens of an exchange do. Tokens without an expiry are valid for an hour.
+func AuthorizationResponseFromToken(token *oauth2.Token) *AuthorizationResponse {
+	expiry := token.Expiry
+	if expiry.IsZero() {
+		expiry = time.Now().Add(time.Hour)
+	}
+
+	response := &AuthorizationResponse{
+		TokenType:    token.TokenType,
+		ExpiresAt:    expiry.Unix(),
+		ExpiresIn:    int64(time.Until(expiry) / time.Second),
+		RefreshToken: token.RefreshToken,
+		AccessToken:  token.AccessToken,
+	}
+
+	switch athlete := token.Extra("athlete").(type) {
+	case *AthleteDetailed:
+		response.Athlete = athlete
+	case map[string]interface{}:
+		// decoded from the json of the token response
+		if data, err := json.Marshal(athlete); err == nil {
+			json.Unmarshal(data, &response.Athlete)
+		}
+	}
+
+	return response
+}
+
+/*********************************************************/
+
+// OAuth2TokenSource returns the tokens of the client as an oauth2.TokenSource, e.g. for an oauth2.Transport.
+// Expired tokens are refreshed, and saved in the TokenSource of the client, as before its calls.
+func (client *Client) OAuth2TokenSource() oauth2.TokenSource {
+	return clientTokenSource{client}
+}
+
+type clientTokenSource struct {
+	client *Client
+}
+
+func (s clientTokenSource) Token() (*oauth2.Token, error) {
+	response, err := s.client.validateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return response.OAuth2Token(), nil
+}
+
+// TokenSourceFromOAuth2 returns the oauth2.TokenSource as a TokenSource of a client, to use the tokens
+// of existing oauth2 storage. The source is expected to refresh its tokens, e.g. one of oauth2.Config.TokenSource.
+// Tokens the client refreshes itself, when the source returns expired ones, are kept in memory.
+func TokenSourceFromOAuth2(source oauth2.TokenSource) TokenSource {
+	return &oauth2TokenSource{source: source}
+}
+
+type oauth2TokenSource struct {
+	source oauth2.TokenSource
+
+	lock      sync.Mutex
+	refreshed *AuthorizationResponse
+}
+
+func (s *oauth2TokenSource) GetAuthorizationResponse() (*AuthorizationResponse, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	response := AuthorizationResponseFromToken(token)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.refreshed != nil && s.refreshed.ExpiresAt > response.ExpiresAt {
+		return s.refreshed, nil
+	}
+
+	return response, nil
+}
+
+func (s *oauth2TokenSource) SaveAuthorizationResponse(state string, response *AuthorizationResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refreshed = response
+	return nil
+}
@@ -0,0 +1,103 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestOAuth2Config(t *testing.T) {
+	config := OAuth2Config(1, "secret", "http://abc.com/strava/oauth", ScopeRead, ScopeActivityReadAll)
+
+	u, err := url.Parse(config.AuthCodeURL("state"))
+	if err != nil {
+		t.Fatalf("url error: %v", err)
+	}
+
+	if u.Host != "www.strava.com" || u.Query().Get("client_id") != "1" || u.Query().Get("scope") != "read,activity:read_all" {
+		t.Errorf("incorrect authorization url, got %v", u)
+	}
+
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token_type":"Bearer","expires_in":21600,"access_token":"abc","refresh_token":"def","athlete":{"id":1,"firstname":"Jane"}}`))
+	}))
+	defer server.Close()
+
+	config.Endpoint.TokenURL = server.URL + "/oauth/token"
+
+	token, err := config.Exchange(context.Background(), "75e251e3ff8fff")
+	if err != nil {
+		t.Fatalf("exchange error: %v", err)
+	}
+
+	if form.Get("client_secret") != "secret" || form.Get("code") != "75e251e3ff8fff" || form.Get("grant_type") != "authorization_code" {
+		t.Errorf("incorrect exchange, got %v", form)
+	}
+
+	response := AuthorizationResponseFromToken(token)
+	if response.AccessToken != "abc" || response.RefreshToken != "def" || response.Athlete == nil || response.Athlete.FirstName != "Jane" {
+		t.Errorf("incorrect response, got %+v", response)
+	}
+
+	if tokenExpired(response, time.Now().Add(5*time.Hour), TokenRefreshMargin) || !tokenExpired(response, time.Now().Add(7*time.Hour), TokenRefreshMargin) {
+		t.Errorf("incorrect expiry, got %v", response.ExpiresAt)
+	}
+
+	if back := response.OAuth2Token(); back.AccessToken != "abc" || back.Extra("athlete") != response.Athlete {
+		t.Errorf("incorrect token, got %+v", back)
+	}
+}
+
+func TestClientOAuth2TokenSource(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/oauth/token": `{"access_token":"refreshed","refresh_token":"refresh"}`,
+	})
+
+	token, err := client.OAuth2TokenSource().Token()
+	if err != nil {
+		t.Fatalf("token error: %v", err)
+	}
+
+	if token.AccessToken != "token" || token.TokenType != "Bearer" || !token.Valid() {
+		t.Errorf("incorrect token, got %+v", token)
+	}
+
+	ts := client.tokenSource.(*stubTokenSource)
+	ts.response.ExpiresAt = 0
+
+	if token, _ := client.OAuth2TokenSource().Token(); token.AccessToken != "refreshed" || ts.saves != 1 {
+		t.Errorf("expired token should be refreshed and saved, got %+v", token)
+	}
+}
+
+func TestTokenSourceFromOAuth2(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/athlete": `{"id":1}`,
+	})
+	client.tokenSource = TokenSourceFromOAuth2(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "oauth2"}))
+
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if auth := transport.requests[0].Header.Get("Authorization"); auth != "Bearer oauth2" {
+		t.Errorf("token of the oauth2 source should be used, got %v", auth)
+	}
+
+	// tokens refreshed by the client are kept
+	source := client.tokenSource
+	source.SaveAuthorizationResponse("", &AuthorizationResponse{AccessToken: "refreshed", ExpiresAt: time.Now().Add(2 * time.Hour).Unix()})
+	if response, _ := source.GetAuthorizationResponse(); response.AccessToken != "refreshed" {
+		t.Errorf("refreshed token should be returned, got %v", response.AccessToken)
+	}
+}
@@ -0,0 +1,126 @@
+package strava
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+// OAuthErrorCategory groups the errors that can occur during the OAuth exchange
+// into the cases a web application wants to present differently to the user.
+type OAuthErrorCategory string
+
+var OAuthErrorCategories = struct {
+	Denied             OAuthErrorCategory
+	InvalidCode        OAuthErrorCategory
+	InvalidCredentials OAuthErrorCategory
+	ServerError        OAuthErrorCategory
+	Unknown            OAuthErrorCategory
+}{"access_denied", "invalid_code", "invalid_credentials", "server_error", "unknown"}
+
+// OAuthErrorCategoryOf returns the category of an error returned by Authorize or passed to
+// the failure callback of HandlerFunc.
+func OAuthErrorCategoryOf(err error) OAuthErrorCategory {
+	switch {
+	case errors.Is(err, OAuthAuthorizationDeniedErr):
+		return OAuthErrorCategories.Denied
+	case errors.Is(err, OAuthInvalidCodeErr):
+		return OAuthErrorCategories.InvalidCode
+	case errors.Is(err, OAuthInvalidCredentialsErr):
+		return OAuthErrorCategories.InvalidCredentials
+	case errors.Is(err, OAuthServerErr):
+		return OAuthErrorCategories.ServerError
+	}
+
+	return OAuthErrorCategories.Unknown
+}
+
+// StatusCode returns the http status code a failure page of this category should be served with.
+func (c OAuthErrorCategory) StatusCode() int {
+	switch c {
+	case OAuthErrorCategories.Denied:
+		return http.StatusForbidden
+	case OAuthErrorCategories.InvalidCode:
+		return http.StatusBadRequest
+	case OAuthErrorCategories.ServerError:
+		return http.StatusBadGateway
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Message returns a message describing the category that is safe to show to users.
+// Other errors are not shown as is, since they may contain details about the application.
+func (c OAuthErrorCategory) Message() string {
+	switch c {
+	case OAuthErrorCategories.Denied:
+		return "Access to your Strava account was not granted."
+	case OAuthErrorCategories.InvalidCode:
+		return "The Strava authorization has expired or was already used."
+	case OAuthErrorCategories.InvalidCredentials:
+		return "This application is not configured correctly to connect with Strava."
+	case OAuthErrorCategories.ServerError:
+		return "Strava could not be reached, please try again later."
+	}
+
+	return "Connecting with Strava failed."
+}
+
+// OAuthFailure is the body rendered by the failure handlers.
+type OAuthFailure struct {
+	Category OAuthErrorCategory `json:"error"`
+	Message  string             `json:"message"`
+	RetryURL string             `json:"retry_url,omitempty"`
+}
+
+func newOAuthFailure(err error, retryURL string) *OAuthFailure {
+	category := OAuthErrorCategoryOf(err)
+	return &OAuthFailure{
+		Category: category,
+		Message:  category.Message(),
+		RetryURL: retryURL,
+	}
+}
+
+// JSONFailureHandler builds a failure callback for HandlerFunc that responds with
+// the error category, a message and the retry url as json, for example:
+//
+//	{"error":"access_denied","message":"...","retry_url":"https://..."}
+//
+// The retryURL is typically the AuthorizationURL, it is omitted if empty.
+func JSONFailureHandler(retryURL string) func(err error, w http.ResponseWriter, r *http.Request) {
+	return func(err error, w http.ResponseWriter, r *http.Request) {
+		failure := newOAuthFailure(err, retryURL)
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(failure.Category.StatusCode())
+		json.NewEncoder(w).Encode(failure)
+	}
+}
+
+var oauthFailureTemplate = template.Must(template.New("failure").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>Connecting with Strava failed</title>
+</head>
+<body>
+	<h1>Connecting with Strava failed</h1>
+	<p class="{{.Category}}">{{.Message}}</p>
+	{{if .RetryURL}}<p><a href="{{.RetryURL}}">Try again</a></p>{{end}}
+</body>
+</html>
+`))
+
+// HTMLFailureHandler builds a failure callback for HandlerFunc that renders a simple html page
+// with a message for the error category and a link to retryURL, if not empty.
+func HTMLFailureHandler(retryURL string) func(err error, w http.ResponseWriter, r *http.Request) {
+	return func(err error, w http.ResponseWriter, r *http.Request) {
+		failure := newOAuthFailure(err, retryURL)
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(failure.Category.StatusCode())
+		oauthFailureTemplate.Execute(w, failure)
+	}
+}
@@ -0,0 +1,89 @@
+package strava
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOAuthErrorCategoryOf(t *testing.T) {
+	cases := map[error]OAuthErrorCategory{
+		OAuthAuthorizationDeniedErr:                        OAuthErrorCategories.Denied,
+		OAuthInvalidCodeErr:                                OAuthErrorCategories.InvalidCode,
+		OAuthInvalidCredentialsErr:                         OAuthErrorCategories.InvalidCredentials,
+		OAuthServerErr:                                     OAuthErrorCategories.ServerError,
+		fmt.Errorf("wrapped: %w", OAuthServerErr):          OAuthErrorCategories.ServerError,
+		errors.New("connection reset"):                     OAuthErrorCategories.Unknown,
+		&Error{Message: "bad", Errors: []*ErrorDetailed{}}: OAuthErrorCategories.Unknown,
+	}
+
+	for err, expected := range cases {
+		if c := OAuthErrorCategoryOf(err); c != expected {
+			t.Errorf("incorrect category for %v, got %v", err, c)
+		}
+	}
+}
+
+func TestJSONFailureHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/oauth?error=access_denied", nil)
+
+	JSONFailureHandler("https://example.com/connect")(OAuthAuthorizationDeniedErr, w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("incorrect status code, got %d", w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("incorrect content type, got %v", ct)
+	}
+
+	var failure OAuthFailure
+	if err := json.Unmarshal(w.Body.Bytes(), &failure); err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+
+	if failure.Category != OAuthErrorCategories.Denied || failure.RetryURL != "https://example.com/connect" || failure.Message == "" {
+		t.Errorf("incorrect failure, got %v", failure)
+	}
+
+	// unknown errors are not exposed
+	w = httptest.NewRecorder()
+	JSONFailureHandler("")(errors.New("secret internals"), w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("incorrect status code, got %d", w.Code)
+	}
+
+	if body := w.Body.String(); strings.Contains(body, "secret internals") || strings.Contains(body, "retry_url") {
+		t.Errorf("incorrect body, got %v", body)
+	}
+}
+
+func TestHTMLFailureHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/oauth?code=abc", nil)
+
+	HTMLFailureHandler("https://example.com/connect?a=1&b=2")(OAuthServerErr, w, r)
+
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("incorrect status code, got %d", w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("incorrect content type, got %v", ct)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, OAuthErrorCategories.ServerError.Message()) {
+		t.Errorf("message not rendered, got %v", body)
+	}
+
+	if !strings.Contains(body, `href="https://example.com/connect?a=1&amp;b=2"`) {
+		t.Errorf("retry link not rendered, got %v", body)
+	}
+}
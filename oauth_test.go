@@ -1,9 +1,14 @@
 package strava
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
@@ -12,7 +17,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		requestClientGenerator: func(r *http.Request) *http.Client { return &http.Client{Transport: &storeRequestTransport{}} },
 	}
 
-	f := auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f := auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should handle request failure")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err == nil {
@@ -28,7 +33,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		requestClientGenerator: func(r *http.Request) *http.Client { return nil },
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should handle request failure")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err == nil {
@@ -41,7 +46,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 
 	// access denied
 	auth = OAuthAuthenticator{}
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("access denied should be failure")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err != OAuthAuthorizationDeniedErr {
@@ -59,7 +64,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should return error when strava returned error")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err != OAuthServerErr {
@@ -77,7 +82,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should return error when strava returned error")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err != OAuthServerErr {
@@ -95,7 +100,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should return error when strava returned error")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err != OAuthInvalidCredentialsErr {
@@ -113,7 +118,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should return error when strava returned error")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err != OAuthInvalidCodeErr {
@@ -131,7 +136,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should return error when strava returned error")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if _, ok := err.(*Error); !ok {
@@ -149,7 +154,7 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("should return error when strava returned error")
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		if err == nil {
@@ -162,12 +167,13 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 
 	// success!
 	auth = OAuthAuthenticator{
+		tokenSource: staticTokenSource{},
 		requestClientGenerator: func(r *http.Request) *http.Client {
 			return NewStubResponseClient(`{}`, http.StatusOK).httpClient
 		},
 	}
 
-	f = auth.HandlerFunc(func(auth *AuthorizationResponse, w http.ResponseWriter, r *http.Request) {
+	f = auth.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 	}, func(err error, w http.ResponseWriter, r *http.Request) {
 		t.Error("should be success")
 	})
@@ -179,10 +185,113 @@ func TestOAuthAuthenticatorCallbackHandler(t *testing.T) {
 func TestOAuthAuthenticatorAuthorize(t *testing.T) {
 	auth := OAuthAuthenticator{}
 
-	_, err := auth.Authorize("", nil)
+	_, err := auth.Authorize(context.Background(), "", "", nil)
 	if err != OAuthInvalidCodeErr {
 		t.Errorf("returned incorrect error, got %v", err)
 	}
+
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/oauth/token": `{"access_token":"abc","refresh_token":"def","athlete":{"id":1}}`,
+	})
+
+	tokenSource := newStubTokenSource()
+	auth = OAuthAuthenticator{tokenSource: tokenSource}
+	auth.SetBaseURL(client.baseURL)
+
+	response, err := auth.Authorize(context.Background(), "75e251e3ff8fff", "state", client.httpClient)
+	if err != nil {
+		t.Fatalf("authorize error: %v", err)
+	}
+
+	if response.AccessToken != "abc" || response.Athlete.Id != 1 || tokenSource.response != response {
+		t.Errorf("response should be returned and saved, got %+v", response)
+	}
+
+	transport.requests[0].ParseForm()
+	if grantType := transport.requests[0].PostForm.Get("grant_type"); grantType != "authorization_code" {
+		t.Errorf("incorrect grant type, got %v", grantType)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	stalling := &http.Client{Transport: stallingTransport{}}
+	if _, err := auth.Authorize(ctx, "75e251e3ff8fff", "state", stalling); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("exchange should end with its context, got %v", err)
+	}
+}
+
+func TestOAuthAuthenticatorAuthorizeFromRequest(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/oauth/token": `{"access_token":"abc","refresh_token":"def","athlete":{"id":1}}`,
+	})
+
+	tokenSource := newStubTokenSource()
+	auth := OAuthAuthenticator{tokenSource: tokenSource}
+	auth.SetBaseURL(client.baseURL)
+	auth.requestClientGenerator = func(r *http.Request) *http.Client { return client.httpClient }
+
+	req, _ := http.NewRequest("GET", "/strava/authorize?code=75e251e3ff8fff&state=state", nil)
+	response, err := auth.AuthorizeFromRequest(req)
+	if err != nil {
+		t.Fatalf("authorize error: %v", err)
+	}
+
+	if response.AccessToken != "abc" || response.Athlete.Id != 1 {
+		t.Errorf("incorrect response, got %+v", response)
+	}
+
+	transport.requests[0].ParseForm()
+	if code := transport.requests[0].PostForm.Get("code"); code != "75e251e3ff8fff" {
+		t.Errorf("incorrect code exchanged, got %v", code)
+	}
+
+	if tokenSource.saves != 0 {
+		t.Error("response should not be saved")
+	}
+
+	req, _ = http.NewRequest("GET", "/strava/authorize?error=access_denied", nil)
+	if _, err := auth.AuthorizeFromRequest(req); err != OAuthAuthorizationDeniedErr {
+		t.Errorf("returned incorrect error, got %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("denied authorization should not be exchanged, got %d requests", len(transport.requests))
+	}
+}
+
+func TestOAuthAuthenticatorGrantedScopes(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/oauth/token": `{"access_token":"abc","refresh_token":"def"}`,
+	})
+
+	auth := OAuthAuthenticator{tokenSource: newStubTokenSource()}
+	auth.SetBaseURL(client.baseURL)
+	auth.requestClientGenerator = func(r *http.Request) *http.Client { return client.httpClient }
+	auth.RequireScopes(ScopeActivityReadAll)
+
+	req, _ := http.NewRequest("GET", "/strava/authorize?code=75e251e3ff8fff&scope=read,activity:read_all", nil)
+	response, err := auth.AuthorizeFromRequest(req)
+	if err != nil {
+		t.Fatalf("authorize error: %v", err)
+	}
+
+	if !reflect.DeepEqual(response.Scopes, []Scope{ScopeRead, ScopeActivityReadAll}) {
+		t.Errorf("incorrect scopes, got %v", response.Scopes)
+	}
+
+	// the user unchecked activity:read_all
+	req, _ = http.NewRequest("GET", "/strava/authorize?code=75e251e3ff8fff&scope=read,activity:read", nil)
+	_, err = auth.AuthorizeFromRequest(req)
+
+	var missing *ErrMissingScope
+	if !errors.As(err, &missing) || missing.Need != ScopeActivityReadAll {
+		t.Errorf("should return missing scope error, got %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("missing scopes should not be exchanged, got %d requests", len(transport.requests))
+	}
 }
 
 func TestOAuthAuthenticatorCallbackPath(t *testing.T) {
@@ -216,26 +325,117 @@ func TestOAuthAuthenticatorAuthorizationURL(t *testing.T) {
 	}
 
 	url := auth.AuthorizationURL("state", []Scope{ScopeRead}, false)
-	if url != basePath+"/oauth/authorize?client_id=0&response_type=code&redirect_uri=http://abc.com/strava/oauth&scope=public&state=state" {
+	if url != "https://www.strava.com/oauth/authorize?client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=read&state=state" {
 		t.Errorf("incorrect oauth url, got %v", url)
 	}
 
 	url = auth.AuthorizationURL("state", []Scope{ScopeRead}, true)
-	if url != basePath+"/oauth/authorize?client_id=0&response_type=code&redirect_uri=http://abc.com/strava/oauth&scope=public&state=state&approval_prompt=force" {
+	if url != "https://www.strava.com/oauth/authorize?approval_prompt=force&client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=read&state=state" {
+		t.Errorf("incorrect oauth url, got %v", url)
+	}
+
+	url = auth.AuthorizationURL("state", []Scope{ScopeReadAll, ScopeActivityRead}, false)
+	if url != "https://www.strava.com/oauth/authorize?client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=read_all%2Cactivity%3Aread&state=state" {
 		t.Errorf("incorrect oauth url, got %v", url)
 	}
 
-	url = auth.AuthorizationURL("state", []Scope{ScopeReadAll}, false)
-	if url != basePath+"/oauth/authorize?client_id=0&response_type=code&redirect_uri=http://abc.com/strava/oauth&scope=view_private&state=state" {
+	url = auth.AuthorizationURL("", []Scope{ScopeRead}, false)
+	if url != "https://www.strava.com/oauth/authorize?client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=read" {
 		t.Errorf("incorrect oauth url, got %v", url)
 	}
 
+	auth.SetOAuthBaseURL("http://localhost:8080/oauth/")
 	url = auth.AuthorizationURL("", []Scope{ScopeRead}, false)
-	if url != basePath+"/oauth/authorize?client_id=0&response_type=code&redirect_uri=http://abc.com/strava/oauth&scope=public" {
+	if url != "http://localhost:8080/oauth/authorize?client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=read" {
 		t.Errorf("incorrect oauth url, got %v", url)
 	}
 }
 
+func TestOAuthAuthenticatorBaseURL(t *testing.T) {
+	auth := OAuthAuthenticator{}
+	auth.SetBaseURL("http://localhost:8080/api/v3/")
+
+	if u := auth.AuthorizationURL("", nil, false); !strings.HasPrefix(u, "http://localhost:8080/api/v3/oauth/authorize?") {
+		t.Errorf("base url not used, got %v", u)
+	}
+
+	client := newStoreRequestClient()
+	auth.Authorize(context.Background(), "code", "", client.httpClient)
+
+	transport := client.httpClient.Transport.(*storeRequestTransport)
+	if u := transport.request.URL.String(); u != "http://localhost:8080/api/v3/oauth/token" {
+		t.Errorf("base url not used for the token exchange, got %v", u)
+	}
+
+	// the oauth base url takes precedence
+	auth.SetOAuthBaseURL("http://localhost:9090/oauth")
+	if u := auth.AuthorizationURL("", nil, false); !strings.HasPrefix(u, "http://localhost:9090/oauth/authorize?") {
+		t.Errorf("oauth base url not used, got %v", u)
+	}
+}
+
+func TestOAuthAuthenticatorCredentials(t *testing.T) {
+	ClientId, ClientSecret = 1, "global"
+	defer func() { ClientId, ClientSecret = 0, "" }()
+
+	auth := OAuthAuthenticator{}
+	if id, secret := auth.credentials(); id != 1 || secret != "global" {
+		t.Errorf("should fall back to the package credentials, got %v %v", id, secret)
+	}
+
+	auth.SetCredentials(2, "secret")
+	if id, secret := auth.credentials(); id != 2 || secret != "secret" {
+		t.Errorf("incorrect credentials, got %v %v", id, secret)
+	}
+
+	u, _ := auth.ParsedAuthorizationURL("", []Scope{ScopeRead}, false)
+	if id := u.Query().Get("client_id"); id != "2" {
+		t.Errorf("incorrect client id, got %v", id)
+	}
+
+	client := newStoreRequestClient()
+	auth.Authorize(context.Background(), "code", "", client.httpClient)
+
+	transport := client.httpClient.Transport.(*storeRequestTransport)
+	transport.request.ParseForm()
+	if transport.request.PostForm.Get("client_id") != "2" || transport.request.PostForm.Get("client_secret") != "secret" {
+		t.Errorf("incorrect credentials sent, got %v", transport.request.PostForm)
+	}
+}
+
+func TestOAuthAuthenticatorParsedAuthorizationURL(t *testing.T) {
+	auth := OAuthAuthenticator{
+		callbackUrl: "http://abc.com/strava/oauth?next=/dashboard&tab=1",
+	}
+
+	u, err := auth.ParsedAuthorizationURL("a b&c=d", []Scope{ScopeRead, ScopeActivityReadAll}, false)
+	if err != nil {
+		t.Fatalf("url error: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("redirect_uri") != "http://abc.com/strava/oauth?next=/dashboard&tab=1" {
+		t.Errorf("redirect uri not escaped, got %v", q.Get("redirect_uri"))
+	}
+
+	if q.Get("state") != "a b&c=d" {
+		t.Errorf("state not escaped, got %v", q.Get("state"))
+	}
+
+	if q.Get("scope") != "read,activity:read_all" {
+		t.Errorf("incorrect scope, got %v", q.Get("scope"))
+	}
+
+	if u.Host != "www.strava.com" || u.Path != "/oauth/authorize" {
+		t.Errorf("incorrect url, got %v", u)
+	}
+
+	auth.SetOAuthBaseURL("http://local%host")
+	if _, err := auth.ParsedAuthorizationURL("", nil, false); err == nil {
+		t.Error("should return error for invalid base url")
+	}
+}
+
 func TestOAuthErrorError(t *testing.T) {
 	err := OAuthAuthorizationDeniedErr
 	if err.Error() != err.message {
@@ -254,7 +454,7 @@ func TestErrorString(t *testing.T) {
 }
 
 func TestOAuthDeauthorize(t *testing.T) {
-	client := newCassetteClient("f774a6dd01b16de401ba1531e770c951dcd7523f", "oauth_deauthorize")
+	client := newFixtureClient(t, "oauth_deauthorize")
 	err := NewOAuthService(client).Deauthorize().Do()
 
 	if err != nil {
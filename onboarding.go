@@ -0,0 +1,99 @@
+package strava
+
+import (
+	"context"
+)
+
+// OnboardingStep is a step of onboarding an athlete, see Onboarder.
+type OnboardingStep string
+
+var OnboardingSteps = struct {
+	Authorized OnboardingStep
+	Profile    OnboardingStep
+	Zones      OnboardingStep
+	Backfill   OnboardingStep
+	Done       OnboardingStep
+}{"authorized", "profile", "zones", "backfill", "done"}
+
+// An OnboardingEvent reports the progress of onboarding an athlete, e.g. for a progress bar.
+type OnboardingEvent struct {
+	Step OnboardingStep
+	Err  error // the step failed, only reported for the zones, other failures end the onboarding
+}
+
+// Onboarding is the result of onboarding an athlete.
+type Onboarding struct {
+	Client  *Client
+	Athlete *AthleteDetailed
+	Zones   *AthleteZones // nil if the zones could not be fetched, e.g. without the profile:read_all scope
+}
+
+// An Onboarder runs the steps every application connected to Strava takes once an athlete authorized it:
+// fetching the profile and zones of the athlete and starting the backfill of the activities.
+type Onboarder struct {
+	clientFor func(auth *AuthorizationResponse) (*Client, error)
+	backfill  func(ctx context.Context, onboarding *Onboarding) error
+	progress  func(event OnboardingEvent)
+}
+
+// NewOnboarder creates an onboarder making the calls with the client returned by clientFor for the tokens
+// of the athlete, e.g. a client with a TokenSource in which the tokens were saved.
+func NewOnboarder(clientFor func(auth *AuthorizationResponse) (*Client, error)) *Onboarder {
+	return &Onboarder{clientFor: clientFor}
+}
+
+// Backfill sets the function starting the backfill of the activities of the athlete, called last.
+// A long backfill should be started in the background, e.g. by queueing a job.
+func (o *Onboarder) Backfill(backfill func(ctx context.Context, onboarding *Onboarding) error) *Onboarder {
+	o.backfill = backfill
+	return o
+}
+
+// OnProgress sets the function receiving an event for every step taken.
+func (o *Onboarder) OnProgress(progress func(event OnboardingEvent)) *Onboarder {
+	o.progress = progress
+	return o
+}
+
+// Onboard onboards the athlete of the authorization, e.g. in the success callback of OAuthAuthenticator.HandlerFunc.
+// Missing zones don't fail the onboarding, other failures and the cancellation of ctx stop it with the error.
+func (o *Onboarder) Onboard(ctx context.Context, auth *AuthorizationResponse) (*Onboarding, error) {
+	client, err := o.clientFor(auth)
+	if err != nil {
+		return nil, wrapError(err, "onboard")
+	}
+
+	onboarding := &Onboarding{Client: client}
+	o.report(OnboardingSteps.Authorized, nil)
+
+	if err := ctx.Err(); err != nil {
+		return nil, wrapError(err, "onboard")
+	}
+
+	if onboarding.Athlete, err = NewCurrentAthleteService(client).Get().Do(); err != nil {
+		return nil, wrapError(err, "onboard")
+	}
+	o.report(OnboardingSteps.Profile, nil)
+
+	onboarding.Zones, err = Call[AthleteZones](ctx, client, "GET", "/athlete/zones", nil)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, wrapError(ctxErr, "onboard")
+	}
+	o.report(OnboardingSteps.Zones, err)
+
+	if o.backfill != nil {
+		if err := o.backfill(ctx, onboarding); err != nil {
+			return nil, wrapError(err, "onboard")
+		}
+		o.report(OnboardingSteps.Backfill, nil)
+	}
+
+	o.report(OnboardingSteps.Done, nil)
+	return onboarding, nil
+}
+
+func (o *Onboarder) report(step OnboardingStep, err error) {
+	if o.progress != nil {
+		o.progress(OnboardingEvent{Step: step, Err: err})
+	}
+}
@@ -0,0 +1,70 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOnboarder(t *testing.T) {
+	routes := map[string]string{
+		"/api/v3/athlete":       `{"id":1,"firstname":"Jane"}`,
+		"/api/v3/athlete/zones": `{"heart_rate":{"custom_zones":false,"zones":[{"min":0,"max":120},{"min":120,"max":-1}]}}`,
+	}
+	client, _ := newRouteClient(routes)
+
+	var steps []OnboardingStep
+	var backfilled *AthleteDetailed
+	onboarder := NewOnboarder(func(auth *AuthorizationResponse) (*Client, error) {
+		return client, nil
+	}).Backfill(func(ctx context.Context, onboarding *Onboarding) error {
+		backfilled = onboarding.Athlete
+		return nil
+	}).OnProgress(func(event OnboardingEvent) {
+		if event.Err != nil {
+			t.Errorf("%s should not fail, got %v", event.Step, event.Err)
+		}
+		steps = append(steps, event.Step)
+	})
+
+	onboarding, err := onboarder.Onboard(context.Background(), &AuthorizationResponse{})
+	if err != nil {
+		t.Fatalf("onboard error: %v", err)
+	}
+
+	if onboarding.Athlete.FirstName != "Jane" || len(onboarding.Zones.HeartRate.Zones) != 2 || onboarding.Zones.Power != nil {
+		t.Errorf("incorrect onboarding, got %+v", onboarding)
+	}
+
+	if backfilled != onboarding.Athlete {
+		t.Error("backfill should start with the athlete")
+	}
+
+	expected := []OnboardingStep{OnboardingSteps.Authorized, OnboardingSteps.Profile, OnboardingSteps.Zones, OnboardingSteps.Backfill, OnboardingSteps.Done}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Errorf("incorrect steps, got %v", steps)
+	}
+
+	// missing zones don't fail the onboarding
+	delete(routes, "/api/v3/athlete/zones")
+	var zonesErr error
+	onboarding, err = onboarder.OnProgress(func(event OnboardingEvent) {
+		if event.Step == OnboardingSteps.Zones {
+			zonesErr = event.Err
+		}
+	}).Onboard(context.Background(), &AuthorizationResponse{})
+	if err != nil {
+		t.Fatalf("onboard error: %v", err)
+	}
+
+	if onboarding.Zones != nil || !errors.Is(zonesErr, ErrNotFound) {
+		t.Errorf("zones should be missing, got %+v and %v", onboarding.Zones, zonesErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := onboarder.Onboard(ctx, &AuthorizationResponse{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled onboarding should fail, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package strava
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// An Option configures a Client, see NewClient.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used for requests, e.g. when http.DefaultClient can not be used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base url of the api, e.g. to point the client at a mock server or a proxy.
+// Defaults to https://www.strava.com/api/v3.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// apiPath returns the path of the base url, e.g. /api/v3.
+func (client *Client) apiPath() string {
+	u, err := url.Parse(client.baseURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Path
+}
+
+// WithRateLimiter sets the RateLimit updated after every request of the client,
+// so clients of different applications don't share the global RateLimiting.
+func WithRateLimiter(rateLimit *RateLimit) Option {
+	return func(c *Client) {
+		c.rateLimit = rateLimit
+	}
+}
+
+// WithLogger sets the Logger of the client, see Client.Logger.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request. Defaults to caselongo/strava-go.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithCredentials sets the credentials of the application, see Client.Credentials.
+func WithCredentials(clientId int, clientSecret string) Option {
+	return func(c *Client) {
+		c.clientId = clientId
+		c.clientSecret = clientSecret
+	}
+}
+
+// WithTimeout sets the timeout of the calls of the client that have no Timeout of their own,
+// independent of the timeout of the http.Client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRefreshMargin sets how long before it expires the token of the client is refreshed, e.g. a minute for
+// slow uploads, so it doesn't expire while a request is made. Defaults to TokenRefreshMargin.
+// Zero refreshes tokens once they expired.
+func WithRefreshMargin(margin time.Duration) Option {
+	return func(c *Client) {
+		c.refreshMargin = margin
+	}
+}
+
+// WithErrorHandler sets the ErrorHandler of the client, see Client.OnError.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(c *Client) {
+		c.errorHandler = handler
+	}
+}
+
+// WithTokenRefreshed sets the function called after every refresh of the token, see Client.OnTokenRefreshed.
+func WithTokenRefreshed(callback TokenRefreshedFunc) Option {
+	return func(c *Client) {
+		c.tokenRefreshed = callback
+	}
+}
+
+// ClientOptions describe how a client is configured, see Client.ClientOptionsSnapshot.
+// Experimental subsystems are off unless enabled with their option.
+type ClientOptions struct {
+	BaseURL        string `json:"base_url"`
+	UserAgent      string `json:"user_agent"`
+	Credentials    bool   `json:"credentials"`    // the client has credentials of its own, see WithCredentials
+	ErrorHandler   bool   `json:"error_handler"`  // a custom ErrorHandler is set
+	SharedLimiter  bool   `json:"shared_limiter"` // rate limits are tracked in the global RateLimiting
+	Logger         bool   `json:"logger"`
+	Tracer         bool   `json:"tracer"`
+	Metrics        bool   `json:"metrics"`
+	MetricTenant   string `json:"metric_tenant,omitempty"`
+	MetricAthlete  bool   `json:"metric_athlete"` // observations are labeled with an HMAC of the athlete id
+	ScopeCheck     bool   `json:"scope_check"`
+	Subscribed     bool   `json:"subscribed"` // false for clients created WithSubscription(false)
+	RawJSON        bool   `json:"raw_json"`
+	StrictJSON     bool   `json:"strict_json"`
+	DryRun         bool   `json:"dry_run"`    // writes are not sent, see WithDryRun
+	ReadOnly       bool   `json:"read_only"`  // writes are rejected, see WithReadOnly
+	Restricted     bool   `json:"restricted"` // only some requests are allowed, see Client.Restrict
+	ETags          bool   `json:"etags"`      // conditional requests, see WithETags
+	Cache          bool   `json:"cache"`
+	Coalescing     bool   `json:"coalescing"`      // concurrent GET requests to the same url are shared
+	CircuitBreaker bool   `json:"circuit_breaker"` // requests stop while Strava keeps failing, see WithCircuitBreaker
+	Dump           bool   `json:"dump"`            // requests and responses are dumped, see Client.Dump
+	Validators     int    `json:"validators"`      // number of validators run on decoded models, see WithValidators
+	Concurrency    int    `json:"concurrency"`     // cap of the requests in flight at once, 0 for none, see WithMaxConcurrency
+	Retries        int    `json:"retries"`         // of calls during maintenance of Strava, see WithMaintenanceRetry
+
+	RequestDelay  time.Duration `json:"request_delay"` // minimum time between requests, see WithRequestDelay
+	RequestJitter time.Duration `json:"request_jitter"`
+	CacheTTL      time.Duration `json:"cache_ttl"`
+	Timeout       time.Duration `json:"timeout"`        // of calls without a Timeout of their own, see WithTimeout
+	RefreshMargin time.Duration `json:"refresh_margin"` // tokens are refreshed this long before they expire
+}
+
+// ClientOptionsSnapshot returns the options of the client, so operators can confirm which subsystems
+// are enabled in production, e.g. by logging it on start or serving it on a debug endpoint.
+func (client *Client) ClientOptionsSnapshot() ClientOptions {
+	options := ClientOptions{
+		BaseURL:        client.baseURL,
+		UserAgent:      client.userAgent,
+		Credentials:    client.clientId != 0 || client.clientSecret != "",
+		ErrorHandler:   client.errorHandler != nil,
+		SharedLimiter:  client.rateLimit == &RateLimiting,
+		Logger:         client.logger != nil,
+		Tracer:         client.tracer != nil,
+		Metrics:        client.metrics != nil,
+		MetricTenant:   client.metricTenant,
+		MetricAthlete:  len(client.metricAthleteKey) > 0,
+		ScopeCheck:     client.scopeSource != nil,
+		Subscribed:     !client.unsubscribed,
+		RawJSON:        client.rawJSON,
+		StrictJSON:     client.strictJSON,
+		DryRun:         client.dryRun,
+		ReadOnly:       client.readOnly,
+		Restricted:     len(client.restrictions) > 0,
+		ETags:          client.etags != nil,
+		Cache:          client.cache != nil,
+		CacheTTL:       client.cacheTTL,
+		Timeout:        client.timeout,
+		RefreshMargin:  client.refreshMargin,
+		Coalescing:     client.flights != nil,
+		CircuitBreaker: client.breaker != nil,
+		Dump:           client.dump.enabled(),
+		Validators:     len(client.validators),
+		Concurrency:    cap(client.limiter),
+	}
+
+	if client.maintenance != nil {
+		options.Retries = client.maintenance.retries
+	}
+
+	if client.pacer != nil {
+		options.RequestDelay = client.pacer.minDelay
+		options.RequestJitter = client.pacer.jitter
+	}
+
+	return options
+}
@@ -0,0 +1,64 @@
+package strava
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// WithRequestDelay spaces the requests of the client at least minDelay apart, plus a random jitter
+// of up to jitter, so bulk operations such as syncing a club or fetching many activities don't
+// send bursts of requests that trip the throttling of Strava. Requests wait for their turn,
+// also when made from several goroutines.
+func WithRequestDelay(minDelay, jitter time.Duration) Option {
+	return func(c *Client) {
+		c.pacer = &pacer{
+			minDelay: minDelay,
+			jitter:   jitter,
+			rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+	}
+}
+
+// pacer reserves a time slot for each request.
+type pacer struct {
+	lock     sync.Mutex
+	minDelay time.Duration
+	jitter   time.Duration
+	rand     *rand.Rand
+	next     time.Time // earliest time of the next request
+}
+
+// wait blocks until the request may be made, by the clock, and returns how long it waited. If the turn of the request
+// comes after the deadline, if not zero, it returns ErrRateLimited right away, without taking the turn.
+func (p *pacer) wait(clock Clock, deadline time.Time) (time.Duration, error) {
+	if p == nil {
+		return 0, nil
+	}
+
+	p.lock.Lock()
+	now := clock.Now()
+	wait := p.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+
+	if !deadline.IsZero() && now.Add(wait).After(deadline) {
+		p.lock.Unlock()
+		return 0, fmt.Errorf("%w: the turn of the request comes after its deadline, in %v", ErrRateLimited, wait)
+	}
+
+	delay := p.minDelay
+	if p.jitter > 0 {
+		delay += time.Duration(p.rand.Int63n(int64(p.jitter)))
+	}
+	p.next = now.Add(wait + delay)
+	p.lock.Unlock()
+
+	if wait > 0 {
+		clock.Sleep(wait)
+	}
+
+	return wait, nil
+}
@@ -0,0 +1,80 @@
+package strava
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClientWithRequestDelay(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{"/api/v3/clubs/1": `{"id":1}`})
+	WithRequestDelay(time.Hour, time.Minute)(client)
+
+	clock := newFakeClock()
+	WithClock(clock)(client)
+
+	// the clock moves past the expiry of the token
+	client.tokenSource.(*stubTokenSource).response.ExpiresAt = time.Now().Add(24 * time.Hour).Unix()
+
+	for i := 0; i < 3; i++ {
+		if _, err := NewClubsService(client).Get(1).Do(); err != nil {
+			t.Fatalf("service error: %v", err)
+		}
+	}
+
+	if len(transport.requests) != 3 {
+		t.Fatalf("all requests should be made, got %d", len(transport.requests))
+	}
+
+	// the first request is not delayed
+	waits := clock.sleeps
+	if len(waits) != 2 {
+		t.Fatalf("should wait before the second and third request, got %v", waits)
+	}
+
+	if waits[0] < time.Hour || waits[0] > time.Hour+time.Minute {
+		t.Errorf("incorrect wait, got %v", waits[0])
+	}
+
+	if waits[1] < time.Hour || waits[1] > time.Hour+time.Minute {
+		t.Errorf("incorrect wait, got %v", waits[1])
+	}
+}
+
+func TestPacerWithoutDelay(t *testing.T) {
+	var p *pacer
+	if wait, err := p.wait(systemClock{}, time.Now()); wait != 0 || err != nil {
+		t.Error("clients without a delay should not wait")
+	}
+}
+
+func TestClientRequestDelayDeadline(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{"/api/v3/clubs/1": `{"id":1}`})
+	WithRequestDelay(time.Hour, 0)(client)
+
+	clock := newFakeClock()
+	WithClock(clock)(client)
+
+	if _, err := NewClubsService(client).Get(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	// the turn of the second request comes in an hour
+	_, err := NewClubsService(client).Get(1).Timeout(time.Minute).Do()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("should be rate limited, got %v", err)
+	}
+
+	if len(transport.requests) != 1 || len(clock.sleeps) != 0 {
+		t.Fatalf("should not wait for nor make the request, got %d requests and waits %v", len(transport.requests), clock.sleeps)
+	}
+
+	// the turn was not taken
+	if _, err := NewClubsService(client).Get(1).Timeout(2 * time.Hour).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(clock.sleeps) != 1 || clock.sleeps[0] > time.Hour {
+		t.Errorf("should wait for the first turn, got %v", clock.sleeps)
+	}
+}
@@ -5,6 +5,7 @@ import (
 )
 
 type PhotoSummary struct {
+	RawJSON
 	Id         int64     `json:"id"`
 	ActivityId int64     `json:"activity_id"`
 	Reference  string    `json:"ref"`
@@ -0,0 +1,113 @@
+package strava
+
+import (
+	"time"
+)
+
+// PlannedIntervalTolerance is the relative difference between the planned and actual
+// duration of an interval for it to still count as hit.
+var PlannedIntervalTolerance = 0.1
+
+// A PlannedWorkout is a workout as planned by a coach or training app, to be compared with the activity
+// that was actually done. Zero values are not planned and not compared.
+type PlannedWorkout struct {
+	Name           string
+	Date           time.Time
+	Sport          ActivityType
+	Duration       time.Duration
+	Distance       Distance
+	TrainingStress float64
+	Intervals      []PlannedInterval
+}
+
+// A PlannedInterval is a step of a PlannedWorkout with its targets. Zero targets are not compared.
+type PlannedInterval struct {
+	Duration     time.Duration
+	MinPower     Power
+	MaxPower     Power
+	MinHeartrate float64
+	MaxHeartrate float64
+}
+
+// A WorkoutComparison is the result of comparing a PlannedWorkout with an activity.
+// The ratios are actual over planned, 0 if not planned.
+type WorkoutComparison struct {
+	SportMatches        bool
+	DurationRatio       float64
+	DistanceRatio       float64
+	TrainingStressRatio float64
+	Intervals           []IntervalComparison
+	IntervalsHit        int
+}
+
+// An IntervalComparison compares a planned interval with the lap done for it, Actual is nil if it was missed.
+type IntervalComparison struct {
+	Planned PlannedInterval
+	Actual  *LapEffortSummary
+	Hit     bool
+}
+
+// Compare compares the workout with the activity done for it. Planned intervals are matched with the laps
+// in order, so the athlete is expected to press lap for every interval, including the recoveries.
+// The settings are used for the training stress, they can be nil if no training stress was planned.
+func (w *PlannedWorkout) Compare(activity *ActivitySummary, laps []*LapEffortSummary, settings *AthleteSettings) *WorkoutComparison {
+	c := &WorkoutComparison{
+		SportMatches: w.Sport == "" || w.Sport.BaseType() == activity.Type.BaseType() || w.Sport == activity.SportType,
+	}
+
+	if w.Duration > 0 {
+		c.DurationRatio = float64(activity.MovingTime) / w.Duration.Seconds()
+	}
+
+	if w.Distance > 0 {
+		c.DistanceRatio = float64(activity.Distance / w.Distance)
+	}
+
+	if w.TrainingStress > 0 && settings != nil {
+		c.TrainingStressRatio = settings.TrainingStress(activity) / w.TrainingStress
+	}
+
+	for i, planned := range w.Intervals {
+		interval := IntervalComparison{Planned: planned}
+		if i < len(laps) {
+			interval.Actual = laps[i]
+			interval.Hit = planned.hitBy(laps[i])
+		}
+
+		if interval.Hit {
+			c.IntervalsHit++
+		}
+
+		c.Intervals = append(c.Intervals, interval)
+	}
+
+	return c
+}
+
+// hitBy returns true if the lap is within the tolerance of the duration and within the targets.
+func (p PlannedInterval) hitBy(lap *LapEffortSummary) bool {
+	if p.Duration > 0 {
+		difference := float64(lap.ElapsedTime)/p.Duration.Seconds() - 1
+		if difference > PlannedIntervalTolerance || difference < -PlannedIntervalTolerance {
+			return false
+		}
+	}
+
+	if p.MinPower > 0 && lap.AveragePower < p.MinPower {
+		return false
+	}
+
+	if p.MaxPower > 0 && lap.AveragePower > p.MaxPower {
+		return false
+	}
+
+	if p.MinHeartrate > 0 && lap.AverageHeartrate < p.MinHeartrate {
+		return false
+	}
+
+	if p.MaxHeartrate > 0 && lap.AverageHeartrate > p.MaxHeartrate {
+		return false
+	}
+
+	return true
+}
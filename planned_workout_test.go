@@ -0,0 +1,73 @@
+package strava
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPlannedWorkoutCompare(t *testing.T) {
+	settings := NewAthleteSettings()
+	settings.SetThresholds(ActivityTypes.Ride, time.Time{}, Thresholds{FTP: 200})
+
+	workout := &PlannedWorkout{
+		Sport:          ActivityTypes.Ride,
+		Duration:       time.Hour,
+		Distance:       30 * Kilometer,
+		TrainingStress: 80,
+		Intervals: []PlannedInterval{
+			{Duration: 10 * time.Minute, MaxPower: 180},
+			{Duration: 5 * time.Minute, MinPower: 250, MaxPower: 280},
+			{Duration: 5 * time.Minute, MinHeartrate: 150},
+			{Duration: 10 * time.Minute},
+		},
+	}
+
+	activity := &ActivitySummary{
+		Type:         ActivityTypes.Ride,
+		SportType:    ActivityTypes.MountainBikeRide,
+		MovingTime:   3000,
+		Distance:     27 * Kilometer,
+		AveragePower: 200,
+	}
+
+	lap := func(seconds int, power Power, heartrate float64) *LapEffortSummary {
+		l := &LapEffortSummary{AveragePower: power, AverageHeartrate: heartrate}
+		l.ElapsedTime = seconds
+		return l
+	}
+
+	laps := []*LapEffortSummary{
+		lap(620, 150, 120),
+		lap(300, 240, 160),
+		lap(290, 220, 155),
+	}
+
+	c := workout.Compare(activity, laps, settings)
+
+	if !c.SportMatches {
+		t.Error("sport should match")
+	}
+
+	if math.Abs(c.DurationRatio-50.0/60) > 1e-9 || math.Abs(c.DistanceRatio-0.9) > 1e-9 {
+		t.Errorf("incorrect ratios, got %+v", c)
+	}
+
+	if expected := settings.TrainingStress(activity) / 80; math.Abs(c.TrainingStressRatio-expected) > 1e-9 {
+		t.Errorf("incorrect training stress ratio, got %v", c.TrainingStressRatio)
+	}
+
+	if len(c.Intervals) != 4 || c.IntervalsHit != 2 {
+		t.Fatalf("incorrect intervals, got %+v", c.Intervals)
+	}
+
+	if !c.Intervals[0].Hit || c.Intervals[1].Hit || !c.Intervals[2].Hit || c.Intervals[3].Hit || c.Intervals[3].Actual != nil {
+		t.Errorf("incorrect intervals hit, got %+v", c.Intervals)
+	}
+
+	// other sport, nothing else planned
+	c = (&PlannedWorkout{Sport: ActivityTypes.Run}).Compare(activity, nil, nil)
+	if c.SportMatches || c.DurationRatio != 0 || c.TrainingStressRatio != 0 || len(c.Intervals) != 0 {
+		t.Errorf("incorrect comparison, got %+v", c)
+	}
+}
@@ -1,5 +1,7 @@
 package strava
 
+import "math"
+
 type Polyline string
 
 // Decode will take the polyline which is a string
@@ -46,3 +48,33 @@ func (p Polyline) Decode() [][2]float64 {
 
 	return line
 }
+
+// EncodePolyline encodes the points in the Google polyline encoding, with a precision of 5 decimals.
+func EncodePolyline(points [][2]float64) Polyline {
+	var line []byte
+	var previous [2]int
+
+	for _, point := range points {
+		for i := range point {
+			value := int(math.Round(point[i] * 1.0e5))
+			line = appendPolylineValue(line, value-previous[i])
+			previous[i] = value
+		}
+	}
+
+	return Polyline(line)
+}
+
+func appendPolylineValue(line []byte, value int) []byte {
+	shifted := value << 1
+	if value < 0 {
+		shifted = ^shifted
+	}
+
+	for shifted >= 0x20 {
+		line = append(line, byte((0x20|(shifted&0x1f))+63))
+		shifted >>= 5
+	}
+
+	return append(line, byte(shifted+63))
+}
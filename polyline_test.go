@@ -19,3 +19,15 @@ func TestPolylineDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodePolyline(t *testing.T) {
+	points := [][2]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+	if encoded := EncodePolyline(points); encoded != "_p~iF~ps|U_ulLnnqC_mqNvxq`@" {
+		t.Errorf("incorrect encoding, got %v", encoded)
+	}
+
+	if encoded := EncodePolyline(nil); encoded != "" {
+		t.Errorf("no points should be encoded as empty string, got %v", encoded)
+	}
+}
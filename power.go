@@ -0,0 +1,208 @@
+package strava
+
+import (
+	"errors"
+	"time"
+)
+
+// A PowerCurve holds the best average power held for a number of durations, the mean maximal power.
+type PowerCurve map[time.Duration]Power
+
+// DefaultPowerCurveDurations are the durations of the power curve used to estimate the critical power.
+var DefaultPowerCurveDurations = []time.Duration{
+	3 * time.Minute,
+	5 * time.Minute,
+	8 * time.Minute,
+	12 * time.Minute,
+	20 * time.Minute,
+}
+
+// PowerCurve returns the best average power for each of the durations, rounded down to whole seconds.
+// Durations longer than the activity are left out. Time not covered by samples, like the gaps
+// in the recording, counts as zero power. Requires the Time and Power streams, nil without them.
+// Fails with ErrTimeNotIncreasing for corrupted recordings.
+func (s *StreamSet) PowerCurve(durations []time.Duration) (PowerCurve, error) {
+	watts, err := s.powerPerSecond()
+	if watts == nil {
+		return nil, err
+	}
+
+	// cumulative work, so the power of any window is a single subtraction
+	work := make([]float64, len(watts)+1)
+	for i, w := range watts {
+		work[i+1] = work[i] + w
+	}
+
+	curve := make(PowerCurve)
+	for _, d := range durations {
+		seconds := int(d / time.Second)
+		if seconds <= 0 || seconds > len(watts) {
+			continue
+		}
+
+		var best float64
+		for i := seconds; i < len(work); i++ {
+			if w := work[i] - work[i-seconds]; w > best {
+				best = w
+			}
+		}
+
+		curve[d] = Power(best / float64(seconds))
+	}
+
+	return curve, nil
+}
+
+// ErrTimeNotIncreasing is returned by the computations resampling streams per second when the times
+// of the Time stream don't increase from sample to sample, e.g. in corrupted recordings.
+var ErrTimeNotIncreasing = errors.New("time stream is not increasing")
+
+// powerPerSecond resamples the power stream to one value per second, see perSecond.
+func (s *StreamSet) powerPerSecond() ([]float64, error) {
+	if s.Power == nil {
+		return nil, nil
+	}
+
+	return s.perSecond(s.Power.Data)
+}
+
+// perSecond resamples data of an integer stream to one value per second, from the first sample on,
+// each sample holding its value until the next one. Gaps in the recording are left zero.
+// Requires the Time stream, nil without it, and fails with ErrTimeNotIncreasing.
+func (s *StreamSet) perSecond(data []int) ([]float64, error) {
+	if s.Time == nil || len(s.Time.Data) == 0 {
+		return nil, nil
+	}
+
+	n := len(s.Time.Data)
+	if len(data) < n {
+		n = len(data)
+	}
+
+	if n == 0 {
+		return nil, nil
+	}
+
+	for i := 1; i < n; i++ {
+		if s.Time.Data[i] <= s.Time.Data[i-1] {
+			return nil, ErrTimeNotIncreasing
+		}
+	}
+
+	values := make([]float64, s.Time.Data[n-1]-s.Time.Data[0])
+	for i := 1; i < n; i++ {
+		if time.Duration(s.Time.Data[i]-s.Time.Data[i-1])*time.Second > DefaultGapThreshold {
+			continue
+		}
+
+		for t := s.Time.Data[i-1]; t < s.Time.Data[i]; t++ {
+			values[t-s.Time.Data[0]] = float64(data[i])
+		}
+	}
+
+	return values, nil
+}
+
+// Merge returns a curve with the best power of both curves for every duration,
+// used to build the best efforts over a period from the curves of the activities.
+func (c PowerCurve) Merge(other PowerCurve) PowerCurve {
+	merged := make(PowerCurve, len(c))
+	for d, p := range c {
+		merged[d] = p
+	}
+
+	for d, p := range other {
+		if p > merged[d] {
+			merged[d] = p
+		}
+	}
+
+	return merged
+}
+
+/*********************************************************/
+
+// CriticalPower is the two parameter critical power model of an athlete.
+// CP is the power that can be sustained for a long time and W' the amount of work,
+// in joules, that can be done above it before exhaustion.
+type CriticalPower struct {
+	CP     Power
+	WPrime float64
+}
+
+// EstimateCriticalPower fits the critical power model to the best efforts of a power curve.
+// It uses a linear regression of the work done against the duration of the efforts, so it needs at least two
+// maximal efforts between about 2 and 20 minutes, as in DefaultPowerCurveDurations, for a meaningful result.
+func EstimateCriticalPower(curve PowerCurve) (*CriticalPower, error) {
+	var n, sumT, sumW, sumTT, sumTW float64
+	for d, p := range curve {
+		if p <= 0 {
+			continue
+		}
+
+		t := d.Seconds()
+		w := float64(p) * t
+
+		n++
+		sumT += t
+		sumW += w
+		sumTT += t * t
+		sumTW += t * w
+	}
+
+	if n < 2 {
+		return nil, errors.New("at least two efforts are needed to estimate critical power")
+	}
+
+	denominator := n*sumTT - sumT*sumT
+	if denominator == 0 {
+		return nil, errors.New("efforts of different durations are needed to estimate critical power")
+	}
+
+	cp := (n*sumTW - sumT*sumW) / denominator
+	wPrime := (sumW - cp*sumT) / n
+
+	if cp <= 0 || wPrime <= 0 {
+		return nil, errors.New("efforts don't fit the critical power model")
+	}
+
+	return &CriticalPower{CP: Power(cp), WPrime: wPrime}, nil
+}
+
+// WBalance returns the balance of W' at every sample of the power stream, starting full.
+// Work above CP depletes the balance, below CP it recovers exponentially, faster the further below CP,
+// following the differential model of Skiba et al. The balance goes negative if the athlete did more
+// than the model predicts, which usually means CP or W' are set too low. Requires the Time and Power streams.
+func (m *CriticalPower) WBalance(s *StreamSet) []float64 {
+	if s.Time == nil || s.Power == nil {
+		return nil
+	}
+
+	n := len(s.Time.Data)
+	if len(s.Power.Data) < n {
+		n = len(s.Power.Data)
+	}
+
+	balance := make([]float64, n)
+	current := m.WPrime
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			dt := float64(s.Time.Data[i] - s.Time.Data[i-1])
+			power := float64(s.Power.Data[i])
+
+			if power > float64(m.CP) {
+				current -= (power - float64(m.CP)) * dt
+			} else if m.WPrime > 0 {
+				current += (m.WPrime - current) * (float64(m.CP) - power) / m.WPrime * dt
+			}
+
+			if current > m.WPrime {
+				current = m.WPrime
+			}
+		}
+
+		balance[i] = current
+	}
+
+	return balance
+}
@@ -0,0 +1,112 @@
+package strava
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStreamSetPowerCurve(t *testing.T) {
+	s := &StreamSet{
+		Time:  &IntegerStream{Data: []int{0, 1, 2, 3, 4, 5, 6}},
+		Power: &IntegerStream{Data: []int{0, 100, 300, 200, 100, 100, 400}},
+	}
+
+	curve, err := s.PowerCurve([]time.Duration{time.Second, 2 * time.Second, 6 * time.Second, time.Minute})
+
+	expected := PowerCurve{time.Second: 400, 2 * time.Second: 250, 6 * time.Second: 200}
+	if err != nil || len(curve) != len(expected) {
+		t.Fatalf("incorrect curve, got %v", curve)
+	}
+
+	for d, p := range expected {
+		if curve[d] != p {
+			t.Errorf("incorrect power for %v, got %v", d, curve[d])
+		}
+	}
+
+	// samples every other second hold their power
+	s = &StreamSet{
+		Time:  &IntegerStream{Data: []int{0, 2, 4}},
+		Power: &IntegerStream{Data: []int{0, 300, 100}},
+	}
+
+	if curve, _ := s.PowerCurve([]time.Duration{2 * time.Second}); curve[2*time.Second] != 300 {
+		t.Errorf("incorrect power, got %v", curve[2*time.Second])
+	}
+
+	if curve, err := (&StreamSet{}).PowerCurve(DefaultPowerCurveDurations); curve != nil || err != nil {
+		t.Error("should require time and power streams")
+	}
+
+	// paused or corrupted recordings with times that don't increase
+	for _, times := range [][]int{{4, 2, 0}, {0, 2, 2}, {0, 5, 1}} {
+		s.Time.Data = times
+		if _, err := s.PowerCurve(DefaultPowerCurveDurations); !errors.Is(err, ErrTimeNotIncreasing) {
+			t.Errorf("times %v should fail, got %v", times, err)
+		}
+	}
+}
+
+func TestPowerCurveMerge(t *testing.T) {
+	a := PowerCurve{time.Minute: 300, 5 * time.Minute: 250}
+	b := PowerCurve{time.Minute: 320, 20 * time.Minute: 200}
+
+	merged := a.Merge(b)
+	if merged[time.Minute] != 320 || merged[5*time.Minute] != 250 || merged[20*time.Minute] != 200 {
+		t.Errorf("incorrect merge, got %v", merged)
+	}
+
+	if a[time.Minute] != 300 {
+		t.Error("merge should not modify the curve")
+	}
+}
+
+func TestEstimateCriticalPower(t *testing.T) {
+	// efforts exactly following cp 250 and w' 20000
+	curve := make(PowerCurve)
+	for _, d := range DefaultPowerCurveDurations {
+		curve[d] = Power(250 + 20000/d.Seconds())
+	}
+
+	model, err := EstimateCriticalPower(curve)
+	if err != nil {
+		t.Fatalf("estimate error: %v", err)
+	}
+
+	if math.Abs(float64(model.CP)-250) > 1e-6 || math.Abs(model.WPrime-20000) > 1e-6 {
+		t.Errorf("incorrect model, got %v", model)
+	}
+
+	if _, err := EstimateCriticalPower(PowerCurve{time.Minute: 300}); err == nil {
+		t.Error("should require two efforts")
+	}
+
+	if _, err := EstimateCriticalPower(PowerCurve{time.Minute: 300, 5 * time.Minute: 350}); err == nil {
+		t.Error("should reject efforts not fitting the model")
+	}
+}
+
+func TestCriticalPowerWBalance(t *testing.T) {
+	model := &CriticalPower{CP: 250, WPrime: 20000}
+
+	s := &StreamSet{
+		Time:  &IntegerStream{Data: []int{0, 10, 20, 30}},
+		Power: &IntegerStream{Data: []int{0, 450, 450, 150}},
+	}
+
+	balance := model.WBalance(s)
+	if len(balance) != 4 || balance[0] != 20000 || balance[1] != 18000 || balance[2] != 16000 {
+		t.Fatalf("incorrect balance, got %v", balance)
+	}
+
+	// recovers part of the difference below cp
+	if expected := 16000 + 4000*100.0/20000*10; balance[3] != expected {
+		t.Errorf("incorrect recovery, got %v, expected %v", balance[3], expected)
+	}
+
+	if model.WBalance(&StreamSet{}) != nil {
+		t.Error("should require time and power streams")
+	}
+}
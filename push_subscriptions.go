@@ -0,0 +1,368 @@
+package strava
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// A PushSubscription makes Strava post the WebhookEvents of the athletes of the application to its callback url.
+type PushSubscription struct {
+	Id            int64     `json:"id"`
+	ApplicationId int64     `json:"application_id"`
+	CallbackURL   string    `json:"callback_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PushSubscriptionsService manages the push subscription of the application, with the credentials
+// of the client, see WithCredentials. No token is needed.
+//
+// Strava allows one push subscription per application, so deployments of several environments, such as dev,
+// staging and prod, need an application of their own for each environment. They list the callback url of each
+// environment in a SubscriptionEnvironments, to find the subscription of an old callback url the application
+// still has, and tag the verify token of a subscription with the environment, so an environment doesn't confirm
+// the subscription of another application posting to its callback url by mistake.
+type PushSubscriptionsService struct {
+	client *Client
+}
+
+func NewPushSubscriptionsService(client *Client) *PushSubscriptionsService {
+	return &PushSubscriptionsService{client}
+}
+
+/*********************************************************/
+
+type PushSubscriptionsCreateCall struct {
+	service      *PushSubscriptionsService
+	callbackURL  string
+	verifyToken  string
+	errorHandler ErrorHandler
+	ctx          context.Context
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
+}
+
+// Create subscribes the callback url. Strava validates the callback with a GET request
+// that should be answered by a PushSubscriptionHandler with the same verify token.
+// Strava rejects it if the application already has a subscription, delete that one first, e.g. with PruneStale.
+func (s *PushSubscriptionsService) Create(callbackURL, verifyToken string) *PushSubscriptionsCreateCall {
+	return &PushSubscriptionsCreateCall{
+		service:     s,
+		callbackURL: callbackURL,
+		verifyToken: verifyToken,
+	}
+}
+
+func (c *PushSubscriptionsCreateCall) OnError(handler ErrorHandler) *PushSubscriptionsCreateCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Context makes the call use ctx, its request is cancelled when ctx is done.
+func (c *PushSubscriptionsCreateCall) Context(ctx context.Context) *PushSubscriptionsCreateCall {
+	c.ctx = ctx
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *PushSubscriptionsCreateCall) Response(resp *Response) *PushSubscriptionsCreateCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *PushSubscriptionsCreateCall) Timeout(d time.Duration) *PushSubscriptionsCreateCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *PushSubscriptionsCreateCall) Header(key, value string) *PushSubscriptionsCreateCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *PushSubscriptionsCreateCall) Do() (*PushSubscription, error) {
+	values := url.Values{"callback_url": {c.callbackURL}, "verify_token": {c.verifyToken}}
+
+	var subscription PushSubscription
+	err := c.service.client.runWithCredentials(c.ctx, "POST", "/push_subscriptions", values, c.errorHandler, c.response, c.timeout, c.header, &subscription)
+	if err != nil {
+		return nil, wrapError(err, "push_subscriptions.create callback_url=%s", c.callbackURL)
+	}
+
+	return &subscription, nil
+}
+
+/*********************************************************/
+
+type PushSubscriptionsListCall struct {
+	service      *PushSubscriptionsService
+	errorHandler ErrorHandler
+	ctx          context.Context
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
+}
+
+func (s *PushSubscriptionsService) List() *PushSubscriptionsListCall {
+	return &PushSubscriptionsListCall{
+		service: s,
+	}
+}
+
+func (c *PushSubscriptionsListCall) OnError(handler ErrorHandler) *PushSubscriptionsListCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Context makes the call use ctx, its request is cancelled when ctx is done.
+func (c *PushSubscriptionsListCall) Context(ctx context.Context) *PushSubscriptionsListCall {
+	c.ctx = ctx
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *PushSubscriptionsListCall) Response(resp *Response) *PushSubscriptionsListCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *PushSubscriptionsListCall) Timeout(d time.Duration) *PushSubscriptionsListCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *PushSubscriptionsListCall) Header(key, value string) *PushSubscriptionsListCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *PushSubscriptionsListCall) Do() ([]*PushSubscription, error) {
+	var subscriptions []*PushSubscription
+	err := c.service.client.runWithCredentials(c.ctx, "GET", "/push_subscriptions", nil, c.errorHandler, c.response, c.timeout, c.header, &subscriptions)
+	return subscriptions, wrapError(err, "push_subscriptions.list")
+}
+
+/*********************************************************/
+
+type PushSubscriptionsDeleteCall struct {
+	service      *PushSubscriptionsService
+	id           int64
+	errorHandler ErrorHandler
+	ctx          context.Context
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
+}
+
+func (s *PushSubscriptionsService) Delete(subscriptionId int64) *PushSubscriptionsDeleteCall {
+	return &PushSubscriptionsDeleteCall{
+		service: s,
+		id:      subscriptionId,
+	}
+}
+
+func (c *PushSubscriptionsDeleteCall) OnError(handler ErrorHandler) *PushSubscriptionsDeleteCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Context makes the call use ctx, its request is cancelled when ctx is done.
+func (c *PushSubscriptionsDeleteCall) Context(ctx context.Context) *PushSubscriptionsDeleteCall {
+	c.ctx = ctx
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *PushSubscriptionsDeleteCall) Response(resp *Response) *PushSubscriptionsDeleteCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *PushSubscriptionsDeleteCall) Timeout(d time.Duration) *PushSubscriptionsDeleteCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *PushSubscriptionsDeleteCall) Header(key, value string) *PushSubscriptionsDeleteCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *PushSubscriptionsDeleteCall) Do() error {
+	err := c.service.client.runWithCredentials(c.ctx, "DELETE", fmt.Sprintf("/push_subscriptions/%d", c.id), nil, c.errorHandler, c.response, c.timeout, c.header, nil)
+	return wrapError(err, "push_subscriptions.delete id=%d", c.id)
+}
+
+/*********************************************************/
+
+// SubscriptionEnvironments are the callback urls of the push subscriptions of the environments
+// of a deployment, by environment, e.g. {"staging": "https://staging.example.com/strava/webhook"}.
+type SubscriptionEnvironments map[string]string
+
+// Environment returns the environment of the subscription, the one with its callback url,
+// or an empty string for stale subscriptions of no environment.
+func (e SubscriptionEnvironments) Environment(subscription *PushSubscription) string {
+	for environment, callbackURL := range e {
+		if callbackURL == subscription.CallbackURL {
+			return environment
+		}
+	}
+
+	return ""
+}
+
+// ListEnvironment returns the subscriptions of the environment.
+func (s *PushSubscriptionsService) ListEnvironment(environments SubscriptionEnvironments, environment string) ([]*PushSubscription, error) {
+	subscriptions, err := s.List().Do()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*PushSubscription
+	for _, subscription := range subscriptions {
+		if environments.Environment(subscription) == environment {
+			selected = append(selected, subscription)
+		}
+	}
+
+	return selected, nil
+}
+
+// PruneStale deletes the subscription of no environment, of an old callback url or a retired environment,
+// making room for the one subscription of the application, and returns the deleted subscriptions.
+// Deletion stops at the first error.
+func (s *PushSubscriptionsService) PruneStale(environments SubscriptionEnvironments) ([]*PushSubscription, error) {
+	stale, err := s.ListEnvironment(environments, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []*PushSubscription
+	for _, subscription := range stale {
+		if err := s.Delete(subscription.Id).Do(); err != nil {
+			return deleted, err
+		}
+
+		deleted = append(deleted, subscription)
+	}
+
+	return deleted, nil
+}
+
+// Ensure subscribes the callback url of the environment for the application of the client, the application
+// of the environment. The subscription of the environment is kept, the subscription of an old callback url is
+// deleted first. It fails if the application is subscribed for another environment, which has an application
+// of its own.
+func (s *PushSubscriptionsService) Ensure(environments SubscriptionEnvironments, environment, secret string) (*PushSubscription, error) {
+	callbackURL, ok := environments[environment]
+	if !ok {
+		return nil, fmt.Errorf("unknown environment %s", environment)
+	}
+
+	subscriptions, err := s.List().Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subscription := range subscriptions {
+		switch other := environments.Environment(subscription); other {
+		case environment:
+			return subscription, nil
+		case "":
+			if err := s.Delete(subscription.Id).Do(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("the application is subscribed for the %s environment, not %s", other, environment)
+		}
+	}
+
+	return s.Create(callbackURL, SubscriptionVerifyToken(environment, secret)).Do()
+}
+
+// SubscriptionVerifyToken returns the verify token of a subscription of the environment,
+// the secret tagged with the environment.
+func SubscriptionVerifyToken(environment, secret string) string {
+	return environment + ":" + secret
+}
+
+// PushSubscriptionHandler answers the validation request Strava makes to the callback url of a subscription
+// being created, if the verify token is the one of the environment, see SubscriptionVerifyToken, and refuses
+// validations of other environments. Other requests, the posted events, are passed to next.
+func PushSubscriptionHandler(environment, secret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Query().Get("hub.mode") != "subscribe" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.URL.Query().Get("hub.verify_token") != SubscriptionVerifyToken(environment, secret) {
+			http.Error(w, "unknown verify token", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"hub.challenge": r.URL.Query().Get("hub.challenge")})
+	})
+}
+
+/*********************************************************/
+
+// credentialsKey marks the context of requests of the application, authenticated with its credentials.
+type credentialsKey struct{}
+
+// withCredentials reports whether the request is authenticated with the credentials of the application
+// instead of a token.
+func withCredentials(req *http.Request) bool {
+	return req.Context().Value(credentialsKey{}) != nil
+}
+
+// runWithCredentials runs the request of the application, authenticated with the credentials of the client
+// instead of a token, which are sent with the values, and decodes the response into v, unless v is nil.
+// The request is cancelled when ctx, if not nil, is done, see runWithErrorHandler for the other arguments.
+func (client *Client) runWithCredentials(ctx context.Context, method, path string, values url.Values, errorHandler ErrorHandler, response *Response, timeout time.Duration, header http.Header, v interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := client.contextWithTimeout(context.WithValue(ctx, credentialsKey{}, true), timeout)
+	defer cancel()
+
+	clientId, clientSecret := client.credentials()
+	params := map[string]interface{}{"client_id": clientId, "client_secret": clientSecret}
+	for key := range values {
+		params[key] = values.Get(key)
+	}
+
+	req, err := client.newRequest(ctx, method, path, params)
+	if err != nil {
+		return err
+	}
+	addHeader(req, header)
+
+	_, err = client.execute(req, errorHandler, response, v)
+	return err
+}
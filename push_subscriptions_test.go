@@ -0,0 +1,199 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSubscriptionClient returns a client of an application with the subscription of the callback url,
+// none if empty, allowing one subscription like Strava does.
+func newSubscriptionClient(t *testing.T, callbackURL *string, deleted *int) *Client {
+	client := NewClient(newStubTokenSource(), WithCredentials(1, "secret"))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.ParseForm()
+		if req.Form.Get("client_id") != "1" || req.Form.Get("client_secret") != "secret" || req.Header.Get("Authorization") != "" {
+			t.Errorf("requests should be authenticated with the credentials, got %v", req.Form)
+		}
+
+		status, body := http.StatusOK, ""
+		switch req.Method {
+		case "GET":
+			body = "[]"
+			if *callbackURL != "" {
+				body = fmt.Sprintf(`[{"id":1,"callback_url":%q}]`, *callbackURL)
+			}
+		case "POST":
+			if *callbackURL != "" {
+				status, body = http.StatusBadRequest, `{"message":"Bad Request","errors":[{"resource":"PushSubscription","field":"","code":"already exists"}]}`
+				break
+			}
+			*callbackURL = req.PostForm.Get("callback_url")
+			body = `{"id":2}`
+		case "DELETE":
+			*callbackURL = ""
+			*deleted++
+			status = http.StatusNoContent
+		}
+
+		return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})}
+
+	return client
+}
+
+func TestPushSubscriptions(t *testing.T) {
+	environments := SubscriptionEnvironments{
+		"prod":    "https://example.com/strava/webhook",
+		"staging": "https://staging.example.com/strava/webhook",
+	}
+
+	callbackURL, deleted := environments["staging"], 0
+	service := NewPushSubscriptionsService(newSubscriptionClient(t, &callbackURL, &deleted))
+
+	staging, err := service.ListEnvironment(environments, "staging")
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(staging) != 1 || staging[0].Id != 1 {
+		t.Errorf("incorrect subscription of the environment, got %v", staging)
+	}
+
+	pruned, err := service.PruneStale(environments)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(pruned) != 0 || deleted != 0 {
+		t.Errorf("the subscription of the environment should be kept, got %v", pruned)
+	}
+
+	callbackURL = "https://old.example.com/strava/webhook"
+	if _, err := service.Create(environments["staging"], SubscriptionVerifyToken("staging", "token")).Do(); err == nil {
+		t.Error("should not create a second subscription of the application")
+	}
+
+	pruned, err = service.PruneStale(environments)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(pruned) != 1 || pruned[0].Id != 1 || deleted != 1 {
+		t.Errorf("the subscription of the old callback url should be deleted, got %v", pruned)
+	}
+
+	subscription, err := service.Create(environments["staging"], SubscriptionVerifyToken("staging", "token")).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if subscription.Id != 2 || callbackURL != environments["staging"] {
+		t.Errorf("subscription should be created, got %v", callbackURL)
+	}
+}
+
+func TestPushSubscriptionsEnsure(t *testing.T) {
+	environments := SubscriptionEnvironments{
+		"prod":    "https://example.com/strava/webhook",
+		"staging": "https://staging.example.com/strava/webhook",
+	}
+
+	callbackURL, deleted := environments["staging"], 0
+	service := NewPushSubscriptionsService(newSubscriptionClient(t, &callbackURL, &deleted))
+
+	subscription, err := service.Ensure(environments, "staging", "token")
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if subscription.Id != 1 || deleted != 0 {
+		t.Errorf("the subscription of the environment should be kept, got %v", subscription)
+	}
+
+	callbackURL = "https://old.example.com/strava/webhook"
+	subscription, err = service.Ensure(environments, "staging", "token")
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if subscription.Id != 2 || deleted != 1 || callbackURL != environments["staging"] {
+		t.Errorf("the old subscription should be replaced, got %v", callbackURL)
+	}
+
+	if _, err := service.Ensure(environments, "prod", "token"); err == nil || deleted != 1 {
+		t.Error("should not replace the subscription of another environment")
+	}
+
+	if _, err := service.Ensure(environments, "dev", "token"); err == nil {
+		t.Error("should return error for an unknown environment")
+	}
+}
+
+func TestPushSubscriptionsCall(t *testing.T) {
+	var header http.Header
+	metrics := &recordingMetrics{}
+	client := NewClient(nil, WithCredentials(1, "secret"), WithMetrics(metrics))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+
+		header = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(`[]`)), Request: req}, nil
+	})}
+
+	var resp Response
+	_, err := NewPushSubscriptionsService(client).List().Header("X-Proxy", "1").Response(&resp).Timeout(time.Minute).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if header.Get("X-Proxy") != "1" || header.Get("Authorization") != "" || header.Get("User-Agent") == "" {
+		t.Errorf("incorrect headers, got %v", header)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.BodyLength != 2 || !resp.Empty {
+		t.Errorf("response should be filled, got %+v", resp)
+	}
+
+	if len(metrics.requests) != 1 || metrics.requests[0] != "GET /push_subscriptions OK" {
+		t.Errorf("request should be observed, got %v", metrics.requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewPushSubscriptionsService(client).Delete(1).Context(ctx).Do(); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled call should fail, got %v", err)
+	}
+}
+
+func TestPushSubscriptionHandler(t *testing.T) {
+	var events int
+	handler := PushSubscriptionHandler("staging", "token", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events++
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/webhook?hub.mode=subscribe&hub.challenge=abc&hub.verify_token=staging:token", nil))
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"hub.challenge":"abc"}` {
+		t.Errorf("validation should be answered, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/webhook?hub.mode=subscribe&hub.challenge=abc&hub.verify_token=prod:token", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("validations of other environments should be refused, got %d", w.Code)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/webhook", strings.NewReader(`{}`)))
+	if events != 1 {
+		t.Errorf("events should be passed on, got %d", events)
+	}
+}
@@ -1,6 +1,7 @@
 package strava
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -58,7 +59,7 @@ func (rl *RateLimit) FractionReached() float32 {
 }
 
 // ignoring error, instead will reset struct to initial values, so rate limiting is ignored
-func (rl *RateLimit) updateRateLimits(resp *http.Response) {
+func (rl *RateLimit) updateRateLimits(resp *http.Response, now time.Time) {
 	rl.lock.Lock()
 	defer rl.lock.Unlock()
 
@@ -90,10 +91,35 @@ func (rl *RateLimit) updateRateLimits(resp *http.Response) {
 		return
 	}
 
-	rl.RequestTime = time.Now()
+	rl.RequestTime = now
 	return
 }
 
+// exceededUsage returns the usage of the limits, e.g. to log, and if one of them was reached.
+// Not exceeded if the limits are unknown.
+func (rl *RateLimit) exceededUsage() (string, bool) {
+	rl.lock.RLock()
+	defer rl.lock.RUnlock()
+
+	if rl.RequestTime.IsZero() || (rl.UsageShort < rl.LimitShort && rl.UsageLong < rl.LimitLong) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%d/%d requests short term, %d/%d long term", rl.UsageShort, rl.LimitShort, rl.UsageLong, rl.LimitLong), true
+}
+
+// usage returns the limits and usage of the most recent response, false if they are unknown.
+func (rl *RateLimit) usage() (RateLimitUsage, bool) {
+	rl.lock.RLock()
+	defer rl.lock.RUnlock()
+
+	if rl.RequestTime.IsZero() {
+		return RateLimitUsage{}, false
+	}
+
+	return RateLimitUsage{LimitShort: rl.LimitShort, LimitLong: rl.LimitLong, UsageShort: rl.UsageShort, UsageLong: rl.UsageLong}, true
+}
+
 func (rl *RateLimit) clear() {
 	rl.RequestTime = time.Time{}
 	rl.LimitShort = 0
@@ -3,6 +3,7 @@ package strava
 import (
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestRateLimitUpdating(t *testing.T) {
@@ -10,7 +11,7 @@ func TestRateLimitUpdating(t *testing.T) {
 
 	resp.StatusCode = 200
 	resp.Header = http.Header{"Date": []string{"Tue, 10 Oct 2013 20:11:05 GMT"}, "X-Ratelimit-Limit": []string{"600,30000"}, "X-Ratelimit-Usage": []string{"300,10000"}}
-	RateLimiting.updateRateLimits(&resp)
+	RateLimiting.updateRateLimits(&resp, time.Now())
 
 	if RateLimiting.RequestTime.IsZero() {
 		t.Errorf("rate limiting should set request time")
@@ -21,7 +22,7 @@ func TestRateLimitUpdating(t *testing.T) {
 	}
 
 	resp.Header = http.Header{"Date": []string{"Tue, 10 Oct 2013 20:11:05 GMT"}, "X-Ratelimit-Limit": []string{"600,30000"}, "X-Ratelimit-Usage": []string{"300,27000"}}
-	RateLimiting.updateRateLimits(&resp)
+	RateLimiting.updateRateLimits(&resp, time.Now())
 
 	if RateLimiting.RequestTime.IsZero() {
 		t.Errorf("rate limiting should set request time")
@@ -33,7 +34,7 @@ func TestRateLimitUpdating(t *testing.T) {
 
 	// we'll feed it nonsense
 	resp.Header = http.Header{"Date": []string{"Tue, 10 Oct 2013 20:11:05 GMT"}, "X-Ratelimit-Limit": []string{"xxx"}, "X-Ratelimit-Usage": []string{"zzz"}}
-	RateLimiting.updateRateLimits(&resp)
+	RateLimiting.updateRateLimits(&resp, time.Now())
 
 	if !RateLimiting.RequestTime.IsZero() {
 		t.Errorf("nonsense in rate limiting fields should set next reset to zero")
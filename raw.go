@@ -0,0 +1,151 @@
+package strava
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// RawJSON is embedded in the models returned by the calls of a client created WithRawJSON,
+// to keep the json they were decoded from. It holds the json behind a pointer, so the models stay comparable.
+type RawJSON struct {
+	raw *rawJSON
+}
+
+type rawJSON struct {
+	data  json.RawMessage
+	model reflect.Type // of the model embedding it, to tell its fields from extra ones
+}
+
+// Raw returns the json the model was decoded from, as returned by Strava.
+// Nil unless the client was created WithRawJSON, the raw json is not encoded with the model.
+func (r *RawJSON) Raw() json.RawMessage {
+	if r.raw == nil {
+		return nil
+	}
+
+	return r.raw.data
+}
+
+// Extra returns the fields of the json the model was decoded from that the model doesn't have,
+// e.g. attributes Strava added after this version of the package. Nil unless the client was created WithRawJSON.
+func (r *RawJSON) Extra() map[string]json.RawMessage {
+	if r.raw == nil || r.raw.model == nil {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(r.raw.data, &fields) != nil {
+		return nil
+	}
+
+	for name := range jsonFields(r.raw.model) {
+		delete(fields, name)
+	}
+
+	return fields
+}
+
+func (r *RawJSON) setRaw(data []byte, model reflect.Type) {
+	r.raw = &rawJSON{data: data, model: model}
+}
+
+type rawSetter interface {
+	setRaw(data []byte, model reflect.Type)
+}
+
+// modelFields caches the json fields of model types, see jsonFields.
+var modelFields sync.Map
+
+// jsonFields returns the names of the json fields of the struct type t, including the ones of embedded structs.
+func jsonFields(t reflect.Type) map[string]bool {
+	if fields, ok := modelFields.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+
+		switch {
+		case field.Anonymous && name == "" && embedded.Kind() == reflect.Struct:
+			for name := range jsonFields(embedded) {
+				fields[name] = true
+			}
+		case !field.IsExported() || name == "-":
+		case name == "":
+			fields[field.Name] = true
+		default:
+			fields[name] = true
+		}
+	}
+
+	modelFields.Store(t, fields)
+	return fields
+}
+
+// WithRawJSON makes the models returned by the calls of the client keep the json they were decoded from,
+// see RawJSON.Raw, so the pristine payload can be stored along with the typed values.
+// Models in a list each keep their own element of the list.
+func WithRawJSON() Option {
+	return func(c *Client) {
+		c.rawJSON = true
+	}
+}
+
+// decode unmarshals the response data into v, strictly for clients created WithStrictJSON, and,
+// for clients created WithRawJSON, keeps the data in the models v refers to.
+func (client *Client) decode(data []byte, v interface{}) error {
+	var err error
+	if client.strictJSON {
+		err = decodeStrict(data, v)
+	} else {
+		err = json.Unmarshal(data, v)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if client.rawJSON {
+		keepRawJSON(data, reflect.ValueOf(v))
+	}
+
+	return nil
+}
+
+// keepRawJSON sets the raw json of the model, or the models in the slice, v points to.
+func keepRawJSON(data []byte, v reflect.Value) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+
+		if setter, ok := v.Interface().(rawSetter); ok {
+			setter.setRaw(append(json.RawMessage(nil), data...), v.Elem().Type())
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return
+	}
+
+	var items []json.RawMessage
+	if json.Unmarshal(data, &items) != nil {
+		return
+	}
+
+	for i := 0; i < v.Len() && i < len(items); i++ {
+		keepRawJSON(items[i], v.Index(i).Addr())
+	}
+}
@@ -0,0 +1,119 @@
+package strava
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClientWithRawJSON(t *testing.T) {
+	routes := map[string]string{
+		"/api/v3/activities/1":       `{"id":1,"name":"Morning Ride","new_field":{"x":1}}`,
+		"/api/v3/athlete/activities": `[{"id":1,"name":"Ride"}, {"id":2,"name":"Run"}]`,
+		"/api/v3/segments/explore":   `{"segments":[{"id":3,"name":"Climb"}]}`,
+	}
+
+	client, _ := newRouteClient(routes)
+	activity, err := NewActivitiesService(client).Get(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if activity.Raw() != nil {
+		t.Errorf("raw json should only be kept when enabled, got %s", activity.Raw())
+	}
+
+	client, _ = newRouteClient(routes)
+	WithRawJSON()(client)
+
+	activity, err = NewActivitiesService(client).Get(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if string(activity.Raw()) != routes["/api/v3/activities/1"] {
+		t.Errorf("incorrect raw json, got %s", activity.Raw())
+	}
+
+	if activity.Name != "Morning Ride" {
+		t.Errorf("typed fields should still be decoded, got %q", activity.Name)
+	}
+
+	activities, err := NewCurrentAthleteService(client).ListActivities().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 2 || string(activities[0].Raw()) != `{"id":1,"name":"Ride"}` || string(activities[1].Raw()) != `{"id":2,"name":"Run"}` {
+		t.Errorf("each activity should keep its own element, got %v", activities)
+	}
+
+	segments, err := NewSegmentsService(client).Explore(52, 4, 53, 5).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(segments) != 1 || string(segments[0].Raw()) != `{"id":3,"name":"Climb"}` {
+		t.Errorf("incorrect raw json of explored segment, got %v", segments)
+	}
+
+	// the raw json is not part of the encoded model
+	data, _ := json.Marshal(activity)
+	var decoded ActivityDetailed
+	json.Unmarshal(data, &decoded)
+	if decoded.Raw() != nil || decoded.Name != activity.Name {
+		t.Errorf("incorrect round trip, got %s", data)
+	}
+}
+
+func TestRawJSONExtra(t *testing.T) {
+	routes := map[string]string{
+		"/api/v3/activities/1":     `{"id":1,"name":"Morning Ride","calories":500,"athlete":{"id":2},"new_field":{"x":1},"sport_variant":"e_gravel"}`,
+		"/api/v3/segments/explore": `{"segments":[{"id":3,"name":"Climb","star_count":7}]}`,
+	}
+
+	client, _ := newRouteClient(routes)
+	activity, err := NewActivitiesService(client).Get(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if activity.Extra() != nil {
+		t.Errorf("extra fields should only be kept with the raw json, got %v", activity.Extra())
+	}
+
+	WithRawJSON()(client)
+	activity, err = NewActivitiesService(client).Get(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	extra := activity.Extra()
+	if len(extra) != 2 || string(extra["new_field"]) != `{"x":1}` || string(extra["sport_variant"]) != `"e_gravel"` {
+		t.Errorf("incorrect extra fields, got %v", extra)
+	}
+
+	segments, err := NewSegmentsService(client).Explore(52, 4, 53, 5).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if extra := segments[0].Extra(); len(extra) != 1 || string(extra["star_count"]) != "7" {
+		t.Errorf("incorrect extra fields of explored segment, got %v", extra)
+	}
+}
+
+func TestRawJSONComparable(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{"/api/v3/athlete": `{"id":1,"firstname":"Jane"}`})
+	WithRawJSON()(client)
+
+	athlete, err := NewCurrentAthleteService(client).Get().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	// models with raw json can be compared and used as map keys
+	summaries := map[AthleteSummary]bool{athlete.AthleteSummary: true}
+	if !summaries[athlete.AthleteSummary] || (ClubSummary{}) != (ClubSummary{}) || (CommentSummary{}) != (CommentSummary{}) || (AthleteStats{}) != (AthleteStats{}) {
+		t.Error("models should be comparable")
+	}
+}
@@ -0,0 +1,24 @@
+package strava
+
+import "errors"
+
+// ErrReadOnlyClient is returned by the calls of a client created WithReadOnly that would change data.
+var ErrReadOnlyClient = errors.New("read-only client")
+
+// WithReadOnly makes the client reject every request but GET requests with ErrReadOnlyClient, before they are
+// checked or sent, so services such as analytics can't change the data of athletes, even by mistake.
+// Dry runs are rejected too, see WithDryRun. The refresh of the token is still made.
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// checkReadOnly returns ErrReadOnlyClient if the client is read-only and the request is not a GET request.
+func (client *Client) checkReadOnly(method string) error {
+	if client.readOnly && method != "GET" {
+		return ErrReadOnlyClient
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package strava
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClientWithReadOnly(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/activities/1": `{"id":1}`,
+	})
+	WithReadOnly()(client)
+	WithDryRun()(client)
+	WithCredentials(1, "secret")(client)
+
+	if _, err := NewActivitiesService(client).Get(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if _, err := NewActivitiesService(client).Update(1).Name("Morning Ride").Do(); !errors.Is(err, ErrReadOnlyClient) {
+		t.Errorf("update should be rejected, got %v", err)
+	}
+
+	if _, err := NewPushSubscriptionsService(client).Create("https://example.com/strava/webhook", "token").Do(); !errors.Is(err, ErrReadOnlyClient) {
+		t.Errorf("subscription should be rejected, got %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("only the read should be sent, got %d requests", len(transport.requests))
+	}
+
+	if options := client.ClientOptionsSnapshot(); !options.ReadOnly {
+		t.Errorf("incorrect options, got %+v", options)
+	}
+}
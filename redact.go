@@ -0,0 +1,151 @@
+package strava
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces secrets and personal data removed by Redact.
+const redacted = "[REDACTED]"
+
+var redactPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// query strings and form values, e.g. ?code=abc&scope=read
+	{regexp.MustCompile(`\b(access_token|refresh_token|client_secret|code|email)=[^&\s"']+`), "${1}=" + redacted},
+	// json fields, e.g. {"access_token":"abc"}. Not "code", it is the error code in errors of Strava
+	{regexp.MustCompile(`"(access_token|refresh_token|client_secret|email)"(\s*:\s*)"[^"]*"`), `"${1}"${2}"` + redacted + `"`},
+	// authorization headers
+	{regexp.MustCompile(`\bBearer\s+[^\s"']+`), "Bearer " + redacted},
+	// email addresses anywhere else
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), redacted},
+}
+
+// WithRedaction makes the client also remove the values of the headers, e.g. of an api key of a proxy, and of
+// the query, form and json parameters, e.g. a signature added to urls, from its log lines, dumps and the messages
+// of errors of its calls. Header names are case insensitive, parameters are matched as whole names, like the names
+// redacted by default, see Redact.
+func WithRedaction(headers, params []string) Option {
+	r := newRedaction(headers, params)
+	return func(c *Client) {
+		c.redaction = r
+		c.dump.setRedaction(r)
+	}
+}
+
+// redaction removes the values of the headers and parameters of a client, see WithRedaction,
+// after the secrets removed by Redact. A nil redaction only applies Redact.
+type redaction struct {
+	patterns []*regexp.Regexp // with the name and separator in the first group
+}
+
+func newRedaction(headers, params []string) *redaction {
+	alternation := func(names []string) string {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = regexp.QuoteMeta(name)
+		}
+
+		return strings.Join(quoted, "|")
+	}
+
+	r := &redaction{}
+	if len(headers) > 0 {
+		r.patterns = append(r.patterns,
+			// header lines, also of dumps, e.g. "> X-Api-Key: abc"
+			regexp.MustCompile(`(?im)^((?:[<>] )?(?:`+alternation(headers)+`):[ \t]*)[^\r\n]*`))
+	}
+
+	if len(params) > 0 {
+		names := alternation(params)
+		r.patterns = append(r.patterns,
+			regexp.MustCompile(`(\b(?:`+names+`)=)[^&\s"']+`),
+			regexp.MustCompile(`("(?:`+names+`)"\s*:\s*")[^"]*`))
+	}
+
+	return r
+}
+
+func (r *redaction) redact(s string) string {
+	s = Redact(s)
+	if r == nil {
+		return s
+	}
+
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, "${1}"+redacted)
+	}
+
+	return s
+}
+
+// Redact removes access and refresh tokens, authorization codes, client secrets and email addresses from s.
+// It is applied to everything passed to the Logger of a client, to dumps and to the messages of errors
+// returned by calls, with the headers and parameters of the client, see WithRedaction, and can be used
+// for other log lines that may contain responses of Strava.
+func Redact(s string) string {
+	for _, p := range redactPatterns {
+		s = p.pattern.ReplaceAllString(s, p.replacement)
+	}
+
+	return s
+}
+
+// A Logger receives a line for every request made by a client, token refreshes and reached rate limits,
+// e.g. a *log.Logger. Lines are passed through Redact before they are logged. Clients without a Logger are silent.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LoggerFunc adapts a function to a Logger, to log through other logging packages, e.g. log/slog:
+//
+//	strava.LoggerFunc(func(format string, v ...interface{}) { slog.Debug(fmt.Sprintf(format, v...)) })
+type LoggerFunc func(format string, v ...interface{})
+
+func (f LoggerFunc) Printf(format string, v ...interface{}) {
+	f(format, v...)
+}
+
+// redactedError hides secrets in the message of an error. The original error stays in the chain,
+// so errors.Is and errors.As keep working, but the messages of the errors found that way are not redacted.
+type redactedError struct {
+	err       error
+	redaction *redaction
+}
+
+func (e *redactedError) Error() string {
+	return e.redaction.redact(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err with a redacted message, or nil if err is nil.
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &redactedError{err: err}
+}
+
+// redactError returns err with a message redacted for the client, see WithRedaction, or nil if err is nil.
+func (client *Client) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &redactedError{err: err, redaction: client.redaction}
+}
+
+// logf logs a redacted line to the Logger of the client, if it has one.
+func (client *Client) logf(format string, args ...interface{}) {
+	if client.logger == nil {
+		return
+	}
+
+	client.logger.Printf("%s", client.redaction.redact(fmt.Sprintf(format, args...)))
+}
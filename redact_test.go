@@ -0,0 +1,200 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var redactSecrets = []string{"secret-access", "secret-refresh", "secret-code", "secret-client", "jane@example.com"}
+
+func assertRedacted(t *testing.T, s string) {
+	t.Helper()
+
+	for _, secret := range redactSecrets {
+		if strings.Contains(s, secret) {
+			t.Errorf("%q not redacted from %q", secret, s)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	cases := []string{
+		"https://example.com/callback?state=abc&code=secret-code&scope=read",
+		"client_id=1&client_secret=secret-client&grant_type=refresh_token&refresh_token=secret-refresh",
+		`{"token_type":"Bearer","access_token":"secret-access","refresh_token" : "secret-refresh"}`,
+		`{"athlete":{"id":1,"email":"jane@example.com"}}`,
+		"Authorization: Bearer secret-access",
+		"sent to jane@example.com",
+	}
+
+	for _, c := range cases {
+		assertRedacted(t, Redact(c))
+	}
+
+	if s := Redact("https://www.strava.com/api/v3/activities?page=2&per_page=30"); s != "https://www.strava.com/api/v3/activities?page=2&per_page=30" {
+		t.Errorf("other values should not be redacted, got %v", s)
+	}
+
+	if s := Redact("response_code=200"); s != "response_code=200" {
+		t.Errorf("only whole parameter names should be redacted, got %v", s)
+	}
+}
+
+func TestClientWithRedaction(t *testing.T) {
+	line := "GET /api/v3/athlete?signature=secret-signature&page=2 HTTP/1.1\r\nX-Api-Key: secret-key\r\nAccept: */*\r\n\r\n{\"signature\": \"secret-signature\"}"
+	if s := Redact(line); s != line {
+		t.Errorf("only the default names should be redacted, got %q", s)
+	}
+
+	r := newRedaction([]string{"x-api-key"}, []string{"signature"})
+	s := r.redact(line)
+	if strings.Contains(s, "secret-key") || strings.Contains(s, "secret-signature") {
+		t.Errorf("headers and parameters should be redacted, got %q", s)
+	}
+
+	if !strings.Contains(s, "X-Api-Key: [REDACTED]\r\nAccept: */*") || !strings.Contains(s, "page=2") {
+		t.Errorf("other headers and parameters should be kept, got %q", s)
+	}
+
+	if s := r.redact("no_signature=1"); s != "no_signature=1" {
+		t.Errorf("only whole parameter names should be redacted, got %v", s)
+	}
+
+	// the logs, dumps and errors of the client are redacted, not those of other clients
+	var logs, dump strings.Builder
+	client := NewClient(newStubTokenSource(), WithRedaction([]string{"X-Api-Key"}, []string{"signature"}), WithDump(&dump),
+		WithLogger(LoggerFunc(func(format string, v ...interface{}) { fmt.Fprintf(&logs, format+"\n", v...) })))
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("refused\nX-Api-Key: " + req.Header.Get("X-Api-Key"))
+	})}
+
+	_, err := NewClubsService(client).Get(1).Header("X-Api-Key", "secret-key").Do()
+	for _, s := range []string{logs.String(), dump.String(), err.Error()} {
+		if strings.Contains(s, "secret-key") || !strings.Contains(s, redacted) {
+			t.Errorf("the header should be redacted, got %q", s)
+		}
+	}
+
+	other := client.With(WithRedaction(nil, nil), WithDump(nil))
+	if _, err := NewClubsService(other).Get(1).Header("X-Api-Key", "secret-key").Do(); !strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("the header should only be redacted by the client, got %q", err)
+	}
+}
+
+func TestRedactErrors(t *testing.T) {
+	inner := errors.New("Get https://example.com/?access_token=secret-access: timeout")
+	err := wrapError(inner, "activities.get id=%d", 1)
+
+	assertRedacted(t, err.Error())
+	if !strings.HasPrefix(err.Error(), "strava: activities.get id=1: ") {
+		t.Errorf("operation should be kept, got %v", err)
+	}
+
+	if !errors.Is(err, inner) {
+		t.Error("original error should be kept in the chain")
+	}
+
+	e := Error{Message: "Bad Request", Errors: []*ErrorDetailed{{Resource: "jane@example.com", Code: "invalid"}}}
+	assertRedacted(t, e.Error())
+	if !strings.Contains(e.Error(), `"code":"invalid"`) {
+		t.Errorf("error codes should not be redacted, got %v", e.Error())
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestClientLogger(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athletes/1/koms": `[]`,
+	})
+
+	logger := &recordingLogger{}
+	client.Logger(logger)
+
+	NewAthletesService(client).ListKOMs(1).Do()
+	NewClubsService(client).ListMembers(1).Do()
+
+	if len(logger.lines) != 2 {
+		t.Fatalf("incorrect number of lines, got %v", logger.lines)
+	}
+
+	if !strings.Contains(logger.lines[0], "GET") || !strings.Contains(logger.lines[0], "/athletes/1/koms") || !strings.Contains(logger.lines[0], "200") {
+		t.Errorf("incorrect line, got %v", logger.lines[0])
+	}
+
+	if !strings.Contains(logger.lines[1], "404") {
+		t.Errorf("incorrect line, got %v", logger.lines[1])
+	}
+
+	client.Logger(nil)
+	NewClubsService(client).ListMembers(1).Do()
+	if len(logger.lines) != 2 {
+		t.Errorf("logging should be disabled, got %v", logger.lines)
+	}
+
+	// lines are redacted
+	client.Logger(logger).logf("GET %s", "https://example.com/?code=secret-code&email=jane@example.com")
+	assertRedacted(t, logger.lines[2])
+}
+
+func TestClientLoggerTransportError(t *testing.T) {
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: &storeRequestTransport{}}
+
+	logger := &recordingLogger{}
+	client.Logger(logger)
+
+	_, err := NewClubsService(client).Get(1).Do()
+	if err == nil || len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "failed") {
+		t.Errorf("transport error should be logged, got %v", logger.lines)
+	}
+}
+
+func TestClientLoggerRateLimit(t *testing.T) {
+	var lines []string
+	logger := LoggerFunc(func(format string, v ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, v...))
+	})
+
+	client := NewClient(newStubTokenSource(), WithLogger(logger), WithRateLimiter(&RateLimit{}))
+	client.httpClient = &http.Client{Transport: &rateLimitTransport{usage: "600,1000"}}
+
+	NewClubsService(client).Get(1).Do()
+	if len(lines) != 2 || lines[1] != "strava: rate limit reached, 600/600 requests short term, 1000/30000 long term" {
+		t.Errorf("rate limit should be logged, got %v", lines)
+	}
+
+	lines = nil
+	client.httpClient.Transport.(*rateLimitTransport).usage = "10,1000"
+	NewClubsService(client).Get(1).Do()
+	if len(lines) != 1 {
+		t.Errorf("only the request should be logged, got %v", lines)
+	}
+}
+
+type rateLimitTransport struct {
+	usage string
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"id":1}`)),
+		Request:    req,
+	}
+	resp.Header.Set("X-Ratelimit-Limit", "600,30000")
+	resp.Header.Set("X-Ratelimit-Usage", t.usage)
+
+	return resp, nil
+}
@@ -0,0 +1,87 @@
+package strava
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// A Response holds the metadata of the response to a call, e.g. for dashboards of the rate limit usage.
+// Calls fill it when it is passed to their Response method:
+//
+//	var resp strava.Response
+//	activity, err := service.Get(id).Response(&resp).Do()
+//	log.Println(resp.StatusCode, resp.RateLimit.UsageShort)
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	RateLimit  RateLimitUsage // usage of the rate limit right after the call, zero if never reported
+	BodyLength int64          // bytes read from the body, of errors too, before decompression
+	Empty      bool           // the body was empty or an empty list, the call returned an empty result
+	Warnings   []Warning      // suspicious data in the result, see WithValidators
+	DryRun     bool           // the request was not sent, see WithDryRun
+}
+
+// fill sets the metadata of the response, read from the given body.
+func (r *Response) fill(resp *http.Response, body *countingReader, rateLimit *RateLimit) {
+	if r == nil {
+		return
+	}
+
+	r.StatusCode = resp.StatusCode
+	r.Header = resp.Header
+	r.RateLimit, _ = rateLimit.usage()
+	r.BodyLength = body.n
+}
+
+// countingReader counts the bytes read from a response body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// decompressBody replaces the body of a gzip encoded response by its decompressed content.
+// Requests set their own Accept-Encoding header, so http.Transport leaves this to the client,
+// which also makes it work for other transports. Partial content is left as is, a part of a gzip stream
+// can't be decompressed on its own.
+func decompressBody(resp *http.Response) {
+	if resp.Uncompressed || resp.StatusCode == http.StatusPartialContent || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+
+	resp.Body = &gzipReader{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipReader decompresses the body when it is first read, empty bodies are not valid gzip.
+type gzipReader struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+func (r *gzipReader) Read(p []byte) (int, error) {
+	if r.reader == nil && r.err == nil {
+		r.reader, r.err = gzip.NewReader(r.body)
+	}
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return r.reader.Read(p)
+}
+
+func (r *gzipReader) Close() error {
+	return r.body.Close()
+}
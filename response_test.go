@@ -0,0 +1,136 @@
+package strava
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCallResponse(t *testing.T) {
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: &rateLimitTransport{usage: "10,1000"}}
+
+	var resp Response
+	if _, err := NewClubsService(client).Get(1).Response(&resp).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.BodyLength != int64(len(`{"id":1}`)) {
+		t.Errorf("incorrect response, got %+v", resp)
+	}
+
+	if resp.Header.Get("X-Ratelimit-Usage") != "10,1000" {
+		t.Errorf("headers should be kept, got %v", resp.Header)
+	}
+
+	if expected := (RateLimitUsage{LimitShort: 600, LimitLong: 30000, UsageShort: 10, UsageLong: 1000}); resp.RateLimit != expected {
+		t.Errorf("incorrect rate limit, got %+v", resp.RateLimit)
+	}
+
+	// snapshot at call time
+	client.httpClient.Transport.(*rateLimitTransport).usage = "20,1010"
+	NewClubsService(client).Get(1).Do()
+
+	if resp.RateLimit.UsageShort != 10 {
+		t.Errorf("response should not change after the call, got %+v", resp.RateLimit)
+	}
+
+	// errors
+	client, _ = newRouteClient(nil)
+
+	resp = Response{}
+	if err := NewActivitiesService(client).Delete(1).Response(&resp).Do(); err == nil {
+		t.Fatal("error expected")
+	}
+
+	if resp.StatusCode != http.StatusNotFound || resp.BodyLength != int64(len(`{"message":"Record Not Found","errors":[]}`)) {
+		t.Errorf("incorrect response of error, got %+v", resp)
+	}
+
+	// calls without a response work as before
+	if _, err := NewActivitiesService(client).Get(1).Do(); err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestClientGzip(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte(`[{"id":1},{"id":2}]`))
+	writer.Close()
+
+	transport := &gzipTransport{body: compressed.Bytes()}
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: transport}
+
+	var resp Response
+	activities, err := NewCurrentAthleteService(client).ListActivities().Response(&resp).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if transport.acceptEncoding != "gzip" {
+		t.Errorf("gzip should be accepted, got %v", transport.acceptEncoding)
+	}
+
+	if len(activities) != 2 || activities[1].Id != 2 {
+		t.Errorf("response should be decompressed, got %v", activities)
+	}
+
+	if resp.BodyLength != int64(compressed.Len()) || resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("incorrect response, got %+v", resp)
+	}
+
+	// errors are decompressed for the error handler
+	transport.status = http.StatusNotFound
+	compressed.Reset()
+	writer.Reset(&compressed)
+	writer.Write([]byte(`{"message":"Record Not Found","errors":[]}`))
+	writer.Close()
+	transport.body = compressed.Bytes()
+
+	_, err = NewCurrentAthleteService(client).ListActivities().Do()
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Payload == nil || apiErr.Payload.Message != "Record Not Found" {
+		t.Errorf("error should be decoded, got %v", err)
+	}
+}
+
+func TestDecompressBodyPartialContent(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusPartialContent, Header: make(http.Header), Body: io.NopCloser(strings.NewReader("part"))}
+	resp.Header.Set("Content-Encoding", "gzip")
+
+	decompressBody(resp)
+	if data, _ := io.ReadAll(resp.Body); string(data) != "part" || resp.Uncompressed {
+		t.Errorf("partial content should be left as is, got %q", data)
+	}
+}
+
+type gzipTransport struct {
+	status         int
+	body           []byte
+	acceptEncoding string
+}
+
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.acceptEncoding = req.Header.Get("Accept-Encoding")
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(t.body)),
+		Request:    req,
+	}
+
+	if t.status != 0 {
+		resp.StatusCode = t.status
+	}
+	resp.Header.Set("Content-Encoding", "gzip")
+
+	return resp, nil
+}
@@ -0,0 +1,98 @@
+package strava
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// An Access allows the requests of a restricted client to an endpoint and the endpoints below it,
+// see Client.Restrict.
+type Access struct {
+	Method string // e.g. "GET", empty for every method
+	Path   string // relative to the base url, e.g. "/activities", which includes "/activities/123/laps"
+}
+
+// ReadAccess returns the accesses that allow GET requests to the endpoints of the paths,
+// e.g. ReadAccess("/activities") for the calls of ActivitiesService that read.
+func ReadAccess(paths ...string) []Access {
+	accesses := make([]Access, len(paths))
+	for i, path := range paths {
+		accesses[i] = Access{Method: "GET", Path: path}
+	}
+
+	return accesses
+}
+
+// allows returns if the access allows the request to the path, relative to the base url.
+func (a Access) allows(method, path string) bool {
+	if a.Method != "" && a.Method != method {
+		return false
+	}
+
+	prefix := strings.TrimSuffix(a.Path, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// A RestrictedError is returned by the calls of a restricted client that none of its accesses allow,
+// before a request is made. See Client.Restrict.
+type RestrictedError struct {
+	Method   string
+	Endpoint string
+}
+
+func (e *RestrictedError) Error() string {
+	return fmt.Sprintf("%s %s is not allowed for this client", e.Method, e.Endpoint)
+}
+
+// Restrict returns a copy of the client that can only make the requests the accesses allow, other calls fail
+// with a *RestrictedError. It shares the token of the client, so parts of an application can be handed a client
+// for only what they need, e.g. client.Restrict(strava.ReadAccess("/activities")...). Copies of a restricted
+// client, also with Restrict, can't make requests it can't make. Token refreshes are still made.
+func (client *Client) Restrict(accesses ...Access) *Client {
+	c := client.With()
+	c.restrictions = append(append([][]Access(nil), client.restrictions...), accesses)
+	return c
+}
+
+// checkRestrictions returns a *RestrictedError if a restriction of the client doesn't allow the request
+// to the path, relative to the base url. Paths with dot segments, which could escape the allowed endpoints,
+// e.g. "/activities/../athlete", are never allowed.
+func (client *Client) checkRestrictions(method, path string) error {
+	if len(client.restrictions) != 0 && hasDotSegments(path) {
+		return &RestrictedError{Method: method, Endpoint: path}
+	}
+
+	for _, accesses := range client.restrictions {
+		allowed := false
+		for _, access := range accesses {
+			if access.allows(method, path) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return &RestrictedError{Method: method, Endpoint: path}
+		}
+	}
+
+	return nil
+}
+
+// hasDotSegments returns if the path, before its query, has "." or ".." segments, also when escaped,
+// or escapes that can't be decoded.
+func hasDotSegments(path string) bool {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		segment, err := url.PathUnescape(segment)
+		if err != nil || segment == "." || segment == ".." {
+			return true
+		}
+	}
+
+	return false
+}
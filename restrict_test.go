@@ -0,0 +1,59 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClientRestrict(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/activities/1":      `{"id":1}`,
+		"/api/v3/activities/1/laps": `[]`,
+		"/api/v3/athlete":           `{"id":1}`,
+	})
+
+	activities := client.Restrict(ReadAccess("/activities")...)
+
+	if _, err := NewActivitiesService(activities).Get(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if _, err := NewActivitiesService(activities).ListLaps(1).Do(); err != nil {
+		t.Fatalf("endpoints below the path should be allowed, got %v", err)
+	}
+
+	var restricted *RestrictedError
+	if _, err := NewActivitiesService(activities).Update(1).Name("Morning Ride").Do(); !errors.As(err, &restricted) || restricted.Method != "PUT" {
+		t.Errorf("update should not be allowed, got %v", err)
+	}
+
+	if _, err := NewCurrentAthleteService(activities).Get().Do(); !errors.As(err, &restricted) || restricted.Endpoint != "/athlete" {
+		t.Errorf("other endpoints should not be allowed, got %v", err)
+	}
+
+	// dot segments can't escape the allowed endpoints
+	for _, path := range []string{"/activities/../athlete", "/activities/%2e%2e/athlete", "/activities/./1"} {
+		if err := activities.Do(context.Background(), "GET", path, nil, nil); !errors.As(err, &restricted) {
+			t.Errorf("%s should not be allowed, got %v", path, err)
+		}
+	}
+
+	// further restrictions don't widen the access
+	athlete := activities.Restrict(Access{Path: "/athlete"})
+	if _, err := NewCurrentAthleteService(athlete).Get().Do(); !errors.As(err, &restricted) {
+		t.Errorf("access of the restricted client should be kept, got %v", err)
+	}
+
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Errorf("the client should not be restricted, got %v", err)
+	}
+
+	if len(transport.requests) != 3 {
+		t.Errorf("only allowed requests should be sent, got %d requests", len(transport.requests))
+	}
+
+	if !activities.ClientOptionsSnapshot().Restricted || client.ClientOptionsSnapshot().Restricted {
+		t.Error("only the copy should be restricted")
+	}
+}
@@ -0,0 +1,97 @@
+package strava
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ExportFormat is the file format of an exported route.
+type ExportFormat string
+
+var ExportFormats = struct {
+	GPX ExportFormat
+	TCX ExportFormat
+}{"gpx", "tcx"}
+
+type RoutesService struct {
+	client *Client
+}
+
+func NewRoutesService(client *Client) *RoutesService {
+	return &RoutesService{client}
+}
+
+/*********************************************************/
+
+type RoutesExportCall struct {
+	service      *RoutesService
+	id           int64
+	format       ExportFormat
+	checksum     string
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
+}
+
+// Export exports the route as a GPX or TCX file.
+func (s *RoutesService) Export(routeId int64, format ExportFormat) *RoutesExportCall {
+	return &RoutesExportCall{
+		service: s,
+		id:      routeId,
+		format:  format,
+	}
+}
+
+// Checksum makes Download verify the file against its hex encoded SHA-256 checksum, e.g. known from an earlier export.
+func (c *RoutesExportCall) Checksum(sha256 string) *RoutesExportCall {
+	c.checksum = sha256
+	return c
+}
+
+func (c *RoutesExportCall) OnError(handler ErrorHandler) *RoutesExportCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *RoutesExportCall) Response(resp *Response) *RoutesExportCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *RoutesExportCall) Timeout(d time.Duration) *RoutesExportCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *RoutesExportCall) Header(key, value string) *RoutesExportCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *RoutesExportCall) path() string {
+	return fmt.Sprintf("/routes/%d/export_%s", c.id, c.format)
+}
+
+// Do returns the exported file.
+func (c *RoutesExportCall) Do() ([]byte, error) {
+	data, err := c.service.client.runWithErrorHandler("GET", c.path(), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return data, wrapError(err, "routes.export id=%d format=%s", c.id, c.format)
+}
+
+// Download writes the exported file to filename, for large routes over flaky connections. The file is
+// received in filename.part, and a failed download is resumed where it stopped by the next Download.
+// The file is only renamed to filename once its size, and its checksum if set, are verified, otherwise
+// the error wraps ErrIncompleteDownload. The policy tells what to do if filename exists.
+func (c *RoutesExportCall) Download(filename string, policy DownloadPolicy) (*DownloadedFile, error) {
+	file, err := c.service.client.downloadFile(c.path(), filename, policy, c.checksum, c.errorHandler, c.response, c.timeout, c.header)
+	return file, wrapError(err, "routes.export id=%d format=%s", c.id, c.format)
+}
@@ -0,0 +1,144 @@
+package strava
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const routeGPX = `<?xml version="1.0" encoding="UTF-8"?><gpx creator="StravaGPX"><trk><name>Loop</name></trk></gpx>`
+
+// newExportClient returns a client serving the export of route 1, with support for ranges.
+func newExportClient(content string) (*Client, *[]*http.Request) {
+	var requests []*http.Request
+
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+
+		w := httptest.NewRecorder()
+		if req.URL.Path != "/api/v3/routes/1/export_gpx" {
+			http.NotFound(w, req)
+		} else {
+			http.ServeContent(w, req, "", time.Time{}, strings.NewReader(content))
+		}
+
+		resp := w.Result()
+		resp.Request = req
+		return resp, nil
+	})}
+
+	return client, &requests
+}
+
+func TestRoutesExport(t *testing.T) {
+	client, requests := newExportClient(routeGPX)
+
+	data, err := NewRoutesService(client).Export(1, ExportFormats.GPX).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if string(data) != routeGPX || (*requests)[0].URL.Path != "/api/v3/routes/1/export_gpx" {
+		t.Errorf("incorrect export, got %s", data)
+	}
+
+	_, err = NewRoutesService(client).Export(2, ExportFormats.GPX).Do()
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing route should not be found, got %v", err)
+	}
+}
+
+func TestRoutesExportDownload(t *testing.T) {
+	client, requests := newExportClient(routeGPX)
+	filename := filepath.Join(t.TempDir(), "loop.gpx")
+
+	// an interrupted download
+	os.WriteFile(filename+".part", []byte(routeGPX[:40]), 0o644)
+
+	file, err := NewRoutesService(client).Export(1, ExportFormats.GPX).Download(filename, DownloadPolicies.Fail)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if data, _ := os.ReadFile(filename); string(data) != routeGPX {
+		t.Errorf("incorrect file, got %s", data)
+	}
+
+	if !file.Resumed || file.Size != int64(len(routeGPX)) || len(file.SHA256) != 64 {
+		t.Errorf("incorrect download, got %+v", file)
+	}
+
+	if r := (*requests)[0].Header.Get("Range"); r != "bytes=40-" {
+		t.Errorf("download should be resumed, got range %q", r)
+	}
+
+	if e := (*requests)[0].Header.Get("Accept-Encoding"); e != "identity" {
+		t.Errorf("ranges should not be compressed, got %q", e)
+	}
+
+	if _, err := os.Stat(filename + ".part"); !os.IsNotExist(err) {
+		t.Error("part should be renamed")
+	}
+
+	// the file exists
+	_, err = NewRoutesService(client).Export(1, ExportFormats.GPX).Download(filename, DownloadPolicies.Fail)
+	if !errors.Is(err, os.ErrExist) {
+		t.Errorf("existing file should fail, got %v", err)
+	}
+
+	skipped, err := NewRoutesService(client).Export(1, ExportFormats.GPX).Download(filename, DownloadPolicies.Skip)
+	if err != nil || !skipped.Skipped || len(*requests) != 1 {
+		t.Errorf("existing file should be skipped, got %+v and %v", skipped, err)
+	}
+
+	overwritten, err := NewRoutesService(client).Export(1, ExportFormats.GPX).Checksum(file.SHA256).Download(filename, DownloadPolicies.Overwrite)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if overwritten.Resumed || overwritten.SHA256 != file.SHA256 || (*requests)[1].Header.Get("Range") != "bytes=0-" {
+		t.Errorf("file should be downloaded again, got %+v", overwritten)
+	}
+}
+
+func TestRoutesExportDownloadIncomplete(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "loop.gpx")
+
+	// the connection drops halfway
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: int64(len(routeGPX)),
+			Body:          io.NopCloser(strings.NewReader(routeGPX[:40])),
+			Request:       req,
+		}, nil
+	})}
+
+	_, err := NewRoutesService(client).Export(1, ExportFormats.GPX).Download(filename, DownloadPolicies.Fail)
+	if !errors.Is(err, ErrIncompleteDownload) {
+		t.Fatalf("download should be incomplete, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(filename + ".part"); string(data) != routeGPX[:40] {
+		t.Errorf("received part should be kept, got %s", data)
+	}
+
+	// the checksum doesn't match
+	client, _ = newExportClient(routeGPX)
+	_, err = NewRoutesService(client).Export(1, ExportFormats.GPX).Checksum("abc").Download(filename, DownloadPolicies.Fail)
+	if !errors.Is(err, ErrIncompleteDownload) {
+		t.Errorf("checksum should not match, got %v", err)
+	}
+
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Error("file should not be created")
+	}
+}
@@ -0,0 +1,92 @@
+package strava
+
+import (
+	"time"
+)
+
+// runningEnergyCost is the mechanical work, in J/kg/m, of running on flat ground.
+// Running power meters use values between 0.98 and 1.04, the higher value is used here.
+const runningEnergyCost = 1.04
+
+// StrideLength returns the distance covered per stride, two steps, when running at speed.
+// Strava reports the cadence of runs in strides, or steps of one foot, per minute.
+func StrideLength(speed Speed, cadence int) Distance {
+	if cadence <= 0 {
+		return 0
+	}
+
+	return Distance(float64(speed) * 60 / float64(cadence))
+}
+
+// StrideLengths returns the stride length of every sample, 0 where the cadence is 0.
+// Requires the Speed and Cadence streams, returns nil otherwise.
+func (s *StreamSet) StrideLengths() []Distance {
+	if s.Speed == nil || s.Cadence == nil {
+		return nil
+	}
+
+	n := len(s.Speed.Data)
+	if len(s.Cadence.Data) < n {
+		n = len(s.Cadence.Data)
+	}
+
+	lengths := make([]Distance, n)
+	for i := 0; i < n; i++ {
+		lengths[i] = StrideLength(Speed(s.Speed.Data[i]), s.Cadence.Data[i])
+	}
+
+	return lengths
+}
+
+// AverageStrideLength returns the distance covered divided by the number of strides taken while moving.
+// Gaps longer than threshold are left out, if threshold is 0 DefaultGapThreshold is used.
+// Requires the Time, Speed and Cadence streams.
+func (s *StreamSet) AverageStrideLength(threshold time.Duration) Distance {
+	if s.Speed == nil || s.Cadence == nil {
+		return 0
+	}
+
+	var distance, strides float64
+	s.eachMoving(threshold, func(i int) {
+		if i < len(s.Speed.Data) && i < len(s.Cadence.Data) {
+			dt := float64(s.Time.Data[i] - s.Time.Data[i-1])
+			distance += s.Speed.Data[i] * dt
+			strides += float64(s.Cadence.Data[i]) / 60 * dt
+		}
+	})
+
+	if strides == 0 {
+		return 0
+	}
+
+	return Distance(distance / strides)
+}
+
+// EstimateRunningPower estimates the power of running at speed on a grade, in percent,
+// for an athlete of the given weight in kilograms. The power on flat ground is based on the energy cost
+// of running used by running power meters, and is adjusted for the grade the same way as GradeAdjustedSpeed.
+// Wind and running economy are not taken into account, so values are comparable between runs of the same
+// athlete rather than to those of a power meter.
+func EstimateRunningPower(speed Speed, grade float64, weight float64) Power {
+	return Power(weight * float64(GradeAdjustedSpeed(speed, grade)) * runningEnergyCost)
+}
+
+// RunningPowers returns the estimated running power of every sample, see EstimateRunningPower.
+// Requires the Speed stream, the Grade stream is used if available. Returns nil without the Speed stream.
+func (s *StreamSet) RunningPowers(weight float64) []Power {
+	if s.Speed == nil {
+		return nil
+	}
+
+	powers := make([]Power, len(s.Speed.Data))
+	for i, speed := range s.Speed.Data {
+		var grade float64
+		if s.Grade != nil && i < len(s.Grade.Data) {
+			grade = s.Grade.Data[i]
+		}
+
+		powers[i] = EstimateRunningPower(Speed(speed), grade, weight)
+	}
+
+	return powers
+}
@@ -0,0 +1,64 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStrideLength(t *testing.T) {
+	// 4 m/s at 90 strides per minute
+	if l := StrideLength(4, 90); math.Abs(l.Meters()-2.6667) > 0.001 {
+		t.Errorf("incorrect stride length, got %v", l)
+	}
+
+	if l := StrideLength(4, 0); l != 0 {
+		t.Errorf("should be 0 without cadence, got %v", l)
+	}
+}
+
+func TestStreamSetStrideLengths(t *testing.T) {
+	s := &StreamSet{
+		Time:    &IntegerStream{Data: []int{0, 10, 20, 30}},
+		Speed:   &DecimalStream{Data: []float64{0, 3, 3, 4.5}},
+		Cadence: &IntegerStream{Data: []int{0, 90, 90, 90}},
+	}
+
+	lengths := s.StrideLengths()
+	if len(lengths) != 4 || lengths[0] != 0 || lengths[1] != 2 || lengths[3] != 3 {
+		t.Errorf("incorrect stride lengths, got %v", lengths)
+	}
+
+	// 105 m in 45 strides
+	if l := s.AverageStrideLength(0); math.Abs(l.Meters()-105.0/45) > 1e-9 {
+		t.Errorf("incorrect average stride length, got %v", l)
+	}
+
+	s.Cadence = nil
+	if s.StrideLengths() != nil || s.AverageStrideLength(0) != 0 {
+		t.Error("should require cadence stream")
+	}
+}
+
+func TestEstimateRunningPower(t *testing.T) {
+	if p := EstimateRunningPower(4, 0, 70); math.Abs(p.Watts()-291.2) > 1e-9 {
+		t.Errorf("incorrect power, got %v", p)
+	}
+
+	if EstimateRunningPower(4, 5, 70) <= EstimateRunningPower(4, 0, 70) {
+		t.Error("uphill should take more power")
+	}
+
+	s := &StreamSet{
+		Speed: &DecimalStream{Data: []float64{4, 4}},
+		Grade: &DecimalStream{Data: []float64{0}},
+	}
+
+	powers := s.RunningPowers(70)
+	if len(powers) != 2 || powers[0] != powers[1] || powers[0] != EstimateRunningPower(4, 0, 70) {
+		t.Errorf("incorrect powers, got %v", powers)
+	}
+
+	if (&StreamSet{}).RunningPowers(70) != nil {
+		t.Error("should require speed stream")
+	}
+}
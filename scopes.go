@@ -0,0 +1,264 @@
+package strava
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FeatureSets are the scopes to request for common kinds of applications,
+// to pass to OAuthAuthenticator.ConnectURL.
+var FeatureSets = struct {
+	// ReadOnlyDashboard shows the profile and public activities of the athlete.
+	ReadOnlyDashboard []Scope
+	// PrivateDashboard also shows private activities and profile details such as zones.
+	PrivateDashboard []Scope
+	// FullWrite also creates, uploads and updates activities and updates the profile.
+	FullWrite []Scope
+}{
+	ReadOnlyDashboard: []Scope{ScopeRead, ScopeActivityRead},
+	PrivateDashboard:  []Scope{ScopeRead, ScopeProfileReadAll, ScopeActivityReadAll},
+	FullWrite:         []Scope{ScopeRead, ScopeProfileReadAll, ScopeProfileWrite, ScopeActivityReadAll, ScopeActivityWrite},
+}
+
+// ConnectURL returns the url for a "Connect with Strava" button, requesting the scopes of the feature sets,
+// e.g. FeatureSets.ReadOnlyDashboard. Users that already authorized the application are not asked again.
+func (auth OAuthAuthenticator) ConnectURL(state string, featureSets ...[]Scope) string {
+	var scopes []Scope
+	for _, set := range featureSets {
+		scopes = append(scopes, set...)
+	}
+
+	return auth.AuthorizationURL(state, uniqueScopes(scopes), false)
+}
+
+// scopeRequirements maps the operations of the calls, as used in their errors, to the scope they need.
+// Calls not listed, such as segments.get, only need ScopeRead, which is granted to every token.
+var scopeRequirements = map[string]Scope{
+	"activities.create":        ScopeActivityWrite,
+	"activities.delete":        ScopeActivityWrite,
+	"activities.get":           ScopeActivityRead,
+	"activities.list_laps":     ScopeActivityRead,
+	"activities.list_photos":   ScopeActivityRead,
+	"activities.list_zones":    ScopeActivityRead,
+	"activities.streams":       ScopeActivityRead,
+	"activities.update":        ScopeActivityWrite,
+	"activity_comments.create": ScopeActivityWrite,
+	"activity_comments.delete": ScopeActivityWrite,
+	"activity_comments.list":   ScopeActivityRead,
+	"activity_kudos.create":    ScopeActivityWrite,
+	"activity_kudos.delete":    ScopeActivityWrite,
+	"activity_kudos.list":      ScopeActivityRead,
+	"athlete.list_activities":  ScopeActivityRead,
+	"athlete.update":           ScopeProfileWrite,
+	"athletes.list_activities": ScopeActivityRead,
+	"segment_efforts.get":      ScopeActivityRead,
+	"segment_efforts.streams":  ScopeActivityRead,
+	"segments.list_efforts":    ScopeActivityRead,
+	"uploads.create":           ScopeActivityWrite,
+	"uploads.get":              ScopeActivityWrite,
+}
+
+// routes maps the endpoints of the api, relative to the base url, to the operations of the calls
+// used by scopeRequirements and subscriptionOperations.
+var routes = []struct {
+	method    string
+	path      *regexp.Regexp
+	operation string
+}{
+	{"POST", regexp.MustCompile(`^/activities$`), "activities.create"},
+	{"GET", regexp.MustCompile(`^/activities/\d+$`), "activities.get"},
+	{"PUT", regexp.MustCompile(`^/activities/\d+$`), "activities.update"},
+	{"DELETE", regexp.MustCompile(`^/activities/\d+$`), "activities.delete"},
+	{"GET", regexp.MustCompile(`^/activities/\d+/laps$`), "activities.list_laps"},
+	{"GET", regexp.MustCompile(`^/activities/\d+/photos$`), "activities.list_photos"},
+	{"GET", regexp.MustCompile(`^/activities/\d+/zones$`), "activities.list_zones"},
+	{"GET", regexp.MustCompile(`^/activities/\d+/streams/`), "activities.streams"},
+	{"GET", regexp.MustCompile(`^/activities/\d+/comments$`), "activity_comments.list"},
+	{"POST", regexp.MustCompile(`^/activities/\d+/comments$`), "activity_comments.create"},
+	{"DELETE", regexp.MustCompile(`^/activities/\d+/comments/\d+$`), "activity_comments.delete"},
+	{"GET", regexp.MustCompile(`^/activities/\d+/kudos$`), "activity_kudos.list"},
+	{"POST", regexp.MustCompile(`^/activities/\d+/kudos$`), "activity_kudos.create"},
+	{"DELETE", regexp.MustCompile(`^/activities/\d+/kudos$`), "activity_kudos.delete"},
+	{"GET", regexp.MustCompile(`^/athlete/activities$`), "athlete.list_activities"},
+	{"PUT", regexp.MustCompile(`^/athlete$`), "athlete.update"},
+	{"GET", regexp.MustCompile(`^/athletes/\d+/activities$`), "athletes.list_activities"},
+	{"GET", regexp.MustCompile(`^/segment_efforts/\d+$`), "segment_efforts.get"},
+	{"GET", regexp.MustCompile(`^/segment_efforts/\d+/streams/`), "segment_efforts.streams"},
+	{"GET", regexp.MustCompile(`^/segments/\d+/all_efforts$`), "segments.list_efforts"},
+	{"GET", regexp.MustCompile(`^/segments/\d+/leaderboard$`), "segments.get_leaderboard"},
+	{"POST", regexp.MustCompile(`^/uploads$`), "uploads.create"},
+	{"GET", regexp.MustCompile(`^/uploads/\d+$`), "uploads.get"},
+}
+
+// operationOf returns the operation of a request to the path, relative to the base url,
+// or an empty string if the endpoint needs no scope other than ScopeRead and no subscription.
+func operationOf(method, path string) string {
+	for _, route := range routes {
+		if route.method == method && route.path.MatchString(path) {
+			return route.operation
+		}
+	}
+
+	return ""
+}
+
+// A ScopeSource provides the scopes granted to the token of a client, e.g. a TokenSource that stores
+// the scope parameter of the oauth callback. See WithScopeCheck.
+type ScopeSource interface {
+	GrantedScopes() ([]Scope, error)
+}
+
+// GrantedScopes is a ScopeSource of a fixed list of scopes.
+type GrantedScopes []Scope
+
+func (s GrantedScopes) GrantedScopes() ([]Scope, error) {
+	return s, nil
+}
+
+// GrantedScopes makes the tokens a ScopeSource, of the scopes granted in the authorization.
+func (r *AuthorizationResponse) GrantedScopes() ([]Scope, error) {
+	return r.Scopes, nil
+}
+
+// ErrMissingScope is returned by calls that need a scope that was not granted, before a request is made.
+// See WithScopeCheck.
+type ErrMissingScope struct {
+	Need      Scope
+	Operation string
+}
+
+func (e *ErrMissingScope) Error() string {
+	return fmt.Sprintf("%s needs the %s scope, which was not granted", e.Operation, e.Need)
+}
+
+// WithScopeCheck makes the client verify, before every request, that the scopes of the source cover
+// the scope needed by the call. Calls fail fast with an *ErrMissingScope otherwise, saving requests
+// that Strava would refuse and telling users which access to grant.
+func WithScopeCheck(source ScopeSource) Option {
+	return func(c *Client) {
+		c.scopeSource = source
+	}
+}
+
+// checkScope returns an *ErrMissingScope if the scopes of the client don't cover the request
+// to the path, relative to the base url.
+func (client *Client) checkScope(method, path string) error {
+	if client.scopeSource == nil {
+		return nil
+	}
+
+	operation := operationOf(method, path)
+	if operation == "" {
+		return nil
+	}
+
+	granted, err := client.scopeSource.GrantedScopes()
+	if err != nil {
+		return err
+	}
+
+	if need := RequiredScope(operation); !ScopesCover(granted, need) {
+		return &ErrMissingScope{Need: need, Operation: operation}
+	}
+
+	return nil
+}
+
+// RequireScopes makes the oauth callback fail with an *ErrMissingScope if the user didn't grant the scopes,
+// e.g. unchecked ScopeActivityReadAll, so the application can explain why and ask again. See AuthorizeFromRequest.
+func (auth *OAuthAuthenticator) RequireScopes(scopes ...Scope) {
+	auth.requiredScopes = scopes
+}
+
+// RequireScopes returns an *ErrMissingScope for the first needed scope the granted scopes don't cover,
+// e.g. the Scopes of an AuthorizationResponse, or nil if they cover them all.
+func RequireScopes(granted []Scope, need ...Scope) error {
+	for _, scope := range need {
+		if !ScopesCover(granted, scope) {
+			return &ErrMissingScope{Need: scope, Operation: "oauth.authorize"}
+		}
+	}
+
+	return nil
+}
+
+// RequiredScope returns the scope needed by a call, identified by its operation as shown in its errors,
+// e.g. "activities.update". Returns ScopeRead for calls that need no other scope.
+func RequiredScope(operation string) Scope {
+	if scope, ok := scopeRequirements[operation]; ok {
+		return scope
+	}
+
+	return ScopeRead
+}
+
+// ParseScopes parses the comma separated scopes as returned in the scope parameter of the oauth callback.
+func ParseScopes(s string) []Scope {
+	var scopes []Scope
+	for _, scope := range strings.Split(s, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, Scope(scope))
+		}
+	}
+
+	return scopes
+}
+
+// impliedScopes are the scopes granted along with a scope, e.g. reading private activities includes
+// reading the public ones.
+var impliedScopes = map[Scope][]Scope{
+	ScopeReadAll:         {ScopeRead},
+	ScopeProfileReadAll:  {ScopeRead},
+	ScopeActivityReadAll: {ScopeActivityRead},
+}
+
+// ScopesCover returns if the granted scopes include the needed scope, directly or implied by a broader scope.
+// ScopeRead is always covered, Strava grants it to every token.
+func ScopesCover(granted []Scope, need Scope) bool {
+	if need == ScopeRead {
+		return true
+	}
+
+	for _, scope := range granted {
+		if scope == need {
+			return true
+		}
+
+		for _, implied := range impliedScopes[scope] {
+			if implied == need {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// uniqueScopes removes duplicate scopes and scopes implied by others, sorted for stable urls.
+// ScopeRead is kept, it is requested explicitly.
+func uniqueScopes(scopes []Scope) []Scope {
+	seen := make(map[Scope]bool)
+	for _, scope := range scopes {
+		seen[scope] = true
+	}
+
+	var unique []Scope
+	for scope := range seen {
+		implied := false
+		for other := range seen {
+			if scope != ScopeRead && other != scope && ScopesCover([]Scope{other}, scope) {
+				implied = true
+			}
+		}
+
+		if !implied {
+			unique = append(unique, scope)
+		}
+	}
+
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+
+	return unique
+}
@@ -0,0 +1,145 @@
+package strava
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOAuthAuthenticatorConnectURL(t *testing.T) {
+	auth := OAuthAuthenticator{callbackUrl: "http://abc.com/strava/oauth"}
+
+	u := auth.ConnectURL("state", FeatureSets.ReadOnlyDashboard)
+	if u != "https://www.strava.com/oauth/authorize?client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=activity%3Aread%2Cread&state=state" {
+		t.Errorf("incorrect connect url, got %v", u)
+	}
+
+	// broader scopes replace the ones they imply
+	u = auth.ConnectURL("", FeatureSets.ReadOnlyDashboard, FeatureSets.PrivateDashboard)
+	if u != "https://www.strava.com/oauth/authorize?client_id=0&redirect_uri=http%3A%2F%2Fabc.com%2Fstrava%2Foauth&response_type=code&scope=activity%3Aread_all%2Cprofile%3Aread_all%2Cread" {
+		t.Errorf("incorrect connect url, got %v", u)
+	}
+}
+
+func TestRequiredScope(t *testing.T) {
+	if s := RequiredScope("activities.update"); s != ScopeActivityWrite {
+		t.Errorf("incorrect scope, got %v", s)
+	}
+
+	if s := RequiredScope("segments.get"); s != ScopeRead {
+		t.Errorf("incorrect scope, got %v", s)
+	}
+
+	// every feature set can use the calls it is meant for
+	if !ScopesCover(FeatureSets.ReadOnlyDashboard, RequiredScope("athlete.list_activities")) {
+		t.Error("read only dashboard should list activities")
+	}
+
+	if ScopesCover(FeatureSets.PrivateDashboard, RequiredScope("uploads.create")) {
+		t.Error("private dashboard should not upload")
+	}
+
+	if !ScopesCover(FeatureSets.FullWrite, RequiredScope("athlete.update")) {
+		t.Error("full write should update the athlete")
+	}
+}
+
+func TestScopesCover(t *testing.T) {
+	granted := ParseScopes("read, activity:read_all")
+	if len(granted) != 2 || granted[1] != ScopeActivityReadAll {
+		t.Fatalf("incorrect scopes, got %v", granted)
+	}
+
+	if !ScopesCover(granted, ScopeActivityRead) {
+		t.Error("activity:read_all should cover activity:read")
+	}
+
+	if ScopesCover(granted, ScopeActivityWrite) {
+		t.Error("activity:write should not be covered")
+	}
+
+	if !ScopesCover(nil, ScopeRead) {
+		t.Error("read should always be covered")
+	}
+}
+
+func TestClientScopeCheck(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/activities/1": `{"id":1}`,
+	})
+	WithScopeCheck(GrantedScopes{ScopeRead, ScopeActivityReadAll})(client)
+
+	if _, err := NewActivitiesService(client).Get(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	_, err := NewActivitiesService(client).Update(1).Name("new name").Do()
+
+	var missing *ErrMissingScope
+	if !errors.As(err, &missing) || missing.Need != ScopeActivityWrite || missing.Operation != "activities.update" {
+		t.Errorf("should return missing scope error, got %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("no request should be made without the scope, got %v requests", len(transport.requests))
+	}
+
+	// endpoints that only need read are not checked
+	if err := client.checkScope("GET", "/segments/1"); err != nil {
+		t.Errorf("should not need a scope, got %v", err)
+	}
+
+	if err := client.checkScope("DELETE", "/activities/1/comments/2"); err == nil {
+		t.Error("should need activity:write")
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	granted := ParseScopes("read,activity:read_all")
+
+	if err := RequireScopes(granted, ScopeRead, ScopeActivityRead, ScopeActivityReadAll); err != nil {
+		t.Errorf("scopes should be covered, got %v", err)
+	}
+
+	err := RequireScopes(ParseScopes("read,activity:read"), ScopeActivityRead, ScopeActivityReadAll)
+
+	var missing *ErrMissingScope
+	if !errors.As(err, &missing) || missing.Need != ScopeActivityReadAll {
+		t.Errorf("should return missing scope error, got %v", err)
+	}
+
+	client, _ := newRouteClient(map[string]string{})
+	WithScopeCheck(&AuthorizationResponse{Scopes: granted})(client)
+
+	if err := client.checkScope("GET", "/activities/1"); err != nil {
+		t.Errorf("granted scopes of the tokens should be checked, got %v", err)
+	}
+
+	if err := client.checkScope("PUT", "/activities/1"); err == nil {
+		t.Error("should need activity:write")
+	}
+}
+
+func TestClientRefreshTokenKeepsScopes(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/oauth/token": `{"access_token":"new","refresh_token":"refresh"}`,
+	})
+
+	ts := client.tokenSource.(*stubTokenSource)
+	ts.response.Scopes = []Scope{ScopeRead, ScopeActivityReadAll}
+	ts.response.Athlete = &AthleteDetailed{}
+	ts.response.Athlete.Id = 42
+
+	response, err := client.refreshToken()
+	if err != nil {
+		t.Fatalf("refresh error: %v", err)
+	}
+
+	if response.AccessToken != "new" || !reflect.DeepEqual(ts.response.Scopes, []Scope{ScopeRead, ScopeActivityReadAll}) {
+		t.Errorf("scopes should be kept, got %+v", ts.response)
+	}
+
+	if ts.response.Athlete == nil || ts.response.Athlete.Id != 42 {
+		t.Errorf("athlete should be kept, got %+v", ts.response.Athlete)
+	}
+}
@@ -1,8 +1,9 @@
 package strava
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 type SegmentEffortDetailed struct {
@@ -13,7 +14,7 @@ type SegmentEffortSummary struct {
 	EffortSummary
 	Segment          SegmentSummary `json:"segment"`
 	AverageCadence   float64        `json:"average_cadence"`
-	AveragePower     float64        `json:"average_watts"`
+	AveragePower     Power          `json:"average_watts"`
 	AverageHeartrate float64        `json:"average_heartrate"`
 	MaximumHeartrate float64        `json:"max_heartrate"`
 	KOMRank          int            `json:"kom_rank"` // 1-10 rank on segment at time of upload
@@ -32,8 +33,12 @@ func NewSegmentEffortsService(client *Client) *SegmentEffortsService {
 /*********************************************************/
 
 type SegmentEffortsGetCall struct {
-	service *SegmentEffortsService
-	id      int64
+	service      *SegmentEffortsService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *SegmentEffortsService) Get(segmentEffortId int64) *SegmentEffortsGetCall {
@@ -43,17 +48,34 @@ func (s *SegmentEffortsService) Get(segmentEffortId int64) *SegmentEffortsGetCal
 	}
 }
 
-func (c *SegmentEffortsGetCall) Do() (*SegmentEffortDetailed, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/segment_efforts/%d", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *SegmentEffortsGetCall) OnError(handler ErrorHandler) *SegmentEffortsGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *SegmentEffortsGetCall) Response(resp *Response) *SegmentEffortsGetCall {
+	c.response = resp
+	return c
+}
 
-	var effort SegmentEffortDetailed
-	err = json.Unmarshal(data, &effort)
-	if err != nil {
-		return nil, err
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *SegmentEffortsGetCall) Timeout(d time.Duration) *SegmentEffortsGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *SegmentEffortsGetCall) Header(key, value string) *SegmentEffortsGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &effort, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *SegmentEffortsGetCall) Do() (*SegmentEffortDetailed, error) {
+	effort, err := runAndDecode[*SegmentEffortDetailed](c.service.client, "GET", fmt.Sprintf("/segment_efforts/%d", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return effort, wrapError(err, "segment_efforts.get id=%d", c.id)
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func TestSegmentEffortsGet(t *testing.T) {
-	client := newCassetteClient(testToken, "segment_effort_get")
+	client := newFixtureClient(t, "segment_effort_get")
 	effort, err := NewSegmentEffortsService(client).Get(801006623).Do()
 
 	expected := &SegmentEffortDetailed{}
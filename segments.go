@@ -3,6 +3,8 @@ package strava
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"reflect"
 	"time"
 )
 
@@ -12,7 +14,7 @@ type SegmentDetailed struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
-	TotalElevationGain float64 `json:"total_elevation_gain"`
+	TotalElevationGain Elevation `json:"total_elevation_gain"`
 	Map                struct {
 		Id       string   `json:"id"`
 		Polyline Polyline `json:"polyline"`
@@ -24,14 +26,15 @@ type SegmentDetailed struct {
 }
 
 type SegmentSummary struct {
-	Id            int64         `json:"id"`
+	RawJSON
+	Id            int64         `json:"id" strava:"required"`
 	Name          string        `json:"name"`
 	ActivityType  ActivityType  `json:"activity_type"`
-	Distance      float64       `json:"distance"`
+	Distance      Distance      `json:"distance"`
 	AverageGrade  float64       `json:"average_grade"`
 	MaximumGrade  float64       `json:"maximum_grade"`
-	ElevationHigh float64       `json:"elevation_high"`
-	ElevationLow  float64       `json:"elevation_low"`
+	ElevationHigh Elevation     `json:"elevation_high"`
+	ElevationLow  Elevation     `json:"elevation_low"`
 	ClimbCategory ClimbCategory `json:"climb_category"`
 	StartLocation Location      `json:"start_latlng"`
 	EndLocation   Location      `json:"end_latlng"`
@@ -47,7 +50,7 @@ type PersonalSegmentSummary struct {
 	AthletePR struct {
 		Id             int64     `json:"id"`
 		ElapsedTime    int       `json:"elapsed_time"`
-		Distance       float64   `json:"distance"`
+		Distance       Distance  `json:"distance"`
 		StartDate      time.Time `json:"start_date"`
 		StartDateLocal time.Time `json:"start_date_local"`
 		IsKOM          bool      `json:"is_kom"`
@@ -56,6 +59,7 @@ type PersonalSegmentSummary struct {
 }
 
 type SegmentLeaderboard struct {
+	RawJSON
 	EntryCount int                        `json:"entry_count"`
 	Entries    []*SegmentLeaderboardEntry `json:"entries"`
 }
@@ -65,8 +69,8 @@ type SegmentLeaderboardEntry struct {
 	AthleteId        int64     `json:"athlete_id"`
 	AthleteGender    Gender    `json:"athlete_gender"`
 	AverageHeartrate float64   `json:"average_hr"`
-	AveragePower     float64   `json:"average_watts"`
-	Distance         float64   `json:"distance"`
+	AveragePower     Power     `json:"average_watts"`
+	Distance         Distance  `json:"distance"`
 	ElapsedTime      int       `json:"elapsed_time"`
 	MovingTime       int       `json:"moving_time"`
 	StartDate        time.Time `json:"start_date"`
@@ -82,14 +86,15 @@ type segmentExplorer struct {
 }
 
 type SegmentExplorerSegment struct {
+	RawJSON
 	Id                  int64         `json:"id"`
 	Name                string        `json:"name"`
 	ClimbCategory       ClimbCategory `json:"climb_category"`
 	AverageGrade        float64       `json:"avg_grade"`
 	StartLocation       Location      `json:"start_latlng"`
 	EndLocation         Location      `json:"end_latlng"`
-	ElevationDifference float64       `json:"elev_difference"`
-	Distance            float64       `json:"distance"`
+	ElevationDifference Elevation     `json:"elev_difference"`
+	Distance            Distance      `json:"distance"`
 	Polyline            Polyline      `json:"points"`
 }
 
@@ -153,8 +158,12 @@ func NewSegmentsService(client *Client) *SegmentsService {
 /*********************************************************/
 
 type SegmentsGetCall struct {
-	service *SegmentsService
-	id      int64
+	service      *SegmentsService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *SegmentsService) Get(segmentId int64) *SegmentsGetCall {
@@ -164,27 +173,48 @@ func (s *SegmentsService) Get(segmentId int64) *SegmentsGetCall {
 	}
 }
 
-func (s *SegmentsGetCall) Do() (*SegmentDetailed, error) {
-	data, err := s.service.client.run("GET", fmt.Sprintf("/segments/%d", s.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (s *SegmentsGetCall) OnError(handler ErrorHandler) *SegmentsGetCall {
+	s.errorHandler = handler
+	return s
+}
 
-	var segment SegmentDetailed
-	err = json.Unmarshal(data, &segment)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (s *SegmentsGetCall) Response(resp *Response) *SegmentsGetCall {
+	s.response = resp
+	return s
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (s *SegmentsGetCall) Timeout(d time.Duration) *SegmentsGetCall {
+	s.timeout = d
+	return s
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (s *SegmentsGetCall) Header(key, value string) *SegmentsGetCall {
+	if s.header == nil {
+		s.header = make(http.Header)
 	}
 
-	return &segment, nil
+	s.header.Add(key, value)
+	return s
+}
+
+func (s *SegmentsGetCall) Do() (*SegmentDetailed, error) {
+	segment, err := runAndDecode[*SegmentDetailed](s.service.client, "GET", fmt.Sprintf("/segments/%d", s.id), nil, s.errorHandler, s.response, s.timeout, s.header)
+	return segment, wrapError(err, "segments.get id=%d", s.id)
 }
 
 /*********************************************************/
 
 type SegmentsListEffortsCall struct {
-	service *SegmentsService
-	id      int64
-	ops     map[string]interface{}
+	service      *SegmentsService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *SegmentsService) ListEfforts(segmentId int64) *SegmentsListEffortsCall {
@@ -216,27 +246,48 @@ func (c *SegmentsListEffortsCall) PerPage(perPage int) *SegmentsListEffortsCall
 	return c
 }
 
-func (c *SegmentsListEffortsCall) Do() ([]*SegmentEffortSummary, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/segments/%d/all_efforts", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *SegmentsListEffortsCall) OnError(handler ErrorHandler) *SegmentsListEffortsCall {
+	c.errorHandler = handler
+	return c
+}
 
-	efforts := make([]*SegmentEffortSummary, 0)
-	err = json.Unmarshal(data, &efforts)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *SegmentsListEffortsCall) Response(resp *Response) *SegmentsListEffortsCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *SegmentsListEffortsCall) Timeout(d time.Duration) *SegmentsListEffortsCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *SegmentsListEffortsCall) Header(key, value string) *SegmentsListEffortsCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return efforts, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *SegmentsListEffortsCall) Do() ([]*SegmentEffortSummary, error) {
+	efforts, err := runAndDecode[[]*SegmentEffortSummary](c.service.client, "GET", fmt.Sprintf("/segments/%d/all_efforts", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return efforts, wrapError(err, "segments.list_efforts id=%d", c.id)
 }
 
 /*********************************************************/
 
 type SegmentsGetLeaderboardCall struct {
-	service *SegmentsService
-	id      int64
-	ops     map[string]interface{}
+	service      *SegmentsService
+	id           int64
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *SegmentsService) GetLeaderboard(segmentId int64) *SegmentsGetLeaderboardCall {
@@ -292,26 +343,47 @@ func (c *SegmentsGetLeaderboardCall) PerPage(perPage int) *SegmentsGetLeaderboar
 	return c
 }
 
-func (c *SegmentsGetLeaderboardCall) Do() (*SegmentLeaderboard, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/segments/%d/leaderboard", c.id), c.ops)
-	if err != nil {
-		return nil, err
-	}
+func (c *SegmentsGetLeaderboardCall) OnError(handler ErrorHandler) *SegmentsGetLeaderboardCall {
+	c.errorHandler = handler
+	return c
+}
 
-	var leaderboard SegmentLeaderboard
-	err = json.Unmarshal(data, &leaderboard)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *SegmentsGetLeaderboardCall) Response(resp *Response) *SegmentsGetLeaderboardCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *SegmentsGetLeaderboardCall) Timeout(d time.Duration) *SegmentsGetLeaderboardCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *SegmentsGetLeaderboardCall) Header(key, value string) *SegmentsGetLeaderboardCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &leaderboard, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *SegmentsGetLeaderboardCall) Do() (*SegmentLeaderboard, error) {
+	leaderboard, err := runAndDecode[*SegmentLeaderboard](c.service.client, "GET", fmt.Sprintf("/segments/%d/leaderboard", c.id), c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	return leaderboard, wrapError(err, "segments.get_leaderboard id=%d", c.id)
 }
 
 /*********************************************************/
 
 type SegmentsExplorerCall struct {
-	service *SegmentsService
-	ops     map[string]interface{}
+	service      *SegmentsService
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *SegmentsService) Explore(south, west, north, east float64) *SegmentsExplorerCall {
@@ -339,16 +411,60 @@ func (c *SegmentsExplorerCall) MaximumCategory(cat int) *SegmentsExplorerCall {
 	return c
 }
 
+func (c *SegmentsExplorerCall) OnError(handler ErrorHandler) *SegmentsExplorerCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *SegmentsExplorerCall) Response(resp *Response) *SegmentsExplorerCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *SegmentsExplorerCall) Timeout(d time.Duration) *SegmentsExplorerCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *SegmentsExplorerCall) Header(key, value string) *SegmentsExplorerCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *SegmentsExplorerCall) Do() ([]*SegmentExplorerSegment, error) {
-	data, err := c.service.client.run("GET", "/segments/explore", c.ops)
+	data, err := c.service.client.runWithErrorHandler("GET", "/segments/explore", c.ops, c.errorHandler, c.response, c.timeout, c.header)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err, "segments.explore")
+	}
+
+	if emptyJSON(data, true) {
+		if c.response != nil {
+			c.response.Empty = true
+		}
+
+		return []*SegmentExplorerSegment{}, nil
 	}
 
 	var explorer segmentExplorer
-	err = json.Unmarshal(data, &explorer)
+	err = c.service.client.decode(data, &explorer)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err, "segments.explore")
+	}
+
+	if c.service.client.rawJSON {
+		// the segments are wrapped in an object, each keeps its own element of the list
+		var raw struct {
+			Segments json.RawMessage `json:"segments"`
+		}
+		json.Unmarshal(data, &raw)
+		keepRawJSON(raw.Segments, reflect.ValueOf(explorer.Segments))
 	}
 
 	return explorer.Segments, nil
@@ -7,7 +7,7 @@ import (
 )
 
 func TestSegmentsGet(t *testing.T) {
-	client := newCassetteClient(testToken, "segment_get")
+	client := newFixtureClient(t, "segment_get")
 	segment, err := NewSegmentsService(client).Get(229781).Do()
 
 	expected := &SegmentDetailed{}
@@ -67,7 +67,7 @@ func TestSegmentsGet(t *testing.T) {
 }
 
 func TestSegmentsListEfforts(t *testing.T) {
-	client := newCassetteClient(testToken, "segment_list_efforts")
+	client := newFixtureClient(t, "segment_list_efforts")
 	efforts, err := NewSegmentsService(client).ListEfforts(229781).Do()
 
 	if err != nil {
@@ -163,7 +163,7 @@ func TestSegmentsListEfforts(t *testing.T) {
 }
 
 func TestSegmentsGetLeaderboard(t *testing.T) {
-	client := newCassetteClient(testToken, "segment_get_leaderboard")
+	client := newFixtureClient(t, "segment_get_leaderboard")
 	leaderboard, err := NewSegmentsService(client).GetLeaderboard(229781).Do()
 
 	if err != nil {
@@ -253,7 +253,7 @@ func TestSegmentsGetLeaderboard(t *testing.T) {
 }
 
 func TestSegmentsExplore(t *testing.T) {
-	client := newCassetteClient(testToken, "segment_explore")
+	client := newFixtureClient(t, "segment_explore")
 	segments, err := NewSegmentsService(client).Explore(37.674887, -122.595185, 37.840461, -122.280015).Do()
 
 	if err != nil {
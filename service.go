@@ -1,56 +1,102 @@
 package strava
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ClientId and ClientSecret are used by clients and authenticators without credentials of their own.
+//
+// Deprecated: set the credentials with Client.Credentials and OAuthAuthenticator.SetCredentials,
+// so one process can serve multiple Strava applications.
 var ClientId int
 var ClientSecret string
 
 const basePath = "https://www.strava.com/api/v3"
+const defaultUserAgent = "caselongo/strava-go"
 const timeFormat = "2006-01-02T15:04:05Z"
 
 type Client struct {
 	tokenSource TokenSource
 	//authorizationResponse *AuthorizationResponse
-	httpClient *http.Client
+	httpClient   *http.Client
+	errorHandler ErrorHandler
+	logger       Logger
+	baseURL      string
+	userAgent    string
+	rateLimit    *RateLimit
+	scopeSource  ScopeSource
+	unsubscribed bool // set by WithSubscription(false)
+	rawJSON      bool // set by WithRawJSON
+	strictJSON   bool // set by WithStrictJSON
+	dryRun       bool // set by WithDryRun
+	readOnly     bool // set by WithReadOnly
+	tracer       Tracer
+	metrics      Metrics
+	pacer        *pacer             // set by WithRequestDelay
+	maintenance  *maintenance       // set by WithMaintenanceRetry
+	breaker      *circuitBreaker    // set by WithCircuitBreaker
+	limiter      concurrencyLimiter // set by WithMaxConcurrency
+	etags        ETagStore          // set by WithETags
+	cache        Cache              // set by WithCache
+	cacheTTL     time.Duration
+	flights      *flightGroup  // set by WithCoalescing
+	dump         *dumper       // set by WithDump and Client.Dump
+	redaction    *redaction    // set by WithRedaction
+	timeout      time.Duration // of calls without a Timeout of their own, set by WithTimeout
+	validators   []Validator   // set by WithValidators
+	clock        Clock         // set by WithClock
+
+	tokenRefreshed TokenRefreshedFunc // set by OnTokenRefreshed
+	refreshMargin  time.Duration      // before the expiry of the token, set by WithRefreshMargin
+	restrictions   [][]Access         // set by Client.Restrict, requests must be allowed by each
+	revoked        *revokedToken      // shared by the copies of the client
+
+	// labels of the observations of the metrics, set by WithMetricLabels
+	metricTenant     string
+	metricAthleteKey []byte
+
+	// credentials of the application, used to refresh the token
+	clientId     int
+	clientSecret string
 }
 
+// An ErrorHandler converts a non 2xx response into an error.
+// Handlers can be set per client with Client.OnError and per call with the OnError method of the call.
+// The Request of the response is always set to the request that was made,
+// so handlers can log or route errors by method and endpoint, e.g. resp.Request.URL.Path.
 type ErrorHandler func(*http.Response) error
 
-var defaultErrorHandler ErrorHandler = func(resp *http.Response) error {
-	// check status code, could be 500, or most likely the client_secret is incorrect
-	if resp.StatusCode/100 == 5 {
-		return errors.New("server error")
-	}
+// defaultErrorHandler is used by clients that have no ErrorHandler of their own, with the clock of the client.
+var defaultErrorHandler = errorHandlerWithClock(systemClock{})
 
-	if resp.StatusCode/100 == 4 {
-		var response Error
-		contents, _ := io.ReadAll(resp.Body)
-		err := json.Unmarshal(contents, &response)
-		if err != nil {
-			return err
+// errorHandlerWithClock returns the default ErrorHandler, telling the RetryAfter of 503 responses by the clock.
+func errorHandlerWithClock(clock Clock) ErrorHandler {
+	return func(resp *http.Response) error {
+		if resp.StatusCode/100 == 3 {
+			return errors.New("redirect error")
 		}
 
-		return response
-	}
-
-	if resp.StatusCode/100 == 3 {
-		return errors.New("redirect error")
+		return newAPIError(resp, clock.Now())
 	}
-	return nil
 }
 
 // validateToken validates the current token provided by TokenSource.
 // if retrieves the token if it not already did and refreshes the token if it has expired
 func (client *Client) validateToken() (*AuthorizationResponse, error) {
+	if client.tokenSource == nil {
+		return nil, ErrNoTokenSource
+	}
+
 	authorizationResponse, err := client.tokenSource.GetAuthorizationResponse()
 	if err != nil {
 		return nil, err
@@ -60,32 +106,67 @@ func (client *Client) validateToken() (*AuthorizationResponse, error) {
 		return nil, errors.New("accesstoken is empty string")
 	}
 
-	expiresAt := time.UnixMicro(authorizationResponse.ExpiresAt * 1000)
-	if expiresAt.After(time.Now().Add(10 * time.Second)) {
+	if !tokenExpired(authorizationResponse, client.clock.Now(), client.refreshMargin) {
 		return authorizationResponse, nil
 	}
 
+	refreshed, err := client.refreshToken()
+	if !errors.Is(err, ErrTokenRevoked) {
+		return refreshed, err
+	}
+
+	// another process may have refreshed the token first, rotating the refresh token,
+	// and saved the new tokens in the TokenSource
+	reloaded, reloadErr := client.tokenSource.GetAuthorizationResponse()
+	if reloadErr != nil || reloaded.RefreshToken == authorizationResponse.RefreshToken {
+		return nil, err
+	}
+
+	client.logf("strava: refresh token was rotated, retrying with the stored token")
+	if !tokenExpired(reloaded, client.clock.Now(), client.refreshMargin) {
+		return reloaded, nil
+	}
+
 	return client.refreshToken()
 }
 
-// refreshToken refreshes the token if it has expired
+// TokenRefreshMargin is how long before it expires an access token is refreshed by default, so it doesn't
+// expire while a request is made. See WithRefreshMargin.
+const TokenRefreshMargin = 10 * time.Second
+
+// tokenExpired returns if the access token expired, or expires within the margin of now.
+func tokenExpired(authorizationResponse *AuthorizationResponse, now time.Time, margin time.Duration) bool {
+	return !authorizationResponse.ExpiresAtTime().After(now.Add(margin))
+}
+
+// refreshToken refreshes the token if it has expired. If Strava rejects the refresh token,
+// e.g. because it was rotated by another process, the error wraps ErrTokenRevoked.
 func (client *Client) refreshToken() (*AuthorizationResponse, error) {
 	authorizationResponse, err := client.tokenSource.GetAuthorizationResponse()
 	if err != nil {
 		return nil, err
 	}
 
+	if client.revoked.is(authorizationResponse.RefreshToken) {
+		return nil, fmt.Errorf("%w: %w: the refresh token was rejected before", ErrReauthorizationRequired, ErrTokenRevoked)
+	}
+
+	clientId, clientSecret := client.credentials()
+
 	values := make(url.Values)
-	values.Set("client_id", fmt.Sprintf("%d", ClientId))
-	values.Set("client_secret", ClientSecret)
+	values.Set("client_id", fmt.Sprintf("%d", clientId))
+	values.Set("client_secret", clientSecret)
 	values.Set("grant_type", "refresh_token")
 	values.Set("refresh_token", authorizationResponse.RefreshToken)
 
-	resp, err := client.httpClient.PostForm(basePath+"/oauth/token", values)
+	resp, err := client.httpClient.PostForm(client.baseURL+"/oauth/token", values)
 	if err != nil {
-		return nil, err
+		client.logf("strava: token refresh failed: %v", err)
+		return nil, client.redactError(err)
 	}
 
+	client.logf("strava: token refresh %d", resp.StatusCode)
+
 	// check status code, could be 500, or most likely the client_secret is incorrect
 	if resp.StatusCode/100 == 5 {
 		return nil, OAuthServerErr
@@ -99,6 +180,11 @@ func (client *Client) refreshToken() (*AuthorizationResponse, error) {
 			return nil, err
 		}
 
+		if invalidGrant(&response, contents) {
+			client.revoked.set(authorizationResponse.RefreshToken)
+			return nil, fmt.Errorf("%w: %w: %w", ErrReauthorizationRequired, ErrTokenRevoked, &response)
+		}
+
 		if len(response.Errors) == 0 {
 			return nil, OAuthServerErr
 		}
@@ -121,31 +207,183 @@ func (client *Client) refreshToken() (*AuthorizationResponse, error) {
 		return nil, err
 	}
 
+	// the scopes and athlete of a token don't change when it is refreshed, Strava doesn't return them
+	if newAuthorizationResponse.Scopes == nil {
+		newAuthorizationResponse.Scopes = authorizationResponse.Scopes
+	}
+
+	if newAuthorizationResponse.Athlete == nil {
+		newAuthorizationResponse.Athlete = authorizationResponse.Athlete
+	}
+
 	err = client.tokenSource.SaveAuthorizationResponse("", &newAuthorizationResponse)
 	if err != nil {
 		return nil, err
 	}
 
+	if client.tokenRefreshed != nil {
+		client.tokenRefreshed(authorizationResponse, &newAuthorizationResponse)
+	}
+
 	return &newAuthorizationResponse, nil
 }
 
-// NewClient builds a normal client for making requests to the strava api.
-// a http.Client can be passed in if http.DefaultClient can not be used.
-func NewClient(tokenSource TokenSource, client ...*http.Client) *Client {
-	c := &Client{tokenSource: tokenSource}
-	if len(client) != 0 {
-		c.httpClient = client[0]
-	} else {
-		c.httpClient = http.DefaultClient
+// invalidGrant returns if a failed token refresh was rejected because of the refresh token,
+// as an error of Strava or an oauth invalid_grant error.
+func invalidGrant(response *Error, contents []byte) bool {
+	for _, e := range response.Errors {
+		if e.Resource == "RefreshToken" {
+			return true
+		}
+	}
+
+	var oauthErr struct {
+		Error string `json:"error"`
+	}
+
+	return json.Unmarshal(contents, &oauthErr) == nil && oauthErr.Error == "invalid_grant"
+}
+
+// revokedToken is the refresh token Strava rejected last, so it is not sent again. Tokens saved
+// in the TokenSource after the athlete connected the application again are refreshed as usual.
+type revokedToken struct {
+	lock         sync.Mutex
+	refreshToken string
+}
+
+func (r *revokedToken) is(refreshToken string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.refreshToken != "" && r.refreshToken == refreshToken
+}
+
+func (r *revokedToken) set(refreshToken string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.refreshToken = refreshToken
+}
+
+// NewClient builds a normal client for making requests to the strava api, configured with options
+// such as WithHTTPClient if http.DefaultClient can not be used, for example:
+//
+//	client := strava.NewClient(tokenSource, strava.WithHTTPClient(httpClient), strava.WithUserAgent("my-app"))
+func NewClient(tokenSource TokenSource, options ...Option) *Client {
+	c := &Client{
+		tokenSource: tokenSource,
+		httpClient:  http.DefaultClient,
+		baseURL:     basePath,
+		userAgent:   defaultUserAgent,
+		rateLimit:   &RateLimiting,
+		dump:        &dumper{},
+		clock:       systemClock{},
+		revoked:     &revokedToken{},
+
+		refreshMargin: TokenRefreshMargin,
+	}
+
+	for _, option := range options {
+		option(c)
 	}
+
 	return c
 }
 
-// NewStubResponseClient can be used for testing
-// TODO, stub out with an actual response
+// With returns a copy of the client with the options applied, e.g. a Logger, ErrorHandler or timeout
+// of its own for batch jobs. The copy shares the TokenSource, rate limits, request delay, circuit breaker, Cache
+// and ETagStore of the client, so both stay within the same limits. Dumping and coalescing are not shared.
+func (client *Client) With(options ...Option) *Client {
+	c := *client
+	c.dump = &dumper{w: client.dump.writer(), redaction: client.redaction}
+	c.validators = append([]Validator(nil), client.validators...)
+	if client.flights != nil {
+		WithCoalescing()(&c)
+	}
+
+	for _, option := range options {
+		option(&c)
+	}
+
+	return &c
+}
+
+// OnError sets the ErrorHandler used for all calls made with this client,
+// calls can still override it with their own OnError. Passing nil restores the default handler.
+func (client *Client) OnError(handler ErrorHandler) *Client {
+	client.errorHandler = handler
+	return client
+}
+
+// Credentials sets the id and secret of the Strava application the client refreshes tokens for.
+// If not set, the deprecated package level ClientId and ClientSecret are used.
+func (client *Client) Credentials(clientId int, clientSecret string) *Client {
+	client.clientId = clientId
+	client.clientSecret = clientSecret
+	return client
+}
+
+// credentials returns the credentials of the client, or the package level ones if it has none.
+func (client *Client) credentials() (int, string) {
+	return credentialsOrDefault(client.clientId, client.clientSecret)
+}
+
+// credentialsOrDefault returns the given credentials, falling back to the package level
+// ClientId and ClientSecret if the id is not set.
+func credentialsOrDefault(clientId int, clientSecret string) (int, string) {
+	if clientId == 0 {
+		return ClientId, ClientSecret
+	}
+
+	return clientId, clientSecret
+}
+
+// Logger sets the Logger that receives a line for every request made with this client. Tokens, codes and
+// email addresses are redacted from the lines. Passing nil disables logging, which is the default.
+func (client *Client) Logger(logger Logger) *Client {
+	client.logger = logger
+	return client
+}
+
+// A TokenRefreshedFunc is called with the previous and the refreshed tokens after a refresh of the token
+// succeeded and was saved in the TokenSource, see Client.OnTokenRefreshed.
+type TokenRefreshedFunc func(previous, refreshed *AuthorizationResponse)
+
+// OnTokenRefreshed sets the function called after every successful refresh of the token of the client,
+// e.g. to count refreshes, write an audit log or push the tokens to other services. It is called
+// synchronously, before the call that needed the refresh is made. Passing nil removes it.
+func (client *Client) OnTokenRefreshed(callback TokenRefreshedFunc) *Client {
+	client.tokenRefreshed = callback
+	return client
+}
+
+// errorHandlerFor returns the handler to use for a call, the call's own handler
+// if it has one, otherwise the client's handler or the default handler.
+func (client *Client) errorHandlerFor(handler ErrorHandler) ErrorHandler {
+	if handler != nil {
+		return handler
+	}
+
+	if client.errorHandler != nil {
+		return client.errorHandler
+	}
+
+	return errorHandlerWithClock(client.clock)
+}
+
+// NewStubResponseClient can be used for testing, every request gets the content
+// with the status code, 200 by default.
 func NewStubResponseClient(content string, statusCode ...int) *Client {
-	c := NewClient(nil)
-	t := &stubResponseTransport{content: content}
+	c := NewClient(staticTokenSource{})
+	t := &stubResponseTransport{content: content, statusCode: http.StatusOK}
 
 	if len(statusCode) != 0 {
 		t.statusCode = statusCode[0]
@@ -156,6 +394,17 @@ func NewStubResponseClient(content string, statusCode ...int) *Client {
 	return c
 }
 
+// staticTokenSource is the never expiring token of stub clients.
+type staticTokenSource struct{}
+
+func (staticTokenSource) GetAuthorizationResponse() (*AuthorizationResponse, error) {
+	return &AuthorizationResponse{AccessToken: "stub", ExpiresAt: math.MaxInt64 / 1000}, nil
+}
+
+func (staticTokenSource) SaveAuthorizationResponse(state string, response *AuthorizationResponse) error {
+	return nil
+}
+
 type stubResponseTransport struct {
 	http.Transport
 	content    string
@@ -173,54 +422,283 @@ func (t *stubResponseTransport) RoundTrip(req *http.Request) (*http.Response, er
 }
 
 func (client *Client) run(method, path string, params map[string]interface{}) ([]byte, error) {
-	var err error
+	return client.runWithErrorHandler(method, path, params, nil, nil, 0, nil)
+}
+
+// runWithErrorHandler runs the request using the given ErrorHandler,
+// or the client's handler if it is nil, and fills the Response, if not nil.
+// The request fails if it takes longer than the timeout, if positive, and has the header, if any.
+func (client *Client) runWithErrorHandler(method, path string, params map[string]interface{}, errorHandler ErrorHandler, response *Response, timeout time.Duration, header http.Header) ([]byte, error) {
+	ctx, cancel := client.callContext(timeout)
+	defer cancel()
 
+	return client.runWithContext(ctx, method, path, params, errorHandler, response, header)
+}
+
+// runWithContext runs the request with the context, see runWithErrorHandler.
+func (client *Client) runWithContext(ctx context.Context, method, path string, params map[string]interface{}, errorHandler ErrorHandler, response *Response, header http.Header) ([]byte, error) {
+	req, err := client.newRequest(ctx, method, path, params)
+	if err != nil {
+		return nil, err
+	}
+	addHeader(req, header)
+
+	return client.execute(req, errorHandler, response, nil)
+}
+
+// newRequest creates a request to the path, relative to the base url. The params are sent
+// as the query string, or as a form for POST requests.
+func (client *Client) newRequest(ctx context.Context, method, path string, params map[string]interface{}) (*http.Request, error) {
 	values := make(url.Values)
 	for k, v := range params {
 		values.Set(k, fmt.Sprintf("%v", v))
 	}
 
-	var req *http.Request
 	if method == "POST" {
-		req, err = http.NewRequest("POST", basePath+path, strings.NewReader(values.Encode()))
+		req, err := http.NewRequestWithContext(ctx, "POST", client.baseURL+path, strings.NewReader(values.Encode()))
 		if err != nil {
 			return nil, err
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return req, nil
+	}
+
+	return http.NewRequestWithContext(ctx, method, client.baseURL+path+"?"+values.Encode(), nil)
+}
+
+// Do calls an endpoint of the api that has no call in this package, e.g. a new endpoint of Strava,
+// with the authorization, scope checks, rate limiting, logging and error handling of the other calls.
+// The path is relative to the base url, e.g. "/athlete/zones". The params are sent as the query string,
+// or as a form for POST requests. The response is decoded into v, unless v is nil.
+func (client *Client) Do(ctx context.Context, method, path string, params map[string]interface{}, v interface{}) error {
+	if client.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.timeout)
+		defer cancel()
+	}
+
+	req, err := client.newRequest(ctx, method, path, params)
+	if err != nil {
+		return wrapError(err, "%s %s", method, path)
+	}
+
+	_, err = client.execute(req, nil, nil, v)
+	return wrapError(err, "%s %s", method, path)
+}
+
+func (client *Client) runRequestWithErrorHandler(req *http.Request, errorHandler ErrorHandler) ([]byte, error) {
+	return client.execute(req, errorHandler, nil, nil)
+}
+
+// execute makes the request and, unless v is nil, decodes the response into v. The data of the
+// response is only returned if v is nil or the client keeps raw json or decodes strictly, other
+// responses are decoded while they are read. The call is traced, observed by the metrics and logged.
+func (client *Client) execute(req *http.Request, errorHandler ErrorHandler, response *Response, v interface{}) ([]byte, error) {
+	path := strings.TrimPrefix(req.URL.Path, client.apiPath())
+	record := &callRecord{SpanAttributes: SpanAttributes{Method: req.Method, Endpoint: endpointTemplate(path)}, response: response}
+	if response != nil {
+		*response = Response{}
+	}
+
+	ctx, span := client.startSpan(req.Context(), record.Method, record.Endpoint)
+	req = req.WithContext(ctx)
+
+	var data []byte
+	var err error
+	if key := client.coalesceKey(req, errorHandler); key != "" {
+		// the data is shared by the calls, each decodes it
+		data, err = client.flights.do(key, func() ([]byte, error) {
+			return client.maintenance.doRequest(client, req, path, errorHandler, record, nil)
+		})
+
+		if err == nil && v != nil {
+			err = client.decodeResponse(record, data, v)
+		}
 	} else {
-		req, err = http.NewRequest(method, basePath+path+"?"+values.Encode(), nil)
+		data, err = client.maintenance.doRequest(client, req, path, errorHandler, record, v)
+	}
+
+	if err == nil {
+		client.validate(record, v)
+	}
+
+	if record.responded {
+		client.logf("strava: %s %s %d (%s)", req.Method, req.URL, record.StatusCode, record.timing)
+		client.observeTiming(record)
+
+		if usage, exceeded := client.rateLimit.exceededUsage(); exceeded {
+			client.logf("strava: rate limit reached, %s", usage)
+		}
+	}
+
+	span.End(record.SpanAttributes, err)
+
+	return data, err
+}
+
+// doRequest makes the request to the path, relative to the base url, decodes the response into v, if not nil,
+// and records the call.
+func (client *Client) doRequest(req *http.Request, path string, errorHandler ErrorHandler, record *callRecord, v interface{}) ([]byte, error) {
+	if err := client.checkReadOnly(req.Method); err != nil {
+		return nil, err
+	}
+
+	if err := client.checkRestrictions(req.Method, path); err != nil {
+		return nil, err
+	}
+
+	if err := client.checkScope(req.Method, path); err != nil {
+		return nil, err
+	}
+
+	if err := client.checkSubscription(req.Method, path); err != nil {
+		return nil, err
+	}
+
+	// requests of the application send its credentials instead of a token, see runWithCredentials
+	var authorizationResponse *AuthorizationResponse
+	var err error
+	start := time.Now()
+	if !withCredentials(req) {
+		authorizationResponse, err = client.validateToken()
+		record.timing.TokenValidation = time.Since(start)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return client.runRequest(req)
-}
+	record.labels = client.metricLabels(authorizationResponse)
 
-func (client *Client) runRequestWithErrorHandler(req *http.Request, errorHandler ErrorHandler) ([]byte, error) {
-	authorizationResponse, err := client.validateToken()
+	if client.dryRunning(req.Method) {
+		return client.doDryRun(req, record, v)
+	}
+
+	cacheKey, data, ok := client.cached(req, authorizationResponse)
+	if ok {
+		if v != nil {
+			err = client.decodeResponse(record, data, v)
+		}
+
+		return data, err
+	}
+
+	if authorizationResponse != nil {
+		req.Header.Set("Authorization", "Bearer "+authorizationResponse.AccessToken)
+	}
+	req.Header.Set("User-Agent", client.userAgent)
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	etagKey, cached := client.conditional(req, authorizationResponse)
+
+	// the waits of all requests of the call, retried during maintenance, add up
+	wait, err := client.limiter.acquire(req.Context())
+	record.RateLimitWait += wait
+	record.timing.RateLimitWait = record.RateLimitWait
+	if err != nil {
+		return nil, err
+	}
+	defer client.limiter.release()
+
+	deadline, _ := req.Context().Deadline()
+	wait, err = client.pacer.wait(client.clock, deadline)
+	record.RateLimitWait += wait
+	record.timing.RateLimitWait = record.RateLimitWait
+	if err != nil {
+		return nil, err
+	}
+
+	wait, err = client.maintenance.wait(client.clock, deadline)
+	record.RateLimitWait += wait
+	record.timing.RateLimitWait = record.RateLimitWait
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+authorizationResponse.AccessToken)
-	req.Header.Set("User-Agent", "caselongo/strava-go")
+	if err := client.breaker.allow(client.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	client.dump.dumpRequest(req)
+
+	start = time.Now()
 	resp, err := client.httpClient.Do(req)
+	client.breaker.done(err != nil || resp.StatusCode >= http.StatusInternalServerError, req.Context().Err() != nil, client.clock.Now())
 
 	// this was a poor request, maybe strava servers down?
 	if err != nil {
-		return nil, err
+		client.dump.dumpError(req, err)
+		client.observeRequest(record.labels, req.Method, record.Endpoint, 0, time.Since(start))
+		client.logf("strava: %s %s failed: %v", req.Method, req.URL, err)
+		return nil, client.redactError(err)
 	}
 
+	record.StatusCode = resp.StatusCode
+	record.responded = true
+	client.observeRequest(record.labels, req.Method, record.Endpoint, resp.StatusCode, time.Since(start))
+
 	defer resp.Body.Close()
 
-	RateLimiting.updateRateLimits(resp)
+	// transports are not required to set the request, error handlers rely on it
+	if resp.Request == nil {
+		resp.Request = req
+	}
+
+	client.rateLimit.updateRateLimits(resp, client.clock.Now())
+	client.maintenance.hold(resp, client.clock.Now())
+	client.observeRateLimit(record.labels)
+
+	body := &countingReader{ReadCloser: resp.Body}
+	resp.Body = body
+	decompressBody(resp)
+	client.dump.dumpResponse(req, resp)
+
+	errorHandler = subscriptionErrorHandler(client.errorHandlerFor(errorHandler), req.Method, path)
+
+	download, isDownload := v.(downloader)
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		// unchanged since the body was stored, see WithETags
+		data = cached
+		client.storeCache(cacheKey, data)
+	case resp.StatusCode/100 == 2 && isDownload:
+		// files are written while they are read
+		record.timing.Network = time.Since(start)
+
+		err = download.download(resp)
+		record.response.fill(resp, body, client.rateLimit)
+
+		return nil, err
+	case resp.StatusCode/100 > 2 || v == nil || client.rawJSON || client.strictJSON || etagKey != "" || cacheKey != "":
+		// the data is returned, kept in the models, checked strictly or stored
+		data, err = checkResponseForErrorsWithErrorHandler(resp, errorHandler)
+		if err == nil {
+			client.storeETag(etagKey, resp, data)
+			client.storeCache(cacheKey, data)
+		}
+	default:
+		// large lists and streams are decoded without holding the whole body in memory
+		record.timing.Network = time.Since(start)
+
+		err = client.decodeStream(record, resp.Body, v)
+		record.response.fill(resp, body, client.rateLimit)
+
+		return nil, err
+	}
+
+	record.timing.Network = time.Since(start)
+	if err == nil && v != nil {
+		err = client.decodeResponse(record, data, v)
+	}
+
+	record.response.fill(resp, body, client.rateLimit)
 
-	return checkResponseForErrorsWithErrorHandler(resp, errorHandler)
+	return data, err
 }
 
 func (client *Client) runRequest(req *http.Request) ([]byte, error) {
-	return client.runRequestWithErrorHandler(req, defaultErrorHandler)
+	return client.runRequestWithErrorHandler(req, nil)
 }
 
 func checkResponseForErrorsWithErrorHandler(resp *http.Response, errorHandler ErrorHandler) ([]byte, error) {
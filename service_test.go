@@ -1,128 +1,412 @@
 package strava
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"testing"
 )
 
-var testToken string
-
-func init() {
-	testToken = os.Getenv("access_token")
-	if testToken == "" {
-		// please don't deauthorize this token. It offers no special access.
-		testToken = "21b4fe41a815dd7de4f0cae7f04bbbf9aa0f9507"
+// newFixtureClient returns a client answering every request with the fixture testdata/<fixture>.json,
+// a json body as returned by Strava, and the status code, 200 by default.
+func newFixtureClient(t *testing.T, fixture string, statusCode ...int) *Client {
+	content, err := os.ReadFile("testdata/" + fixture + ".json")
+	if err != nil {
+		t.Fatalf("fixture error: %v", err)
 	}
+
+	return NewStubResponseClient(string(content), statusCode...)
 }
 
-var cassetteDirectory = "cassettes"
+/*********************************************************/
 
-func newCassetteClient(token, cassette string) *Client {
-	c := NewClient(token)
-	c.httpClient = &http.Client{
-		Transport: &cassetteTransport{
-			token:     token,
-			directory: cassetteDirectory,
-			cassette:  cassette},
-	}
+func newStoreRequestClient() *Client {
+	c := NewClient(newStubTokenSource())
+	c.httpClient = &http.Client{Transport: &storeRequestTransport{}}
 
 	return c
 }
 
-type cassetteTransport struct {
+type storeRequestTransport struct {
 	http.Transport
-	token     string
-	directory string
-	cassette  string
+	request *http.Request
 }
 
-// if directory/cassette.json exists, return it
-// else run, save and return
-func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	filename := t.directory + "/" + t.cassette
-	if _, e := os.Stat(filename + ".resp"); e == nil {
-		content, _ := ioutil.ReadFile(filename + ".resp")
-		var resp http.Response
-		json.Unmarshal(content, &resp)
+func (t *storeRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.request = req
 
-		// and the body
-		resp.Body, _ = os.Open(filename + ".body")
+	return nil, errors.New("for testing, no request made")
+}
 
-		// check for the error too
-		var err error = nil
-		if _, e = os.Stat(filename + ".err"); err == nil {
-			content, _ := ioutil.ReadFile(filename + ".err")
+/*********************************************************/
 
-			json.Unmarshal(content, &err)
-		}
+type stubTokenSource struct {
+	response *AuthorizationResponse
+	saves    int
+}
 
-		return &resp, err
+func newStubTokenSource() *stubTokenSource {
+	return &stubTokenSource{
+		response: &AuthorizationResponse{
+			AccessToken:  "token",
+			RefreshToken: "refresh",
+			ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+		},
 	}
+}
 
-	// need to fetch the data from the web, so use the default transport
-	resp, err := http.DefaultClient.Do(req)
+func (ts *stubTokenSource) GetAuthorizationResponse() (*AuthorizationResponse, error) {
+	return ts.response, nil
+}
 
-	if err != nil {
-		return resp, err
-	}
+func (ts *stubTokenSource) SaveAuthorizationResponse(state string, response *AuthorizationResponse) error {
+	ts.response = response
+	ts.saves++
+	return nil
+}
 
-	// save the cassette to disk
-	j, _ := json.Marshal(resp)
-	ioutil.WriteFile(filename+".resp", j, 0644)
+// newRouteClient returns a client that answers requests with the content
+// registered for the request path, or a 404 if the path is unknown.
+func newRouteClient(routes map[string]string) (*Client, *routeTransport) {
+	t := &routeTransport{routes: routes}
 
-	body, _ := ioutil.ReadAll(resp.Body)
-	ioutil.WriteFile(filename+".body", body, 0644)
-	resp.Body, _ = os.Open(filename + ".body")
+	c := NewClient(newStubTokenSource())
+	c.httpClient = &http.Client{Transport: t}
 
-	if err != nil {
-		j, _ = json.Marshal(err)
-		ioutil.WriteFile(filename+".err", j, 0644)
+	return c, t
+}
+
+type routeTransport struct {
+	http.Transport
+	routes   map[string]string
+	requests []*http.Request
+}
+
+func (t *routeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Request:    req,
+	}
+
+	content, ok := t.routes[req.URL.Path]
+	if !ok {
+		resp.StatusCode = http.StatusNotFound
+		content = `{"message":"Record Not Found","errors":[]}`
 	}
 
-	return resp, err
+	resp.Status = http.StatusText(resp.StatusCode)
+	resp.Body = ioutil.NopCloser(strings.NewReader(content))
+
+	return resp, nil
 }
 
 /*********************************************************/
 
-func newStoreRequestClient() *Client {
-	c := NewClient("")
+func TestClient(t *testing.T) {
+	ts := newStubTokenSource()
+	c := NewClient(ts)
+	if c.tokenSource != ts {
+		t.Errorf("token source not set correctly")
+	}
+
+	if c.httpClient != http.DefaultClient || c.baseURL != basePath || c.userAgent != defaultUserAgent || c.rateLimit != &RateLimiting {
+		t.Errorf("incorrect defaults, got %+v", c)
+	}
+
+	httpClient := &http.Client{}
+	c = NewClient(ts, WithHTTPClient(httpClient))
+	if c.httpClient != httpClient {
+		t.Errorf("http client not set correctly")
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	rateLimit := &RateLimit{}
+	logger := &recordingLogger{}
+
+	c := NewClient(newStubTokenSource(),
+		WithBaseURL("http://localhost:8080/api/v3/"),
+		WithUserAgent("my-app"),
+		WithRateLimiter(rateLimit),
+		WithLogger(logger),
+		WithCredentials(1, "secret"),
+	)
+
+	transport := &routeTransport{routes: map[string]string{"/api/v3/athletes/1/koms": `[]`}}
+	c.httpClient = &http.Client{Transport: transport}
+
+	if _, err := NewAthletesService(c).ListKOMs(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	req := transport.requests[0]
+	if req.URL.Host != "localhost:8080" || req.URL.Path != "/api/v3/athletes/1/koms" {
+		t.Errorf("base url not used, got %v", req.URL)
+	}
+
+	if ua := req.Header.Get("User-Agent"); ua != "my-app" {
+		t.Errorf("incorrect user agent, got %v", ua)
+	}
+
+	if len(logger.lines) != 1 {
+		t.Errorf("logger not used, got %v", logger.lines)
+	}
+
+	if id, secret := c.credentials(); id != 1 || secret != "secret" {
+		t.Errorf("incorrect credentials, got %v %v", id, secret)
+	}
+
+	handler := func(resp *http.Response) error { return nil }
+	if c := NewClient(nil, WithErrorHandler(handler)); c.errorHandler == nil {
+		t.Error("error handler not set")
+	}
+}
+
+func TestClientOptionsSnapshot(t *testing.T) {
+	options := NewClient(newStubTokenSource()).ClientOptionsSnapshot()
+
+	expected := ClientOptions{BaseURL: basePath, UserAgent: defaultUserAgent, SharedLimiter: true, Subscribed: true, RefreshMargin: TokenRefreshMargin}
+	if options != expected {
+		t.Errorf("experimental subsystems should be off by default, got %+v", options)
+	}
+
+	options = NewClient(newStubTokenSource(),
+		WithRateLimiter(&RateLimit{}),
+		WithRawJSON(),
+		WithRequestDelay(time.Second, 100*time.Millisecond),
+		WithMetricLabels("app", []byte("key")),
+		WithCredentials(1, "secret"),
+	).ClientOptionsSnapshot()
+
+	if options.SharedLimiter || !options.RawJSON || options.RequestDelay != time.Second || options.RequestJitter != 100*time.Millisecond ||
+		options.MetricTenant != "app" || !options.MetricAthlete || !options.Credentials {
+		t.Errorf("incorrect options, got %+v", options)
+	}
+}
+
+func TestClientWith(t *testing.T) {
+	var dump bytes.Buffer
+	limit := &RateLimit{}
+	client := NewClient(newStubTokenSource(), WithRateLimiter(limit), WithRequestDelay(time.Second, 0), WithDump(&dump), WithCoalescing())
+
+	handler := func(resp *http.Response) error { return errors.New("batch error") }
+	batch := client.With(WithErrorHandler(handler), WithTimeout(time.Minute))
+
+	if batch == client || batch.tokenSource != client.tokenSource || batch.rateLimit != limit || batch.pacer != client.pacer {
+		t.Error("the copy should share the token source, rate limits and request delay")
+	}
+
+	if batch.errorHandler == nil || batch.timeout != time.Minute || client.errorHandler != nil || client.timeout != 0 {
+		t.Error("the options should only apply to the copy")
+	}
+
+	batch.Dump(nil)
+	if !client.dump.enabled() {
+		t.Error("dumping of the copy should not change the client")
+	}
+
+	if batch.flights == nil || batch.flights == client.flights {
+		t.Error("the copy should coalesce its own calls")
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	ClientId, ClientSecret = 1, "global"
+	defer func() { ClientId, ClientSecret = 0, "" }()
+
+	ts := newStubTokenSource()
+	ts.response.ExpiresAt = 0
+
+	c := NewClient(ts)
 	c.httpClient = &http.Client{Transport: &storeRequestTransport{}}
+	c.refreshToken()
 
-	return c
+	transport := c.httpClient.Transport.(*storeRequestTransport)
+	transport.request.ParseForm()
+	if transport.request.PostForm.Get("client_id") != "1" || transport.request.PostForm.Get("client_secret") != "global" {
+		t.Errorf("should fall back to the package credentials, got %v", transport.request.PostForm)
+	}
+
+	c.Credentials(2, "secret").refreshToken()
+	transport.request.ParseForm()
+	if transport.request.PostForm.Get("client_id") != "2" || transport.request.PostForm.Get("client_secret") != "secret" {
+		t.Errorf("incorrect credentials sent, got %v", transport.request.PostForm)
+	}
 }
 
-type storeRequestTransport struct {
-	http.Transport
-	request *http.Request
+func TestClientRefreshTokenRotated(t *testing.T) {
+	rotated := &AuthorizationResponse{AccessToken: "new", RefreshToken: "rotated", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	for name, test := range map[string]struct {
+		reloaded     *AuthorizationResponse // saved by another process during the refresh, if not nil
+		refreshes    int
+		expectedAuth string
+		revoked      bool
+	}{
+		"stored token":   {reloaded: rotated, refreshes: 1, expectedAuth: "Bearer new"},
+		"expired stored": {reloaded: &AuthorizationResponse{AccessToken: "new", RefreshToken: "rotated"}, refreshes: 2, expectedAuth: "Bearer refreshed"},
+		"revoked":        {refreshes: 1, revoked: true},
+	} {
+		ts := newStubTokenSource()
+		ts.response.ExpiresAt = 0
+
+		var refreshes int
+		var auth string
+		client := NewClient(ts)
+		client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			status, body := http.StatusOK, `{"id":1}`
+			switch req.URL.Path {
+			case "/api/v3/oauth/token":
+				refreshes++
+				req.ParseForm()
+				if req.PostForm.Get("refresh_token") == "rotated" {
+					body = `{"access_token":"refreshed","refresh_token":"next","expires_at":` + fmt.Sprint(time.Now().Add(time.Hour).Unix()) + `}`
+					break
+				}
+
+				if test.reloaded != nil {
+					ts.response = test.reloaded
+				}
+				status, body = http.StatusBadRequest, `{"message":"Bad Request","errors":[{"resource":"RefreshToken","field":"refresh_token","code":"invalid"}]}`
+			default:
+				auth = req.Header.Get("Authorization")
+			}
+
+			return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+		})}
+
+		_, err := NewCurrentAthleteService(client).Get().Do()
+		if test.revoked {
+			if !errors.Is(err, ErrTokenRevoked) {
+				t.Errorf("%s: should be revoked, got %v", name, err)
+			}
+
+			var e *Error
+			if !errors.As(err, &e) || e.Errors[0].Resource != "RefreshToken" {
+				t.Errorf("%s: should keep the error of Strava, got %v", name, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: service error: %v", name, err)
+		}
+
+		if refreshes != test.refreshes || auth != test.expectedAuth {
+			t.Errorf("%s: incorrect refreshes %d or authorization %q", name, refreshes, auth)
+		}
+	}
 }
 
-func (t *storeRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	t.request = req
+func TestClientRefreshMargin(t *testing.T) {
+	// as sent by Strava, in seconds
+	response := &AuthorizationResponse{ExpiresAt: 1568775134}
+	if expiresAt := response.ExpiresAtTime(); !expiresAt.Equal(time.Date(2019, 9, 18, 2, 52, 14, 0, time.UTC)) {
+		t.Errorf("incorrect expiry, got %v", expiresAt)
+	}
 
-	return nil, errors.New("for testing, no request made")
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athlete":     `{"id":1}`,
+		"/api/v3/oauth/token": `{"access_token":"refreshed","refresh_token":"refresh"}`,
+	})
+
+	ts := client.tokenSource.(*stubTokenSource)
+	ts.response.ExpiresAt = time.Now().Add(30 * time.Second).Unix()
+
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil || ts.saves != 0 {
+		t.Fatalf("token should not be refreshed yet, got %v and %d saves", err, ts.saves)
+	}
+
+	WithRefreshMargin(time.Minute)(client)
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil || ts.saves != 1 {
+		t.Errorf("token should be refreshed within the margin, got %v and %d saves", err, ts.saves)
+	}
+
+	if options := client.ClientOptionsSnapshot(); options.RefreshMargin != time.Minute {
+		t.Errorf("incorrect options, got %+v", options)
+	}
 }
 
-/*********************************************************/
+func TestClientReauthorizationRequired(t *testing.T) {
+	ts := newStubTokenSource()
+	ts.response.ExpiresAt = 0
+
+	var refreshes int
+	client := NewClient(ts)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status, body := http.StatusOK, `{"id":1}`
+		if req.URL.Path == "/api/v3/oauth/token" {
+			refreshes++
+			req.ParseForm()
+			status, body = http.StatusBadRequest, `{"error":"invalid_grant"}`
+			if req.PostForm.Get("refresh_token") == "reconnected" {
+				status, body = http.StatusOK, `{"access_token":"new","refresh_token":"next","expires_at":`+fmt.Sprint(time.Now().Add(time.Hour).Unix())+`}`
+			}
+		}
 
-func TestClient(t *testing.T) {
-	c := NewClient("token")
-	if c.token != "token" {
-		t.Errorf("token not set correctly")
+		return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})}
+
+	for i := 0; i < 2; i++ {
+		_, err := NewCurrentAthleteService(client).Get().Do()
+		if !errors.Is(err, ErrReauthorizationRequired) || !errors.Is(err, ErrTokenRevoked) {
+			t.Fatalf("should require reauthorization, got %v", err)
+		}
 	}
 
-	httpClient := &http.Client{}
-	c = NewClient("token", httpClient)
-	if c.httpClient != httpClient {
-		t.Errorf("http client not set correctly")
+	if refreshes != 1 {
+		t.Errorf("the rejected refresh token should not be sent again, got %d refreshes", refreshes)
+	}
+
+	// the athlete connected the application again
+	ts.response = &AuthorizationResponse{AccessToken: "old", RefreshToken: "reconnected"}
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil || refreshes != 2 {
+		t.Errorf("new tokens should be refreshed, got %v and %d refreshes", err, refreshes)
+	}
+}
+
+func TestClientOnTokenRefreshed(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/oauth/token": `{"access_token":"new","refresh_token":"refresh"}`,
+	})
+
+	var calls []*AuthorizationResponse
+	client.OnTokenRefreshed(func(previous, refreshed *AuthorizationResponse) {
+		calls = append(calls, previous, refreshed)
+	})
+
+	ts := client.tokenSource.(*stubTokenSource)
+	if _, err := client.refreshToken(); err != nil {
+		t.Fatalf("refresh error: %v", err)
+	}
+
+	if len(calls) != 2 || calls[0].AccessToken != "token" || calls[1].AccessToken != "new" || ts.saves != 1 {
+		t.Fatalf("should be called with the previous and saved tokens, got %v", calls)
+	}
+
+	// failed refreshes are not reported
+	client.baseURL = "http://localhost/missing"
+	if _, err := client.refreshToken(); err == nil || len(calls) != 2 {
+		t.Errorf("should not be called for failed refreshes, got %v, %v", err, calls)
 	}
 }
 
+// roundTripFunc adapts a function to an http.RoundTripper.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 func TestRun(t *testing.T) {
 	var err error
 	c := newStoreRequestClient()
@@ -161,7 +445,8 @@ func TestCheckResponseForErrors(t *testing.T) {
 		t.Error("should have returned error")
 	}
 
-	if se, ok := err.(Error); ok {
+	var se Error
+	if errors.As(err, &se) {
 		if len(se.Errors) == 0 {
 			t.Error("Detailed errors not parsed")
 		}
@@ -196,7 +481,6 @@ func TestCheckResponseForErrorsWithErrorHandler(t *testing.T) {
 
 func TestTransport(t *testing.T) {
 	c := newStoreRequestClient()
-	c.token = "token"
 	NewClubsService(c).Get(122).Do()
 
 	transport := c.httpClient.Transport.(*storeRequestTransport)
@@ -204,3 +488,206 @@ func TestTransport(t *testing.T) {
 		t.Errorf("request header incorrect, got %v", h)
 	}
 }
+
+func TestClientOnError(t *testing.T) {
+	clientErr := errors.New("client error")
+	callErr := errors.New("call error")
+
+	client, _ := newRouteClient(map[string]string{})
+
+	// default handler
+	_, err := NewClubsService(client).Get(1).Do()
+	if !errors.As(err, &Error{}) {
+		t.Errorf("should return strava error, got %v", err)
+	}
+
+	// client handler
+	client.OnError(func(resp *http.Response) error { return clientErr })
+
+	_, err = NewClubsService(client).Get(1).Do()
+	if !errors.Is(err, clientErr) {
+		t.Errorf("should use client error handler, got %v", err)
+	}
+
+	_, err = NewActivityStreamsService(client).Get(1, []StreamType{StreamTypes.Time}).Do()
+	if !errors.Is(err, clientErr) {
+		t.Errorf("should use client error handler, got %v", err)
+	}
+
+	// call handler
+	_, err = NewClubsService(client).Get(1).
+		OnError(func(resp *http.Response) error { return callErr }).
+		Do()
+	if !errors.Is(err, callErr) {
+		t.Errorf("should use call error handler, got %v", err)
+	}
+
+	_, err = NewActivityStreamsService(client).Get(1, []StreamType{StreamTypes.Time}).
+		OnError(func(resp *http.Response) error { return callErr }).
+		Do()
+	if !errors.Is(err, callErr) {
+		t.Errorf("should use call error handler, got %v", err)
+	}
+
+	// back to default
+	client.OnError(nil)
+
+	_, err = NewClubsService(client).Get(1).Do()
+	if !errors.As(err, &Error{}) {
+		t.Errorf("should return strava error, got %v", err)
+	}
+}
+
+func TestErrorHandlerRequest(t *testing.T) {
+	client := NewStubResponseClient(`{"message":"bad","errors":[]}`, http.StatusNotFound)
+	client.tokenSource = newStubTokenSource()
+
+	var request *http.Request
+	client.OnError(func(resp *http.Response) error {
+		request = resp.Request
+		return errors.New("not found")
+	})
+
+	NewClubsService(client).Get(123).Do()
+
+	if request == nil {
+		t.Fatal("request should be passed to the error handler")
+	}
+
+	if request.Method != "GET" || request.URL.Path != "/api/v3/clubs/123" {
+		t.Errorf("incorrect request, got %v %v", request.Method, request.URL.Path)
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/activities/123": `bad json`,
+	})
+
+	_, err := NewActivitiesService(client).Get(123).Do()
+	if err == nil || !strings.HasPrefix(err.Error(), "strava: activities.get id=123: ") {
+		t.Errorf("error should contain the operation, got %v", err)
+	}
+
+	_, err = NewClubsService(client).Get(5).Do()
+	if err == nil || !strings.HasPrefix(err.Error(), "strava: clubs.get id=5: ") {
+		t.Errorf("error should contain the operation, got %v", err)
+	}
+
+	var e Error
+	if !errors.As(err, &e) || e.Message != "Record Not Found" {
+		t.Errorf("strava error should be kept in the chain, got %v", err)
+	}
+
+	if err := wrapError(nil, "activities.delete id=%d", 1); err != nil {
+		t.Errorf("nil error should not be wrapped, got %v", err)
+	}
+}
+
+func TestClientDo(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/athlete/zones": `{"heart_rate":{"custom_zones":false,"zones":[{"min":0,"max":120}]}}`,
+	})
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	var zones struct {
+		HeartRate struct {
+			Zones []struct{ Min, Max int } `json:"zones"`
+		} `json:"heart_rate"`
+	}
+
+	err := client.Do(ctx, "GET", "/athlete/zones", map[string]interface{}{"page": 1}, &zones)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(zones.HeartRate.Zones) != 1 || zones.HeartRate.Zones[0].Max != 120 {
+		t.Errorf("response not decoded, got %+v", zones)
+	}
+
+	req := transport.requests[0]
+	if req.URL.Query().Get("page") != "1" || req.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("incorrect request, got %v", req.URL)
+	}
+
+	if req.Context().Value(key{}) != "value" {
+		t.Error("request should use the context")
+	}
+
+	if err := client.Do(ctx, "GET", "/athlete/zones", nil, nil); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+
+	err = client.Do(ctx, "GET", "/athlete/unknown", nil, &zones)
+	if !errors.Is(err, ErrNotFound) || !strings.HasPrefix(err.Error(), "strava: GET /athlete/unknown: ") {
+		t.Errorf("should return not found, got %v", err)
+	}
+}
+
+func TestCall(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athlete/zones": `{"heart_rate":{"custom_zones":true}}`,
+	})
+
+	type athleteZones struct {
+		HeartRate struct {
+			CustomZones bool `json:"custom_zones"`
+		} `json:"heart_rate"`
+	}
+
+	zones, err := Call[athleteZones](context.Background(), client, "GET", "/athlete/zones", nil)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !zones.HeartRate.CustomZones {
+		t.Errorf("response not decoded, got %+v", zones)
+	}
+
+	activities, err := Call[[]*ActivitySummary](context.Background(), client, "GET", "/athlete/unknown", nil)
+	if activities != nil || !errors.Is(err, ErrNotFound) {
+		t.Errorf("should return not found, got %v %v", activities, err)
+	}
+}
+
+func TestClientStreamedDecoding(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athlete/activities": `[{"id":1},{"id":2}]`,
+		"/api/v3/athlete/zones":      `{"heart_rate":`,
+	})
+
+	var resp Response
+	req, _ := client.newRequest(context.Background(), "GET", "/athlete/activities", nil)
+
+	var activities []*ActivitySummary
+	data, err := client.execute(req, nil, &resp, &activities)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if data != nil || len(activities) != 2 || activities[1].Id != 2 {
+		t.Errorf("response should be decoded while read, got %s %v", data, activities)
+	}
+
+	if resp.BodyLength != int64(len(`[{"id":1},{"id":2}]`)) {
+		t.Errorf("whole body should be read, got %v", resp.BodyLength)
+	}
+
+	// the data is kept for raw json
+	WithRawJSON()(client)
+
+	req, _ = client.newRequest(context.Background(), "GET", "/athlete/activities", nil)
+	data, err = client.execute(req, nil, nil, &activities)
+	if err != nil || string(data) != `[{"id":1},{"id":2}]` || string(activities[0].Raw()) != `{"id":1}` {
+		t.Errorf("response should be read for raw json, got %s %v", data, err)
+	}
+
+	// invalid json
+	client.rawJSON = false
+
+	if err := client.Do(context.Background(), "GET", "/athlete/zones", nil, &activities); err == nil {
+		t.Error("invalid json should return an error")
+	}
+}
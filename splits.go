@@ -1,9 +1,58 @@
 package strava
 
 type Split struct {
-	Distance            float64 `json:"distance"`
-	ElapsedTime         int     `json:"elapsed_time"`
-	ElevationDifference float64 `json:"elevation_difference"`
-	MovingTime          int     `json:"moving_time"`
-	Split               int     `json:"split"`
+	Distance            Distance  `json:"distance"`
+	ElapsedTime         int       `json:"elapsed_time"`
+	ElevationDifference Elevation `json:"elevation_difference"`
+	MovingTime          int       `json:"moving_time"`
+	Split               int       `json:"split"`
+}
+
+// Splits generates splits of the given distance from the streams, for activities without them or
+// for custom distances. Use strava.Kilometer and strava.Mile for the equivalent of SplitsMetric and
+// SplitsStandard. Splits end at the first sample past their distance, like the ones Strava shows,
+// the last split is the remaining distance. Moving time leaves out pauses and gaps longer than DefaultGapThreshold.
+// Requires the Time and Distance streams, the Elevation stream is used if available.
+func (s *StreamSet) Splits(distance Distance) []*Split {
+	if s.Time == nil || s.Distance == nil || distance <= 0 {
+		return nil
+	}
+
+	n := s.len()
+	if n < 2 {
+		return nil
+	}
+
+	moving := make([]bool, n)
+	s.eachMoving(0, func(i int) {
+		moving[i] = true
+	})
+
+	var splits []*Split
+	start, movingTime := 0, 0
+	for i := 1; i < n; i++ {
+		if moving[i] {
+			movingTime += s.Time.Data[i] - s.Time.Data[i-1]
+		}
+
+		if s.Distance.Data[i] < float64(distance)*float64(len(splits)+1) && i < n-1 {
+			continue
+		}
+
+		split := &Split{
+			Distance:    Distance(s.Distance.Data[i] - s.Distance.Data[start]),
+			ElapsedTime: s.Time.Data[i] - s.Time.Data[start],
+			MovingTime:  movingTime,
+			Split:       len(splits) + 1,
+		}
+
+		if s.Elevation != nil && i < len(s.Elevation.Data) {
+			split.ElevationDifference = Elevation(s.Elevation.Data[i] - s.Elevation.Data[start])
+		}
+
+		splits = append(splits, split)
+		start, movingTime = i, 0
+	}
+
+	return splits
 }
@@ -0,0 +1,41 @@
+package strava
+
+import (
+	"testing"
+)
+
+func TestStreamSetSplits(t *testing.T) {
+	s := &StreamSet{
+		Time:      &IntegerStream{Data: []int{0, 10, 20, 30, 40, 50, 60}},
+		Distance:  &DecimalStream{Data: []float64{0, 400, 800, 1200, 1200, 1700, 2100}},
+		Elevation: &DecimalStream{Data: []float64{10, 12, 14, 20, 20, 15, 16}},
+		Moving:    &BooleanStream{Data: []bool{false, true, true, true, false, true, true}},
+	}
+
+	splits := s.Splits(Kilometer)
+	if len(splits) != 2 {
+		t.Fatalf("incorrect number of splits, got %v", len(splits))
+	}
+
+	expected := []Split{
+		{Distance: 1200, ElapsedTime: 30, MovingTime: 30, ElevationDifference: 10, Split: 1},
+		{Distance: 900, ElapsedTime: 30, MovingTime: 20, ElevationDifference: -4, Split: 2},
+	}
+
+	for i, e := range expected {
+		if *splits[i] != e {
+			t.Errorf("incorrect split %d, got %+v", i, *splits[i])
+		}
+	}
+
+	// custom distance without elevation
+	s.Elevation = nil
+	splits = s.Splits(3 * Kilometer)
+	if len(splits) != 1 || splits[0].Distance != 2100 || splits[0].MovingTime != 50 || splits[0].ElevationDifference != 0 {
+		t.Errorf("incorrect splits, got %+v", splits)
+	}
+
+	if (&StreamSet{}).Splits(Kilometer) != nil {
+		t.Error("should require time and distance streams")
+	}
+}
@@ -0,0 +1,37 @@
+// Package stravaecho exposes the OAuth callback and webhook endpoints of the strava package as echo handlers:
+//
+//	e.GET("/strava/authorize", stravaecho.Callback(authenticator, saveTokens, showError))
+//	e.Any("/strava/webhook", stravaecho.Webhook("prod", secret, dispatcher.Dispatch, nil))
+//
+// It is a module of its own, so users of other frameworks don't depend on echo.
+package stravaecho
+
+import (
+	"github.com/caselongo/strava-go"
+	"github.com/labstack/echo/v4"
+)
+
+// Callback returns a handler completing the token exchange of the request Strava redirects the user back with,
+// see strava.OAuthAuthenticator.AuthorizeFromRequest, and calling success with the tokens to save,
+// or failure, e.g. with strava.OAuthAuthorizationDeniedErr if the user denied the authorization.
+func Callback(
+	auth *strava.OAuthAuthenticator,
+	success func(c echo.Context, response *strava.AuthorizationResponse) error,
+	failure func(c echo.Context, err error) error) echo.HandlerFunc {
+
+	return func(c echo.Context) error {
+		response, err := auth.AuthorizeFromRequest(c.Request())
+		if err != nil {
+			return failure(c, err)
+		}
+
+		return success(c, response)
+	}
+}
+
+// Webhook returns a handler answering the validation of the push subscriptions of the environment,
+// see strava.PushSubscriptionHandler, and passing the posted events to handle, see strava.WebhookHandler,
+// received at the time of the clock, nil for the system clock. Register it for both GET and POST requests.
+func Webhook(environment, secret string, handle func(event *strava.WebhookEvent) error, clock strava.Clock) echo.HandlerFunc {
+	return echo.WrapHandler(strava.PushSubscriptionHandler(environment, secret, strava.WebhookHandler(handle, clock)))
+}
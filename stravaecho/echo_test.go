@@ -0,0 +1,53 @@
+package stravaecho
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/caselongo/strava-go"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCallback(t *testing.T) {
+	var failure error
+	e := echo.New()
+	e.GET("/strava/authorize", Callback(&strava.OAuthAuthenticator{},
+		func(c echo.Context, response *strava.AuthorizationResponse) error {
+			t.Error("authorization should fail")
+			return nil
+		},
+		func(c echo.Context, err error) error {
+			failure = err
+			return c.NoContent(http.StatusForbidden)
+		}))
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/strava/authorize?error=access_denied", nil))
+	if !errors.Is(failure, strava.OAuthAuthorizationDeniedErr) || w.Code != http.StatusForbidden {
+		t.Errorf("denied authorization should fail, got %d and %v", w.Code, failure)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var events []*strava.WebhookEvent
+	e := echo.New()
+	e.Any("/strava/webhook", Webhook("prod", "secret", func(event *strava.WebhookEvent) error {
+		events = append(events, event)
+		return nil
+	}, nil))
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/strava/webhook?hub.mode=subscribe&hub.challenge=abc&hub.verify_token=prod:secret", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "abc") {
+		t.Errorf("validation should be answered, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("POST", "/strava/webhook", strings.NewReader(`{"object_id":1}`)))
+	if w.Code != http.StatusOK || len(events) != 1 || events[0].ObjectId != 1 {
+		t.Errorf("event should be handled, got %d and %v", w.Code, events)
+	}
+}
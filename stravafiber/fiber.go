@@ -0,0 +1,43 @@
+// Package stravafiber exposes the OAuth callback and webhook endpoints of the strava package as fiber handlers:
+//
+//	app.Get("/strava/authorize", stravafiber.Callback(authenticator, saveTokens, showError))
+//	app.All("/strava/webhook", stravafiber.Webhook("prod", secret, dispatcher.Dispatch, nil))
+//
+// It is a module of its own, so users of other frameworks don't depend on fiber.
+package stravafiber
+
+import (
+	"github.com/caselongo/strava-go"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Callback returns a handler completing the token exchange of the request Strava redirects the user back with,
+// see strava.OAuthAuthenticator.AuthorizeFromRequest, and calling success with the tokens to save,
+// or failure, e.g. with strava.OAuthAuthorizationDeniedErr if the user denied the authorization.
+func Callback(
+	auth *strava.OAuthAuthenticator,
+	success func(c *fiber.Ctx, response *strava.AuthorizationResponse) error,
+	failure func(c *fiber.Ctx, err error) error) fiber.Handler {
+
+	return func(c *fiber.Ctx) error {
+		req, err := adaptor.ConvertRequest(c, false)
+		if err != nil {
+			return failure(c, err)
+		}
+
+		response, err := auth.AuthorizeFromRequest(req)
+		if err != nil {
+			return failure(c, err)
+		}
+
+		return success(c, response)
+	}
+}
+
+// Webhook returns a handler answering the validation of the push subscriptions of the environment,
+// see strava.PushSubscriptionHandler, and passing the posted events to handle, see strava.WebhookHandler,
+// received at the time of the clock, nil for the system clock. Register it for both GET and POST requests.
+func Webhook(environment, secret string, handle func(event *strava.WebhookEvent) error, clock strava.Clock) fiber.Handler {
+	return adaptor.HTTPHandler(strava.PushSubscriptionHandler(environment, secret, strava.WebhookHandler(handle, clock)))
+}
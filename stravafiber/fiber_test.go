@@ -0,0 +1,64 @@
+package stravafiber
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/caselongo/strava-go"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCallback(t *testing.T) {
+	var failure error
+	app := fiber.New()
+	app.Get("/strava/authorize", Callback(&strava.OAuthAuthenticator{},
+		func(c *fiber.Ctx, response *strava.AuthorizationResponse) error {
+			t.Error("authorization should fail")
+			return nil
+		},
+		func(c *fiber.Ctx, err error) error {
+			failure = err
+			return c.SendStatus(http.StatusForbidden)
+		}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/strava/authorize?error=access_denied", nil))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+
+	if !errors.Is(failure, strava.OAuthAuthorizationDeniedErr) || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("denied authorization should fail, got %d and %v", resp.StatusCode, failure)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var events []*strava.WebhookEvent
+	app := fiber.New()
+	app.All("/strava/webhook", Webhook("prod", "secret", func(event *strava.WebhookEvent) error {
+		events = append(events, event)
+		return nil
+	}, nil))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/strava/webhook?hub.mode=subscribe&hub.challenge=abc&hub.verify_token=prod:secret", nil))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "abc") {
+		t.Errorf("validation should be answered, got %d %s", resp.StatusCode, body)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/strava/webhook", strings.NewReader(`{"object_id":1}`)))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || len(events) != 1 || events[0].ObjectId != 1 {
+		t.Errorf("event should be handled, got %d and %v", resp.StatusCode, events)
+	}
+}
@@ -0,0 +1,38 @@
+// Package stravagin exposes the OAuth callback and webhook endpoints of the strava package as gin handlers:
+//
+//	router.GET("/strava/authorize", stravagin.Callback(authenticator, saveTokens, showError))
+//	router.Any("/strava/webhook", stravagin.Webhook("prod", secret, dispatcher.Dispatch, nil))
+//
+// It is a module of its own, so users of other frameworks don't depend on gin.
+package stravagin
+
+import (
+	"github.com/caselongo/strava-go"
+	"github.com/gin-gonic/gin"
+)
+
+// Callback returns a handler completing the token exchange of the request Strava redirects the user back with,
+// see strava.OAuthAuthenticator.AuthorizeFromRequest, and calling success with the tokens to save,
+// or failure, e.g. with strava.OAuthAuthorizationDeniedErr if the user denied the authorization.
+func Callback(
+	auth *strava.OAuthAuthenticator,
+	success func(c *gin.Context, response *strava.AuthorizationResponse),
+	failure func(c *gin.Context, err error)) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+		response, err := auth.AuthorizeFromRequest(c.Request)
+		if err != nil {
+			failure(c, err)
+			return
+		}
+
+		success(c, response)
+	}
+}
+
+// Webhook returns a handler answering the validation of the push subscriptions of the environment,
+// see strava.PushSubscriptionHandler, and passing the posted events to handle, see strava.WebhookHandler,
+// received at the time of the clock, nil for the system clock. Register it for both GET and POST requests.
+func Webhook(environment, secret string, handle func(event *strava.WebhookEvent) error, clock strava.Clock) gin.HandlerFunc {
+	return gin.WrapH(strava.PushSubscriptionHandler(environment, secret, strava.WebhookHandler(handle, clock)))
+}
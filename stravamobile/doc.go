@@ -0,0 +1,18 @@
+// Package stravamobile is a simplified facade of the strava package that binds with gomobile,
+// so iOS and Android apps reuse its token handling and api calls. It only uses types gomobile
+// supports: structs of plain fields, lists with Len and Get instead of slices, times as unix
+// seconds and comma separated scopes:
+//
+//	gomobile bind -target=android github.com/caselongo/strava-go/stravamobile
+//
+// Apps keep the Tokens on the device, in the keychain or keystore, and save them again after
+// calls, as expired tokens are refreshed:
+//
+//	config := stravamobile.NewConfig(clientId, clientSecret, "myapp://strava")
+//	url := config.AuthorizationURL("state", "read,activity:read_all")
+//	tokens, err := config.Exchange(code, scope) // the code and scope parameters of the redirect
+//
+//	client := stravamobile.NewClient(config, tokens)
+//	activities, err := client.ListActivities(1, 30)
+//	tokens = client.Tokens()
+package stravamobile
@@ -0,0 +1,110 @@
+package stravamobile
+
+import (
+	strava "github.com/caselongo/strava-go"
+)
+
+// An Athlete is the profile of an athlete.
+type Athlete struct {
+	Id                    int64
+	FirstName             string
+	LastName              string
+	Profile               string // url of a 124x124 pixel picture
+	City                  string
+	State                 string
+	Country               string
+	Summit                bool
+	MeasurementPreference string  // "feet" or "meters"
+	Weight                float64 // kilograms
+	FTP                   int
+}
+
+func newAthlete(athlete *strava.AthleteDetailed) *Athlete {
+	return &Athlete{
+		Id:                    athlete.Id,
+		FirstName:             athlete.FirstName,
+		LastName:              athlete.LastName,
+		Profile:               athlete.Profile,
+		City:                  athlete.City,
+		State:                 athlete.State,
+		Country:               athlete.Country,
+		Summit:                athlete.Summit,
+		MeasurementPreference: athlete.MeasurementPreference,
+		Weight:                athlete.Weight,
+		FTP:                   athlete.FTP,
+	}
+}
+
+// An Activity is the summary of an activity.
+type Activity struct {
+	Id                 int64
+	Name               string
+	SportType          string
+	StartDate          int64 // unix seconds
+	TimeZone           string
+	Distance           float64 // meters
+	MovingTime         int     // seconds
+	ElapsedTime        int     // seconds
+	TotalElevationGain float64 // meters
+	AverageSpeed       float64 // meters per second
+	SummaryPolyline    string
+	KudosCount         int
+	Commute            bool
+	Trainer            bool
+	Private            bool
+}
+
+func newActivity(activity *strava.ActivitySummary) *Activity {
+	return &Activity{
+		Id:                 activity.Id,
+		Name:               activity.Name,
+		SportType:          string(activity.SportType),
+		StartDate:          activity.StartDate.Unix(),
+		TimeZone:           activity.TimeZone,
+		Distance:           float64(activity.Distance),
+		MovingTime:         activity.MovingTime,
+		ElapsedTime:        activity.ElapsedTime,
+		TotalElevationGain: float64(activity.TotalElevationGain),
+		AverageSpeed:       float64(activity.AverageSpeed),
+		SummaryPolyline:    string(activity.Map.SummaryPolyline),
+		KudosCount:         activity.KudosCount,
+		Commute:            activity.Commute,
+		Trainer:            activity.Trainer,
+		Private:            activity.Private,
+	}
+}
+
+// An ActivityList is a list of activities, gomobile doesn't bind slices of structs.
+type ActivityList struct {
+	activities []*Activity
+}
+
+func (l *ActivityList) Len() int {
+	return len(l.activities)
+}
+
+// Get returns the activity at index i, nil if i is out of range.
+func (l *ActivityList) Get(i int) *Activity {
+	if i < 0 || i >= len(l.activities) {
+		return nil
+	}
+
+	return l.activities[i]
+}
+
+// An Upload is the processing of an uploaded file.
+type Upload struct {
+	Id         int64
+	ActivityId int64 // 0 until the activity is created
+	Status     string
+	Error      string
+}
+
+func newUpload(upload *strava.UploadSummary) *Upload {
+	return &Upload{
+		Id:         upload.Id,
+		ActivityId: upload.ActivityId,
+		Status:     upload.Status,
+		Error:      upload.Error,
+	}
+}
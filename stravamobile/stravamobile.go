@@ -0,0 +1,234 @@
+package stravamobile
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// A Config holds the credentials of the Strava application, the redirect uri of the app
+// and optionally the api to use.
+type Config struct {
+	ClientId      int
+	ClientSecret  string
+	RedirectURI   string
+	BaseURL       string // of the api, e.g. a mock server, https://www.strava.com/api/v3 if empty
+	TimeoutMillis int64  // of each call, the default of the strava package if 0
+}
+
+// NewConfig returns the config of the application.
+func NewConfig(clientId int, clientSecret, redirectURI string) *Config {
+	return &Config{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		RedirectURI:  redirectURI,
+	}
+}
+
+// authenticator returns the authenticator of the application, saving the tokens in the token source.
+func (c *Config) authenticator(tokens *tokenSource) *strava.OAuthAuthenticator {
+	auth, _ := strava.NewOAuthAuthenticator(tokens, c.RedirectURI)
+	auth.SetCredentials(c.ClientId, c.ClientSecret)
+	if c.BaseURL != "" {
+		auth.SetBaseURL(c.BaseURL)
+	}
+
+	return auth
+}
+
+// AuthorizationURL returns the url to open for the user to authorize the app, requesting
+// the comma separated scopes, e.g. "read,activity:read_all".
+func (c *Config) AuthorizationURL(state, scopes string) string {
+	return c.authenticator(nil).AuthorizationURL(state, strava.ParseScopes(scopes), false)
+}
+
+// Exchange exchanges the code of the redirect for the tokens. The scopes are the scope parameter
+// of the redirect, the ones the user granted.
+func (c *Config) Exchange(code, scopes string) (*Tokens, error) {
+	source := &tokenSource{}
+	response, err := c.authenticator(source).Authorize(context.Background(), code, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Scopes = strava.ParseScopes(scopes)
+	return newTokens(response), nil
+}
+
+/*********************************************************/
+
+// Tokens are the tokens of an athlete, to be kept on the device.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    int64  // in seconds since the epoch, as in strava.AuthorizationResponse
+	Scopes       string // comma separated
+	AthleteId    int64
+}
+
+func newTokens(response *strava.AuthorizationResponse) *Tokens {
+	tokens := &Tokens{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		ExpiresAt:    response.ExpiresAt,
+	}
+
+	var scopes []string
+	for _, scope := range response.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	tokens.Scopes = strings.Join(scopes, ",")
+
+	if response.Athlete != nil {
+		tokens.AthleteId = response.Athlete.Id
+	}
+
+	return tokens
+}
+
+// authorizationResponse returns the tokens as the strava package uses them.
+func (t *Tokens) authorizationResponse() *strava.AuthorizationResponse {
+	response := &strava.AuthorizationResponse{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		ExpiresAt:    t.ExpiresAt,
+		Scopes:       strava.ParseScopes(t.Scopes),
+	}
+
+	if t.AthleteId != 0 {
+		response.Athlete = &strava.AthleteDetailed{}
+		response.Athlete.Id = t.AthleteId
+	}
+
+	return response
+}
+
+// tokenSource keeps the tokens of a client in memory.
+type tokenSource struct {
+	lock     sync.Mutex
+	response *strava.AuthorizationResponse
+}
+
+func (s *tokenSource) GetAuthorizationResponse() (*strava.AuthorizationResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.response, nil
+}
+
+func (s *tokenSource) SaveAuthorizationResponse(state string, response *strava.AuthorizationResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// refreshes don't return the athlete
+	if response.Athlete == nil && s.response != nil {
+		response.Athlete = s.response.Athlete
+	}
+
+	s.response = response
+	return nil
+}
+
+/*********************************************************/
+
+// A Client calls the api with the tokens of an athlete.
+type Client struct {
+	client *strava.Client
+	tokens *tokenSource
+}
+
+// NewClient returns a client of the application calling the api with the tokens.
+func NewClient(config *Config, tokens *Tokens) *Client {
+	source := &tokenSource{response: tokens.authorizationResponse()}
+
+	options := []strava.Option{strava.WithCredentials(config.ClientId, config.ClientSecret)}
+	if config.BaseURL != "" {
+		options = append(options, strava.WithBaseURL(config.BaseURL))
+	}
+
+	if config.TimeoutMillis > 0 {
+		options = append(options, strava.WithTimeout(time.Duration(config.TimeoutMillis)*time.Millisecond))
+	}
+
+	return &Client{
+		client: strava.NewClient(source, options...),
+		tokens: source,
+	}
+}
+
+// Tokens returns the current tokens of the client, to save them on the device after calls
+// that refreshed them.
+func (c *Client) Tokens() *Tokens {
+	response, _ := c.tokens.GetAuthorizationResponse()
+	return newTokens(response)
+}
+
+// CurrentAthlete returns the athlete of the tokens.
+func (c *Client) CurrentAthlete() (*Athlete, error) {
+	athlete, err := strava.NewCurrentAthleteService(c.client).Get().Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return newAthlete(athlete), nil
+}
+
+// ListActivities returns a page, starting at 1, of the activities of the athlete, the latest first.
+func (c *Client) ListActivities(page, perPage int) (*ActivityList, error) {
+	activities, err := strava.NewCurrentAthleteService(c.client).ListActivities().Page(page).PerPage(perPage).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ActivityList{}
+	for _, activity := range activities {
+		list.activities = append(list.activities, newActivity(activity))
+	}
+
+	return list, nil
+}
+
+// GetActivity returns the activity.
+func (c *Client) GetActivity(id int64) (*Activity, error) {
+	activity, err := strava.NewActivitiesService(c.client).Get(id).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return newActivity(&activity.ActivitySummary), nil
+}
+
+// Upload uploads the file of an activity, of a data type such as "fit" or "gpx.gz", see strava.FileDataTypes.
+// The name is optional. Check the processing of the upload with GetUpload.
+func (c *Client) Upload(data []byte, filename, dataType, name string) (*Upload, error) {
+	call := strava.NewUploadsService(c.client).Create(strava.FileDataType(dataType), filename, bytes.NewReader(data))
+	if name != "" {
+		call.Name(name)
+	}
+
+	upload, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return newUpload(upload), nil
+}
+
+// GetUpload returns the status of an upload.
+func (c *Client) GetUpload(id int64) (*Upload, error) {
+	upload, err := strava.NewUploadsService(c.client).Get(id).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return newUpload(&upload.UploadSummary), nil
+}
+
+// Deauthorize revokes the tokens, e.g. when the user disconnects Strava from the app.
+func (c *Client) Deauthorize() error {
+	return strava.NewOAuthService(c.client).Deauthorize().Do()
+}
@@ -0,0 +1,98 @@
+package stravamobile
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		expiresAt := time.Now().Add(time.Hour).Unix()
+		if r.FormValue("grant_type") == "refresh_token" {
+			fmt.Fprintf(w, `{"access_token":"refreshed","refresh_token":"refresh2","expires_at":%d}`, expiresAt)
+			return
+		}
+
+		fmt.Fprintf(w, `{"access_token":"token","refresh_token":"refresh","expires_at":%d,"athlete":{"id":1}}`, expiresAt)
+	})
+	mux.HandleFunc("/api/v3/athlete", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"firstname":"Jane","weight":60.5}`)
+	})
+	mux.HandleFunc("/api/v3/athlete/activities", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer refreshed" {
+			t.Errorf("expired token should be refreshed, got %v", auth)
+		}
+
+		fmt.Fprint(w, `[{"id":10,"name":"Morning Run","sport_type":"Run","distance":5000,"start_date":"2024-01-02T07:00:00Z"},{"id":11}]`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestExchange(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	config := NewConfig(1, "secret", "myapp://strava")
+	config.BaseURL = server.URL + "/api/v3"
+
+	if u := config.AuthorizationURL("state", "read,activity:read_all"); !strings.Contains(u, "scope=read%2Cactivity%3Aread_all") {
+		t.Errorf("incorrect authorization url, got %v", u)
+	}
+
+	tokens, err := config.Exchange("code", "read,activity:read_all")
+	if err != nil {
+		t.Fatalf("exchange error: %v", err)
+	}
+
+	if tokens.AccessToken != "token" || tokens.AthleteId != 1 || tokens.Scopes != "read,activity:read_all" {
+		t.Errorf("incorrect tokens, got %+v", tokens)
+	}
+
+	if _, err := config.Exchange("", ""); err == nil {
+		t.Error("exchange without a code should fail")
+	}
+}
+
+func TestClient(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	config := NewConfig(1, "secret", "myapp://strava")
+	config.BaseURL = server.URL + "/api/v3"
+
+	client := NewClient(config, &Tokens{AccessToken: "token", RefreshToken: "refresh", Scopes: "read", AthleteId: 1})
+
+	activities, err := client.ListActivities(1, 30)
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if activities.Len() != 2 || activities.Get(2) != nil {
+		t.Fatalf("incorrect activities, got %d", activities.Len())
+	}
+
+	activity := activities.Get(0)
+	if activity.Id != 10 || activity.SportType != "Run" || activity.Distance != 5000 || activity.StartDate != 1704178800 {
+		t.Errorf("incorrect activity, got %+v", activity)
+	}
+
+	tokens := client.Tokens()
+	if tokens.AccessToken != "refreshed" || tokens.RefreshToken != "refresh2" || tokens.AthleteId != 1 || tokens.Scopes != "read" {
+		t.Errorf("refreshed tokens should be returned, got %+v", tokens)
+	}
+
+	athlete, err := client.CurrentAthlete()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.Id != 1 || athlete.FirstName != "Jane" || athlete.Weight != 60.5 {
+		t.Errorf("incorrect athlete, got %+v", athlete)
+	}
+}
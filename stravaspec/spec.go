@@ -0,0 +1,308 @@
+// Package stravaspec watches the swagger spec Strava publishes for its api, so changes upstream, new or
+// removed endpoints and fields, are seen early. A Snapshot summarizes the spec, and is kept as json to diff
+// later versions against:
+//
+//	snapshot, err := stravaspec.Fetch(ctx, http.DefaultClient, stravaspec.SpecURL)
+//	changes := stravaspec.Diff(previous, snapshot)
+//	fmt.Print(changes)
+//
+// The stravaspec command, in cmd/stravaspec, does this with a snapshot file.
+package stravaspec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SpecURL is where Strava publishes the swagger spec of the api.
+const SpecURL = "https://developers.strava.com/swagger/swagger.json"
+
+// A Snapshot is the summary of a spec, its endpoints and the fields of its models.
+type Snapshot struct {
+	Endpoints []string            `json:"endpoints"` // e.g. "GET /activities/{id}"
+	Models    map[string][]string `json:"models"`    // fields by model, e.g. "DetailedActivity": ["calories", ...]
+}
+
+// ReadSnapshot reads a snapshot saved with Save.
+func ReadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &snapshot, nil
+}
+
+// Save writes the snapshot as indented json, so its changes read well in a diff.
+func (s *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+/*********************************************************/
+
+// Fetch fetches the spec at the url, and the documents its models refer to, and returns its snapshot.
+func Fetch(ctx context.Context, client *http.Client, specURL string) (*Snapshot, error) {
+	f := &fetcher{ctx: ctx, client: client, documents: make(map[string]interface{})}
+	if f.client == nil {
+		f.client = http.DefaultClient
+	}
+
+	base, err := url.Parse(specURL)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := f.document(base.String())
+	if err != nil {
+		return nil, err
+	}
+
+	root, _ := spec.(map[string]interface{})
+	snapshot := &Snapshot{Models: make(map[string][]string)}
+
+	paths, _ := root["paths"].(map[string]interface{})
+	for path, item := range paths {
+		operations, _ := item.(map[string]interface{})
+		for method := range operations {
+			switch method {
+			case "get", "put", "post", "delete", "patch", "head", "options":
+				snapshot.Endpoints = append(snapshot.Endpoints, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(snapshot.Endpoints)
+
+	definitions, _ := root["definitions"].(map[string]interface{})
+	for name, definition := range definitions {
+		fields := make(map[string]bool)
+		if err := f.fields(base, definition, fields, 0); err != nil {
+			return nil, fmt.Errorf("model %s: %w", name, err)
+		}
+
+		snapshot.Models[name] = sortedKeys(fields)
+	}
+
+	return snapshot, nil
+}
+
+// maxRefDepth bounds the references followed for a model, against cycles.
+const maxRefDepth = 16
+
+// fetcher fetches the documents of a spec, each once.
+type fetcher struct {
+	ctx       context.Context
+	client    *http.Client
+	documents map[string]interface{}
+}
+
+// document returns the decoded json document at the url.
+func (f *fetcher) document(u string) (interface{}, error) {
+	if document, ok := f.documents[u]; ok {
+		return document, nil
+	}
+
+	req, err := http.NewRequestWithContext(f.ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+
+	var document interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("%s: %w", u, err)
+	}
+
+	f.documents[u] = document
+	return document, nil
+}
+
+// fields adds the properties of the schema, following its references, relative to the base,
+// and the schemas it is composed of with allOf.
+func (f *fetcher) fields(base *url.URL, schema interface{}, fields map[string]bool, depth int) error {
+	if depth > maxRefDepth {
+		return fmt.Errorf("references nested deeper than %d", maxRefDepth)
+	}
+
+	object, _ := schema.(map[string]interface{})
+	if ref, ok := object["$ref"].(string); ok {
+		target, resolved, err := f.resolve(base, ref)
+		if err != nil {
+			return err
+		}
+
+		return f.fields(target, resolved, fields, depth+1)
+	}
+
+	properties, _ := object["properties"].(map[string]interface{})
+	for name := range properties {
+		fields[name] = true
+	}
+
+	parts, _ := object["allOf"].([]interface{})
+	for _, part := range parts {
+		if err := f.fields(base, part, fields, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the url of the document a reference points to and the value it points to,
+// e.g. https://developers.strava.com/swagger/activity.json#/DetailedActivity or #/definitions/Error.
+func (f *fetcher) resolve(base *url.URL, ref string) (*url.URL, interface{}, error) {
+	u, err := base.Parse(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pointer := u.Fragment
+	u.Fragment = ""
+
+	value, err := f.document(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		object, ok := value.(map[string]interface{})
+		if !ok || object[token] == nil {
+			return nil, nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+		value = object[token]
+	}
+
+	return u, value, nil
+}
+
+/*********************************************************/
+
+// Changes are the differences between two snapshots.
+type Changes struct {
+	AddedEndpoints   []string
+	RemovedEndpoints []string
+	AddedFields      []string // e.g. "DetailedActivity.device_name", all fields of new models
+	RemovedFields    []string // all fields of removed models
+}
+
+// Diff returns the changes of the spec from the previous snapshot to the current one.
+func Diff(previous, current *Snapshot) *Changes {
+	changes := &Changes{}
+	changes.AddedEndpoints, changes.RemovedEndpoints = diffLists(previous.Endpoints, current.Endpoints)
+	changes.AddedFields, changes.RemovedFields = diffLists(qualifiedFields(previous), qualifiedFields(current))
+
+	return changes
+}
+
+// Empty returns if the snapshots are the same.
+func (c *Changes) Empty() bool {
+	return len(c.AddedEndpoints)+len(c.RemovedEndpoints)+len(c.AddedFields)+len(c.RemovedFields) == 0
+}
+
+// String reports the changes, a line for each, e.g. "+ GET /routes/{id}/streams".
+func (c *Changes) String() string {
+	var b strings.Builder
+	report := func(title, sign string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+
+		fmt.Fprintf(&b, "%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "%s %s\n", sign, item)
+		}
+	}
+
+	report("new endpoints", "+", c.AddedEndpoints)
+	report("removed endpoints", "-", c.RemovedEndpoints)
+	report("new fields", "+", c.AddedFields)
+	report("removed fields", "-", c.RemovedFields)
+
+	if b.Len() == 0 {
+		return "no changes\n"
+	}
+
+	return b.String()
+}
+
+// qualifiedFields returns the fields of the models of the snapshot, prefixed with their model.
+func qualifiedFields(s *Snapshot) []string {
+	var fields []string
+	for model, names := range s.Models {
+		for _, name := range names {
+			fields = append(fields, model+"."+name)
+		}
+	}
+
+	return fields
+}
+
+// diffLists returns the items only in b, and the items only in a, sorted.
+func diffLists(a, b []string) ([]string, []string) {
+	inA, inB := make(map[string]bool), make(map[string]bool)
+	for _, item := range a {
+		inA[item] = true
+	}
+
+	for _, item := range b {
+		inB[item] = true
+	}
+
+	var added, removed []string
+	for item := range inB {
+		if !inA[item] {
+			added = append(added, item)
+		}
+	}
+
+	for item := range inA {
+		if !inB[item] {
+			removed = append(removed, item)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
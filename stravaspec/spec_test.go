@@ -0,0 +1,110 @@
+package stravaspec
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newSpecServer(spec, activity string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/swagger/swagger.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(spec))
+	})
+	mux.HandleFunc("/swagger/activity.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(activity))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+const testSpec = `{
+	"paths": {
+		"/activities/{id}": {"get": {}, "put": {}, "parameters": []},
+		"/athlete": {"get": {}}
+	},
+	"definitions": {
+		"DetailedActivity": {"$ref": "activity.json#/DetailedActivity"},
+		"Fault": {"properties": {"errors": {}, "message": {}}}
+	}
+}`
+
+const testActivity = `{
+	"SummaryActivity": {"properties": {"id": {}, "name": {}}},
+	"DetailedActivity": {"allOf": [{"$ref": "#/SummaryActivity"}, {"properties": {"calories": {}}}]}
+}`
+
+func TestFetch(t *testing.T) {
+	server := newSpecServer(testSpec, testActivity)
+	defer server.Close()
+
+	snapshot, err := Fetch(context.Background(), nil, server.URL+"/swagger/swagger.json")
+	if err != nil {
+		t.Fatalf("fetch error: %v", err)
+	}
+
+	if !reflect.DeepEqual(snapshot.Endpoints, []string{"GET /activities/{id}", "GET /athlete", "PUT /activities/{id}"}) {
+		t.Errorf("incorrect endpoints, got %v", snapshot.Endpoints)
+	}
+
+	if !reflect.DeepEqual(snapshot.Models["DetailedActivity"], []string{"calories", "id", "name"}) {
+		t.Errorf("referenced and composed fields should be resolved, got %v", snapshot.Models["DetailedActivity"])
+	}
+
+	if !reflect.DeepEqual(snapshot.Models["Fault"], []string{"errors", "message"}) {
+		t.Errorf("incorrect fields, got %v", snapshot.Models["Fault"])
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := snapshot.Save(path); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	saved, err := ReadSnapshot(path)
+	if err != nil || !reflect.DeepEqual(saved, snapshot) {
+		t.Errorf("saved snapshot should be read back, got %+v, %v", saved, err)
+	}
+
+	broken := newSpecServer(`{"definitions": {"DetailedActivity": {"$ref": "activity.json#/Missing"}}}`, testActivity)
+	defer broken.Close()
+
+	if _, err := Fetch(context.Background(), nil, broken.URL+"/swagger/swagger.json"); err == nil || !strings.Contains(err.Error(), "DetailedActivity") {
+		t.Errorf("unresolved reference should fail, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	previous := &Snapshot{
+		Endpoints: []string{"GET /activities/{id}", "GET /athlete"},
+		Models:    map[string][]string{"DetailedActivity": {"calories", "id"}, "Fault": {"message"}},
+	}
+
+	current := &Snapshot{
+		Endpoints: []string{"GET /activities/{id}", "GET /routes/{id}/streams"},
+		Models:    map[string][]string{"DetailedActivity": {"device_name", "id"}, "Fault": {"message"}},
+	}
+
+	changes := Diff(previous, current)
+	expected := &Changes{
+		AddedEndpoints:   []string{"GET /routes/{id}/streams"},
+		RemovedEndpoints: []string{"GET /athlete"},
+		AddedFields:      []string{"DetailedActivity.device_name"},
+		RemovedFields:    []string{"DetailedActivity.calories"},
+	}
+
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("incorrect changes, got %+v", changes)
+	}
+
+	if s := changes.String(); !strings.Contains(s, "new endpoints:\n+ GET /routes/{id}/streams\n") || !strings.Contains(s, "- DetailedActivity.calories") {
+		t.Errorf("incorrect report, got %v", s)
+	}
+
+	if changes := Diff(current, current); !changes.Empty() || changes.String() != "no changes\n" {
+		t.Errorf("same snapshots should have no changes, got %v", changes)
+	}
+}
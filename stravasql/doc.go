@@ -0,0 +1,21 @@
+// Package stravasql keeps the tokens of athletes in a database with database/sql, as a strava.AthleteTokenSource,
+// so web applications don't each write their own token storage. It works with the drivers of PostgreSQL,
+// MySQL and SQLite, in their Dialect:
+//
+//	db, err := sql.Open("pgx", dsn)
+//	err = stravasql.Migrate(ctx, db)
+//
+//	tokens := stravasql.NewTokenSource(db, stravasql.PostgreSQL)
+//	factory := strava.NewClientFactory(tokens, strava.WithCredentials(clientId, clientSecret))
+//	client := factory.ClientFor(athleteId)
+//
+// The tokens are stored in the strava_tokens table, created by Migrate:
+//
+//	CREATE TABLE IF NOT EXISTS strava_tokens (
+//		athlete_id    BIGINT PRIMARY KEY,
+//		access_token  TEXT NOT NULL,
+//		refresh_token TEXT NOT NULL,
+//		expires_at    BIGINT NOT NULL, -- seconds since the epoch
+//		scopes        TEXT NOT NULL    -- comma separated, e.g. read,activity:read_all
+//	)
+package stravasql
@@ -0,0 +1,12 @@
+module github.com/caselongo/strava-go/stravasql
+
+go 1.20
+
+require (
+	github.com/caselongo/strava-go v0.0.0-00010101000000-000000000000
+	github.com/mattn/go-sqlite3 v1.14.33
+)
+
+require golang.org/x/oauth2 v0.24.0 // indirect
+
+replace github.com/caselongo/strava-go => ../
@@ -0,0 +1,107 @@
+//go:build cgo
+
+package stravasql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	strava "github.com/caselongo/strava-go"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openSQLite opens a new SQLite database in a file, so several connections share it like instances of an app.
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "strava.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestSQLiteMigrateConcurrently(t *testing.T) {
+	db := openSQLite(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Migrate(context.Background(), db)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent migrations should succeed, got %v", err)
+		}
+	}
+
+	var versions int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM strava_migrations`).Scan(&versions); err != nil || versions != len(Migrations) {
+		t.Errorf("every version should be recorded once, got %d %v", versions, err)
+	}
+}
+
+func TestSQLiteTokenSource(t *testing.T) {
+	db := openSQLite(t)
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migrate error: %v", err)
+	}
+
+	tokens := NewTokenSource(db, SQLite)
+	if _, err := tokens.GetTokens(1); !errors.Is(err, strava.ErrNoTokens) {
+		t.Fatalf("unknown athletes should have no tokens, got %v", err)
+	}
+
+	// processes refreshing the tokens at once
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- tokens.SaveTokens(1, &strava.AuthorizationResponse{AccessToken: fmt.Sprint("access-", i), RefreshToken: "refresh", ExpiresAt: 1568775134})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent saves should succeed, got %v", err)
+		}
+	}
+
+	saved := &strava.AuthorizationResponse{AccessToken: "abc", RefreshToken: "def", ExpiresAt: 1568775134, Scopes: []strava.Scope{strava.ScopeRead, strava.ScopeActivityReadAll}}
+	if err := tokens.SaveTokens(1, saved); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	response, err := tokens.GetTokens(1)
+	if err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+
+	if response.AccessToken != "abc" || response.RefreshToken != "def" || len(response.Scopes) != 2 || response.Athlete.Id != 1 {
+		t.Errorf("incorrect tokens, got %+v", response)
+	}
+
+	if err := tokens.DeleteTokens(1); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+
+	if _, err := tokens.GetTokens(1); !errors.Is(err, strava.ErrNoTokens) {
+		t.Errorf("deleted tokens should be gone, got %v", err)
+	}
+}
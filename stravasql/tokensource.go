@@ -0,0 +1,190 @@
+package stravasql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// A Placeholder returns the placeholder of the nth argument of a statement, from 1, in the syntax of the driver.
+type Placeholder func(n int) string
+
+// A Dialect is the syntax of a database: its placeholders and the clause of an INSERT replacing the row
+// with the same key. Drivers of other databases with the same syntax can copy a dialect and change the Placeholder.
+type Dialect struct {
+	Placeholder Placeholder
+	upsert      string
+}
+
+var (
+	PostgreSQL = Dialect{Placeholder: func(n int) string { return "$" + strconv.Itoa(n) }, upsert: onConflict}
+	SQLite     = Dialect{Placeholder: func(int) string { return "?" }, upsert: onConflict} // 3.24 or later
+	MySQL      = Dialect{Placeholder: func(int) string { return "?" }, upsert: onDuplicateKey}
+)
+
+const (
+	onConflict = `ON CONFLICT (athlete_id) DO UPDATE SET access_token = excluded.access_token, refresh_token = excluded.refresh_token,
+		expires_at = excluded.expires_at, scopes = excluded.scopes`
+	onDuplicateKey = `ON DUPLICATE KEY UPDATE access_token = VALUES(access_token), refresh_token = VALUES(refresh_token),
+		expires_at = VALUES(expires_at), scopes = VALUES(scopes)`
+)
+
+// Migrations are the statements that create and change the tables of the package, in order, see Migrate.
+// Applications running their own migrations can copy them. They are idempotent, so instances starting
+// at once can apply them at the same time.
+var Migrations = []string{
+	`CREATE TABLE IF NOT EXISTS strava_tokens (
+		athlete_id    BIGINT PRIMARY KEY,
+		access_token  TEXT NOT NULL,
+		refresh_token TEXT NOT NULL,
+		expires_at    BIGINT NOT NULL,
+		scopes        TEXT NOT NULL
+	)`,
+}
+
+// Migrate applies the Migrations the database doesn't have yet, each in a transaction. The applied versions
+// are kept in the strava_migrations table, so Migrate can be called on every start, also by several instances
+// at once: a version another instance recorded first counts as applied.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	// instances creating the table at once may fail, the table exists if the versions can be read
+	_, createErr := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS strava_migrations (version INTEGER PRIMARY KEY)`)
+
+	var applied int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM strava_migrations`).Scan(&applied); err != nil {
+		if createErr != nil {
+			err = createErr
+		}
+		return fmt.Errorf("stravasql: migrations: %w", err)
+	}
+
+	for version := applied + 1; version <= len(Migrations); version++ {
+		if err := migrate(ctx, db, version); err != nil && !migrated(ctx, db, version) {
+			return fmt.Errorf("stravasql: migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+func migrate(ctx context.Context, db *sql.DB, version int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, Migrations[version-1]); err != nil {
+		return err
+	}
+
+	// the version is a number, no placeholder is needed. The insert fails if another instance recorded it first
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO strava_migrations (version) VALUES (%d)`, version)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrated returns if the version was recorded, e.g. by another instance migrating at the same time.
+func migrated(ctx context.Context, db *sql.DB, version int) bool {
+	var n int
+	err := db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM strava_migrations WHERE version = %d`, version)).Scan(&n)
+	return err == nil && n > 0
+}
+
+/*********************************************************/
+
+// A TokenSource is a strava.AthleteTokenSource keeping the tokens in the strava_tokens table. Safe for concurrent use.
+// Its methods have variants taking a context, like those of database/sql, for callers outside of a client.
+type TokenSource struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewTokenSource returns a source of the tokens in the database, in the dialect of its driver.
+func NewTokenSource(db *sql.DB, dialect Dialect) *TokenSource {
+	return &TokenSource{db: db, dialect: dialect}
+}
+
+// statement replaces the ? of the query with the placeholders of the driver.
+func (s *TokenSource) statement(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(s.dialect.Placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// GetTokens returns the tokens of the athlete, see GetTokensContext.
+func (s *TokenSource) GetTokens(athleteId int64) (*strava.AuthorizationResponse, error) {
+	return s.GetTokensContext(context.Background(), athleteId)
+}
+
+// GetTokensContext returns the tokens of the athlete, an error wrapping strava.ErrNoTokens if there are none.
+// The Athlete of the tokens only has the id.
+func (s *TokenSource) GetTokensContext(ctx context.Context, athleteId int64) (*strava.AuthorizationResponse, error) {
+	response := &strava.AuthorizationResponse{TokenType: "Bearer", Athlete: &strava.AthleteDetailed{}}
+	response.Athlete.Id = athleteId
+	var scopes string
+
+	err := s.db.QueryRowContext(ctx, s.statement(`SELECT access_token, refresh_token, expires_at, scopes FROM strava_tokens WHERE athlete_id = ?`), athleteId).
+		Scan(&response.AccessToken, &response.RefreshToken, &response.ExpiresAt, &scopes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("stravasql: athlete %d: %w", athleteId, strava.ErrNoTokens)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("stravasql: athlete %d: %w", athleteId, err)
+	}
+
+	response.Scopes = strava.ParseScopes(scopes)
+	return response, nil
+}
+
+// SaveTokens stores the tokens of the athlete, see SaveTokensContext.
+func (s *TokenSource) SaveTokens(athleteId int64, response *strava.AuthorizationResponse) error {
+	return s.SaveTokensContext(context.Background(), athleteId, response)
+}
+
+// SaveTokensContext stores the tokens of the athlete, replacing the previous ones in one statement,
+// so processes refreshing the tokens of an athlete at once don't conflict.
+func (s *TokenSource) SaveTokensContext(ctx context.Context, athleteId int64, response *strava.AuthorizationResponse) error {
+	var scopes []string
+	for _, scope := range response.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	_, err := s.db.ExecContext(ctx, s.statement(`INSERT INTO strava_tokens (athlete_id, access_token, refresh_token, expires_at, scopes) VALUES (?, ?, ?, ?, ?) `+s.dialect.upsert),
+		athleteId, response.AccessToken, response.RefreshToken, response.ExpiresAt, strings.Join(scopes, ","))
+	if err != nil {
+		return fmt.Errorf("stravasql: athlete %d: %w", athleteId, err)
+	}
+
+	return nil
+}
+
+// DeleteTokens removes the tokens of the athlete, see DeleteTokensContext.
+func (s *TokenSource) DeleteTokens(athleteId int64) error {
+	return s.DeleteTokensContext(context.Background(), athleteId)
+}
+
+// DeleteTokensContext removes the tokens of the athlete, e.g. after a deauthorization.
+func (s *TokenSource) DeleteTokensContext(ctx context.Context, athleteId int64) error {
+	if _, err := s.db.ExecContext(ctx, s.statement(`DELETE FROM strava_tokens WHERE athlete_id = ?`), athleteId); err != nil {
+		return fmt.Errorf("stravasql: athlete %d: %w", athleteId, err)
+	}
+
+	return nil
+}
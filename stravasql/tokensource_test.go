@@ -0,0 +1,239 @@
+package stravasql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// fakeDriver is a database/sql driver that understands the statements of the package, kept in memory.
+type fakeDriver struct {
+	lock       sync.Mutex
+	statements []string
+	migrations []int64
+	created    int
+	tokens     map[int64][]driver.Value
+
+	// beforeMigration is called before a version is recorded, e.g. to record it as another instance
+	beforeMigration func(version int64)
+}
+
+// fakeDrivers numbers the registered drivers, which can't be registered twice, e.g. with -count
+var fakeDrivers int64
+
+func openFake(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{tokens: make(map[int64][]driver.Value)}
+	name := fmt.Sprint("fake-", t.Name(), "-", atomic.AddInt64(&fakeDrivers, 1))
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+
+	return db, d
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.d
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.statements = append(d.statements, s.query)
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS strava_migrations"):
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS strava_tokens"):
+		d.created++
+	case strings.HasPrefix(s.query, "INSERT INTO strava_migrations"):
+		var version int64
+		fmt.Sscanf(s.query, "INSERT INTO strava_migrations (version) VALUES (%d)", &version)
+		if d.beforeMigration != nil {
+			d.beforeMigration(version)
+		}
+		for _, applied := range d.migrations {
+			if applied == version {
+				return nil, errors.New("duplicate key")
+			}
+		}
+		d.migrations = append(d.migrations, version)
+	case strings.HasPrefix(s.query, "DELETE FROM strava_tokens"):
+		delete(d.tokens, args[0].(int64))
+	case strings.HasPrefix(s.query, "INSERT INTO strava_tokens"):
+		if !strings.Contains(s.query, "ON CONFLICT (athlete_id) DO UPDATE") {
+			return nil, errors.New("not an upsert")
+		}
+		d.tokens[args[0].(int64)] = args[1:]
+	default:
+		return nil, fmt.Errorf("unexpected statement %s", s.query)
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.d
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.statements = append(d.statements, s.query)
+	switch {
+	case strings.HasPrefix(s.query, "SELECT COALESCE(MAX(version), 0)"):
+		max := int64(0)
+		for _, version := range d.migrations {
+			if version > max {
+				max = version
+			}
+		}
+		return &fakeRows{columns: 1, values: [][]driver.Value{{max}}}, nil
+	case strings.HasPrefix(s.query, "SELECT COUNT(*) FROM strava_migrations"):
+		var version, n int64
+		fmt.Sscanf(s.query, "SELECT COUNT(*) FROM strava_migrations WHERE version = %d", &version)
+		for _, applied := range d.migrations {
+			if applied == version {
+				n++
+			}
+		}
+		return &fakeRows{columns: 1, values: [][]driver.Value{{n}}}, nil
+	case strings.HasPrefix(s.query, "SELECT access_token"):
+		row, ok := d.tokens[args[0].(int64)]
+		if !ok {
+			return &fakeRows{columns: 4}, nil
+		}
+		return &fakeRows{columns: 4, values: [][]driver.Value{row}}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected query %s", s.query)
+}
+
+type fakeRows struct {
+	columns int
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return make([]string, r.columns) }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+/*********************************************************/
+
+func TestMigrate(t *testing.T) {
+	db, d := openFake(t)
+
+	for i := 0; i < 2; i++ {
+		if err := Migrate(context.Background(), db); err != nil {
+			t.Fatalf("migrate error: %v", err)
+		}
+	}
+
+	if d.created != 1 || !reflect.DeepEqual(d.migrations, []int64{1}) {
+		t.Errorf("migrations should be applied once, got %v", d.migrations)
+	}
+}
+
+func TestMigrateConcurrently(t *testing.T) {
+	db, d := openFake(t)
+
+	// another instance records the version while this one applies it
+	d.beforeMigration = func(version int64) {
+		d.migrations = append(d.migrations, version)
+		d.beforeMigration = nil
+	}
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("versions recorded by other instances should count as applied, got %v", err)
+	}
+
+	if !reflect.DeepEqual(d.migrations, []int64{1}) {
+		t.Errorf("the version should be recorded once, got %v", d.migrations)
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	db, d := openFake(t)
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migrate error: %v", err)
+	}
+
+	tokens := NewTokenSource(db, PostgreSQL)
+	if _, err := tokens.GetTokens(1); !errors.Is(err, strava.ErrNoTokens) {
+		t.Fatalf("unknown athletes should have no tokens, got %v", err)
+	}
+
+	saved := &strava.AuthorizationResponse{AccessToken: "abc", RefreshToken: "def", ExpiresAt: 1568775134, Scopes: []strava.Scope{strava.ScopeRead, strava.ScopeActivityReadAll}}
+	for i := 0; i < 2; i++ {
+		if err := tokens.SaveTokens(1, saved); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+	}
+
+	response, err := tokens.GetTokens(1)
+	if err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+
+	if response.AccessToken != "abc" || response.RefreshToken != "def" || response.ExpiresAt != 1568775134 || !reflect.DeepEqual(response.Scopes, saved.Scopes) || response.Athlete.Id != 1 {
+		t.Errorf("incorrect tokens, got %+v", response)
+	}
+
+	if last := d.statements[len(d.statements)-1]; !strings.HasSuffix(last, "WHERE athlete_id = $1") {
+		t.Errorf("placeholders of the driver should be used, got %v", last)
+	}
+
+	if err := tokens.DeleteTokens(1); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+
+	if _, err := strava.TokenSourceFor(tokens, 1).GetAuthorizationResponse(); !errors.Is(err, strava.ErrNoTokens) {
+		t.Errorf("deleted tokens should be gone, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tokens.SaveTokensContext(ctx, 1, saved); !errors.Is(err, context.Canceled) {
+		t.Errorf("statements should be cancelled with their context, got %v", err)
+	}
+
+	if _, err := tokens.GetTokensContext(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("statements should be cancelled with their context, got %v", err)
+	}
+}
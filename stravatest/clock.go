@@ -0,0 +1,55 @@
+package stravatest
+
+import (
+	"sync"
+	"time"
+)
+
+// A Clock is a strava.Clock that only moves when told, so tests control when tokens expire
+// and request delays pass without waiting:
+//
+//	clock := stravatest.NewClock(time.Now())
+//	client := strava.NewClient(tokenSource, strava.WithClock(clock), strava.WithRequestDelay(time.Second, 0))
+//	clock.Advance(time.Hour) // the token expired
+type Clock struct {
+	lock  sync.Mutex
+	now   time.Time
+	slept time.Duration
+}
+
+// NewClock returns a clock telling the time now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// Sleep advances the clock by d right away.
+func (c *Clock) Sleep(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	c.slept += d
+}
+
+// Advance moves the clock d forward.
+func (c *Clock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Slept returns the total time slept, e.g. the delays of the requests of a client.
+func (c *Clock) Slept() time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.slept
+}
@@ -0,0 +1,34 @@
+package stravatest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Now()
+	clock := NewClock(start)
+
+	client := strava.NewClient(staticTokenSource{},
+		strava.WithHTTPClient(&http.Client{Transport: &stubTransport{body: `{"id":1}`}}),
+		strava.WithClock(clock),
+		strava.WithRequestDelay(time.Minute, 0))
+
+	for i := 0; i < 3; i++ {
+		if _, err := strava.NewCurrentAthleteService(client).Get().Do(); err != nil {
+			t.Fatalf("service error: %v", err)
+		}
+	}
+
+	if clock.Slept() != 2*time.Minute || !clock.Now().Equal(start.Add(2*time.Minute)) {
+		t.Errorf("delays should pass on the clock, slept %v", clock.Slept())
+	}
+
+	clock.Advance(time.Hour)
+	if !clock.Now().Equal(start.Add(time.Hour + 2*time.Minute)) {
+		t.Errorf("clock should advance, got %v", clock.Now())
+	}
+}
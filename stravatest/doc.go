@@ -0,0 +1,10 @@
+// Package stravatest produces realistic model values of the strava package for unit tests,
+// so tests don't have to spell out large literals. Values are deterministic for a seed:
+//
+//	f := stravatest.New(1)
+//	athlete := f.Athlete()
+//	activity := f.Activity(strava.ActivityTypes.Run)
+//	streams := f.Streams(activity)
+//
+// The streams of an activity agree with its distance, moving time and elevation gain.
+package stravatest
@@ -0,0 +1,193 @@
+package stravatest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// Epoch is the date of the most recent activity of a factory, activities are spread over the days before it.
+var Epoch = time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+
+// A Factory produces model values, the same seed always gives the same values in the same order.
+// Not safe for concurrent use.
+type Factory struct {
+	rand    *rand.Rand
+	nextId  int64
+	days    int // days before Epoch of the last activity
+	athlete *strava.AthleteDetailed
+}
+
+// New creates a factory with the seed.
+func New(seed int64) *Factory {
+	return &Factory{
+		rand:   rand.New(rand.NewSource(seed)),
+		nextId: 1000,
+	}
+}
+
+func (f *Factory) id() int64 {
+	f.nextId += 1 + f.rand.Int63n(1000)
+	return f.nextId
+}
+
+// between returns a random value in [min, max).
+func (f *Factory) between(min, max float64) float64 {
+	return min + f.rand.Float64()*(max-min)
+}
+
+var firstNames = []string{"Anna", "Bram", "Chloe", "Daan", "Eva", "Finn", "Julia", "Lucas", "Noor", "Sem"}
+var lastNames = []string{"de Vries", "Jansen", "Bakker", "Visser", "Smit", "Meijer", "Mulder", "Bos"}
+var cities = []struct{ city, state, country string }{
+	{"Amsterdam", "Noord-Holland", "Netherlands"},
+	{"Utrecht", "Utrecht", "Netherlands"},
+	{"Boulder", "Colorado", "United States"},
+	{"Girona", "Catalonia", "Spain"},
+}
+
+// Athlete returns a new athlete with a bike and a pair of shoes.
+// The first athlete of a factory is also the owner of the activities it produces.
+func (f *Factory) Athlete() *strava.AthleteDetailed {
+	city := cities[f.rand.Intn(len(cities))]
+	gender := strava.Genders.Male
+	if f.rand.Intn(2) == 0 {
+		gender = strava.Genders.Female
+	}
+
+	a := &strava.AthleteDetailed{
+		AthleteSummary: strava.AthleteSummary{
+			AthleteMeta: strava.AthleteMeta{Id: f.id()},
+			FirstName:   firstNames[f.rand.Intn(len(firstNames))],
+			LastName:    lastNames[f.rand.Intn(len(lastNames))],
+			City:        city.city,
+			State:       city.state,
+			Country:     city.country,
+			Gender:      gender,
+			Summit:      f.rand.Intn(2) == 0,
+			CreatedAt:   Epoch.AddDate(-3, 0, -f.rand.Intn(1000)),
+			UpdatedAt:   Epoch.AddDate(0, 0, -f.rand.Intn(30)),
+		},
+		FollowerCount:         f.rand.Intn(500),
+		DatePreference:        "%m/%d/%Y",
+		MeasurementPreference: "meters",
+		FTP:                   int(f.between(180, 320)),
+		Weight:                math.Round(f.between(55, 90)*10) / 10,
+	}
+	a.Email = fmt.Sprintf("athlete%d@example.com", a.Id)
+
+	a.Bikes = []*strava.GearSummary{{Id: fmt.Sprintf("b%d", f.id()), Name: "Road bike", Primary: true, Distance: strava.Distance(math.Round(f.between(1000, 20000)) * 1000)}}
+	a.Shoes = []*strava.GearSummary{{Id: fmt.Sprintf("g%d", f.id()), Name: "Running shoes", Primary: true, Distance: strava.Distance(math.Round(f.between(100, 800)) * 1000)}}
+
+	if f.athlete == nil {
+		f.athlete = a
+	}
+
+	return a
+}
+
+// activityProfile are the ranges of the values of an activity type.
+type activityProfile struct {
+	speed     [2]float64 // m/s
+	duration  [2]float64 // minutes
+	heartrate [2]float64
+	cadence   [2]float64
+	power     bool
+}
+
+var activityProfiles = map[strava.ActivityType]activityProfile{
+	strava.ActivityTypes.Ride: {speed: [2]float64{6, 9.5}, duration: [2]float64{45, 180}, heartrate: [2]float64{120, 155}, cadence: [2]float64{80, 95}, power: true},
+	strava.ActivityTypes.Run:  {speed: [2]float64{2.6, 3.9}, duration: [2]float64{20, 90}, heartrate: [2]float64{135, 170}, cadence: [2]float64{80, 92}},
+	strava.ActivityTypes.Walk: {speed: [2]float64{1.2, 1.6}, duration: [2]float64{20, 120}, heartrate: [2]float64{90, 115}, cadence: [2]float64{50, 60}},
+	strava.ActivityTypes.Hike: {speed: [2]float64{0.9, 1.4}, duration: [2]float64{60, 300}, heartrate: [2]float64{100, 135}, cadence: [2]float64{45, 55}},
+}
+
+// Activity returns a new activity of the first athlete of the factory, one is created if there is none.
+// Rides, runs, walks and hikes get values in a realistic range, other types are produced as rides.
+// Each activity starts one to three days before the previous one.
+func (f *Factory) Activity(activityType strava.ActivityType) *strava.ActivitySummary {
+	if f.athlete == nil {
+		f.Athlete()
+	}
+
+	profile, ok := activityProfiles[activityType]
+	if !ok {
+		activityType = strava.ActivityTypes.Ride
+		profile = activityProfiles[activityType]
+	}
+
+	speed := f.between(profile.speed[0], profile.speed[1])
+	movingTime := int(f.between(profile.duration[0], profile.duration[1]) * 60)
+	distance := math.Round(speed * float64(movingTime))
+	f.days += 1 + f.rand.Intn(3)
+	start := Epoch.AddDate(0, 0, -f.days).Add(time.Duration(f.rand.Intn(12*60)) * time.Minute)
+	location := strava.Location{f.between(51.9, 52.4), f.between(4.7, 5.2)}
+
+	a := &strava.ActivitySummary{
+		Id:                 f.id(),
+		Athlete:            f.athlete.AthleteSummary,
+		Name:               fmt.Sprintf("%s %s", partOfDay(start), activityType),
+		Distance:           strava.Distance(distance),
+		MovingTime:         movingTime,
+		ElapsedTime:        movingTime + f.rand.Intn(movingTime/10+1),
+		TotalElevationGain: strava.Elevation(math.Round(distance / 1000 * f.between(2, 15))),
+		Type:               activityType,
+		SportType:          activityType,
+		StartDate:          start,
+		StartDateLocal:     start,
+		TimeZone:           "(GMT+01:00) Europe/Amsterdam",
+		StartLocation:      location,
+		EndLocation:        location,
+		KudosCount:         f.rand.Intn(30),
+		CommentCount:       f.rand.Intn(5),
+		AthleteCount:       1 + f.rand.Intn(3),
+		AverageSpeed:       strava.Speed(distance / float64(movingTime)),
+		MaximunSpeed:       strava.Speed(speed * f.between(1.3, 1.8)),
+		AverageCadence:     math.Round(f.between(profile.cadence[0], profile.cadence[1])),
+		AverageTemperature: strava.Temperature(math.Round(f.between(5, 28))),
+		AverageHeartrate:   math.Round(f.between(profile.heartrate[0], profile.heartrate[1])),
+	}
+	a.MaximumHeartrate = a.AverageHeartrate + math.Round(f.between(10, 30))
+
+	if profile.power {
+		a.AveragePower = strava.Power(math.Round(f.between(0.55, 0.8) * float64(f.athlete.FTP)))
+		a.WeightedAveragePower = a.AveragePower * strava.Power(f.between(1.02, 1.12))
+		a.Kilojoules = math.Round(float64(a.AveragePower) * float64(movingTime) / 1000)
+		a.DeviceWatts = true
+	}
+
+	if len(f.athlete.Bikes) > 0 && activityType == strava.ActivityTypes.Ride {
+		a.GearId = f.athlete.Bikes[0].Id
+	} else if len(f.athlete.Shoes) > 0 && activityType != strava.ActivityTypes.Ride {
+		a.GearId = f.athlete.Shoes[0].Id
+	}
+
+	return a
+}
+
+// Activities returns n activities of the types, taken in turn.
+func (f *Factory) Activities(n int, activityTypes ...strava.ActivityType) []*strava.ActivitySummary {
+	if len(activityTypes) == 0 {
+		activityTypes = []strava.ActivityType{strava.ActivityTypes.Ride, strava.ActivityTypes.Run}
+	}
+
+	activities := make([]*strava.ActivitySummary, n)
+	for i := range activities {
+		activities[i] = f.Activity(activityTypes[i%len(activityTypes)])
+	}
+
+	return activities
+}
+
+func partOfDay(t time.Time) string {
+	switch {
+	case t.Hour() < 12:
+		return "Morning"
+	case t.Hour() < 17:
+		return "Afternoon"
+	}
+
+	return "Evening"
+}
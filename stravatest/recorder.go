@@ -0,0 +1,195 @@
+package stravatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// A Recorder is an http.RoundTripper that replays responses recorded in a directory and records
+// the responses of requests it has no recording for, so tests can run against real responses of Strava
+// without a network. Recordings are sanitized before they are written, so they are safe to commit:
+//
+//	client := strava.NewClient(tokenSource, strava.WithHTTPClient(&http.Client{
+//		Transport: &stravatest.Recorder{Dir: "testdata/recordings"},
+//	}))
+type Recorder struct {
+	Dir        string
+	Transport  http.RoundTripper   // makes the requests that are recorded, http.DefaultTransport if nil
+	Sanitize   func([]byte) []byte // applied to the recorded bodies, Sanitize if nil
+	ReplayOnly bool                // fail requests without a recording instead of making them, e.g. on CI
+}
+
+// recording is a response as written to disk.
+type recording struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// recordedHeaders are the response headers kept in recordings.
+var recordedHeaders = []string{"Content-Type", "X-Ratelimit-Limit", "X-Ratelimit-Usage"}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	filename := filepath.Join(r.Dir, recordingName(req))
+
+	if data, err := os.ReadFile(filename); err == nil {
+		var rec recording
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("stravatest: invalid recording %s: %w", filename, err)
+		}
+
+		return rec.response(req), nil
+	}
+
+	if r.ReplayOnly {
+		return nil, fmt.Errorf("stravatest: no recording %s of %s %s", filename, req.Method, req.URL.Path)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	sanitize := r.Sanitize
+	if sanitize == nil {
+		sanitize = Sanitize
+	}
+
+	rec := recording{StatusCode: resp.StatusCode, Header: make(http.Header), Body: string(sanitize(body))}
+	for _, key := range recordedHeaders {
+		if value := resp.Header.Get(key); value != "" {
+			rec.Header.Set(key, value)
+		}
+	}
+
+	data, _ := json.MarshalIndent(rec, "", "\t")
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return nil, err
+	}
+
+	return rec.response(req), nil
+}
+
+func (rec recording) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     http.StatusText(rec.StatusCode),
+		StatusCode: rec.StatusCode,
+		Header:     rec.Header,
+		Body:       io.NopCloser(strings.NewReader(rec.Body)),
+		Request:    req,
+	}
+}
+
+var nonNameCharacters = regexp.MustCompile(`[^A-Za-z0-9_.,-]+`)
+
+// recordingName returns the file name of the recording of the request, e.g. GET_api_v3_activities_123.json.
+func recordingName(req *http.Request) string {
+	name := req.Method + req.URL.Path
+	if req.URL.RawQuery != "" {
+		name += "_" + req.URL.RawQuery
+	}
+
+	return strings.Trim(nonNameCharacters.ReplaceAllString(name, "_"), "_") + ".json"
+}
+
+/*********************************************************/
+
+// CoordinateDecimals is the precision Sanitize rounds coordinates to, 2 decimals is about a kilometer.
+var CoordinateDecimals = 2
+
+// coordinateFields are the json fields holding coordinates, rounded by Sanitize.
+var coordinateFields = map[string]bool{"start_latlng": true, "end_latlng": true, "latlng": true}
+
+// polylineFields are the json fields holding encoded polylines, of which the coordinates are rounded by Sanitize.
+var polylineFields = map[string]bool{"polyline": true, "summary_polyline": true}
+
+// Sanitize removes access and refresh tokens, client secrets and email addresses from a response,
+// see strava.Redact, and rounds the coordinates of locations, polylines and latlng streams to CoordinateDecimals,
+// so recordings don't reveal where athletes live. Responses that are not json are only redacted.
+func Sanitize(data []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(strava.Redact(string(data)))
+	}
+
+	value = sanitizeValue("", value)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+
+	return []byte(strava.Redact(strings.TrimSuffix(buf.String(), "\n")))
+}
+
+// sanitizeValue rounds the coordinates in the value of the json field.
+// For streams, the field of the data is the type of the stream.
+func sanitizeValue(field string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			itemField := key
+			if key == "data" {
+				if streamType, ok := v["type"].(string); ok {
+					itemField = streamType
+				}
+			}
+
+			v[key] = sanitizeValue(itemField, item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = sanitizeValue(field, item)
+		}
+	case json.Number:
+		if coordinateFields[field] {
+			if f, err := v.Float64(); err == nil {
+				return json.Number(fmt.Sprint(roundCoordinate(f)))
+			}
+		}
+	case string:
+		if polylineFields[field] {
+			points := strava.Polyline(v).Decode()
+			for i := range points {
+				points[i] = [2]float64{roundCoordinate(points[i][0]), roundCoordinate(points[i][1])}
+			}
+
+			return string(strava.EncodePolyline(points))
+		}
+	}
+
+	return value
+}
+
+func roundCoordinate(f float64) float64 {
+	factor := math.Pow(10, float64(CoordinateDecimals))
+	return math.Round(f*factor) / factor
+}
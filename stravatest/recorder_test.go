@@ -0,0 +1,115 @@
+package stravatest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+type stubTransport struct {
+	body     string
+	requests int
+}
+
+func (t *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    req,
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	resp.Header.Set("Set-Cookie", "session=secret")
+
+	return resp, nil
+}
+
+func TestSanitize(t *testing.T) {
+	data := Sanitize([]byte(`{
+		"id": 12345678901,
+		"name": "Home <-> work",
+		"access_token": "abc",
+		"athlete": {"email": "jane@example.com"},
+		"start_latlng": [52.123456, 5.123456],
+		"distance": 1234.5678,
+		"map": {"summary_polyline": "_p~iF~ps|U_ulLnnqC"}
+	}`))
+
+	expected := `{"access_token":"[REDACTED]","athlete":{"email":"[REDACTED]"},"distance":1234.5678,"id":12345678901,` +
+		`"map":{"summary_polyline":"` + string(strava.EncodePolyline([][2]float64{{38.5, -120.2}, {40.7, -120.95}})) + `"},` +
+		`"name":"Home <-> work","start_latlng":[52.12,5.12]}`
+	if string(data) != expected {
+		t.Errorf("incorrect sanitized data, got %s", data)
+	}
+
+	data = Sanitize([]byte(`[{"type":"latlng","data":[[52.123456,5.123456]]},{"type":"distance","data":[1.23456]}]`))
+	if string(data) != `[{"data":[[52.12,5.12]],"type":"latlng"},{"data":[1.23456],"type":"distance"}]` {
+		t.Errorf("incorrect sanitized streams, got %s", data)
+	}
+
+	if data := Sanitize([]byte(`access_token=abc&scope=read`)); string(data) != `access_token=[REDACTED]&scope=read` {
+		t.Errorf("responses that are not json should be redacted, got %s", data)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	transport := &stubTransport{body: `{"id":1,"email":"jane@example.com","start_latlng":[52.123456,5.123456]}`}
+
+	client := strava.NewClient(staticTokenSource{}, strava.WithHTTPClient(&http.Client{
+		Transport: &Recorder{Dir: dir, Transport: transport},
+	}))
+
+	athlete, err := strava.NewCurrentAthleteService(client).Get().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.Email != "[REDACTED]" {
+		t.Errorf("recorded response should be returned, got %v", athlete.Email)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "GET_api_v3_athlete.json"))
+	if err != nil {
+		t.Fatalf("recording not written: %v", err)
+	}
+
+	if strings.Contains(string(data), "jane") || strings.Contains(string(data), "52.123456") || strings.Contains(string(data), "session") {
+		t.Errorf("recording should be sanitized, got %s", data)
+	}
+
+	// replayed without a request
+	client = strava.NewClient(staticTokenSource{}, strava.WithHTTPClient(&http.Client{
+		Transport: &Recorder{Dir: dir, Transport: transport, ReplayOnly: true},
+	}))
+
+	if _, err := strava.NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if transport.requests != 1 {
+		t.Errorf("recording should be replayed, got %d requests", transport.requests)
+	}
+
+	if _, err := strava.NewAthletesService(client).Get(2).Do(); err == nil || transport.requests != 1 {
+		t.Error("requests without recording should fail in replay only mode")
+	}
+}
+
+type staticTokenSource struct{}
+
+func (staticTokenSource) GetAuthorizationResponse() (*strava.AuthorizationResponse, error) {
+	return &strava.AuthorizationResponse{AccessToken: "token", ExpiresAt: ExpiresAt(time.Now().Add(time.Hour))}, nil
+}
+
+func (staticTokenSource) SaveAuthorizationResponse(string, *strava.AuthorizationResponse) error {
+	return nil
+}
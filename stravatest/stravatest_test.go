@@ -0,0 +1,117 @@
+package stravatest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+func TestFactoryDeterministic(t *testing.T) {
+	a, b := New(1), New(1)
+
+	if !reflect.DeepEqual(a.Athlete(), b.Athlete()) {
+		t.Error("athletes of the same seed should be equal")
+	}
+
+	if !reflect.DeepEqual(a.Activities(5), b.Activities(5)) {
+		t.Error("activities of the same seed should be equal")
+	}
+
+	if reflect.DeepEqual(New(1).Athlete(), New(2).Athlete()) {
+		t.Error("athletes of different seeds should differ")
+	}
+}
+
+func TestFactoryActivities(t *testing.T) {
+	f := New(1)
+	athlete := f.Athlete()
+	f.Athlete()
+
+	activities := f.Activities(10, strava.ActivityTypes.Ride, strava.ActivityTypes.Run, strava.ActivityTypes.Swim)
+	for i, a := range activities {
+		if a.Athlete.Id != athlete.Id {
+			t.Errorf("activity should be of the first athlete, got %v", a.Athlete.Id)
+		}
+
+		if i > 0 && !a.StartDate.Before(activities[i-1].StartDate) {
+			t.Errorf("activities should start before the previous one, got %v", a.StartDate)
+		}
+
+		if a.ElapsedTime < a.MovingTime || a.Distance <= 0 {
+			t.Errorf("incorrect activity, got %+v", a)
+		}
+	}
+
+	if activities[0].Type != strava.ActivityTypes.Ride || activities[0].AveragePower == 0 || activities[0].GearId != athlete.Bikes[0].Id {
+		t.Errorf("incorrect ride, got %+v", activities[0])
+	}
+
+	if activities[1].Type != strava.ActivityTypes.Run || activities[1].AveragePower != 0 || activities[1].GearId != athlete.Shoes[0].Id {
+		t.Errorf("incorrect run, got %+v", activities[1])
+	}
+
+	// unsupported types are produced as rides
+	if activities[2].Type != strava.ActivityTypes.Ride {
+		t.Errorf("incorrect type, got %v", activities[2].Type)
+	}
+
+	if speed := activities[1].AverageSpeed.Pace(strava.Kilometer); speed < 4*time.Minute || speed > 7*time.Minute {
+		t.Errorf("unrealistic pace, got %v", speed)
+	}
+}
+
+func TestFactoryStreams(t *testing.T) {
+	f := New(3)
+	activity := f.Activity(strava.ActivityTypes.Ride)
+	streams := f.Streams(activity)
+
+	n := activity.MovingTime + 1
+	if len(streams.Time.Data) != n || len(streams.Power.Data) != n || len(streams.Location.Data) != n {
+		t.Fatalf("incorrect stream lengths, got %v", len(streams.Time.Data))
+	}
+
+	if d := streams.Distance.Data[n-1]; math.Abs(d-float64(activity.Distance)) > 1 {
+		t.Errorf("distance should match the activity, got %v", d)
+	}
+
+	gain := 0.0
+	for i := 1; i < n; i++ {
+		if diff := streams.Elevation.Data[i] - streams.Elevation.Data[i-1]; diff > 0 {
+			gain += diff
+		}
+	}
+	if math.Abs(gain-float64(activity.TotalElevationGain)) > 1 {
+		t.Errorf("elevation gain should match the activity, got %v", gain)
+	}
+
+	sum := 0
+	for _, p := range streams.Power.Data {
+		sum += p
+	}
+	if avg := float64(sum) / float64(n); math.Abs(avg-float64(activity.AveragePower)) > 1 {
+		t.Errorf("average power should match the activity, got %v", avg)
+	}
+
+	if end := streams.Location.Data[n-1]; math.Abs(end[0]-activity.StartLocation[0]) > 0.001 || math.Abs(end[1]-activity.StartLocation[1]) > 0.001 {
+		t.Errorf("route should end at the start, got %v", end)
+	}
+
+	if moving := streams.MovingTime(0); int(moving.Seconds()) != activity.MovingTime {
+		t.Errorf("moving time should match the activity, got %v", moving)
+	}
+
+	if streams.Time.SeriesType != strava.StreamSeriesTypes.Time {
+		t.Errorf("streams should be sampled by time, got %v", streams.Time.SeriesType)
+	}
+
+	// activities without distance, e.g. on a trainer, stay at the start
+	activity.Distance = 0
+	for _, location := range f.Streams(activity).Location.Data {
+		if location != activity.StartLocation {
+			t.Fatalf("location should be the start, got %v", location)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package stravatest
+
+import (
+	"math"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// Streams returns the streams of the activity, one point per second of moving time without pauses.
+// The distance, elevation gain, heart rate, cadence, power and temperature streams average out to
+// the values of the activity, the route is a loop from its start location.
+func (f *Factory) Streams(activity *strava.ActivitySummary) *strava.StreamSet {
+	n := activity.MovingTime + 1
+	if n < 2 {
+		n = 2
+	}
+
+	s := &strava.StreamSet{
+		Time:      &strava.IntegerStream{Stream: f.stream(strava.StreamTypes.Time, n), Data: make([]int, n)},
+		Location:  &strava.LocationStream{Stream: f.stream(strava.StreamTypes.Location, n), Data: make([][2]float64, n)},
+		Distance:  &strava.DecimalStream{Stream: f.stream(strava.StreamTypes.Distance, n), Data: make([]float64, n)},
+		Elevation: &strava.DecimalStream{Stream: f.stream(strava.StreamTypes.Elevation, n), Data: make([]float64, n)},
+		Speed:     &strava.DecimalStream{Stream: f.stream(strava.StreamTypes.Speed, n), Data: make([]float64, n)},
+		Grade:     &strava.DecimalStream{Stream: f.stream(strava.StreamTypes.Grade, n), Data: make([]float64, n)},
+		Moving:    &strava.BooleanStream{Stream: f.stream(strava.StreamTypes.Moving, n), Data: make([]bool, n)},
+	}
+
+	// speed varies around the average, scaled so the distance matches the activity
+	phase := f.between(0, 2*math.Pi)
+	speeds := make([]float64, n)
+	total := 0.0
+	for i := 1; i < n; i++ {
+		speeds[i] = 1 + 0.15*math.Sin(phase+float64(i)/300) + f.between(-0.05, 0.05)
+		total += speeds[i]
+	}
+
+	// elevation follows a few hills, scaled so the gain matches the activity
+	base := f.between(0, 200)
+	profile := make([]float64, n)
+	gain := 0.0
+	for i := range profile {
+		x := float64(i) / float64(n-1) * 2 * math.Pi
+		profile[i] = math.Sin(3*x+phase) + 0.5*math.Sin(7*x)
+		if i > 0 && profile[i] > profile[i-1] {
+			gain += profile[i] - profile[i-1]
+		}
+	}
+
+	elevationScale := 0.0
+	if gain > 0 {
+		elevationScale = float64(activity.TotalElevationGain) / gain
+	}
+
+	radius := float64(activity.Distance) / (2 * math.Pi)
+	start := activity.StartLocation
+	for i := 0; i < n; i++ {
+		s.Time.Data[i] = i
+		s.Moving.Data[i] = true
+
+		if i > 0 {
+			s.Speed.Data[i] = speeds[i] / total * float64(activity.Distance)
+			s.Distance.Data[i] = s.Distance.Data[i-1] + s.Speed.Data[i]
+		}
+		s.Elevation.Data[i] = base + (profile[i]-profile[0])*elevationScale
+
+		if i > 0 && s.Speed.Data[i] > 0 {
+			s.Grade.Data[i] = (s.Elevation.Data[i] - s.Elevation.Data[i-1]) / s.Speed.Data[i] * 100
+		}
+
+		// a circle through the start location, activities without distance stay there
+		north, east := 0.0, 0.0
+		if radius > 0 {
+			angle := s.Distance.Data[i] / radius
+			north = radius * math.Sin(angle)
+			east = radius * (1 - math.Cos(angle))
+		}
+		s.Location.Data[i] = [2]float64{
+			start[0] + north/111320,
+			start[1] + east/(111320*math.Cos(start[0]*math.Pi/180)),
+		}
+	}
+	s.Speed.Data[0] = s.Speed.Data[1]
+
+	if activity.AverageHeartrate > 0 {
+		s.HeartRate = &strava.IntegerStream{Stream: f.stream(strava.StreamTypes.HeartRate, n), Data: f.around(n, activity.AverageHeartrate, 0.08)}
+	}
+
+	if activity.AverageCadence > 0 {
+		s.Cadence = &strava.IntegerStream{Stream: f.stream(strava.StreamTypes.Cadence, n), Data: f.around(n, activity.AverageCadence, 0.05)}
+	}
+
+	if activity.AveragePower > 0 {
+		s.Power = &strava.IntegerStream{Stream: f.stream(strava.StreamTypes.Power, n), Data: f.around(n, float64(activity.AveragePower), 0.25)}
+	}
+
+	if activity.AverageTemperature != 0 {
+		s.Temperature = &strava.IntegerStream{Stream: f.stream(strava.StreamTypes.Temperature, n), Data: f.around(n, float64(activity.AverageTemperature), 0)}
+	}
+
+	return s
+}
+
+func (f *Factory) stream(streamType strava.StreamType, n int) strava.Stream {
+	return strava.Stream{
+		Type:         streamType,
+		SeriesType:   strava.StreamSeriesTypes.Time, // one point per second
+		OriginalSize: n,
+		Resolution:   strava.StreamResolutions.High,
+	}
+}
+
+// around returns n values varying slowly around average by up to the fraction of it,
+// shifted so they average to it.
+func (f *Factory) around(n int, average, fraction float64) []int {
+	values := make([]float64, n)
+	phase := f.between(0, 2*math.Pi)
+	sum := 0.0
+	for i := range values {
+		values[i] = average * (1 + fraction*(0.7*math.Sin(phase+float64(i)/120)+f.between(-0.3, 0.3)))
+		sum += values[i]
+	}
+
+	shift := average - sum/float64(n)
+
+	data := make([]int, n)
+	for i, v := range values {
+		data[i] = int(math.Round(v + shift))
+	}
+
+	return data
+}
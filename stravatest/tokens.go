@@ -0,0 +1,129 @@
+package stravatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+// ExpiresAt returns the time as the ExpiresAt of the tokens, in seconds since the epoch as Strava sends it.
+func ExpiresAt(t time.Time) int64 {
+	return t.Unix()
+}
+
+// A TokenSource is a strava.TokenSource keeping the tokens in memory, for tests of token expiry and refresh:
+//
+//	clock := stravatest.NewClock(time.Now())
+//	tokenSource := stravatest.NewTokenSource(clock, time.Hour)
+//	client := strava.NewClient(tokenSource, strava.WithClock(clock), strava.WithHTTPClient(&http.Client{
+//		Transport: stravatest.NewTokenServer(clock, 6*time.Hour, nil),
+//	}))
+//	clock.Advance(time.Hour - strava.TokenRefreshMargin) // the next call refreshes the token
+type TokenSource struct {
+	lock     sync.Mutex
+	response *strava.AuthorizationResponse
+	saves    int
+}
+
+// NewTokenSource returns a source of tokens that expire after expiresIn by the clock.
+func NewTokenSource(clock *Clock, expiresIn time.Duration) *TokenSource {
+	return &TokenSource{response: &strava.AuthorizationResponse{
+		TokenType:    "Bearer",
+		AccessToken:  "access-0",
+		RefreshToken: "refresh-0",
+		ExpiresAt:    ExpiresAt(clock.Now().Add(expiresIn)),
+		ExpiresIn:    int64(expiresIn / time.Second),
+	}}
+}
+
+func (s *TokenSource) GetAuthorizationResponse() (*strava.AuthorizationResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.response, nil
+}
+
+func (s *TokenSource) SaveAuthorizationResponse(state string, response *strava.AuthorizationResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.response = response
+	s.saves++
+	return nil
+}
+
+// Saves returns how often tokens were saved, e.g. after refreshes.
+func (s *TokenSource) Saves() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.saves
+}
+
+/*********************************************************/
+
+// A TokenServer is an http.RoundTripper that answers the token refreshes of clients with new tokens,
+// access-1 and refresh-1 for the first, that expire after ExpiresIn by the clock. Other requests are passed
+// to the Transport, or answered with an empty json object if it is nil.
+type TokenServer struct {
+	Clock     *Clock
+	ExpiresIn time.Duration
+	Transport http.RoundTripper
+
+	lock      sync.Mutex
+	refreshes int
+}
+
+// NewTokenServer returns a server of tokens that expire after expiresIn by the clock.
+func NewTokenServer(clock *Clock, expiresIn time.Duration, transport http.RoundTripper) *TokenServer {
+	return &TokenServer{Clock: clock, ExpiresIn: expiresIn, Transport: transport}
+}
+
+func (s *TokenServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/oauth/token") {
+		if s.Transport != nil {
+			return s.Transport.RoundTrip(req)
+		}
+
+		return jsonResponse(req, http.StatusOK, map[string]interface{}{}), nil
+	}
+
+	s.lock.Lock()
+	s.refreshes++
+	n := s.refreshes
+	s.lock.Unlock()
+
+	return jsonResponse(req, http.StatusOK, &strava.AuthorizationResponse{
+		TokenType:    "Bearer",
+		AccessToken:  fmt.Sprintf("access-%d", n),
+		RefreshToken: fmt.Sprintf("refresh-%d", n),
+		ExpiresAt:    ExpiresAt(s.Clock.Now().Add(s.ExpiresIn)),
+		ExpiresIn:    int64(s.ExpiresIn / time.Second),
+	}), nil
+}
+
+// Refreshes returns the number of token refreshes answered.
+func (s *TokenServer) Refreshes() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.refreshes
+}
+
+func jsonResponse(req *http.Request, statusCode int, v interface{}) *http.Response {
+	data, _ := json.Marshal(v)
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(data))),
+		Request:    req,
+	}
+}
@@ -0,0 +1,62 @@
+package stravatest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	strava "github.com/caselongo/strava-go"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	clock := NewClock(time.Now())
+	tokenSource := NewTokenSource(clock, time.Hour)
+
+	var auth string
+	server := NewTokenServer(clock, 6*time.Hour, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		auth = req.Header.Get("Authorization")
+		return jsonResponse(req, http.StatusOK, map[string]interface{}{"id": 1}), nil
+	}))
+
+	client := strava.NewClient(tokenSource, strava.WithClock(clock), strava.WithHTTPClient(&http.Client{Transport: server}))
+	call := func() {
+		t.Helper()
+		if err := client.Do(context.Background(), "GET", "/athlete", nil, nil); err != nil {
+			t.Fatalf("call error: %v", err)
+		}
+	}
+
+	// just before the margin
+	clock.Advance(time.Hour - strava.TokenRefreshMargin - time.Second)
+	call()
+	if server.Refreshes() != 0 || auth != "Bearer access-0" {
+		t.Fatalf("token should not be refreshed yet, got %d refreshes and %q", server.Refreshes(), auth)
+	}
+
+	// the token expires within the margin
+	clock.Advance(time.Second)
+	call()
+	if server.Refreshes() != 1 || tokenSource.Saves() != 1 || auth != "Bearer access-1" {
+		t.Fatalf("token should be refreshed and saved, got %d refreshes and %q", server.Refreshes(), auth)
+	}
+
+	// the refreshed token expires 6 hours later
+	clock.Advance(6*time.Hour - strava.TokenRefreshMargin - time.Second)
+	call()
+	if server.Refreshes() != 1 {
+		t.Errorf("refreshed token should not be refreshed yet, got %d refreshes", server.Refreshes())
+	}
+
+	clock.Advance(time.Second)
+	call()
+	if server.Refreshes() != 2 || auth != "Bearer access-2" {
+		t.Errorf("refreshed token should be refreshed, got %d refreshes and %q", server.Refreshes(), auth)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,189 @@
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Chunks gets the streams like Do, but decodes them while the response is read and passes their data
+// to handle in chunks of at most size values, so the whole streams are never held in memory, e.g. on
+// mobile devices. Each chunk is a StreamSet with a single stream, whose Data starts at offset in the stream.
+// Strava sends the series type, original size and resolution of a stream after its data, so the Stream
+// of a chunk may only have its Type. An error of handle stops the call and is returned.
+func (c *streamsGetCall) Chunks(size int, handle func(offset int, chunk *StreamSet) error) error {
+	op, path, err := c.path()
+	if err != nil {
+		return wrapError(err, "%s id=%d", op, c.id)
+	}
+
+	if size <= 0 {
+		return wrapError(fmt.Errorf("invalid chunk size %d", size), "%s id=%d", op, c.id)
+	}
+
+	client := c.service.client
+	ctx, cancel := client.callContext(c.timeout)
+	defer cancel()
+
+	req, err := client.newRequest(ctx, "GET", path, c.ops)
+	if err != nil {
+		return wrapError(err, "%s id=%d", op, c.id)
+	}
+	addHeader(req, c.header)
+
+	_, err = client.execute(req, c.errorHandler, c.response, &streamChunker{size: size, handle: handle})
+	return wrapError(err, "%s id=%d", op, c.id)
+}
+
+// streamChunker decodes the streams of a response in chunks, see Chunks. The body is read
+// as it arrives, like a download, and cached or shared responses are unmarshaled.
+type streamChunker struct {
+	size   int
+	handle func(offset int, chunk *StreamSet) error
+}
+
+func (c *streamChunker) download(resp *http.Response) error {
+	err := c.decode(resp.Body)
+	io.Copy(io.Discard, resp.Body) // so the connection can be reused
+
+	return err
+}
+
+func (c *streamChunker) UnmarshalJSON(data []byte) error {
+	return c.decode(bytes.NewReader(data))
+}
+
+// decode decodes the list of streams.
+func (c *streamChunker) decode(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '['); err != nil {
+		if err == io.EOF {
+			// no streams
+			return nil
+		}
+		return err
+	}
+
+	for dec.More() {
+		if err := c.decodeStream(dec); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+// decodeStream decodes a stream object, passing its data on in chunks. Data received
+// before the type of the stream is held until the type is known.
+func (c *streamChunker) decodeStream(dec *json.Decoder) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var s Stream
+	var pending [][]interface{}
+	offset := 0
+
+	flush := func(values []interface{}) error {
+		if s.Type == "" {
+			pending = append(pending, values)
+			return nil
+		}
+
+		var chunk StreamSet
+		if f := chunk.add(s); f != nil {
+			f.fill(values)
+			if err := c.handle(offset, &chunk); err != nil {
+				return err
+			}
+		}
+
+		offset += len(values)
+		return nil
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "type":
+			err = dec.Decode(&s.Type)
+		case "series_type":
+			err = dec.Decode(&s.SeriesType)
+		case "original_size":
+			err = dec.Decode(&s.OriginalSize)
+		case "resolution":
+			err = dec.Decode(&s.Resolution)
+		case "data":
+			err = c.decodeData(dec, flush)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if s.Type != "" && len(pending) > 0 {
+			held := pending
+			pending = nil
+			for _, values := range held {
+				if err := flush(values); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// decodeData decodes the data of a stream, passing each chunk of values to flush.
+func (c *streamChunker) decodeData(dec *json.Decoder, flush func([]interface{}) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, 0, c.size)
+	for dec.More() {
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+
+		if values = append(values, v); len(values) == c.size {
+			if err := flush(values); err != nil {
+				return err
+			}
+			values = make([]interface{}, 0, c.size)
+		}
+	}
+
+	if len(values) > 0 {
+		if err := flush(values); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token of the decoder, which must be the delimiter.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+
+	return nil
+}
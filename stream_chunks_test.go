@@ -0,0 +1,128 @@
+package strava
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStreamsChunks(t *testing.T) {
+	types := []StreamType{StreamTypes.Time, StreamTypes.Location, StreamTypes.HeartRate, StreamTypes.Moving}
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/activities/123/streams/time,latlng,heartrate,moving": `[
+			{"type":"time","data":[0,1,2,3,4,5,6],"series_type":"distance","original_size":7,"resolution":"high"},
+			{"type":"latlng","data":[[37.1,-122.1],[37.2,-122.2],null,[37.4,-122.4],[37.5,-122.5],[37.6,-122.6],[37.7,-122.7]],"series_type":"distance","original_size":7,"resolution":"high"},
+			{"type":"heartrate","data":[120,121,null,123,124,125,126],"series_type":"distance","original_size":7,"resolution":"high"},
+			{"type":"moving","data":[false,true,true,true,true,true,false],"series_type":"distance","original_size":7,"resolution":"high"}
+		]`,
+	})
+
+	streams, err := NewActivityStreamsService(client).Get(123, types).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	var set StreamSet
+	set.Time = &IntegerStream{}
+	set.Location = &LocationStream{}
+	set.HeartRate = &IntegerStream{}
+	set.Moving = &BooleanStream{}
+
+	err = NewActivityStreamsService(client).Get(123, types).
+		Chunks(3, func(offset int, chunk *StreamSet) error {
+			switch {
+			case chunk.Time != nil:
+				if offset != len(set.Time.Data) || len(chunk.Time.Data) > 3 {
+					t.Errorf("time chunk at %d of %d values, after %d", offset, len(chunk.Time.Data), len(set.Time.Data))
+				}
+
+				if chunk.Time.SeriesType != "" {
+					t.Errorf("metadata after the data should not be known, got %v", chunk.Time.SeriesType)
+				}
+				set.Time.Data = append(set.Time.Data, chunk.Time.Data...)
+			case chunk.Location != nil:
+				set.Location.Data = append(set.Location.Data, chunk.Location.Data...)
+			case chunk.HeartRate != nil:
+				set.HeartRate.Data = append(set.HeartRate.Data, chunk.HeartRate.Data...)
+				set.HeartRate.RawData = append(set.HeartRate.RawData, chunk.HeartRate.RawData...)
+			case chunk.Moving != nil:
+				set.Moving.Data = append(set.Moving.Data, chunk.Moving.Data...)
+			default:
+				t.Errorf("chunk without a stream at %d", offset)
+			}
+			return nil
+		})
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(set.Time.Data) != 7 || !reflect.DeepEqual(set.Time.Data, streams.Time.Data) {
+		t.Errorf("time chunks incorrect, got %v", set.Time.Data)
+	}
+
+	if !reflect.DeepEqual(set.Location.Data, streams.Location.Data) {
+		t.Errorf("location chunks incorrect, got %v", set.Location.Data)
+	}
+
+	if !reflect.DeepEqual(set.HeartRate.Data, streams.HeartRate.Data) || set.HeartRate.RawData[2] != nil {
+		t.Errorf("heartrate chunks incorrect, got %v", set.HeartRate.Data)
+	}
+
+	if !reflect.DeepEqual(set.Moving.Data, streams.Moving.Data) {
+		t.Errorf("moving chunks incorrect, got %v", set.Moving.Data)
+	}
+}
+
+func TestStreamsChunksDataBeforeType(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/activities/123/streams/watts": `[{"data":[100,null,300,400,500],"type":"watts","series_type":"time","original_size":5,"resolution":"high"}]`,
+	})
+
+	var offsets []int
+	var raw []*int
+	err := NewActivityStreamsService(client).Get(123, []StreamType{StreamTypes.Power}).
+		Chunks(2, func(offset int, chunk *StreamSet) error {
+			if chunk.Power == nil || chunk.Power.Type != StreamTypes.Power {
+				t.Fatalf("chunk without the power stream at %d", offset)
+			}
+
+			offsets = append(offsets, offset)
+			raw = append(raw, chunk.Power.RawData...)
+			return nil
+		})
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !reflect.DeepEqual(offsets, []int{0, 2, 4}) {
+		t.Errorf("offsets incorrect, got %v", offsets)
+	}
+
+	if len(raw) != 5 || raw[1] != nil || *raw[4] != 500 {
+		t.Errorf("values incorrect, got %v", raw)
+	}
+}
+
+func TestStreamsChunksHandleError(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/activities/123/streams/time": `[{"type":"time","data":[0,1,2,3,4,5]}]`,
+	})
+
+	stop := errors.New("stop")
+	calls := 0
+	err := NewActivityStreamsService(client).Get(123, []StreamType{StreamTypes.Time}).
+		Chunks(2, func(offset int, chunk *StreamSet) error {
+			calls++
+			return stop
+		})
+
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("handle error not returned, got %v after %d calls", err, calls)
+	}
+
+	if err := NewActivityStreamsService(client).Get(123, []StreamType{StreamTypes.Time}).Chunks(0, nil); err == nil {
+		t.Error("invalid chunk size should return an error")
+	}
+}
@@ -0,0 +1,156 @@
+package strava
+
+import (
+	"time"
+)
+
+// DefaultGapThreshold is the time between two samples above which it is considered
+// a gap in the recording, e.g. the device lost its signal or was switched off.
+// Smart recording devices can take a few seconds between samples, so it is set well above that.
+const DefaultGapThreshold = 30 * time.Second
+
+// An Interval is a period of an activity, defined by the indexes of its first and last sample in the streams.
+// Start and End are the corresponding values of the Time stream, in seconds from the start.
+type Interval struct {
+	StartIndex int
+	EndIndex   int
+	Start      int
+	End        int
+}
+
+// Duration returns the length of the interval.
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i.End-i.Start) * time.Second
+}
+
+// Gaps returns the intervals where the time between two consecutive samples is more than the threshold,
+// if threshold is 0 DefaultGapThreshold is used. Requires the Time stream.
+func (s *StreamSet) Gaps(threshold time.Duration) []Interval {
+	if s.Time == nil {
+		return nil
+	}
+
+	if threshold == 0 {
+		threshold = DefaultGapThreshold
+	}
+
+	var gaps []Interval
+	for i := 1; i < len(s.Time.Data); i++ {
+		if s.isGap(i, threshold) {
+			gaps = append(gaps, s.interval(i-1, i))
+		}
+	}
+
+	return gaps
+}
+
+// Pauses returns the intervals where the athlete wasn't moving, e.g. waiting at a traffic light or
+// auto-paused by the device. Requires the Time and Moving streams.
+// A pause starts at the last moving sample and ends at the last sample not moving.
+func (s *StreamSet) Pauses() []Interval {
+	if s.Time == nil || s.Moving == nil {
+		return nil
+	}
+
+	var pauses []Interval
+	start := -1
+	for i := 1; i < s.len(); i++ {
+		if !s.Moving.Data[i] {
+			if start == -1 {
+				start = i - 1
+			}
+			continue
+		}
+
+		if start != -1 {
+			pauses = append(pauses, s.interval(start, i-1))
+			start = -1
+		}
+	}
+
+	if start != -1 {
+		pauses = append(pauses, s.interval(start, s.len()-1))
+	}
+
+	return pauses
+}
+
+// MovingTime recomputes the moving time from the streams, leaving out the pauses and the gaps
+// longer than threshold. If threshold is 0 DefaultGapThreshold is used. Without a Moving stream
+// only gaps are left out. Requires the Time stream.
+func (s *StreamSet) MovingTime(threshold time.Duration) time.Duration {
+	var seconds int
+	s.eachMoving(threshold, func(i int) {
+		seconds += s.Time.Data[i] - s.Time.Data[i-1]
+	})
+
+	return time.Duration(seconds) * time.Second
+}
+
+// MovingSpeed returns the average speed while moving, leaving out the distance covered during pauses
+// and gaps, e.g. the jump in distance after the device reacquires its signal.
+// Requires the Time and Distance streams.
+func (s *StreamSet) MovingSpeed(threshold time.Duration) Speed {
+	if s.Distance == nil {
+		return 0
+	}
+
+	var distance float64
+	var seconds int
+	s.eachMoving(threshold, func(i int) {
+		distance += s.Distance.Data[i] - s.Distance.Data[i-1]
+		seconds += s.Time.Data[i] - s.Time.Data[i-1]
+	})
+
+	return Distance(distance).Speed(time.Duration(seconds) * time.Second)
+}
+
+// eachMoving calls f for every sample i where the athlete was moving since sample i-1.
+func (s *StreamSet) eachMoving(threshold time.Duration, f func(i int)) {
+	if s.Time == nil {
+		return
+	}
+
+	if threshold == 0 {
+		threshold = DefaultGapThreshold
+	}
+
+	for i := 1; i < s.len(); i++ {
+		if s.isGap(i, threshold) {
+			continue
+		}
+
+		if s.Moving != nil && !s.Moving.Data[i] {
+			continue
+		}
+
+		f(i)
+	}
+}
+
+func (s *StreamSet) isGap(i int, threshold time.Duration) bool {
+	return time.Duration(s.Time.Data[i]-s.Time.Data[i-1])*time.Second > threshold
+}
+
+func (s *StreamSet) interval(start, end int) Interval {
+	return Interval{
+		StartIndex: start,
+		EndIndex:   end,
+		Start:      s.Time.Data[start],
+		End:        s.Time.Data[end],
+	}
+}
+
+// len returns the number of samples available in all of the Time, Moving and Distance streams.
+func (s *StreamSet) len() int {
+	n := len(s.Time.Data)
+	if s.Moving != nil && len(s.Moving.Data) < n {
+		n = len(s.Moving.Data)
+	}
+
+	if s.Distance != nil && len(s.Distance.Data) < n {
+		n = len(s.Distance.Data)
+	}
+
+	return n
+}
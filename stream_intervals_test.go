@@ -0,0 +1,88 @@
+package strava
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newIntervalsStreamSet() *StreamSet {
+	// moving for 3 seconds, stopped for 2, a gap of 60 seconds and moving for 2 more
+	return &StreamSet{
+		Time:     &IntegerStream{Data: []int{0, 1, 2, 3, 4, 5, 65, 66, 67}},
+		Distance: &DecimalStream{Data: []float64{0, 5, 10, 15, 15, 15, 500, 505, 510}},
+		Moving:   &BooleanStream{Data: []bool{false, true, true, true, false, false, true, true, true}},
+	}
+}
+
+func TestStreamSetGaps(t *testing.T) {
+	s := newIntervalsStreamSet()
+
+	gaps := s.Gaps(0)
+	expected := []Interval{{StartIndex: 5, EndIndex: 6, Start: 5, End: 65}}
+	if !reflect.DeepEqual(gaps, expected) {
+		t.Errorf("incorrect gaps, got %v", gaps)
+	}
+
+	if d := gaps[0].Duration(); d != time.Minute {
+		t.Errorf("incorrect duration, got %v", d)
+	}
+
+	if gaps := s.Gaps(2 * time.Minute); len(gaps) != 0 {
+		t.Errorf("incorrect gaps, got %v", gaps)
+	}
+
+	if gaps := (&StreamSet{}).Gaps(0); gaps != nil {
+		t.Errorf("should not return gaps without time stream, got %v", gaps)
+	}
+}
+
+func TestStreamSetPauses(t *testing.T) {
+	s := newIntervalsStreamSet()
+
+	pauses := s.Pauses()
+	expected := []Interval{{StartIndex: 3, EndIndex: 5, Start: 3, End: 5}}
+	if !reflect.DeepEqual(pauses, expected) {
+		t.Errorf("incorrect pauses, got %v", pauses)
+	}
+
+	// stopped until the end
+	s.Moving.Data[8] = false
+	pauses = s.Pauses()
+	expected = append(expected, Interval{StartIndex: 7, EndIndex: 8, Start: 66, End: 67})
+	if !reflect.DeepEqual(pauses, expected) {
+		t.Errorf("incorrect pauses, got %v", pauses)
+	}
+
+	s.Moving = nil
+	if pauses := s.Pauses(); pauses != nil {
+		t.Errorf("should not return pauses without moving stream, got %v", pauses)
+	}
+}
+
+func TestStreamSetMovingTime(t *testing.T) {
+	s := newIntervalsStreamSet()
+
+	if d := s.MovingTime(0); d != 5*time.Second {
+		t.Errorf("incorrect moving time, got %v", d)
+	}
+
+	if speed := s.MovingSpeed(0); speed != 5 {
+		t.Errorf("incorrect moving speed, got %v", speed)
+	}
+
+	// the gap counts if the threshold is large enough
+	if d := s.MovingTime(time.Hour); d != 65*time.Second {
+		t.Errorf("incorrect moving time, got %v", d)
+	}
+
+	// without moving stream only the gap is left out
+	s.Moving = nil
+	if d := s.MovingTime(0); d != 7*time.Second {
+		t.Errorf("incorrect moving time, got %v", d)
+	}
+
+	if d := (&StreamSet{}).MovingTime(0); d != 0 {
+		t.Errorf("incorrect moving time, got %v", d)
+	}
+}
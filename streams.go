@@ -1,9 +1,12 @@
 package strava
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 // A StreamSet is a collection of possible streams for an Activity, Segment or SegmentEffort.
@@ -62,10 +65,10 @@ type BooleanStream struct {
 // A streams for a given object are the same length. For every time in the Time stream
 // there will be corresponding information in all the other available streams.
 type Stream struct {
-	Type         StreamType `json:"type"`
-	SeriesType   string     `json:"series_type"`
-	OriginalSize int        `json:"original_size"`
-	Resolution   string     `json:"resolution"`
+	Type         StreamType       `json:"type"`
+	SeriesType   StreamSeriesType `json:"series_type"`
+	OriginalSize int              `json:"original_size"`
+	Resolution   StreamResolution `json:"resolution"`
 }
 
 type StreamType string
@@ -85,6 +88,60 @@ var StreamTypes = struct {
 }{"time", "latlng", "distance", "altitude", "velocity_smooth", "heartrate",
 	"cadence", "watts", "temp", "moving", "grade_smooth"}
 
+// StreamResolution is the requested number of data points of a stream.
+// Streams are downsampled by Strava to at most 100 (low), 1000 (medium)
+// or 10000 (high) points. If no resolution is requested all points are returned.
+type StreamResolution string
+
+var StreamResolutions = struct {
+	Low    StreamResolution
+	Medium StreamResolution
+	High   StreamResolution
+}{"low", "medium", "high"}
+
+// StreamSeriesType is the base series used when downsampling a stream.
+type StreamSeriesType string
+
+var StreamSeriesTypes = struct {
+	Time     StreamSeriesType
+	Distance StreamSeriesType
+}{"time", "distance"}
+
+// Points returns the maximum number of points of a stream at this resolution,
+// or 0 if the resolution is unknown.
+func (r StreamResolution) Points() int {
+	switch r {
+	case StreamResolutions.Low:
+		return 100
+	case StreamResolutions.Medium:
+		return 1000
+	case StreamResolutions.High:
+		return 10000
+	}
+
+	return 0
+}
+
+// ResolutionFor returns the lowest resolution that can hold the given number of samples
+// without downsampling them. Larger sample counts are capped at the high resolution.
+func ResolutionFor(samples int) StreamResolution {
+	switch {
+	case samples <= StreamResolutions.Low.Points():
+		return StreamResolutions.Low
+	case samples <= StreamResolutions.Medium.Points():
+		return StreamResolutions.Medium
+	}
+
+	return StreamResolutions.High
+}
+
+// ResolutionForActivity picks a resolution based on the length of the activity,
+// assuming it was recorded at one sample per second. Requesting streams at this
+// resolution avoids downloading more points than the activity has to offer.
+func ResolutionForActivity(activity *ActivitySummary) StreamResolution {
+	return ResolutionFor(activity.ElapsedTime)
+}
+
 type filler interface {
 	fill([]interface{})
 }
@@ -133,10 +190,14 @@ type SegmentEffortStreamsGetCall struct {
 }
 
 type streamsGetCall struct {
-	service streamsService
-	id      int64
-	types   []StreamType
-	ops     map[string]interface{}
+	service      streamsService
+	id           int64
+	types        []StreamType
+	ops          map[string]interface{}
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 /*********************************************************/
@@ -154,16 +215,43 @@ func (s *ActivityStreamsService) Get(activityId int64, types []StreamType) *Acti
 	return call
 }
 
-func (c *ActivityStreamsGetCall) Resolution(resolution string) *ActivityStreamsGetCall {
+func (c *ActivityStreamsGetCall) Resolution(resolution StreamResolution) *ActivityStreamsGetCall {
 	c.ops["resolution"] = resolution
 	return c
 }
 
-func (c *ActivityStreamsGetCall) SeriesType(seriesType string) *ActivityStreamsGetCall {
+func (c *ActivityStreamsGetCall) SeriesType(seriesType StreamSeriesType) *ActivityStreamsGetCall {
 	c.ops["series_type"] = seriesType
 	return c
 }
 
+func (c *ActivityStreamsGetCall) OnError(handler ErrorHandler) *ActivityStreamsGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *ActivityStreamsGetCall) Response(resp *Response) *ActivityStreamsGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *ActivityStreamsGetCall) Timeout(d time.Duration) *ActivityStreamsGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *ActivityStreamsGetCall) Header(key, value string) *ActivityStreamsGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 /*********************************************************/
 
 func (s *SegmentStreamsService) Get(segmentId int64, types []StreamType) *SegmentStreamsGetCall {
@@ -178,16 +266,43 @@ func (s *SegmentStreamsService) Get(segmentId int64, types []StreamType) *Segmen
 	return call
 }
 
-func (c *SegmentStreamsGetCall) Resolution(resolution string) *SegmentStreamsGetCall {
+func (c *SegmentStreamsGetCall) Resolution(resolution StreamResolution) *SegmentStreamsGetCall {
 	c.ops["resolution"] = resolution
 	return c
 }
 
-func (c *SegmentStreamsGetCall) SeriesType(seriesType string) *SegmentStreamsGetCall {
+func (c *SegmentStreamsGetCall) SeriesType(seriesType StreamSeriesType) *SegmentStreamsGetCall {
 	c.ops["series_type"] = seriesType
 	return c
 }
 
+func (c *SegmentStreamsGetCall) OnError(handler ErrorHandler) *SegmentStreamsGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *SegmentStreamsGetCall) Response(resp *Response) *SegmentStreamsGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *SegmentStreamsGetCall) Timeout(d time.Duration) *SegmentStreamsGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *SegmentStreamsGetCall) Header(key, value string) *SegmentStreamsGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 /*********************************************************/
 
 func (s *SegmentEffortStreamsService) Get(segmentEffortId int64, types []StreamType) *SegmentEffortStreamsGetCall {
@@ -202,19 +317,47 @@ func (s *SegmentEffortStreamsService) Get(segmentEffortId int64, types []StreamT
 	return call
 }
 
-func (c *SegmentEffortStreamsGetCall) Resolution(resolution string) *SegmentEffortStreamsGetCall {
+func (c *SegmentEffortStreamsGetCall) Resolution(resolution StreamResolution) *SegmentEffortStreamsGetCall {
 	c.ops["resolution"] = resolution
 	return c
 }
 
-func (c *SegmentEffortStreamsGetCall) SeriesType(seriesType string) *SegmentEffortStreamsGetCall {
+func (c *SegmentEffortStreamsGetCall) SeriesType(seriesType StreamSeriesType) *SegmentEffortStreamsGetCall {
 	c.ops["series_type"] = seriesType
 	return c
 }
 
+func (c *SegmentEffortStreamsGetCall) OnError(handler ErrorHandler) *SegmentEffortStreamsGetCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *SegmentEffortStreamsGetCall) Response(resp *Response) *SegmentEffortStreamsGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *SegmentEffortStreamsGetCall) Timeout(d time.Duration) *SegmentEffortStreamsGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *SegmentEffortStreamsGetCall) Header(key, value string) *SegmentEffortStreamsGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 /*********************************************************/
 
-func (c *streamsGetCall) Do() (*StreamSet, error) {
+// path returns the name of the parent type, for errors, and the path of the streams.
+func (c *streamsGetCall) path() (string, string, error) {
 	var source string
 	switch c.service.parentType {
 	case types.Activity:
@@ -226,11 +369,11 @@ func (c *streamsGetCall) Do() (*StreamSet, error) {
 	}
 
 	if source == "" {
-		return nil, errors.New("invalid stream parent type")
+		return "streams.get", "", errors.New("invalid stream parent type")
 	}
 
 	if len(c.types) == 0 {
-		return nil, errors.New("no streamtypes requested")
+		return source + ".streams", "", errors.New("no streamtypes requested")
 	}
 
 	types := string(c.types[0])
@@ -238,87 +381,148 @@ func (c *streamsGetCall) Do() (*StreamSet, error) {
 		types += "," + string(c.types[i])
 	}
 
-	path := fmt.Sprintf("/%s/%d/streams/%s", source, c.id, types)
-	data, err := c.service.client.run("GET", path, c.ops)
+	return source + ".streams", fmt.Sprintf("/%s/%d/streams/%s", source, c.id, types), nil
+}
 
+func (c *streamsGetCall) Do() (*StreamSet, error) {
+	return c.do(context.Background())
+}
+
+// do runs the call with the context, e.g. of the caller of ActivityBundle.Streams.
+func (c *streamsGetCall) do(ctx context.Context) (*StreamSet, error) {
+	op, path, err := c.path()
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err, "%s id=%d", op, c.id)
 	}
 
-	var set StreamSet
-
-	streams := make([]interface{}, 0, 10)
-	json.Unmarshal(data, &streams)
-
-	for _, stream := range streams {
-		m := stream.(map[string]interface{})
-
-		var base interface{}
-		var s Stream
-		s.Type = StreamType(m["type"].(string))
-		s.SeriesType = m["series_type"].(string)
-		s.OriginalSize = int(m["original_size"].(float64))
-		s.Resolution = m["resolution"].(string)
-
-		switch StreamType(m["type"].(string)) {
-		case StreamTypes.Time:
-			set.Time = &IntegerStream{s, nil, nil}
-			base = set.Time
-
-		case StreamTypes.Location:
-			set.Location = &LocationStream{s, nil}
-			base = set.Location
+	streams, err := runAndDecodeContext[[]map[string]interface{}](ctx, c.service.client, "GET", path, c.ops, c.errorHandler, c.response, c.timeout, c.header)
+	if err != nil {
+		return nil, wrapError(err, "%s id=%d", op, c.id)
+	}
 
-		case StreamTypes.Distance:
-			set.Distance = &DecimalStream{s, nil, nil}
-			base = set.Distance
+	var set StreamSet
+	for _, m := range streams {
+		s, data, err := decodeStream(m)
+		if err != nil {
+			return nil, wrapError(err, "%s id=%d", op, c.id)
+		}
 
-		case StreamTypes.Elevation:
-			set.Elevation = &DecimalStream{s, nil, nil}
-			base = set.Elevation
+		if f := set.add(s); f != nil {
+			f.fill(data)
+		}
+	}
 
-		case StreamTypes.Speed:
-			set.Speed = &DecimalStream{s, nil, nil}
-			base = set.Speed
+	return &set, nil
+}
 
-		case StreamTypes.HeartRate:
-			set.HeartRate = &IntegerStream{s, nil, nil}
-			base = set.HeartRate
+// decodeStream returns the stream and the data of a stream in a response. The type and data are required,
+// the other fields are left zero if they are missing.
+func decodeStream(m map[string]interface{}) (Stream, []interface{}, error) {
+	var s Stream
 
-		case StreamTypes.Cadence:
-			set.Cadence = &IntegerStream{s, nil, nil}
-			base = set.Cadence
+	streamType, ok := m["type"].(string)
+	if !ok {
+		return s, nil, fmt.Errorf("invalid stream: type %v", m["type"])
+	}
+	s.Type = StreamType(streamType)
 
-		case StreamTypes.Power:
-			set.Power = &IntegerStream{s, nil, nil}
-			base = set.Power
+	data, ok := m["data"].([]interface{})
+	if !ok {
+		return s, nil, fmt.Errorf("invalid %s stream: data is not a list", streamType)
+	}
 
-		case StreamTypes.Temperature:
-			set.Temperature = &IntegerStream{s, nil, nil}
-			base = set.Temperature
+	if v, ok := m["series_type"]; ok {
+		seriesType, ok := v.(string)
+		if !ok {
+			return s, nil, fmt.Errorf("invalid %s stream: series_type %v", streamType, v)
+		}
+		s.SeriesType = StreamSeriesType(seriesType)
+	}
 
-		case StreamTypes.Moving:
-			set.Moving = &BooleanStream{s, nil}
-			base = set.Moving
+	if v, ok := m["original_size"]; ok {
+		originalSize, ok := v.(float64)
+		if !ok {
+			return s, nil, fmt.Errorf("invalid %s stream: original_size %v", streamType, v)
+		}
+		s.OriginalSize = int(originalSize)
+	}
 
-		case StreamTypes.Grade:
-			set.Grade = &DecimalStream{s, nil, nil}
-			base = set.Grade
+	if v, ok := m["resolution"]; ok {
+		resolution, ok := v.(string)
+		if !ok {
+			return s, nil, fmt.Errorf("invalid %s stream: resolution %v", streamType, v)
 		}
+		s.Resolution = StreamResolution(resolution)
+	}
 
-		f := base.(filler)
-		f.fill(m["data"].([]interface{}))
+	return s, data, nil
+}
 
+// add sets the stream of the set with the type of s, and returns it to be filled,
+// or nil if the type is unknown.
+func (set *StreamSet) add(s Stream) filler {
+	switch s.Type {
+	case StreamTypes.Time:
+		set.Time = &IntegerStream{s, nil, nil}
+		return set.Time
+
+	case StreamTypes.Location:
+		set.Location = &LocationStream{s, nil}
+		return set.Location
+
+	case StreamTypes.Distance:
+		set.Distance = &DecimalStream{s, nil, nil}
+		return set.Distance
+
+	case StreamTypes.Elevation:
+		set.Elevation = &DecimalStream{s, nil, nil}
+		return set.Elevation
+
+	case StreamTypes.Speed:
+		set.Speed = &DecimalStream{s, nil, nil}
+		return set.Speed
+
+	case StreamTypes.HeartRate:
+		set.HeartRate = &IntegerStream{s, nil, nil}
+		return set.HeartRate
+
+	case StreamTypes.Cadence:
+		set.Cadence = &IntegerStream{s, nil, nil}
+		return set.Cadence
+
+	case StreamTypes.Power:
+		set.Power = &IntegerStream{s, nil, nil}
+		return set.Power
+
+	case StreamTypes.Temperature:
+		set.Temperature = &IntegerStream{s, nil, nil}
+		return set.Temperature
+
+	case StreamTypes.Moving:
+		set.Moving = &BooleanStream{s, nil}
+		return set.Moving
+
+	case StreamTypes.Grade:
+		set.Grade = &DecimalStream{s, nil, nil}
+		return set.Grade
 	}
 
-	return &set, nil
+	return nil
 }
 
+// fill sets the points of the stream, points that aren't a latitude and longitude are left zero, like null points.
 func (s *LocationStream) fill(data []interface{}) {
 	s.Data = make([][2]float64, len(data))
 	for i, v := range data {
-		if l, ok := v.([]interface{}); ok {
-			s.Data[i] = [2]float64{l[0].(float64), l[1].(float64)}
+		l, ok := v.([]interface{})
+		if !ok || len(l) < 2 {
+			continue
+		}
+
+		lat, latOk := l[0].(float64)
+		lng, lngOk := l[1].(float64)
+		if latOk && lngOk {
+			s.Data[i] = [2]float64{lat, lng}
 		}
 	}
 }
@@ -354,3 +558,86 @@ func (s *BooleanStream) fill(data []interface{}) {
 		}
 	}
 }
+
+/*********************************************************/
+
+// streamJSON is the json form of a stream, the format returned by Strava,
+// with nil values as null. Streams are encoded in this form, so a decoded StreamSet
+// can be stored as json and loaded again, including its RawData.
+type streamJSON struct {
+	Stream
+	Data []interface{} `json:"data"`
+}
+
+func (s *LocationStream) MarshalJSON() ([]byte, error) {
+	data := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		data[i] = v
+	}
+
+	return json.Marshal(streamJSON{s.Stream, data})
+}
+
+func (s *LocationStream) UnmarshalJSON(b []byte) error {
+	return unmarshalStream(b, &s.Stream, s)
+}
+
+// MarshalJSON encodes the stream with the values missing from RawData as null.
+// If RawData is nil all values are encoded.
+func (s *IntegerStream) MarshalJSON() ([]byte, error) {
+	data := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		if s.RawData == nil || s.RawData[i] != nil {
+			data[i] = v
+		}
+	}
+
+	return json.Marshal(streamJSON{s.Stream, data})
+}
+
+func (s *IntegerStream) UnmarshalJSON(b []byte) error {
+	return unmarshalStream(b, &s.Stream, s)
+}
+
+// MarshalJSON encodes the stream with the values missing from RawData as null.
+// If RawData is nil all values are encoded.
+func (s *DecimalStream) MarshalJSON() ([]byte, error) {
+	data := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		if s.RawData == nil || s.RawData[i] != nil {
+			data[i] = v
+		}
+	}
+
+	return json.Marshal(streamJSON{s.Stream, data})
+}
+
+func (s *DecimalStream) UnmarshalJSON(b []byte) error {
+	return unmarshalStream(b, &s.Stream, s)
+}
+
+func (s *BooleanStream) MarshalJSON() ([]byte, error) {
+	data := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		data[i] = v
+	}
+
+	return json.Marshal(streamJSON{s.Stream, data})
+}
+
+func (s *BooleanStream) UnmarshalJSON(b []byte) error {
+	return unmarshalStream(b, &s.Stream, s)
+}
+
+// unmarshalStream decodes the json form of a stream into stream and the data of f.
+func unmarshalStream(b []byte, stream *Stream, f filler) error {
+	var s streamJSON
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*stream = s.Stream
+	f.fill(s.Data)
+
+	return nil
+}
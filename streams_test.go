@@ -19,7 +19,7 @@ func TestActivityStreamsGet(t *testing.T) {
 		StreamTypes.Grade,
 	}
 
-	client := newCassetteClient(testToken, "activity_stream")
+	client := newFixtureClient(t, "activity_stream")
 	streams, err := NewActivityStreamsService(client).
 		Get(103221154, types).Resolution("medium").Do()
 
@@ -374,3 +374,56 @@ func TestStreamsBadSource(t *testing.T) {
 		t.Error("should have returned error")
 	}
 }
+
+func TestStreamsInvalid(t *testing.T) {
+	cases := []string{
+		`[{"series_type":"distance","data":[0]}]`,
+		`[{"type":"time","series_type":"distance"}]`,
+		`[{"type":"time","series_type":"distance","original_size":"1","data":[0]}]`,
+		`[{"type":"time","series_type":1,"data":[0]}]`,
+		`[{"type":"time","resolution":false,"data":[0]}]`,
+	}
+
+	for _, c := range cases {
+		if _, err := NewActivityStreamsService(NewStubResponseClient(c)).Get(123, []StreamType{StreamTypes.Time}).Do(); err == nil {
+			t.Errorf("should return error for %s", c)
+		}
+	}
+
+	// points that aren't a latitude and longitude are left zero
+	streams, err := NewActivityStreamsService(NewStubResponseClient(`[{"type":"latlng","data":[[41.9,2.8],[41.9],null,["a",2.8]]}]`)).
+		Get(123, []StreamType{StreamTypes.Location}).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(streams.Location.Data) != 4 || streams.Location.Data[0] != [2]float64{41.9, 2.8} || streams.Location.Data[1] != [2]float64{} || streams.Location.Data[3] != [2]float64{} {
+		t.Errorf("incorrect points, got %v", streams.Location.Data)
+	}
+}
+
+func TestResolutionFor(t *testing.T) {
+	if r := ResolutionFor(0); r != StreamResolutions.Low {
+		t.Errorf("incorrect resolution, got %v", r)
+	}
+
+	if r := ResolutionFor(100); r != StreamResolutions.Low {
+		t.Errorf("incorrect resolution, got %v", r)
+	}
+
+	if r := ResolutionFor(101); r != StreamResolutions.Medium {
+		t.Errorf("incorrect resolution, got %v", r)
+	}
+
+	if r := ResolutionFor(50000); r != StreamResolutions.High {
+		t.Errorf("incorrect resolution, got %v", r)
+	}
+
+	if r := ResolutionForActivity(&ActivitySummary{ElapsedTime: 900}); r != StreamResolutions.Medium {
+		t.Errorf("incorrect resolution, got %v", r)
+	}
+
+	if p := StreamResolution("unknown").Points(); p != 0 {
+		t.Errorf("incorrect points, got %d", p)
+	}
+}
@@ -0,0 +1,116 @@
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithStrictJSON makes the calls of the client fail with a *StrictJSONError when a response has fields
+// its model doesn't have, or lacks fields the model requires, such as the ids of activities and athletes.
+// Integration tests can use it to catch Strava adding or renaming fields, which other clients
+// silently decode as zero values. Not meant for production, a new field of Strava breaks the calls.
+func WithStrictJSON() Option {
+	return func(c *Client) {
+		c.strictJSON = true
+	}
+}
+
+// A StrictJSONError is returned by the calls of a client created WithStrictJSON for a response not matching its model.
+type StrictJSONError struct {
+	Unknown string   // a field of the response the model doesn't have, e.g. "moving_time"
+	Missing []string // required fields missing from the response, e.g. "[2].athlete.id"
+}
+
+func (e *StrictJSONError) Error() string {
+	if e.Unknown != "" {
+		return fmt.Sprintf("strict json: unknown field %q", e.Unknown)
+	}
+
+	return "strict json: missing required fields " + strings.Join(e.Missing, ", ")
+}
+
+// decodeStrict unmarshals the data into v, failing on unknown fields and missing required fields,
+// the fields tagged `strava:"required"`.
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return &StrictJSONError{Unknown: strings.Trim(field, `"`)}
+		}
+
+		return err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if missing := missingFields(value, reflect.TypeOf(v), ""); len(missing) > 0 {
+		return &StrictJSONError{Missing: missing}
+	}
+
+	return nil
+}
+
+// missingFields returns the paths of the required fields of t missing from the decoded json value,
+// looking into the objects and lists of the value the fields of t are decoded from.
+func missingFields(value interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var missing []string
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, _ := value.([]interface{})
+		for i, item := range items {
+			missing = append(missing, missingFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.Anonymous && name == "" {
+				// the fields of embedded structs are in the same object
+				missing = append(missing, missingFields(value, field.Type, path)...)
+				continue
+			}
+
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+
+			fieldValue, ok := object[name]
+			if !ok {
+				if field.Tag.Get("strava") == "required" {
+					missing = append(missing, fieldPath)
+				}
+				continue
+			}
+
+			missing = append(missing, missingFields(fieldValue, field.Type, fieldPath)...)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,60 @@
+package strava
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestModelsStrictJSON(t *testing.T) {
+	for _, model := range models {
+		original := reflect.New(reflect.TypeOf(model).Elem())
+		fillModel(original, 0)
+
+		data, err := json.Marshal(original.Interface())
+		if err != nil {
+			t.Fatalf("%T: marshal error: %v", model, err)
+		}
+
+		if err := decodeStrict(data, reflect.New(reflect.TypeOf(model).Elem()).Interface()); err != nil {
+			t.Errorf("%T: encoded model should decode strictly, got %v", model, err)
+		}
+	}
+}
+
+func TestClientStrictJSON(t *testing.T) {
+	routes := map[string]string{
+		"/api/v3/athlete":            `{"id":1,"firstname":"Jane","pronouns":"she/her"}`,
+		"/api/v3/athlete/activities": `[{"id":1,"athlete":{"id":2}},{"name":"Morning Ride","athlete":{}}]`,
+	}
+
+	client, _ := newRouteClient(routes)
+	WithStrictJSON()(client)
+
+	_, err := NewCurrentAthleteService(client).Get().Do()
+
+	var strictErr *StrictJSONError
+	if !errors.As(err, &strictErr) || strictErr.Unknown != "pronouns" {
+		t.Errorf("should fail on the unknown field, got %v", err)
+	}
+
+	_, err = NewCurrentAthleteService(client).ListActivities().Do()
+	if !errors.As(err, &strictErr) || !reflect.DeepEqual(strictErr.Missing, []string{"[1].id", "[1].athlete.id"}) {
+		t.Errorf("should fail on the missing ids, got %v", err)
+	}
+
+	if !client.ClientOptionsSnapshot().StrictJSON {
+		t.Error("options should show strict json")
+	}
+
+	// other clients decode the same responses
+	client, _ = newRouteClient(routes)
+	if _, err := NewCurrentAthleteService(client).Get().Do(); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+
+	if _, err := NewCurrentAthleteService(client).ListActivities().Do(); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Subscribed returns if the athlete has a Strava subscription, needed for calls such as
+// SegmentsService.ListEfforts and ActivitiesService.ListZones.
+func (a *AthleteSummary) Subscribed() bool {
+	return a.Summit || a.Premium
+}
+
+// subscriptionOperations are the calls that only return data for athletes with a subscription.
+var subscriptionOperations = map[string]bool{
+	"activities.list_zones":    true,
+	"segment_efforts.get":      true,
+	"segments.get_leaderboard": true,
+	"segments.list_efforts":    true,
+}
+
+// RequiresSubscription returns if a call, identified by its operation as shown in its errors,
+// only works for athletes with a subscription, e.g. "segments.list_efforts".
+func RequiresSubscription(operation string) bool {
+	return subscriptionOperations[operation]
+}
+
+// A SubscriptionRequiredError is returned by calls that need a subscription the athlete doesn't have.
+// Strava responds with 402 Payment Required, clients created WithSubscription(false) return it
+// before a request is made. Check for it with IsSubscriptionRequired to leave out the feature.
+type SubscriptionRequiredError struct {
+	Operation string // empty if the error was returned by Strava for an endpoint unknown to this package
+	Endpoint  string
+}
+
+func (e *SubscriptionRequiredError) Error() string {
+	if e.Operation == "" {
+		return fmt.Sprintf("%s requires a subscription", e.Endpoint)
+	}
+
+	return fmt.Sprintf("%s requires a subscription", e.Operation)
+}
+
+// IsSubscriptionRequired returns if err, or an error it wraps, is a *SubscriptionRequiredError.
+func IsSubscriptionRequired(err error) bool {
+	var e *SubscriptionRequiredError
+	return errors.As(err, &e)
+}
+
+// WithSubscription tells the client if the athlete has a subscription, see AthleteSummary.Subscribed.
+// Without one, calls that need it fail fast with a *SubscriptionRequiredError instead of using a request.
+func WithSubscription(subscribed bool) Option {
+	return func(c *Client) {
+		c.unsubscribed = !subscribed
+	}
+}
+
+// checkSubscription returns a *SubscriptionRequiredError if the request needs a subscription
+// and the client was created WithSubscription(false).
+func (client *Client) checkSubscription(method, path string) error {
+	if !client.unsubscribed {
+		return nil
+	}
+
+	operation := operationOf(method, path)
+	if !RequiresSubscription(operation) {
+		return nil
+	}
+
+	return &SubscriptionRequiredError{Operation: operation, Endpoint: path}
+}
+
+// subscriptionErrorHandler returns a *SubscriptionRequiredError for 402 responses to requests to the path,
+// relative to the base url, and leaves other responses to the handler.
+func subscriptionErrorHandler(handler ErrorHandler, method, path string) ErrorHandler {
+	return func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusPaymentRequired {
+			return handler(resp)
+		}
+
+		return &SubscriptionRequiredError{Operation: operationOf(method, path), Endpoint: path}
+	}
+}
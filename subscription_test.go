@@ -0,0 +1,73 @@
+package strava
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestAthleteSubscribed(t *testing.T) {
+	var athlete AthleteSummary
+	json.Unmarshal([]byte(`{"id":1,"summit":true}`), &athlete)
+
+	if !athlete.Subscribed() {
+		t.Error("athlete should be subscribed")
+	}
+
+	athlete = AthleteSummary{Premium: true}
+	if !athlete.Subscribed() {
+		t.Error("premium athletes should be subscribed")
+	}
+
+	if (&AthleteSummary{}).Subscribed() {
+		t.Error("athlete should not be subscribed")
+	}
+}
+
+func TestSubscriptionRequired(t *testing.T) {
+	client := NewStubResponseClient(`{"message":"Payment Required","errors":[]}`, http.StatusPaymentRequired)
+	client.tokenSource = newStubTokenSource()
+
+	_, err := NewSegmentsService(client).ListEfforts(1).Do()
+	if !IsSubscriptionRequired(err) {
+		t.Fatalf("should return subscription required error, got %v", err)
+	}
+
+	if err.Error() != "strava: segments.list_efforts id=1: segments.list_efforts requires a subscription" {
+		t.Errorf("incorrect error, got %v", err)
+	}
+
+	// other errors are left to the error handler
+	client = NewStubResponseClient(`{"message":"Record Not Found","errors":[]}`, http.StatusNotFound)
+	client.tokenSource = newStubTokenSource()
+
+	_, err = NewSegmentsService(client).ListEfforts(1).Do()
+	if err == nil || IsSubscriptionRequired(err) {
+		t.Errorf("should return the error of the handler, got %v", err)
+	}
+}
+
+func TestClientWithSubscription(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/segments/1/all_efforts": `[]`,
+		"/api/v3/segments/1":             `{"id":1}`,
+	})
+	WithSubscription(false)(client)
+
+	if _, err := NewSegmentsService(client).ListEfforts(1).Do(); !IsSubscriptionRequired(err) {
+		t.Errorf("should return subscription required error, got %v", err)
+	}
+
+	if _, err := NewSegmentsService(client).Get(1).Do(); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Errorf("no request should be made without a subscription, got %v requests", len(transport.requests))
+	}
+
+	WithSubscription(true)(client)
+	if _, err := NewSegmentsService(client).ListEfforts(1).Do(); err != nil {
+		t.Errorf("service error: %v", err)
+	}
+}
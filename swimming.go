@@ -0,0 +1,96 @@
+package strava
+
+import (
+	"math"
+	"time"
+)
+
+// A SwimLength is a single length of the pool, found in the streams of a pool swim.
+type SwimLength struct {
+	Interval
+	Strokes int           // estimated from the cadence stream, 0 if not available
+	Pace    time.Duration // per 100 meters
+	SWOLF   int           // seconds plus strokes, 0 if the strokes are not available
+}
+
+// SwimPace returns the time per 100 meters, or per 100 yards if per is strava.Yard,
+// averaged over the moving time of the lap.
+func (l *LapEffortSummary) SwimPace(per Distance) time.Duration {
+	if l.MovingTime <= 0 {
+		return 0
+	}
+
+	return l.Distance.Speed(time.Duration(l.MovingTime) * time.Second).Pace(100 * per)
+}
+
+// Strokes returns the number of strokes in the lap, estimated from the average cadence,
+// which Strava reports in strokes per minute for swims.
+func (l *LapEffortSummary) Strokes() int {
+	return int(math.Round(l.AverageCadence * float64(l.MovingTime) / 60))
+}
+
+// SWOLF returns the average swim golf score per length of the lap, the seconds plus the strokes
+// it takes to swim a length. Lower is more efficient. Returns 0 if the lap has no cadence,
+// which is the case for swims recorded without stroke data.
+func (l *LapEffortSummary) SWOLF(poolLength Distance) float64 {
+	strokes := l.Strokes()
+	if strokes == 0 || poolLength <= 0 || l.Distance <= 0 {
+		return 0
+	}
+
+	lengths := float64(l.Distance / poolLength)
+	return (float64(l.MovingTime) + float64(strokes)) / lengths
+}
+
+// SwimLengths splits a pool swim into lengths where the Distance stream passes a multiple of the pool length.
+// The strokes, and so the SWOLF, of a length are only available with the Cadence stream.
+// Requires the Time and Distance streams, returns nil otherwise.
+func (s *StreamSet) SwimLengths(poolLength Distance) []SwimLength {
+	if s.Time == nil || s.Distance == nil || poolLength <= 0 {
+		return nil
+	}
+
+	n := len(s.Time.Data)
+	if len(s.Distance.Data) < n {
+		n = len(s.Distance.Data)
+	}
+
+	var lengths []SwimLength
+	start := 0
+	for i := 1; i < n; i++ {
+		completed := int(s.Distance.Data[i]/float64(poolLength)) - int(s.Distance.Data[start]/float64(poolLength))
+		if completed < 1 {
+			continue
+		}
+
+		lengths = append(lengths, s.swimLength(start, i, poolLength, completed))
+		start = i
+	}
+
+	return lengths
+}
+
+// swimLength builds the length between the samples, if the pool was passed more than once
+// between them, the values are averaged over the lengths.
+func (s *StreamSet) swimLength(start, end int, poolLength Distance, count int) SwimLength {
+	length := SwimLength{Interval: s.interval(start, end)}
+
+	duration := length.Duration() / time.Duration(count)
+	length.Pace = poolLength.Speed(duration).Pace(100 * Meter)
+
+	if s.Cadence == nil || end >= len(s.Cadence.Data) {
+		return length
+	}
+
+	var strokes float64
+	for i := start + 1; i <= end; i++ {
+		strokes += float64(s.Cadence.Data[i]) / 60 * float64(s.Time.Data[i]-s.Time.Data[i-1])
+	}
+
+	length.Strokes = int(math.Round(strokes / float64(count)))
+	if length.Strokes > 0 {
+		length.SWOLF = int(duration.Seconds()) + length.Strokes
+	}
+
+	return length
+}
@@ -0,0 +1,65 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLapEffortSummarySwim(t *testing.T) {
+	lap := &LapEffortSummary{AverageCadence: 30}
+	lap.Distance = 100
+	lap.MovingTime = 100
+
+	if p := lap.SwimPace(Meter); p != 100*time.Second {
+		t.Errorf("incorrect pace, got %v", p)
+	}
+
+	if p := lap.SwimPace(Yard); p != 91440*time.Millisecond {
+		t.Errorf("incorrect pace, got %v", p)
+	}
+
+	if s := lap.Strokes(); s != 50 {
+		t.Errorf("incorrect strokes, got %v", s)
+	}
+
+	// 4 lengths of 25 meters in 25 seconds and 12.5 strokes each
+	if s := lap.SWOLF(25); s != 37.5 {
+		t.Errorf("incorrect swolf, got %v", s)
+	}
+
+	lap.AverageCadence = 0
+	if s := lap.SWOLF(25); s != 0 {
+		t.Errorf("should not have swolf without strokes, got %v", s)
+	}
+}
+
+func TestStreamSetSwimLengths(t *testing.T) {
+	s := &StreamSet{
+		Time:     &IntegerStream{Data: []int{0, 10, 20, 30, 40, 50, 70}},
+		Distance: &DecimalStream{Data: []float64{0, 12, 25, 38, 50, 55, 100}},
+		Cadence:  &IntegerStream{Data: []int{0, 60, 60, 60, 60, 30, 30}},
+	}
+
+	lengths := s.SwimLengths(25)
+	if len(lengths) != 3 {
+		t.Fatalf("incorrect number of lengths, got %v", lengths)
+	}
+
+	if l := lengths[0]; l.StartIndex != 0 || l.EndIndex != 2 || l.Pace != 80*time.Second || l.Strokes != 20 || l.SWOLF != 40 {
+		t.Errorf("incorrect first length, got %+v", l)
+	}
+
+	// two lengths between the last samples are averaged
+	if l := lengths[2]; l.StartIndex != 4 || l.EndIndex != 6 || l.Pace != 60*time.Second || l.Strokes != 8 || l.SWOLF != 23 {
+		t.Errorf("incorrect last length, got %+v", l)
+	}
+
+	s.Cadence = nil
+	if l := s.SwimLengths(25)[0]; l.Strokes != 0 || l.SWOLF != 0 || l.Pace != 80*time.Second {
+		t.Errorf("incorrect length without cadence, got %+v", l)
+	}
+
+	if (&StreamSet{}).SwimLengths(25) != nil {
+		t.Error("should require time and distance streams")
+	}
+}
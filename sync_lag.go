@@ -0,0 +1,46 @@
+package strava
+
+import (
+	"time"
+)
+
+// SyncLag is how long it took for a webhook event to be synced locally, to alert on a sync falling behind.
+type SyncLag struct {
+	Delivery time.Duration // from the time of the event to its receipt, zero if the event has no time
+	Sync     time.Duration // from the receipt of the event to the completed sync
+}
+
+// Total returns the time from the event to the completed sync.
+func (l SyncLag) Total() time.Duration {
+	return l.Delivery + l.Sync
+}
+
+// A SyncLagObserver receives the SyncLag of synced webhook events. Metrics implementing it, passed to
+// WebhookDispatcher.Metrics, are passed the lag of the events it dispatched, e.g. for Prometheus:
+//
+//	func (m promMetrics) ObserveSyncLag(objectType strava.WebhookObjectType, aspectType strava.WebhookAspectType, lag strava.SyncLag) {
+//		m.syncLag.WithLabelValues(string(objectType), string(aspectType)).Observe(lag.Sync.Seconds())
+//	}
+type SyncLagObserver interface {
+	ObserveSyncLag(objectType WebhookObjectType, aspectType WebhookAspectType, lag SyncLag)
+}
+
+// SyncLag returns the lag of the event synced at syncedAt, for events synced without a WebhookDispatcher.
+// Events without ReceivedAt count as received at syncedAt.
+func (e *WebhookEvent) SyncLag(syncedAt time.Time) SyncLag {
+	receivedAt := e.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = syncedAt
+	}
+
+	return newSyncLag(e, receivedAt, syncedAt)
+}
+
+func newSyncLag(event *WebhookEvent, receivedAt, syncedAt time.Time) SyncLag {
+	lag := SyncLag{Sync: syncedAt.Sub(receivedAt)}
+	if event.EventTime != 0 && receivedAt.After(event.Time()) {
+		lag.Delivery = receivedAt.Sub(event.Time())
+	}
+
+	return lag
+}
@@ -0,0 +1,83 @@
+package strava
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookEventSyncLag(t *testing.T) {
+	received := time.Date(2024, 5, 1, 8, 0, 10, 0, time.UTC)
+	event := &WebhookEvent{EventTime: received.Add(-10 * time.Second).Unix(), ReceivedAt: received}
+
+	lag := event.SyncLag(received.Add(time.Minute))
+	if lag.Delivery != 10*time.Second || lag.Sync != time.Minute || lag.Total() != 70*time.Second {
+		t.Errorf("incorrect lag, got %+v", lag)
+	}
+
+	// events without a time or receipt
+	lag = (&WebhookEvent{}).SyncLag(received)
+	if lag != (SyncLag{}) {
+		t.Errorf("incorrect lag, got %+v", lag)
+	}
+
+	event, err := ParseWebhookEvent(strings.NewReader(`{"object_type":"activity","aspect_type":"create","object_id":1}`), nil)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if time.Since(event.ReceivedAt) > time.Minute {
+		t.Errorf("parsed events should be received now, got %v", event.ReceivedAt)
+	}
+
+	clock := newFakeClock()
+	event, _ = ParseWebhookEvent(strings.NewReader(`{"object_type":"activity","aspect_type":"create","object_id":1}`), clock)
+	if !event.ReceivedAt.Equal(clock.Now()) {
+		t.Errorf("parsed events should be received at the time of the clock, got %v", event.ReceivedAt)
+	}
+}
+
+// lagRecorder is Metrics recording the sync lags.
+type lagRecorder struct {
+	recordingMetrics
+	lags []SyncLag
+}
+
+func (r *lagRecorder) ObserveSyncLag(objectType WebhookObjectType, aspectType WebhookAspectType, lag SyncLag) {
+	r.lags = append(r.lags, lag)
+}
+
+func TestWebhookDispatcherSyncLag(t *testing.T) {
+	fail := false
+	observer := &lagRecorder{}
+	clock := newFakeClock()
+	dispatcher := NewWebhookDispatcher(nil, func(delivery *WebhookDelivery) error {
+		if fail {
+			return errors.New("sync failed")
+		}
+		return nil
+	}).Metrics(observer).Clock(clock)
+
+	deleted := &WebhookEvent{
+		ObjectType: WebhookObjectTypes.Activity,
+		AspectType: WebhookAspectTypes.Delete,
+		ReceivedAt: clock.Now().Add(-time.Minute),
+	}
+	if err := dispatcher.Dispatch(deleted); err != nil {
+		t.Fatalf("dispatch error: %v", err)
+	}
+
+	if len(observer.lags) != 1 || observer.lags[0].Sync != time.Minute {
+		t.Errorf("lag should be observed from the receipt, got %v", observer.lags)
+	}
+
+	fail = true
+	if err := dispatcher.Dispatch(deleted); err == nil {
+		t.Fatal("dispatch should fail")
+	}
+
+	if len(observer.lags) != 1 {
+		t.Errorf("failed syncs should not be observed, got %v", observer.lags)
+	}
+}
@@ -0,0 +1,165 @@
+package strava
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Thresholds are the training thresholds of an athlete for a sport, used to score activities.
+// Values that don't apply to the sport, like the FTP of a runner, are left zero.
+type Thresholds struct {
+	FTP           Power // functional threshold power
+	LTHR          int   // lactate threshold heart rate, in beats per minute
+	ThresholdPace Speed // the pace that can be held for about an hour, as a speed
+}
+
+// AthleteSettings holds the history of the thresholds and weight of an athlete.
+// Each value is effective from its date until the next one, so historical activities
+// are scored against the thresholds valid at the time.
+// Strava only returns the current FTP and weight, see NewAthleteSettingsFromAthlete.
+// Safe for concurrent use.
+type AthleteSettings struct {
+	lock       sync.RWMutex
+	thresholds map[ActivityType][]thresholdsEntry
+	weights    []weightEntry
+}
+
+type thresholdsEntry struct {
+	effective  time.Time
+	thresholds Thresholds
+}
+
+type weightEntry struct {
+	effective time.Time
+	weight    float64
+}
+
+func NewAthleteSettings() *AthleteSettings {
+	return &AthleteSettings{
+		thresholds: make(map[ActivityType][]thresholdsEntry),
+	}
+}
+
+// NewAthleteSettingsFromAthlete creates settings with the FTP and weight of the athlete for all time,
+// the FTP for all cycling sports, see ActivityType.IsCycling. Further history can be added with SetThresholds and SetWeight.
+func NewAthleteSettingsFromAthlete(athlete *AthleteDetailed) *AthleteSettings {
+	s := NewAthleteSettings()
+
+	if athlete.FTP != 0 {
+		for _, sport := range cyclingTypes {
+			s.SetThresholds(sport, time.Time{}, Thresholds{FTP: Power(athlete.FTP)})
+		}
+	}
+
+	if athlete.Weight != 0 {
+		s.SetWeight(time.Time{}, athlete.Weight)
+	}
+
+	return s
+}
+
+// SetThresholds sets the thresholds of the sport from the effective date on.
+// Sports are stored by their BaseType, so the thresholds of Ride also apply to MountainBikeRide.
+// Setting thresholds for an existing date replaces them.
+func (s *AthleteSettings) SetThresholds(sport ActivityType, effective time.Time, thresholds Thresholds) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sport = sport.BaseType()
+	entries := s.thresholds[sport]
+
+	i := sort.Search(len(entries), func(i int) bool { return !entries[i].effective.Before(effective) })
+	if i < len(entries) && entries[i].effective.Equal(effective) {
+		entries[i].thresholds = thresholds
+		return
+	}
+
+	entries = append(entries, thresholdsEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = thresholdsEntry{effective, thresholds}
+
+	s.thresholds[sport] = entries
+}
+
+// SetWeight sets the weight, in kilograms, from the effective date on.
+func (s *AthleteSettings) SetWeight(effective time.Time, weight float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := sort.Search(len(s.weights), func(i int) bool { return !s.weights[i].effective.Before(effective) })
+	if i < len(s.weights) && s.weights[i].effective.Equal(effective) {
+		s.weights[i].weight = weight
+		return
+	}
+
+	s.weights = append(s.weights, weightEntry{})
+	copy(s.weights[i+1:], s.weights[i:])
+	s.weights[i] = weightEntry{effective, weight}
+}
+
+// Thresholds returns the thresholds of the sport effective at the given time,
+// false if none were set before that time.
+func (s *AthleteSettings) Thresholds(sport ActivityType, at time.Time) (Thresholds, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	entries := s.thresholds[sport.BaseType()]
+
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].effective.After(at) })
+	if i == 0 {
+		return Thresholds{}, false
+	}
+
+	return entries[i-1].thresholds, true
+}
+
+// Weight returns the weight, in kilograms, effective at the given time, 0 if none was set before that time.
+func (s *AthleteSettings) Weight(at time.Time) float64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	i := sort.Search(len(s.weights), func(i int) bool { return s.weights[i].effective.After(at) })
+	if i == 0 {
+		return 0
+	}
+
+	return s.weights[i-1].weight
+}
+
+// ThresholdsFor returns the thresholds for the sport of the activity effective at its start.
+func (s *AthleteSettings) ThresholdsFor(activity *ActivitySummary) (Thresholds, bool) {
+	sport := activity.SportType
+	if sport == "" {
+		sport = activity.Type
+	}
+
+	return s.Thresholds(sport, activity.StartDate)
+}
+
+// IntensityFactor returns the weighted average power of the activity relative to the FTP at the time,
+// 0 if the activity has no power or no FTP was set.
+func (s *AthleteSettings) IntensityFactor(activity *ActivitySummary) float64 {
+	thresholds, ok := s.ThresholdsFor(activity)
+	if !ok || thresholds.FTP == 0 {
+		return 0
+	}
+
+	power := activity.WeightedAveragePower
+	if power == 0 {
+		power = activity.AveragePower
+	}
+
+	return float64(power) / float64(thresholds.FTP)
+}
+
+// PowerPerKilogram returns the weighted average power of the activity divided by the weight at the time,
+// 0 if the activity has no power or no weight was set.
+func (s *AthleteSettings) PowerPerKilogram(activity *ActivitySummary) float64 {
+	power := activity.WeightedAveragePower
+	if power == 0 {
+		power = activity.AveragePower
+	}
+
+	return power.PerKilogram(s.Weight(activity.StartDate))
+}
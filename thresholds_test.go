@@ -0,0 +1,90 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAthleteSettingsThresholds(t *testing.T) {
+	s := NewAthleteSettings()
+
+	jan := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	// set out of order
+	s.SetThresholds(ActivityTypes.Ride, jun, Thresholds{FTP: 280})
+	s.SetThresholds(ActivityTypes.Ride, jan, Thresholds{FTP: 250})
+	s.SetThresholds(ActivityTypes.Run, jan, Thresholds{LTHR: 170, ThresholdPace: 4})
+
+	if _, ok := s.Thresholds(ActivityTypes.Ride, jan.Add(-time.Hour)); ok {
+		t.Error("should not have thresholds before the first date")
+	}
+
+	if th, _ := s.Thresholds(ActivityTypes.Ride, jan); th.FTP != 250 {
+		t.Errorf("incorrect ftp, got %v", th.FTP)
+	}
+
+	if th, _ := s.Thresholds(ActivityTypes.MountainBikeRide, jun.Add(-time.Hour)); th.FTP != 250 {
+		t.Errorf("incorrect ftp, got %v", th.FTP)
+	}
+
+	if th, _ := s.Thresholds(ActivityTypes.Ride, jun.Add(time.Hour)); th.FTP != 280 {
+		t.Errorf("incorrect ftp, got %v", th.FTP)
+	}
+
+	if th, _ := s.Thresholds(ActivityTypes.TrailRun, jun); th.LTHR != 170 || th.FTP != 0 {
+		t.Errorf("incorrect run thresholds, got %v", th)
+	}
+
+	if _, ok := s.Thresholds(ActivityTypes.Swim, jun); ok {
+		t.Error("should not have swim thresholds")
+	}
+
+	// replace
+	s.SetThresholds(ActivityTypes.Ride, jun, Thresholds{FTP: 290})
+	if th, _ := s.Thresholds(ActivityTypes.Ride, jun); th.FTP != 290 {
+		t.Errorf("incorrect ftp, got %v", th.FTP)
+	}
+}
+
+func TestAthleteSettingsActivity(t *testing.T) {
+	s := NewAthleteSettingsFromAthlete(&AthleteDetailed{FTP: 250, Weight: 80})
+
+	jun := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+	s.SetWeight(jun, 75)
+
+	activity := &ActivitySummary{
+		Type:                 ActivityTypes.Ride,
+		StartDate:            jun.Add(-time.Hour),
+		WeightedAveragePower: 200,
+	}
+
+	if f := s.IntensityFactor(activity); f != 0.8 {
+		t.Errorf("incorrect intensity factor, got %v", f)
+	}
+
+	if w := s.PowerPerKilogram(activity); w != 2.5 {
+		t.Errorf("incorrect power per kilogram, got %v", w)
+	}
+
+	activity.StartDate = jun
+	activity.WeightedAveragePower = 0
+	activity.AveragePower = 150
+	if w := s.PowerPerKilogram(activity); w != 2 {
+		t.Errorf("incorrect power per kilogram, got %v", w)
+	}
+
+	// the ftp applies to all rides
+	activity.WeightedAveragePower = 200
+	for _, sport := range []ActivityType{ActivityTypes.VirtualRide, ActivityTypes.EBikeRide, ActivityTypes.GravelRide, ActivityTypes.MountainBikeRide, ActivityTypes.EMountainBikeRide} {
+		activity.SportType = sport
+		if f := s.IntensityFactor(activity); f != 0.8 {
+			t.Errorf("%s should have intensity factor, got %v", sport, f)
+		}
+	}
+
+	activity.SportType = ActivityTypes.Run
+	if f := s.IntensityFactor(activity); f != 0 {
+		t.Errorf("should not have intensity factor without ftp, got %v", f)
+	}
+}
@@ -0,0 +1,48 @@
+package strava
+
+import (
+	"fmt"
+	"time"
+)
+
+// CallTiming is the breakdown of the time spent on a call, to tell if the limiter or Strava is the bottleneck.
+// It is logged with every request and passed to Metrics that implement TimingObserver.
+type CallTiming struct {
+	TokenValidation time.Duration // getting the token from the TokenSource and refreshing it if expired
+	RateLimitWait   time.Duration // waiting for a turn, see WithRequestDelay and WithMaxConcurrency, or the end of maintenance
+	Network         time.Duration // the request and reading the response, up to its headers if it is decoded while read
+	Decode          time.Duration // decoding the response into the returned models, reading the body too if decoded while read
+}
+
+// Total returns the time of the call.
+func (t CallTiming) Total() time.Duration {
+	return t.TokenValidation + t.RateLimitWait + t.Network + t.Decode
+}
+
+// String returns the total and the breakdown, e.g. "152ms: token 0s, wait 0s, network 150ms, decode 2ms".
+func (t CallTiming) String() string {
+	round := func(d time.Duration) time.Duration { return d.Round(time.Millisecond) }
+	return fmt.Sprintf("%s: token %s, wait %s, network %s, decode %s",
+		round(t.Total()), round(t.TokenValidation), round(t.RateLimitWait), round(t.Network), round(t.Decode))
+}
+
+// A TimingObserver receives the timing of every call that got a response. Metrics implementing it,
+// see WithMetrics, are passed the timings too.
+type TimingObserver interface {
+	ObserveTiming(labels MetricLabels, method, endpoint string, timing CallTiming)
+}
+
+// callRecord is what is known about a call while it is made.
+type callRecord struct {
+	SpanAttributes
+	labels    MetricLabels
+	timing    CallTiming
+	responded bool      // false if the request failed or was not made
+	response  *Response // filled when the call responded, if not nil
+}
+
+func (client *Client) observeTiming(record *callRecord) {
+	if observer, ok := client.metrics.(TimingObserver); ok {
+		observer.ObserveTiming(record.labels, record.Method, record.Endpoint, record.timing)
+	}
+}
@@ -0,0 +1,61 @@
+package strava
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type timingMetrics struct {
+	recordingMetrics
+	timings []CallTiming
+}
+
+func (m *timingMetrics) ObserveTiming(labels MetricLabels, method, endpoint string, timing CallTiming) {
+	m.timings = append(m.timings, timing)
+}
+
+func TestClientCallTiming(t *testing.T) {
+	metrics := &timingMetrics{}
+	logger := &recordingLogger{}
+
+	client, _ := newRouteClient(map[string]string{"/api/v3/clubs/1": `{"id":1}`})
+	WithMetrics(metrics)(client)
+	WithLogger(logger)(client)
+	WithRequestDelay(time.Hour, 0)(client)
+	WithClock(newFakeClock())(client)
+
+	NewClubsService(client).Get(1).Do()
+	NewClubsService(client).Get(1).Do()
+
+	if len(metrics.timings) != 2 {
+		t.Fatalf("timing should be observed for every call, got %v", metrics.timings)
+	}
+
+	if metrics.timings[0].RateLimitWait != 0 || metrics.timings[1].RateLimitWait < time.Hour-time.Second {
+		t.Errorf("incorrect wait, got %v", metrics.timings)
+	}
+
+	if timing := metrics.timings[1]; timing.Total() != timing.TokenValidation+timing.RateLimitWait+timing.Network+timing.Decode {
+		t.Errorf("incorrect total, got %v", timing)
+	}
+
+	if !strings.Contains(logger.lines[1], "token") || !strings.Contains(logger.lines[1], "wait 1h") || !strings.Contains(logger.lines[1], "decode") {
+		t.Errorf("breakdown should be logged, got %v", logger.lines[1])
+	}
+
+	// requests that are not made are not observed
+	WithScopeCheck(GrantedScopes{ScopeRead})(client)
+	NewActivitiesService(client).Get(1).Do()
+
+	if len(metrics.timings) != 2 {
+		t.Errorf("timing should only be observed for requests that were made, got %v", metrics.timings)
+	}
+}
+
+func TestCallTimingString(t *testing.T) {
+	timing := CallTiming{TokenValidation: time.Millisecond, Network: 150 * time.Millisecond, Decode: 2 * time.Millisecond}
+	if s := timing.String(); s != "153ms: token 1ms, wait 0s, network 150ms, decode 2ms" {
+		t.Errorf("incorrect string, got %v", s)
+	}
+}
@@ -1,6 +1,120 @@
 package strava
 
+import (
+	"errors"
+	"sync"
+)
+
+// A TokenSource stores the tokens of the athlete of a client. The state passed to SaveAuthorizationResponse
+// is the one of the authorization, empty for refreshes. See AthleteTokenSource for the tokens of many athletes.
 type TokenSource interface {
 	GetAuthorizationResponse() (*AuthorizationResponse, error)
 	SaveAuthorizationResponse(string, *AuthorizationResponse) error
 }
+
+/*********************************************************/
+
+// An AthleteTokenSource stores the tokens of many athletes, by the id of their athlete, for applications
+// with many users. Make clients for the athletes with a ClientFactory.
+type AthleteTokenSource interface {
+	// GetTokens returns the tokens of the athlete, an error wrapping ErrNoTokens if there are none.
+	GetTokens(athleteId int64) (*AuthorizationResponse, error)
+	SaveTokens(athleteId int64, response *AuthorizationResponse) error
+}
+
+// ErrNoTokens is returned for athletes whose tokens are not stored, e.g. before they connected the application.
+var ErrNoTokens = errors.New("no tokens stored for the athlete")
+
+// TokenSourceFor returns the tokens of the athlete in the source as a TokenSource, e.g. for NewClient.
+// Tokens are saved for the athlete they were issued to, if the response holds it, as the response of
+// a token exchange does, so TokenSourceFor(source, 0) saves the tokens of NewOAuthAuthenticator for any athlete.
+func TokenSourceFor(source AthleteTokenSource, athleteId int64) TokenSource {
+	return &athleteTokenSource{source: source, athleteId: athleteId}
+}
+
+type athleteTokenSource struct {
+	source    AthleteTokenSource
+	athleteId int64
+}
+
+func (s *athleteTokenSource) GetAuthorizationResponse() (*AuthorizationResponse, error) {
+	response, err := s.source.GetTokens(s.athleteId)
+	if err != nil || response.Athlete != nil || s.athleteId == 0 {
+		return response, err
+	}
+
+	// sources may not store the athlete, the tokens are known to be of the athlete
+	withAthlete := *response
+	withAthlete.Athlete = &AthleteDetailed{}
+	withAthlete.Athlete.Id = s.athleteId
+	return &withAthlete, nil
+}
+
+func (s *athleteTokenSource) SaveAuthorizationResponse(state string, response *AuthorizationResponse) error {
+	athleteId := s.athleteId
+	if response.Athlete != nil && response.Athlete.Id != 0 {
+		athleteId = response.Athlete.Id
+	}
+
+	return s.source.SaveTokens(athleteId, response)
+}
+
+// MemoryTokenSource is an AthleteTokenSource keeping the tokens in memory, e.g. for tests and tools.
+type MemoryTokenSource struct {
+	lock   sync.Mutex
+	tokens map[int64]*AuthorizationResponse
+}
+
+// NewMemoryTokenSource returns an empty MemoryTokenSource.
+func NewMemoryTokenSource() *MemoryTokenSource {
+	return &MemoryTokenSource{tokens: make(map[int64]*AuthorizationResponse)}
+}
+
+func (s *MemoryTokenSource) GetTokens(athleteId int64) (*AuthorizationResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	response, ok := s.tokens[athleteId]
+	if !ok {
+		return nil, ErrNoTokens
+	}
+
+	return response, nil
+}
+
+func (s *MemoryTokenSource) SaveTokens(athleteId int64, response *AuthorizationResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tokens[athleteId] = response
+	return nil
+}
+
+/*********************************************************/
+
+// A ClientFactory makes the clients of the athletes of an AthleteTokenSource, so applications with many users
+// don't need a TokenSource of their own for each athlete:
+//
+//	factory := strava.NewClientFactory(tokens, strava.WithCredentials(clientId, clientSecret))
+//	activities, err := strava.NewCurrentAthleteService(factory.ClientFor(athleteId)).ListActivities().Do()
+//
+// The clients share the rate limits, request delay, Cache and ETagStore of the options, as copies made
+// with Client.With do.
+type ClientFactory struct {
+	source AthleteTokenSource
+	base   *Client
+}
+
+// NewClientFactory returns a factory of clients configured with the options.
+func NewClientFactory(source AthleteTokenSource, options ...Option) *ClientFactory {
+	return &ClientFactory{source: source, base: NewClient(nil, options...)}
+}
+
+// ClientFor returns a client with the tokens of the athlete, refreshed tokens are saved for the athlete.
+func (f *ClientFactory) ClientFor(athleteId int64) *Client {
+	c := f.base.With()
+	c.tokenSource = TokenSourceFor(f.source, athleteId)
+	c.revoked = &revokedToken{}
+
+	return c
+}
@@ -0,0 +1,54 @@
+package strava
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClientFactory(t *testing.T) {
+	tokens := NewMemoryTokenSource()
+	tokens.SaveTokens(1, &AuthorizationResponse{AccessToken: "one", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	tokens.SaveTokens(2, &AuthorizationResponse{AccessToken: "expired", RefreshToken: "refresh"})
+
+	transport := &routeTransport{routes: map[string]string{
+		"/api/v3/athlete":     `{"id":1}`,
+		"/api/v3/oauth/token": `{"access_token":"two","refresh_token":"refresh2"}`,
+	}}
+	factory := NewClientFactory(tokens, WithHTTPClient(&http.Client{Transport: transport}), WithUserAgent("app"))
+
+	for _, athleteId := range []int64{1, 2} {
+		if _, err := NewCurrentAthleteService(factory.ClientFor(athleteId)).Get().Do(); err != nil {
+			t.Fatalf("service error for athlete %d: %v", athleteId, err)
+		}
+	}
+
+	auths := []string{transport.requests[0].Header.Get("Authorization"), transport.requests[2].Header.Get("Authorization")}
+	if auths[0] != "Bearer one" || auths[1] != "Bearer two" || transport.requests[2].Header.Get("User-Agent") != "app" {
+		t.Errorf("requests should use the tokens of the athletes and the options, got %v", auths)
+	}
+
+	if refreshed, _ := tokens.GetTokens(2); refreshed.AccessToken != "two" || refreshed.Athlete == nil || refreshed.Athlete.Id != 2 {
+		t.Errorf("refreshed tokens should be saved for the athlete, got %+v", refreshed)
+	}
+
+	if _, err := NewCurrentAthleteService(factory.ClientFor(3)).Get().Do(); !errors.Is(err, ErrNoTokens) {
+		t.Errorf("athletes without tokens should fail, got %v", err)
+	}
+}
+
+func TestTokenSourceFor(t *testing.T) {
+	tokens := NewMemoryTokenSource()
+
+	// the tokens of an exchange are saved for their athlete
+	source := TokenSourceFor(tokens, 0)
+	exchanged := &AuthorizationResponse{AccessToken: "abc", Athlete: &AthleteDetailed{AthleteSummary: AthleteSummary{AthleteMeta: AthleteMeta{Id: 5}}}}
+	if err := source.SaveAuthorizationResponse("state", exchanged); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	if response, err := TokenSourceFor(tokens, 5).GetAuthorizationResponse(); err != nil || response != exchanged {
+		t.Errorf("tokens should be saved for the athlete, got %v, %v", response, err)
+	}
+}
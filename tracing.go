@@ -0,0 +1,100 @@
+package strava
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// A Tracer starts a span for every api call of a client, see WithTracer. The interface follows
+// OpenTelemetry, without depending on it, an adapter is a few lines:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string) (context.Context, strava.Span) {
+//		ctx, span := t.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End(attributes strava.SpanAttributes, err error) {
+//		for key, value := range attributes.Map() {
+//			s.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//		}
+//		if err != nil {
+//			s.RecordError(err)
+//			s.SetStatus(codes.Error, strava.Redact(err.Error()))
+//		}
+//		s.Span.End()
+//	}
+type Tracer interface {
+	// StartSpan starts a span named after the method and endpoint template of the call, e.g. "GET /activities/{id}".
+	// The returned context is used for the request, so spans of http transports become children of the span.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// A Span is ended when its call returns, with the error returned by the call, if any.
+type Span interface {
+	End(attributes SpanAttributes, err error)
+}
+
+// SpanAttributes describe a traced call.
+type SpanAttributes struct {
+	Method        string
+	Endpoint      string        // path relative to the base url with ids replaced, e.g. /activities/{id}/streams/{types}
+	StatusCode    int           // 0 if there was no response
+	Retries       int           // requests made after the first one, see WithMaintenanceRetry
+	RateLimitWait time.Duration // time spent waiting for a turn or the end of maintenance before the requests were made
+}
+
+// Map returns the attributes with the keys of the OpenTelemetry semantic conventions where they exist.
+func (a SpanAttributes) Map() map[string]interface{} {
+	return map[string]interface{}{
+		"http.request.method":       a.Method,
+		"http.response.status_code": a.StatusCode,
+		"http.request.resend_count": a.Retries,
+		"strava.endpoint":           a.Endpoint,
+		"strava.rate_limit_wait_ms": a.RateLimitWait.Milliseconds(),
+	}
+}
+
+// WithTracer makes the client trace its api calls, see Tracer. Token refreshes are not traced.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// endpointSegments are the variable parts of paths, replaced to group the spans of an endpoint.
+var endpointSegments = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`/streams/[^/]+$`), "/streams/{types}"},
+	{regexp.MustCompile(`^/gear/[^/]+$`), "/gear/{id}"},
+	{regexp.MustCompile(`/\d+(/|$)`), "/{id}$1"},
+}
+
+// endpointTemplate returns the path, relative to the base url, with ids and stream types replaced.
+func endpointTemplate(path string) string {
+	for _, s := range endpointSegments {
+		path = s.pattern.ReplaceAllString(path, s.replacement)
+	}
+
+	return path
+}
+
+// noopSpan is the span of clients without a Tracer.
+type noopSpan struct{}
+
+func (noopSpan) End(SpanAttributes, error) {}
+
+// startSpan starts the span of a call, the returned context should be used for its request.
+func (client *Client) startSpan(ctx context.Context, method, endpoint string) (context.Context, Span) {
+	if client.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return client.tracer.StartSpan(ctx, method+" "+endpoint)
+}
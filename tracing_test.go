@@ -0,0 +1,87 @@
+package strava
+
+import (
+	"context"
+	"testing"
+)
+
+type tracingKey struct{}
+
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name       string
+	attributes SpanAttributes
+	err        error
+	ended      bool
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, tracingKey{}, span), span
+}
+
+func (s *recordingSpan) End(attributes SpanAttributes, err error) {
+	s.attributes, s.err, s.ended = attributes, err, true
+}
+
+func TestClientWithTracer(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/activities/1": `{"id":1}`,
+	})
+
+	tracer := &recordingTracer{}
+	WithTracer(tracer)(client)
+
+	if _, err := NewActivitiesService(client).Get(1).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if _, err := NewActivitiesService(client).Get(2).Do(); err == nil {
+		t.Fatal("should return an error for an unknown activity")
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("should start a span per call, got %d", len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if span.name != "GET /activities/{id}" || !span.ended || span.err != nil {
+		t.Errorf("incorrect span, got %+v", span)
+	}
+
+	if span.attributes != (SpanAttributes{Method: "GET", Endpoint: "/activities/{id}", StatusCode: 200}) {
+		t.Errorf("incorrect attributes, got %+v", span.attributes)
+	}
+
+	if transport.requests[0].Context().Value(tracingKey{}) != span {
+		t.Error("request should use the context of the span")
+	}
+
+	span = tracer.spans[1]
+	if span.attributes.StatusCode != 404 || span.err == nil {
+		t.Errorf("span should record the failure, got %+v", span)
+	}
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	paths := map[string]string{
+		"/athlete":                            "/athlete",
+		"/activities/123":                     "/activities/{id}",
+		"/activities/123/comments/456":        "/activities/{id}/comments/{id}",
+		"/activities/123/streams/time,latlng": "/activities/{id}/streams/{types}",
+		"/segment_efforts/123/streams/watts":  "/segment_efforts/{id}/streams/{types}",
+		"/gear/b12345":                        "/gear/{id}",
+		"/segments/explore":                   "/segments/explore",
+		"/athletes/123/stats":                 "/athletes/{id}/stats",
+	}
+
+	for path, expected := range paths {
+		if template := endpointTemplate(path); template != expected {
+			t.Errorf("incorrect template of %s, got %s", path, template)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package strava
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TrainingStress returns the training stress score of the activity, where an hour at threshold scores 100.
+// It is based on the power if the activity has power and an FTP was set for the sport, then on the pace
+// relative to the threshold pace, then on the heart rate relative to the LTHR. Returns 0 if none of these
+// are available.
+func (s *AthleteSettings) TrainingStress(activity *ActivitySummary) float64 {
+	thresholds, ok := s.ThresholdsFor(activity)
+	if !ok {
+		return 0
+	}
+
+	hours := float64(activity.MovingTime) / 3600
+
+	var intensity float64
+	switch {
+	case thresholds.FTP > 0 && (activity.WeightedAveragePower > 0 || activity.AveragePower > 0):
+		intensity = s.IntensityFactor(activity)
+	case thresholds.ThresholdPace > 0 && activity.AverageSpeed > 0:
+		intensity = float64(activity.AverageSpeed / thresholds.ThresholdPace)
+	case thresholds.LTHR > 0 && activity.AverageHeartrate > 0:
+		intensity = activity.AverageHeartrate / float64(thresholds.LTHR)
+	}
+
+	return hours * intensity * intensity * 100
+}
+
+/*********************************************************/
+
+// A TrainingLoadDay is the training load at the end of a day.
+type TrainingLoadDay struct {
+	Date   time.Time // midnight UTC of the local date of the activities
+	Stress float64   // total training stress of the day
+	CTL    float64   // chronic training load, or fitness
+	ATL    float64   // acute training load, or fatigue
+	TSB    float64   // training stress balance, or form, the CTL minus the ATL of the day before
+}
+
+// TrainingLoad keeps the training stress of an activity history and calculates the chronic and acute
+// training load and form from it. Activities can be added, updated and removed as they arrive,
+// e.g. from webhook events. The calculated series is kept, a change only recalculates it from the date
+// of the activity on, so adding the activity of today only calculates today.
+// Safe for concurrent use.
+type TrainingLoad struct {
+	ChronicDays float64 // time constant of the CTL, defaults to 42
+	AcuteDays   float64 // time constant of the ATL, defaults to 7
+
+	settings *AthleteSettings
+
+	lock       sync.Mutex
+	activities map[int64]time.Time             // date of the activities
+	days       map[time.Time]map[int64]float64 // stress of the activities of a date
+	series     []TrainingLoadDay               // calculated days, from the first date with activities
+	constants  [2]float64                      // ChronicDays and AcuteDays of the series
+}
+
+func NewTrainingLoad(settings *AthleteSettings) *TrainingLoad {
+	return &TrainingLoad{
+		ChronicDays: 42,
+		AcuteDays:   7,
+		settings:    settings,
+		activities:  make(map[int64]time.Time),
+		days:        make(map[time.Time]map[int64]float64),
+	}
+}
+
+// Add adds the activity to the history, replacing it if it was already added.
+func (l *TrainingLoad) Add(activity *ActivitySummary) {
+	date := localDate(activity.StartDateLocal)
+	stress := l.settings.TrainingStress(activity)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.remove(activity.Id)
+
+	if l.days[date] == nil {
+		l.days[date] = make(map[int64]float64)
+	}
+	l.days[date][activity.Id] = stress
+	l.activities[activity.Id] = date
+	l.recalculateFrom(date)
+}
+
+// Remove removes the activity from the history, e.g. when it was deleted on Strava.
+func (l *TrainingLoad) Remove(activityId int64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.remove(activityId)
+}
+
+func (l *TrainingLoad) remove(activityId int64) {
+	date, ok := l.activities[activityId]
+	if !ok {
+		return
+	}
+
+	delete(l.activities, activityId)
+	delete(l.days[date], activityId)
+	if len(l.days[date]) == 0 {
+		delete(l.days, date)
+	}
+	l.recalculateFrom(date)
+}
+
+// recalculateFrom drops the calculated days from the date on, or all of them if the date
+// is on or before the first day, which may have changed.
+func (l *TrainingLoad) recalculateFrom(date time.Time) {
+	if len(l.series) == 0 || !date.After(l.series[0].Date) {
+		l.series = nil
+		return
+	}
+
+	if i := daysBetween(l.series[0].Date, date); i < len(l.series) {
+		l.series = l.series[:i]
+	}
+}
+
+// Series returns the training load of every day from the first day with an activity up to and including the to date.
+// Returns nil if no activities were added.
+func (l *TrainingLoad) Series(to time.Time) []TrainingLoadDay {
+	to = localDate(to)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.days) == 0 {
+		return nil
+	}
+
+	if constants := [2]float64{l.ChronicDays, l.AcuteDays}; constants != l.constants {
+		l.series = nil
+		l.constants = constants
+	}
+
+	var previous TrainingLoadDay
+	var d time.Time
+	if len(l.series) == 0 {
+		for date := range l.days {
+			if d.IsZero() || date.Before(d) {
+				d = date
+			}
+		}
+	} else {
+		previous = l.series[len(l.series)-1]
+		d = previous.Date.AddDate(0, 0, 1)
+	}
+
+	chronic := 1 - math.Exp(-1/l.ChronicDays)
+	acute := 1 - math.Exp(-1/l.AcuteDays)
+
+	for ; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := TrainingLoadDay{
+			Date: d,
+			TSB:  previous.CTL - previous.ATL,
+		}
+
+		for _, stress := range l.days[d] {
+			day.Stress += stress
+		}
+
+		day.CTL = previous.CTL + (day.Stress-previous.CTL)*chronic
+		day.ATL = previous.ATL + (day.Stress-previous.ATL)*acute
+
+		l.series = append(l.series, day)
+		previous = day
+	}
+
+	if len(l.series) == 0 || l.series[0].Date.After(to) {
+		return nil
+	}
+
+	n := daysBetween(l.series[0].Date, to) + 1
+
+	return append([]TrainingLoadDay(nil), l.series[:n]...)
+}
+
+// On returns the training load at the end of the date, false if there are no activities before it.
+func (l *TrainingLoad) On(date time.Time) (TrainingLoadDay, bool) {
+	series := l.Series(date)
+	if len(series) == 0 {
+		return TrainingLoadDay{}, false
+	}
+
+	return series[len(series)-1], true
+}
+
+// localDate returns the date of t, as shown on its clock, at midnight UTC.
+// StartDateLocal is parsed as UTC, so the activities of a day group by the date the athlete saw.
+func localDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of days from one local date to another.
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from) / (24 * time.Hour))
+}
@@ -0,0 +1,150 @@
+package strava
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAthleteSettingsTrainingStress(t *testing.T) {
+	s := NewAthleteSettings()
+	s.SetThresholds(ActivityTypes.Ride, time.Time{}, Thresholds{FTP: 250, LTHR: 160})
+	s.SetThresholds(ActivityTypes.Run, time.Time{}, Thresholds{ThresholdPace: 4})
+
+	ride := &ActivitySummary{Type: ActivityTypes.Ride, MovingTime: 3600, WeightedAveragePower: 250}
+	if tss := s.TrainingStress(ride); math.Abs(tss-100) > 1e-9 {
+		t.Errorf("incorrect power based stress, got %v", tss)
+	}
+
+	ride.WeightedAveragePower = 0
+	ride.AverageHeartrate = 144
+	if tss := s.TrainingStress(ride); math.Abs(tss-81) > 1e-9 {
+		t.Errorf("incorrect heart rate based stress, got %v", tss)
+	}
+
+	run := &ActivitySummary{Type: ActivityTypes.Run, MovingTime: 1800, AverageSpeed: 4}
+	if tss := s.TrainingStress(run); math.Abs(tss-50) > 1e-9 {
+		t.Errorf("incorrect pace based stress, got %v", tss)
+	}
+
+	swim := &ActivitySummary{Type: ActivityTypes.Swim, MovingTime: 1800, AverageSpeed: 1}
+	if tss := s.TrainingStress(swim); tss != 0 {
+		t.Errorf("should not have stress without thresholds, got %v", tss)
+	}
+}
+
+func TestTrainingLoad(t *testing.T) {
+	settings := NewAthleteSettings()
+	settings.SetThresholds(ActivityTypes.Ride, time.Time{}, Thresholds{FTP: 200})
+
+	l := NewTrainingLoad(settings)
+	if l.Series(time.Now()) != nil {
+		t.Error("should not have a series without activities")
+	}
+
+	day1 := time.Date(2020, time.June, 1, 8, 0, 0, 0, time.UTC)
+	l.Add(&ActivitySummary{Id: 1, Type: ActivityTypes.Ride, StartDateLocal: day1, MovingTime: 3600, AveragePower: 200})
+	l.Add(&ActivitySummary{Id: 2, Type: ActivityTypes.Ride, StartDateLocal: day1.Add(10 * time.Hour), MovingTime: 1800, AveragePower: 200})
+	l.Add(&ActivitySummary{Id: 3, Type: ActivityTypes.Ride, StartDateLocal: day1.AddDate(0, 0, 2), MovingTime: 3600, AveragePower: 200})
+
+	series := l.Series(day1.AddDate(0, 0, 3))
+	if len(series) != 4 {
+		t.Fatalf("incorrect series length, got %v", len(series))
+	}
+
+	if !series[0].Date.Equal(time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)) || series[0].Stress != 150 || series[1].Stress != 0 {
+		t.Errorf("incorrect stress, got %+v", series)
+	}
+
+	chronic := 1 - math.Exp(-1.0/42)
+	acute := 1 - math.Exp(-1.0/7)
+	if math.Abs(series[0].CTL-150*chronic) > 1e-9 || math.Abs(series[0].ATL-150*acute) > 1e-9 || series[0].TSB != 0 {
+		t.Errorf("incorrect first day, got %+v", series[0])
+	}
+
+	if math.Abs(series[1].TSB-(series[0].CTL-series[0].ATL)) > 1e-9 || series[1].TSB >= 0 {
+		t.Errorf("incorrect form, got %+v", series[1])
+	}
+
+	// updating and removing activities
+	l.Add(&ActivitySummary{Id: 2, Type: ActivityTypes.Ride, StartDateLocal: day1, MovingTime: 3600, AveragePower: 200})
+	l.Remove(3)
+
+	day, ok := l.On(day1.AddDate(0, 0, 2))
+	if !ok || day.Stress != 0 || !day.Date.Equal(time.Date(2020, time.June, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("incorrect day, got %+v", day)
+	}
+
+	if day, _ := l.On(day1); day.Stress != 200 {
+		t.Errorf("incorrect stress after update, got %v", day.Stress)
+	}
+
+	if _, ok := l.On(day1.AddDate(0, 0, -1)); ok {
+		t.Error("should not have load before the first activity")
+	}
+}
+
+func TestTrainingLoadIncremental(t *testing.T) {
+	settings := NewAthleteSettings()
+	settings.SetThresholds(ActivityTypes.Ride, time.Time{}, Thresholds{FTP: 200})
+
+	day1 := time.Date(2020, time.June, 1, 8, 0, 0, 0, time.UTC)
+	ride := func(id int64, days int, power Power) *ActivitySummary {
+		return &ActivitySummary{Id: id, Type: ActivityTypes.Ride, StartDateLocal: day1.AddDate(0, 0, days), MovingTime: 3600, AveragePower: power}
+	}
+
+	l := NewTrainingLoad(settings)
+	l.Add(ride(1, 0, 200))
+	l.Add(ride(2, 3, 180))
+	before := l.Series(day1.AddDate(0, 0, 9))
+
+	// an activity arriving later only recalculates from its date on
+	l.Add(ride(3, 7, 220))
+	if len(l.series) != 7 {
+		t.Errorf("days before the activity should be kept, got %d days", len(l.series))
+	}
+
+	series := l.Series(day1.AddDate(0, 0, 9))
+	for i := 0; i < 7; i++ {
+		if series[i] != before[i] {
+			t.Errorf("day %d should not change, got %+v", i, series[i])
+		}
+	}
+
+	// the same series as calculated from scratch, after updates and removals
+	l.Add(ride(2, 2, 150))
+	l.Add(ride(4, -2, 100))
+	l.Remove(1)
+	l.Series(day1.AddDate(0, 0, 5))
+	l.Add(ride(5, 4, 120))
+
+	fresh := NewTrainingLoad(settings)
+	for _, a := range []*ActivitySummary{ride(2, 2, 150), ride(3, 7, 220), ride(4, -2, 100), ride(5, 4, 120)} {
+		fresh.Add(a)
+	}
+
+	series, expected := l.Series(day1.AddDate(0, 0, 9)), fresh.Series(day1.AddDate(0, 0, 9))
+	if len(series) != len(expected) || len(series) != 12 {
+		t.Fatalf("incorrect series length, got %d, expected %d", len(series), len(expected))
+	}
+
+	for i := range expected {
+		if series[i] != expected[i] {
+			t.Errorf("day %d should be %+v, got %+v", i, expected[i], series[i])
+		}
+	}
+
+	// earlier dates return the start of the series, changed time constants recalculate it
+	if short := l.Series(day1); len(short) != 3 || short[2] != expected[2] {
+		t.Errorf("incorrect shorter series, got %+v", short)
+	}
+
+	if l.Series(day1.AddDate(0, 0, -3)) != nil {
+		t.Error("should not have a series before the first activity")
+	}
+
+	l.ChronicDays = 28
+	if day, _ := l.On(day1.AddDate(0, 0, 9)); day.CTL == expected[11].CTL {
+		t.Error("changed time constants should recalculate the series")
+	}
+}
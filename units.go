@@ -0,0 +1,203 @@
+package strava
+
+import (
+	"fmt"
+	"time"
+)
+
+// Distance is a length in meters, as returned by the Strava API.
+// Like time.Duration the constants can be used for conversion,
+// e.g. `10 * strava.Kilometer` or `d / strava.Mile`.
+type Distance float64
+
+const (
+	Meter     Distance = 1
+	Kilometer Distance = 1000
+	Foot      Distance = 0.3048
+	Yard      Distance = 0.9144
+	Mile      Distance = 1609.344
+)
+
+// Elevation is a height or elevation difference in meters.
+type Elevation float64
+
+// Speed is a velocity in meters per second.
+type Speed float64
+
+// Power is a power output in watts.
+type Power float64
+
+// Temperature is a temperature in degrees Celsius.
+type Temperature float64
+
+// MeasurementPreferences are the possible values of AthleteDetailed.MeasurementPreference,
+// they can be used to format values in the units preferred by the athlete.
+var MeasurementPreferences = struct {
+	Meters string
+	Feet   string
+}{"meters", "feet"}
+
+/*********************************************************/
+
+func (d Distance) Meters() float64 {
+	return float64(d)
+}
+
+func (d Distance) Kilometers() float64 {
+	return float64(d / Kilometer)
+}
+
+func (d Distance) Miles() float64 {
+	return float64(d / Mile)
+}
+
+func (d Distance) Feet() float64 {
+	return float64(d / Foot)
+}
+
+// Speed returns the average speed when covering the distance in the given duration.
+func (d Distance) Speed(duration time.Duration) Speed {
+	if duration <= 0 {
+		return 0
+	}
+
+	return Speed(float64(d) / duration.Seconds())
+}
+
+// String formats the distance in meters below a kilometer, in kilometers otherwise.
+func (d Distance) String() string {
+	return d.Format(MeasurementPreferences.Meters)
+}
+
+// Format formats the distance in the units of the measurement preference,
+// "feet" for imperial units, anything else for metric units.
+func (d Distance) Format(preference string) string {
+	if preference == MeasurementPreferences.Feet {
+		if d < Mile && d > -Mile {
+			return fmt.Sprintf("%.0f ft", d.Feet())
+		}
+		return fmt.Sprintf("%.2f mi", d.Miles())
+	}
+
+	if d < Kilometer && d > -Kilometer {
+		return fmt.Sprintf("%.0f m", d.Meters())
+	}
+	return fmt.Sprintf("%.2f km", d.Kilometers())
+}
+
+/*********************************************************/
+
+func (e Elevation) Meters() float64 {
+	return float64(e)
+}
+
+func (e Elevation) Feet() float64 {
+	return float64(Distance(e) / Foot)
+}
+
+// Distance converts the elevation to a Distance.
+func (e Elevation) Distance() Distance {
+	return Distance(e)
+}
+
+func (e Elevation) String() string {
+	return e.Format(MeasurementPreferences.Meters)
+}
+
+// Format formats the elevation in feet or meters depending on the measurement preference.
+func (e Elevation) Format(preference string) string {
+	if preference == MeasurementPreferences.Feet {
+		return fmt.Sprintf("%.0f ft", e.Feet())
+	}
+
+	return fmt.Sprintf("%.0f m", e.Meters())
+}
+
+/*********************************************************/
+
+func (s Speed) MetersPerSecond() float64 {
+	return float64(s)
+}
+
+func (s Speed) KilometersPerHour() float64 {
+	return float64(s) * 3.6
+}
+
+func (s Speed) MilesPerHour() float64 {
+	return float64(s) * 3600 / float64(Mile)
+}
+
+// Pace returns the time it takes to cover the given distance at this speed.
+// Returns 0 if the speed is not positive.
+func (s Speed) Pace(per Distance) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(per) / float64(s) * float64(time.Second))
+}
+
+// Distance returns the distance covered at this speed in the given duration.
+func (s Speed) Distance(duration time.Duration) Distance {
+	return Distance(float64(s) * duration.Seconds())
+}
+
+func (s Speed) String() string {
+	return s.Format(MeasurementPreferences.Meters)
+}
+
+// Format formats the speed in km/h or mi/h depending on the measurement preference.
+func (s Speed) Format(preference string) string {
+	if preference == MeasurementPreferences.Feet {
+		return fmt.Sprintf("%.1f mi/h", s.MilesPerHour())
+	}
+
+	return fmt.Sprintf("%.1f km/h", s.KilometersPerHour())
+}
+
+/*********************************************************/
+
+func (p Power) Watts() float64 {
+	return float64(p)
+}
+
+// Kilojoules returns the work done when producing this power for the given duration.
+func (p Power) Kilojoules(duration time.Duration) float64 {
+	return float64(p) * duration.Seconds() / 1000
+}
+
+// PerKilogram returns the power to weight ratio in watts per kilogram.
+func (p Power) PerKilogram(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	return float64(p) / weight
+}
+
+func (p Power) String() string {
+	return fmt.Sprintf("%.0f W", float64(p))
+}
+
+/*********************************************************/
+
+func (t Temperature) Celsius() float64 {
+	return float64(t)
+}
+
+func (t Temperature) Fahrenheit() float64 {
+	return float64(t)*9/5 + 32
+}
+
+func (t Temperature) String() string {
+	return t.Format(MeasurementPreferences.Meters)
+}
+
+// Format formats the temperature in °F or °C depending on the measurement preference.
+func (t Temperature) Format(preference string) string {
+	if preference == MeasurementPreferences.Feet {
+		return fmt.Sprintf("%.0f °F", t.Fahrenheit())
+	}
+
+	return fmt.Sprintf("%.0f °C", t.Celsius())
+}
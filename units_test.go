@@ -0,0 +1,144 @@
+package strava
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDistance(t *testing.T) {
+	d := 42195 * Meter
+
+	if v := d.Kilometers(); v != 42.195 {
+		t.Errorf("incorrect kilometers, got %v", v)
+	}
+
+	if v := d.Miles(); math.Abs(v-26.2188) > 0.0001 {
+		t.Errorf("incorrect miles, got %v", v)
+	}
+
+	if v := Mile.Feet(); math.Abs(v-5280) > 0.0001 {
+		t.Errorf("incorrect feet, got %v", v)
+	}
+
+	if s := d.String(); s != "42.20 km" {
+		t.Errorf("incorrect format, got %v", s)
+	}
+
+	if s := (500 * Meter).String(); s != "500 m" {
+		t.Errorf("incorrect format, got %v", s)
+	}
+
+	if s := d.Format(MeasurementPreferences.Feet); s != "26.22 mi" {
+		t.Errorf("incorrect format, got %v", s)
+	}
+
+	if s := (100 * Foot).Format(MeasurementPreferences.Feet); s != "100 ft" {
+		t.Errorf("incorrect format, got %v", s)
+	}
+
+	if v := (10 * Kilometer).Speed(time.Hour); math.Abs(v.KilometersPerHour()-10) > 0.0001 {
+		t.Errorf("incorrect speed, got %v", v)
+	}
+
+	if v := (10 * Kilometer).Speed(0); v != 0 {
+		t.Errorf("speed should be 0 for no duration, got %v", v)
+	}
+}
+
+func TestElevation(t *testing.T) {
+	e := Elevation(304.8)
+
+	if v := e.Feet(); math.Abs(v-1000) > 0.0001 {
+		t.Errorf("incorrect feet, got %v", v)
+	}
+
+	if s := e.String(); s != "305 m" {
+		t.Errorf("incorrect format, got %v", s)
+	}
+
+	if s := e.Format(MeasurementPreferences.Feet); s != "1000 ft" {
+		t.Errorf("incorrect format, got %v", s)
+	}
+}
+
+func TestSpeed(t *testing.T) {
+	s := Speed(5)
+
+	if v := s.KilometersPerHour(); v != 18 {
+		t.Errorf("incorrect km/h, got %v", v)
+	}
+
+	if v := s.Pace(Kilometer); v != 200*time.Second {
+		t.Errorf("incorrect pace, got %v", v)
+	}
+
+	if v := Speed(0).Pace(Kilometer); v != 0 {
+		t.Errorf("pace should be 0 when not moving, got %v", v)
+	}
+
+	if v := s.Distance(time.Minute); v != 300 {
+		t.Errorf("incorrect distance, got %v", v)
+	}
+
+	if v := s.String(); v != "18.0 km/h" {
+		t.Errorf("incorrect format, got %v", v)
+	}
+
+	if v := s.Format(MeasurementPreferences.Feet); v != "11.2 mi/h" {
+		t.Errorf("incorrect format, got %v", v)
+	}
+}
+
+func TestPower(t *testing.T) {
+	p := Power(250)
+
+	if v := p.Kilojoules(time.Hour); v != 900 {
+		t.Errorf("incorrect kilojoules, got %v", v)
+	}
+
+	if v := p.PerKilogram(62.5); v != 4 {
+		t.Errorf("incorrect w/kg, got %v", v)
+	}
+
+	if v := p.String(); v != "250 W" {
+		t.Errorf("incorrect format, got %v", v)
+	}
+}
+
+func TestTemperature(t *testing.T) {
+	temp := Temperature(20)
+
+	if v := temp.Fahrenheit(); v != 68 {
+		t.Errorf("incorrect fahrenheit, got %v", v)
+	}
+
+	if v := temp.String(); v != "20 °C" {
+		t.Errorf("incorrect format, got %v", v)
+	}
+
+	if v := temp.Format(MeasurementPreferences.Feet); v != "68 °F" {
+		t.Errorf("incorrect format, got %v", v)
+	}
+}
+
+func TestUnitsJSON(t *testing.T) {
+	var activity ActivitySummary
+	err := json.Unmarshal([]byte(`{"distance":1234.5,"total_elevation_gain":12.5,"average_speed":4.5,"average_watts":180.2,"weighted_average_watts":201}`), &activity)
+	if err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+
+	if activity.Distance != 1234.5 || activity.TotalElevationGain != 12.5 ||
+		activity.AverageSpeed != 4.5 || activity.AveragePower != 180.2 || activity.WeightedAveragePower != 201 {
+		t.Errorf("units not decoded, got %v", activity)
+	}
+
+	data, _ := json.Marshal(struct {
+		Distance Distance `json:"distance"`
+	}{1234.5})
+	if string(data) != `{"distance":1234.5}` {
+		t.Errorf("units should encode as numbers, got %s", data)
+	}
+}
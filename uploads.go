@@ -10,6 +10,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"time"
 )
 
 type UploadDetailed struct {
@@ -17,7 +18,8 @@ type UploadDetailed struct {
 }
 
 type UploadSummary struct {
-	Id         int64  `json:"id"`
+	RawJSON
+	Id         int64  `json:"id" strava:"required"`
 	ExternalId string `json:"external_id"`
 	Error      string `json:"error"`
 	Status     string `json:"status"`
@@ -39,14 +41,18 @@ var FileDataTypes = struct {
 	GPXGZ FileDataType
 }{"fit", "fit.gz", "tcx", "tcx.gz", "gpx", "gpx.gz"}
 
-var errorHandler ErrorHandler = func(response *http.Response) error {
-	if response.StatusCode == 400 {
-		contents, _ := ioutil.ReadAll(response.Body)
-		var e UploadSummary
-		json.Unmarshal(contents, &e)
-		return Error{e.Error, nil}
-	} else {
-		return defaultErrorHandler(response)
+// uploadErrorHandler handles the upload specific 400 response,
+// all other errors are passed on to the fallback handler.
+func uploadErrorHandler(fallback ErrorHandler) ErrorHandler {
+	return func(response *http.Response) error {
+		if response.StatusCode == 400 {
+			contents, _ := ioutil.ReadAll(response.Body)
+			var e UploadSummary
+			json.Unmarshal(contents, &e)
+			return Error{Message: e.Error}
+		} else {
+			return fallback(response)
+		}
 	}
 }
 
@@ -57,8 +63,12 @@ func NewUploadsService(client *Client) *UploadsService {
 /*********************************************************/
 
 type UploadsGetCall struct {
-	service *UploadsService
-	id      int64
+	service      *UploadsService
+	id           int64
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 func (s *UploadsService) Get(uploadId int64) *UploadsGetCall {
@@ -68,28 +78,50 @@ func (s *UploadsService) Get(uploadId int64) *UploadsGetCall {
 	}
 }
 
-func (c *UploadsGetCall) Do() (*UploadDetailed, error) {
-	data, err := c.service.client.run("GET", fmt.Sprintf("/uploads/%d", c.id), nil)
-	if err != nil {
-		return nil, err
-	}
+func (c *UploadsGetCall) OnError(handler ErrorHandler) *UploadsGetCall {
+	c.errorHandler = handler
+	return c
+}
 
-	var upload UploadDetailed
-	err = json.Unmarshal(data, &upload)
-	if err != nil {
-		return nil, err
+// Response makes the call fill resp with the metadata of its response.
+func (c *UploadsGetCall) Response(resp *Response) *UploadsGetCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *UploadsGetCall) Timeout(d time.Duration) *UploadsGetCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *UploadsGetCall) Header(key, value string) *UploadsGetCall {
+	if c.header == nil {
+		c.header = make(http.Header)
 	}
 
-	return &upload, nil
+	c.header.Add(key, value)
+	return c
+}
+
+func (c *UploadsGetCall) Do() (*UploadDetailed, error) {
+	upload, err := runAndDecode[*UploadDetailed](c.service.client, "GET", fmt.Sprintf("/uploads/%d", c.id), nil, c.errorHandler, c.response, c.timeout, c.header)
+	return upload, wrapError(err, "uploads.get id=%d", c.id)
 }
 
 /*********************************************************/
 
 type UploadsCreateCall struct {
-	service    *UploadsService
-	ops        map[string]interface{}
-	filename   string
-	fileReader io.Reader
+	service      *UploadsService
+	ops          map[string]interface{}
+	filename     string
+	fileReader   io.Reader
+	progress     func(sent, total int64)
+	errorHandler ErrorHandler
+	response     *Response
+	timeout      time.Duration
+	header       http.Header
 }
 
 // Create defines an upload call containing the contents of the reader.
@@ -140,8 +172,66 @@ func (c *UploadsCreateCall) ExternalId(id string) *UploadsCreateCall {
 	return c
 }
 
+// Progress makes the call report the bytes of the upload sent so far and the total while the file is sent,
+// e.g. for a progress bar of a large file. The total is the size of the request body, with the file gzipped.
+func (c *UploadsCreateCall) Progress(progress func(sent, total int64)) *UploadsCreateCall {
+	c.progress = progress
+	return c
+}
+
+// Validate checks the upload before sending it, e.g. that the data type is known
+// and a trainer is only set for activity types that can be done on a trainer.
+// Do validates the call before sending it.
+func (c *UploadsCreateCall) Validate() error {
+	if c.fileReader == nil {
+		return &ValidationError{"file", "is required"}
+	}
+
+	if !c.ops["data_type"].(FileDataType).isValid() {
+		return &ValidationError{"data_type", fmt.Sprintf("unknown data type %q", c.ops["data_type"])}
+	}
+
+	activityType, hasType := c.ops["activity_type"].(string)
+	if _, trainer := c.ops["trainer"]; trainer && hasType && !ActivityType(activityType).SupportsTrainer() {
+		return &ValidationError{"trainer", fmt.Sprintf("not applicable to %s activities", activityType)}
+	}
+
+	return nil
+}
+
+func (c *UploadsCreateCall) OnError(handler ErrorHandler) *UploadsCreateCall {
+	c.errorHandler = handler
+	return c
+}
+
+// Response makes the call fill resp with the metadata of its response.
+func (c *UploadsCreateCall) Response(resp *Response) *UploadsCreateCall {
+	c.response = resp
+	return c
+}
+
+// Timeout makes the call fail if it takes longer than d, including the wait for its turn, see WithRequestDelay.
+func (c *UploadsCreateCall) Timeout(d time.Duration) *UploadsCreateCall {
+	c.timeout = d
+	return c
+}
+
+// Header adds a header to the request of the call, e.g. a flag for a proxy.
+func (c *UploadsCreateCall) Header(key, value string) *UploadsCreateCall {
+	if c.header == nil {
+		c.header = make(http.Header)
+	}
+
+	c.header.Add(key, value)
+	return c
+}
+
 func (c *UploadsCreateCall) Do() (*UploadSummary, error) {
 	var err error
+	if err = c.Validate(); err != nil {
+		return nil, wrapError(err, "uploads.create")
+	}
+
 	// since we're doing a multipart post, the request is custom built
 
 	body := &bytes.Buffer{}
@@ -152,7 +242,7 @@ func (c *UploadsCreateCall) Do() (*UploadSummary, error) {
 	if c.ops["data_type"].(FileDataType).isGzipped() {
 		_, err = io.Copy(part, c.fileReader)
 		if err != nil {
-			return nil, err
+			return nil, wrapError(err, "uploads.create")
 		}
 	} else {
 		// gzip here for the user
@@ -163,7 +253,7 @@ func (c *UploadsCreateCall) Do() (*UploadSummary, error) {
 		_, err = io.Copy(gzWriter, c.fileReader)
 		gzWriter.Close()
 		if err != nil {
-			return nil, err
+			return nil, wrapError(err, "uploads.create")
 		}
 
 		io.Copy(part, gzBuffer)
@@ -177,18 +267,26 @@ func (c *UploadsCreateCall) Do() (*UploadSummary, error) {
 
 	writer.Close() // so it finishes writing everything to the body buffer
 
-	req, err := http.NewRequest("POST", basePath+"/uploads", body)
+	ctx, cancel := c.service.client.callContext(c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.service.client.baseURL+"/uploads", body)
 	req.Header.Add("Content-Type", "multipart/form-data; boundary="+writer.Boundary())
+	if c.progress != nil {
+		data := body.Bytes()
+		req.Body = io.NopCloser(&progressReader{Reader: bytes.NewReader(data), total: int64(len(data)), progress: c.progress})
+	}
+	addHeader(req, c.header)
 
-	data, err := c.service.client.runRequestWithErrorHandler(req, errorHandler)
-	if err != nil {
-		return nil, err
+	handler := c.errorHandler
+	if handler == nil {
+		handler = uploadErrorHandler(c.service.client.errorHandlerFor(nil))
 	}
 
 	var upload UploadSummary
-	err = json.Unmarshal(data, &upload)
+	_, err = c.service.client.execute(req, handler, c.response, &upload)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err, "uploads.create")
 	}
 
 	return &upload, nil
@@ -196,6 +294,37 @@ func (c *UploadsCreateCall) Do() (*UploadSummary, error) {
 
 /*********************************************************/
 
+// progressReader reports the bytes read of a body of known length.
+type progressReader struct {
+	io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+
+	return n, err
+}
+
+/*********************************************************/
+
+func (f FileDataType) isValid() bool {
+	switch f {
+	case FileDataTypes.FIT, FileDataTypes.FITGZ,
+		FileDataTypes.TCX, FileDataTypes.TCXGZ,
+		FileDataTypes.GPX, FileDataTypes.GPXGZ:
+		return true
+	}
+
+	return false
+}
+
 func (f FileDataType) isGzipped() bool {
 	return f == FileDataTypes.FITGZ || f == FileDataTypes.TCXGZ || f == FileDataTypes.GPXGZ
 }
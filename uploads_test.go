@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"reflect"
 	"strings"
 	"time"
@@ -15,7 +16,7 @@ import (
 )
 
 func TestUploadsGet(t *testing.T) {
-	client := newCassetteClient(testToken, "upload_get")
+	client := newFixtureClient(t, "upload_get")
 	upload, err := NewUploadsService(client).Get(46440854).Do()
 
 	expected := &UploadDetailed{}
@@ -52,7 +53,7 @@ func TestUploadsGet(t *testing.T) {
 func TestUploadsCreate(t *testing.T) {
 	data := strings.NewReader(rawGPXDataForTesting())
 
-	client := newCassetteClient("special token with write permissions here", "upload_create")
+	client := newFixtureClient(t, "upload_create")
 	upload, err := NewUploadsService(client).Create(FileDataTypes.GPX, "", data).
 		Private().
 		Do()
@@ -79,7 +80,7 @@ func TestUploadsCreate(t *testing.T) {
 	io.Copy(gzWriter, strings.NewReader(rawGPXDataForTesting()))
 	gzWriter.Close()
 
-	client = newCassetteClient("special token with write permissions here", "upload_create_gz")
+	client = newFixtureClient(t, "upload_create_gz")
 	upload, err = NewUploadsService(client).Create(FileDataTypes.GPXGZ, "upload", gzDataBuffer).
 		Private().
 		Do()
@@ -122,7 +123,7 @@ func TestUploadsCreate(t *testing.T) {
 	// test unauthorized if no write permissions
 	data2 := strings.NewReader(rawGPXDataForTesting())
 
-	client = newCassetteClient(testToken, "upload_create_unauthorized")
+	client = newFixtureClient(t, "upload_create_unauthorized", http.StatusUnauthorized)
 	upload, err = NewUploadsService(client).Create(FileDataTypes.GPX, "", data2).
 		Private().
 		Do()
@@ -135,8 +136,8 @@ func TestUploadsCreate(t *testing.T) {
 		t.Error("should return error when using unauthorized token")
 	}
 
-	e, ok := err.(Error)
-	if !ok {
+	var e Error
+	if !errors.As(err, &e) {
 		t.Fatal("should return strava error type")
 	}
 
@@ -245,6 +246,38 @@ func TestUploadsCreate(t *testing.T) {
 	}
 }
 
+func TestUploadsCreateProgress(t *testing.T) {
+	var length int64
+	client := NewClient(newStubTokenSource())
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		length = int64(len(body))
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(`{"id":1}`)), Request: req}, nil
+	})}
+
+	var sent []int64
+	var total int64
+	_, err := NewUploadsService(client).Create(FileDataTypes.GPX, "", strings.NewReader(rawGPXDataForTesting())).
+		Progress(func(s, t int64) {
+			sent = append(sent, s)
+			total = t
+		}).
+		Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(sent) == 0 || sent[len(sent)-1] != length || total != length {
+		t.Fatalf("progress should reach the length of the body %d, got %v of %d", length, sent, total)
+	}
+
+	for i := 1; i < len(sent); i++ {
+		if sent[i] <= sent[i-1] {
+			t.Errorf("progress should increase, got %v", sent)
+		}
+	}
+}
+
 func TestUploadsBadJSON(t *testing.T) {
 	var err error
 	s := NewUploadsService(NewStubResponseClient("bad json"))
@@ -348,3 +381,32 @@ type badReader int
 func (badReader) Read(b []byte) (int, error) {
 	return 0, errors.New("bad reader")
 }
+
+func TestUploadsCreateValidate(t *testing.T) {
+	s := NewUploadsService(newStoreRequestClient())
+
+	cases := []struct {
+		call  *UploadsCreateCall
+		field string
+	}{
+		{s.Create(FileDataTypes.GPX, "", strings.NewReader("data")), ""},
+		{s.Create(FileDataTypes.FIT, "", strings.NewReader("data")).ActivityType(ActivityTypes.VirtualRide).Trainer(), ""},
+		{s.Create(FileDataTypes.FIT, "", strings.NewReader("data")).Trainer(), ""},
+		{s.Create(FileDataTypes.GPX, "", nil), "file"},
+		{s.Create(FileDataType("kml"), "", strings.NewReader("data")), "data_type"},
+		{s.Create(FileDataTypes.FIT, "", strings.NewReader("data")).ActivityType(ActivityTypes.Swim).Trainer(), "trainer"},
+	}
+
+	for i, c := range cases {
+		err := c.call.Validate()
+
+		var validationErr *ValidationError
+		if c.field == "" {
+			if err != nil {
+				t.Errorf("case %d: should be valid, got %v", i, err)
+			}
+		} else if !errors.As(err, &validationErr) || validationErr.Field != c.field {
+			t.Errorf("case %d: should be invalid %s, got %v", i, c.field, err)
+		}
+	}
+}
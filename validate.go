@@ -0,0 +1,131 @@
+package strava
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A Validator checks a model decoded by a call of a client created WithValidators, e.g. an *ActivityDetailed
+// or each *ActivitySummary of a list, and returns warnings for suspicious data. See ValidateModel.
+type Validator func(model interface{}) []Warning
+
+// A Warning reports suspicious data in a model. Calls return the model as is, the warnings of a call
+// are in the Response passed to it and logged.
+type Warning struct {
+	Code    string      // identifies the check, e.g. "zero_elapsed_time"
+	Message string      // e.g. "activity 123 has no elapsed time"
+	Model   interface{} // the model the warning is about
+}
+
+func (w Warning) String() string {
+	return w.Code + ": " + w.Message
+}
+
+// Codes of the warnings of ActivityValidator.
+const (
+	WarningZeroElapsedTime   = "zero_elapsed_time"
+	WarningMovingTimeTooLong = "moving_time_too_long"
+	WarningNegativeDistance  = "negative_distance"
+	WarningNoStartDate       = "no_start_date"
+)
+
+// WithValidators makes the client run the validators on the models decoded by its calls.
+func WithValidators(validators ...Validator) Option {
+	return func(c *Client) {
+		c.validators = append(c.validators, validators...)
+	}
+}
+
+// ValidateModel returns a Validator running check on the models of type T, and ignoring others:
+//
+//	strava.ValidateModel(func(gear *strava.GearDetailed) []strava.Warning { ... })
+func ValidateModel[T any](check func(model T) []Warning) Validator {
+	return func(model interface{}) []Warning {
+		if m, ok := model.(T); ok {
+			return check(m)
+		}
+
+		return nil
+	}
+}
+
+// ActivityValidator returns a Validator warning about activities without elapsed time or start date,
+// with a negative distance or moving longer than they lasted, both summaries and detailed activities.
+func ActivityValidator() Validator {
+	return func(model interface{}) []Warning {
+		switch activity := model.(type) {
+		case *ActivitySummary:
+			return validateActivity(activity)
+		case *ActivityDetailed:
+			return validateActivity(&activity.ActivitySummary)
+		}
+
+		return nil
+	}
+}
+
+func validateActivity(activity *ActivitySummary) []Warning {
+	var warnings []Warning
+	warn := func(code, format string, args ...interface{}) {
+		message := fmt.Sprintf("activity %d ", activity.Id) + fmt.Sprintf(format, args...)
+		warnings = append(warnings, Warning{Code: code, Message: message, Model: activity})
+	}
+
+	if activity.ElapsedTime == 0 {
+		warn(WarningZeroElapsedTime, "has no elapsed time")
+	} else if activity.MovingTime > activity.ElapsedTime {
+		warn(WarningMovingTimeTooLong, "moved %ds of %ds", activity.MovingTime, activity.ElapsedTime)
+	}
+
+	if activity.Distance < 0 {
+		warn(WarningNegativeDistance, "has a distance of %v", activity.Distance)
+	}
+
+	if activity.StartDate.IsZero() {
+		warn(WarningNoStartDate, "has no start date")
+	}
+
+	return warnings
+}
+
+/*********************************************************/
+
+// validate runs the validators of the client on the decoded value v points to, or on each item if it is a list,
+// adds the warnings to the response of the call and logs them.
+func (client *Client) validate(record *callRecord, v interface{}) {
+	if len(client.validators) == 0 || v == nil {
+		return
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return
+	}
+
+	var warnings []Warning
+	check := func(model reflect.Value) {
+		if !model.CanInterface() || (model.Kind() == reflect.Ptr && model.IsNil()) {
+			return
+		}
+
+		for _, validator := range client.validators {
+			warnings = append(warnings, validator(model.Interface())...)
+		}
+	}
+
+	if value = value.Elem(); value.Kind() == reflect.Slice {
+		for i := 0; i < value.Len(); i++ {
+			check(value.Index(i))
+		}
+	} else {
+		check(value)
+	}
+
+	for _, warning := range warnings {
+		client.logf("strava: %s %s warning: %s", record.Method, record.Endpoint, warning)
+	}
+
+	if record.response != nil {
+		record.response.Warnings = append(record.response.Warnings, warnings...)
+	}
+}
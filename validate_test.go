@@ -0,0 +1,67 @@
+package strava
+
+import (
+	"testing"
+)
+
+func TestClientValidators(t *testing.T) {
+	client, _ := newRouteClient(map[string]string{
+		"/api/v3/athlete/activities": `[{"id":1,"elapsed_time":0,"start_date":"2024-01-01T00:00:00Z"},{"id":2,"elapsed_time":60,"moving_time":60,"start_date":"2024-01-01T00:00:00Z"}]`,
+		"/api/v3/activities/3":       `{"id":3,"elapsed_time":60,"moving_time":90,"distance":-1}`,
+		"/api/v3/gear/b1":            `{"id":"b1","name":""}`,
+	})
+
+	WithValidators(
+		ActivityValidator(),
+		ValidateModel(func(gear *GearDetailed) []Warning {
+			if gear.Name == "" {
+				return []Warning{{Code: "no_name", Message: "gear has no name", Model: gear}}
+			}
+			return nil
+		}),
+	)(client)
+
+	var resp Response
+	activities, err := NewCurrentAthleteService(client).ListActivities().Response(&resp).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("suspicious activities should be returned, got %d", len(activities))
+	}
+
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Code != WarningZeroElapsedTime || resp.Warnings[0].Model != activities[0] {
+		t.Errorf("incorrect warnings, got %v", resp.Warnings)
+	}
+
+	activity, err := NewActivitiesService(client).Get(3).Response(&resp).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	codes := make(map[string]bool)
+	for _, w := range resp.Warnings {
+		codes[w.Code] = true
+	}
+
+	if len(resp.Warnings) != 3 || !codes[WarningMovingTimeTooLong] || !codes[WarningNegativeDistance] || !codes[WarningNoStartDate] {
+		t.Errorf("incorrect warnings, got %v", resp.Warnings)
+	}
+
+	if activity.Id != 3 {
+		t.Errorf("activity should be returned, got %d", activity.Id)
+	}
+
+	if _, err := NewGearService(client).Get("b1").Response(&resp).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(resp.Warnings) != 1 || resp.Warnings[0].String() != "no_name: gear has no name" {
+		t.Errorf("incorrect warnings, got %v", resp.Warnings)
+	}
+
+	if client.ClientOptionsSnapshot().Validators != 2 {
+		t.Errorf("options should show the validators, got %+v", client.ClientOptionsSnapshot())
+	}
+}
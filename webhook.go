@@ -0,0 +1,195 @@
+package strava
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WebhookObjectType is the kind of object a WebhookEvent is about.
+type WebhookObjectType string
+
+var WebhookObjectTypes = struct {
+	Activity WebhookObjectType
+	Athlete  WebhookObjectType
+}{"activity", "athlete"}
+
+// WebhookAspectType is the kind of change of a WebhookEvent.
+type WebhookAspectType string
+
+var WebhookAspectTypes = struct {
+	Create WebhookAspectType
+	Update WebhookAspectType
+	Delete WebhookAspectType
+}{"create", "update", "delete"}
+
+// A WebhookEvent is posted by Strava to the callback url of a push subscription when an activity
+// is created, updated or deleted, or when an athlete deauthorizes the application.
+type WebhookEvent struct {
+	ObjectType     WebhookObjectType `json:"object_type"`
+	ObjectId       int64             `json:"object_id"` // id of the activity or athlete
+	AspectType     WebhookAspectType `json:"aspect_type"`
+	Updates        WebhookUpdates    `json:"updates"`
+	OwnerId        int64             `json:"owner_id"` // id of the athlete
+	SubscriptionId int64             `json:"subscription_id"`
+	EventTime      int64             `json:"event_time"` // unix timestamp
+
+	// ReceivedAt is when the event was received, set by ParseWebhookEvent and kept when events are queued as json.
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// WebhookUpdates are the fields changed by an update event, nil if not changed.
+// Strava sends the flags as the strings "true" and "false", they are encoded the same way.
+type WebhookUpdates struct {
+	Title      *string       // new name of the activity
+	Type       *ActivityType // new type of the activity
+	Private    *bool         // if the activity is now only visible to the athlete
+	Authorized *bool         // always false, set when the athlete deauthorized the application
+}
+
+// ParseWebhookEvent decodes the body of a request posted by Strava to the callback url of a push subscription.
+// The event is received at the time of the clock, e.g. of the clients and WebhookDispatcher, nil for the system clock.
+func ParseWebhookEvent(r io.Reader, clock Clock) (*WebhookEvent, error) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	var event WebhookEvent
+	if err := json.NewDecoder(r).Decode(&event); err != nil {
+		return nil, wrapError(err, "webhook.parse")
+	}
+	event.ReceivedAt = clock.Now()
+
+	return &event, nil
+}
+
+// Time returns the time of the event.
+func (e *WebhookEvent) Time() time.Time {
+	return time.Unix(e.EventTime, 0)
+}
+
+// IsDeauthorization returns if the event tells the athlete deauthorized the application,
+// the tokens of the athlete should be deleted.
+func (e *WebhookEvent) IsDeauthorization() bool {
+	return e.ObjectType == WebhookObjectTypes.Athlete && e.Updates.Authorized != nil && !*e.Updates.Authorized
+}
+
+// Apply applies the updates of an activity update event to a locally cached activity,
+// saving a request to fetch the activity again. Returns false, leaving the activity as is,
+// if the event is not an update of the activity. A private activity is visible to only the athlete,
+// an activity made public again to everyone, the event doesn't tell if it is visible to followers only.
+func (e *WebhookEvent) Apply(activity *ActivitySummary) bool {
+	if e.ObjectType != WebhookObjectTypes.Activity || e.AspectType != WebhookAspectTypes.Update || e.ObjectId != activity.Id {
+		return false
+	}
+
+	if e.Updates.Title != nil {
+		activity.Name = *e.Updates.Title
+	}
+
+	if e.Updates.Type != nil {
+		activity.SportType = *e.Updates.Type
+		activity.Type = e.Updates.Type.BaseType()
+	}
+
+	if e.Updates.Private != nil {
+		activity.Private = *e.Updates.Private
+
+		switch {
+		case activity.Private:
+			activity.Visibility = Visibilities.OnlyMe
+		case activity.Visibility == Visibilities.OnlyMe:
+			activity.Visibility = Visibilities.Everyone
+		}
+	}
+
+	return true
+}
+
+// WebhookHandler handles the events Strava posts to the callback url of a push subscription, passing them to handle,
+// e.g. the Dispatch of a WebhookDispatcher. Strava expects an answer within two seconds, handle should queue slow work.
+// Events that can't be parsed are answered with 400 Bad Request, those handle fails with 500 Internal Server Error,
+// which makes Strava retry them. Wrap it in a PushSubscriptionHandler to answer the validation of the subscription.
+// Events are received at the time of the clock, see ParseWebhookEvent, nil for the system clock.
+func WebhookHandler(handle func(event *WebhookEvent) error, clock Clock) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		event, err := ParseWebhookEvent(r.Body, clock)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := handle(event); err != nil {
+			http.Error(w, "event not handled", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+/*********************************************************/
+
+func (u WebhookUpdates) MarshalJSON() ([]byte, error) {
+	updates := make(map[string]string)
+	if u.Title != nil {
+		updates["title"] = *u.Title
+	}
+
+	if u.Type != nil {
+		updates["type"] = string(*u.Type)
+	}
+
+	if u.Private != nil {
+		updates["private"] = strconv.FormatBool(*u.Private)
+	}
+
+	if u.Authorized != nil {
+		updates["authorized"] = strconv.FormatBool(*u.Authorized)
+	}
+
+	return json.Marshal(updates)
+}
+
+// UnmarshalJSON decodes the updates, accepting the flags both as strings and as booleans.
+func (u *WebhookUpdates) UnmarshalJSON(data []byte) error {
+	var updates map[string]interface{}
+	if err := json.Unmarshal(data, &updates); err != nil {
+		return err
+	}
+
+	*u = WebhookUpdates{}
+	if title, ok := updates["title"].(string); ok {
+		u.Title = &title
+	}
+
+	if t, ok := updates["type"].(string); ok {
+		activityType := ActivityType(t)
+		u.Type = &activityType
+	}
+
+	u.Private = webhookFlag(updates["private"])
+	u.Authorized = webhookFlag(updates["authorized"])
+
+	return nil
+}
+
+func webhookFlag(value interface{}) *bool {
+	switch v := value.(type) {
+	case bool:
+		return &v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return &b
+		}
+	}
+
+	return nil
+}
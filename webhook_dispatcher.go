@@ -0,0 +1,191 @@
+package strava
+
+// A RefetchPolicy tells a WebhookDispatcher what to fetch for the events of an object and aspect type,
+// balancing fresh data against the rate limit. The zero value only forwards the event.
+type RefetchPolicy struct {
+	Fetch bool // fetch the object of the event, required for the sub-resources
+
+	// sub-resources of activities fetched along with the activity
+	Laps     bool
+	Zones    bool
+	Photos   bool
+	Comments bool
+	Kudoers  bool
+	Streams  []StreamType
+}
+
+// DefaultRefetchPolicies are the policies of a new WebhookDispatcher: created activities are fetched,
+// other events are forwarded as is. Updates can be applied with WebhookEvent.Apply and deleted
+// activities or deauthorized athletes can't be fetched.
+var DefaultRefetchPolicies = map[WebhookObjectType]map[WebhookAspectType]RefetchPolicy{
+	WebhookObjectTypes.Activity: {
+		WebhookAspectTypes.Create: {Fetch: true},
+	},
+}
+
+// A WebhookDelivery is a webhook event with the data fetched according to the RefetchPolicy of the event.
+// Fields of data that wasn't fetched are nil.
+type WebhookDelivery struct {
+	Event    *WebhookEvent
+	Activity *ActivityDetailed
+	Athlete  *AthleteSummary
+	Laps     []*LapEffortSummary
+	Zones    []*ZonesSummary
+	Photos   []*PhotoSummary
+	Comments []*CommentSummary
+	Kudoers  []*AthleteSummary
+	Streams  *StreamSet
+}
+
+// A WebhookDispatcher passes webhook events to a handler, after fetching the objects of the events
+// as declared by the refetch policies.
+type WebhookDispatcher struct {
+	clientFor    func(athleteId int64) (*Client, error)
+	handler      func(delivery *WebhookDelivery) error
+	policies     map[WebhookObjectType]map[WebhookAspectType]RefetchPolicy
+	errorHandler ErrorHandler
+	metrics      Metrics
+	clock        Clock
+}
+
+// NewWebhookDispatcher creates a dispatcher using DefaultRefetchPolicies. Objects are fetched
+// with the client returned by clientFor for the owner of the event, the athlete that authorized
+// the application, which is not called for events that need no fetching.
+func NewWebhookDispatcher(clientFor func(athleteId int64) (*Client, error), handler func(delivery *WebhookDelivery) error) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		clientFor: clientFor,
+		handler:   handler,
+		policies:  make(map[WebhookObjectType]map[WebhookAspectType]RefetchPolicy),
+		clock:     systemClock{},
+	}
+
+	for objectType, policies := range DefaultRefetchPolicies {
+		for aspectType, policy := range policies {
+			d.Policy(objectType, aspectType, policy)
+		}
+	}
+
+	return d
+}
+
+// Policy sets the refetch policy of the events of the object and aspect type.
+func (d *WebhookDispatcher) Policy(objectType WebhookObjectType, aspectType WebhookAspectType, policy RefetchPolicy) *WebhookDispatcher {
+	if d.policies[objectType] == nil {
+		d.policies[objectType] = make(map[WebhookAspectType]RefetchPolicy)
+	}
+
+	d.policies[objectType][aspectType] = policy
+	return d
+}
+
+// OnError sets the ErrorHandler of the calls fetching the objects.
+func (d *WebhookDispatcher) OnError(handler ErrorHandler) *WebhookDispatcher {
+	d.errorHandler = handler
+	return d
+}
+
+// Metrics makes the dispatcher report the SyncLag of every event the handler synced without an error
+// to the metrics, usually those of the clients, if they implement SyncLagObserver.
+func (d *WebhookDispatcher) Metrics(metrics Metrics) *WebhookDispatcher {
+	d.metrics = metrics
+	return d
+}
+
+// Clock makes the dispatcher tell the time of the sync of events with the clock, like the clients, see WithClock.
+// Nil restores the system clock.
+func (d *WebhookDispatcher) Clock(clock Clock) *WebhookDispatcher {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	d.clock = clock
+	return d
+}
+
+// Dispatch fetches the data of the event according to its policy and passes it to the handler.
+// The error of a failed fetch is returned without calling the handler, so the event can be retried.
+func (d *WebhookDispatcher) Dispatch(event *WebhookEvent) error {
+	receivedAt := event.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = d.clock.Now()
+	}
+
+	delivery := &WebhookDelivery{Event: event}
+
+	policy := d.policies[event.ObjectType][event.AspectType]
+	if policy.Fetch {
+		client, err := d.clientFor(event.OwnerId)
+		if err != nil {
+			return err
+		}
+
+		switch event.ObjectType {
+		case WebhookObjectTypes.Activity:
+			err = d.fetchActivity(client, delivery, policy)
+		case WebhookObjectTypes.Athlete:
+			delivery.Athlete, err = NewAthletesService(client).Get(event.ObjectId).OnError(d.errorHandler).Do()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := d.handler(delivery); err != nil {
+		return err
+	}
+
+	if observer, ok := d.metrics.(SyncLagObserver); ok {
+		observer.ObserveSyncLag(event.ObjectType, event.AspectType, newSyncLag(event, receivedAt, d.clock.Now()))
+	}
+
+	return nil
+}
+
+func (d *WebhookDispatcher) fetchActivity(client *Client, delivery *WebhookDelivery, policy RefetchPolicy) error {
+	id := delivery.Event.ObjectId
+	service := NewActivitiesService(client)
+
+	var err error
+	if delivery.Activity, err = service.Get(id).OnError(d.errorHandler).Do(); err != nil {
+		return err
+	}
+
+	if policy.Laps {
+		if delivery.Laps, err = service.ListLaps(id).OnError(d.errorHandler).Do(); err != nil {
+			return err
+		}
+	}
+
+	if policy.Zones {
+		if delivery.Zones, err = service.ListZones(id).OnError(d.errorHandler).Do(); err != nil {
+			return err
+		}
+	}
+
+	if policy.Photos {
+		if delivery.Photos, err = service.ListPhotos(id).OnError(d.errorHandler).Do(); err != nil {
+			return err
+		}
+	}
+
+	if policy.Comments {
+		if delivery.Comments, err = NewActivityCommentsService(client, id).List().OnError(d.errorHandler).Do(); err != nil {
+			return err
+		}
+	}
+
+	if policy.Kudoers {
+		if delivery.Kudoers, err = NewActivityKudosService(client, id).List().OnError(d.errorHandler).Do(); err != nil {
+			return err
+		}
+	}
+
+	if len(policy.Streams) > 0 {
+		if delivery.Streams, err = NewActivityStreamsService(client).Get(id, policy.Streams).OnError(d.errorHandler).Do(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
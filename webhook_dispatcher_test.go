@@ -0,0 +1,83 @@
+package strava
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	client, transport := newRouteClient(map[string]string{
+		"/api/v3/activities/1":      `{"id":1,"name":"Morning Ride"}`,
+		"/api/v3/activities/1/laps": `[{"id":10},{"id":11}]`,
+	})
+
+	var deliveries []*WebhookDelivery
+	var owners []int64
+	dispatcher := NewWebhookDispatcher(func(athleteId int64) (*Client, error) {
+		owners = append(owners, athleteId)
+		return client, nil
+	}, func(delivery *WebhookDelivery) error {
+		deliveries = append(deliveries, delivery)
+		return nil
+	})
+
+	created := &WebhookEvent{ObjectType: WebhookObjectTypes.Activity, AspectType: WebhookAspectTypes.Create, ObjectId: 1, OwnerId: 5}
+	if err := dispatcher.Dispatch(created); err != nil {
+		t.Fatalf("dispatch error: %v", err)
+	}
+
+	if len(deliveries) != 1 || deliveries[0].Activity == nil || deliveries[0].Activity.Name != "Morning Ride" || deliveries[0].Laps != nil {
+		t.Errorf("created activity should be fetched by default, got %+v", deliveries)
+	}
+
+	if len(owners) != 1 || owners[0] != 5 {
+		t.Errorf("should fetch with the client of the owner, got %v", owners)
+	}
+
+	updated := &WebhookEvent{ObjectType: WebhookObjectTypes.Activity, AspectType: WebhookAspectTypes.Update, ObjectId: 1, OwnerId: 5}
+	if err := dispatcher.Dispatch(updated); err != nil {
+		t.Fatalf("dispatch error: %v", err)
+	}
+
+	if len(deliveries) != 2 || deliveries[1].Event != updated || deliveries[1].Activity != nil || len(transport.requests) != 1 {
+		t.Errorf("updates should only be forwarded by default, got %+v", deliveries[1])
+	}
+
+	dispatcher.Policy(WebhookObjectTypes.Activity, WebhookAspectTypes.Update, RefetchPolicy{Fetch: true, Laps: true})
+	if err := dispatcher.Dispatch(updated); err != nil {
+		t.Fatalf("dispatch error: %v", err)
+	}
+
+	if deliveries[2].Activity == nil || len(deliveries[2].Laps) != 2 {
+		t.Errorf("activity should be fetched with its laps, got %+v", deliveries[2])
+	}
+
+	// failed fetches are returned without calling the handler
+	missing := &WebhookEvent{ObjectType: WebhookObjectTypes.Activity, AspectType: WebhookAspectTypes.Create, ObjectId: 2, OwnerId: 5}
+	if err := dispatcher.Dispatch(missing); err == nil {
+		t.Error("should return the error of the fetch")
+	}
+
+	if len(deliveries) != 3 {
+		t.Errorf("handler should not be called, got %d deliveries", len(deliveries))
+	}
+}
+
+func TestWebhookDispatcherClientError(t *testing.T) {
+	clientErr := errors.New("no token")
+	dispatcher := NewWebhookDispatcher(func(athleteId int64) (*Client, error) {
+		return nil, clientErr
+	}, func(delivery *WebhookDelivery) error {
+		return nil
+	})
+
+	deauthorized := &WebhookEvent{ObjectType: WebhookObjectTypes.Athlete, AspectType: WebhookAspectTypes.Update, ObjectId: 5, OwnerId: 5}
+	if err := dispatcher.Dispatch(deauthorized); err != nil {
+		t.Errorf("events that need no fetching should not need a client, got %v", err)
+	}
+
+	created := &WebhookEvent{ObjectType: WebhookObjectTypes.Activity, AspectType: WebhookAspectTypes.Create, ObjectId: 1, OwnerId: 5}
+	if err := dispatcher.Dispatch(created); err != clientErr {
+		t.Errorf("should return the error of clientFor, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package strava
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	event, err := ParseWebhookEvent(strings.NewReader(`{
+		"aspect_type": "update",
+		"event_time": 1516126040,
+		"object_id": 1360128428,
+		"object_type": "activity",
+		"owner_id": 134815,
+		"subscription_id": 120475,
+		"updates": {"title": "Messy", "type": "TrailRun", "private": "true"}
+	}`), nil)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if event.ObjectType != WebhookObjectTypes.Activity || event.AspectType != WebhookAspectTypes.Update || event.ObjectId != 1360128428 {
+		t.Errorf("incorrect event, got %+v", event)
+	}
+
+	if event.Time().Unix() != 1516126040 {
+		t.Errorf("incorrect time, got %v", event.Time())
+	}
+
+	if u := event.Updates; u.Title == nil || *u.Title != "Messy" || u.Type == nil || *u.Type != ActivityTypes.TrailRun || u.Private == nil || !*u.Private || u.Authorized != nil {
+		t.Errorf("incorrect updates, got %+v", u)
+	}
+
+	if event.IsDeauthorization() {
+		t.Error("event should not be a deauthorization")
+	}
+
+	event, err = ParseWebhookEvent(strings.NewReader(`{"aspect_type":"update","object_id":134815,"object_type":"athlete","owner_id":134815,"updates":{"authorized":"false"}}`), nil)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if !event.IsDeauthorization() {
+		t.Error("event should be a deauthorization")
+	}
+
+	if _, err := ParseWebhookEvent(strings.NewReader(`{"updates":[]}`), nil); err == nil {
+		t.Error("should return an error for bad json")
+	}
+}
+
+func TestWebhookEventApply(t *testing.T) {
+	activity := &ActivitySummary{Id: 1, Name: "Morning Run", Type: ActivityTypes.Run, SportType: ActivityTypes.Run, Private: true, Visibility: Visibilities.OnlyMe}
+
+	title, private := "Evening Trail Run", false
+	sportType := ActivityTypes.TrailRun
+	event := &WebhookEvent{
+		ObjectType: WebhookObjectTypes.Activity,
+		ObjectId:   1,
+		AspectType: WebhookAspectTypes.Update,
+		Updates:    WebhookUpdates{Title: &title, Type: &sportType, Private: &private},
+	}
+
+	if !event.Apply(activity) {
+		t.Fatal("update should be applied")
+	}
+
+	if activity.Name != title || activity.SportType != ActivityTypes.TrailRun || activity.Type != ActivityTypes.Run || activity.Private || activity.Visibility != Visibilities.Everyone {
+		t.Errorf("incorrect activity, got %+v", activity)
+	}
+
+	// the visibility follows the private flag
+	private = true
+	activity.Visibility = Visibilities.FollowersOnly
+	event.Apply(activity)
+	if !activity.Private || activity.Visibility != Visibilities.OnlyMe {
+		t.Errorf("private activity should be visible to only the athlete, got %v", activity.Visibility)
+	}
+
+	private = false
+	activity.Visibility = Visibilities.FollowersOnly
+	event.Apply(activity)
+	if activity.Visibility != Visibilities.FollowersOnly {
+		t.Errorf("visibility of a public activity should be kept, got %v", activity.Visibility)
+	}
+
+	// only the updated fields change
+	event.Updates = WebhookUpdates{Title: &title}
+	activity.Private = true
+	event.Apply(activity)
+	if !activity.Private {
+		t.Error("private should not be changed")
+	}
+
+	event.ObjectId = 2
+	if event.Apply(activity) {
+		t.Error("update of another activity should not be applied")
+	}
+
+	event.ObjectId, event.AspectType = 1, WebhookAspectTypes.Delete
+	if event.Apply(activity) {
+		t.Error("delete event should not be applied")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var events []*WebhookEvent
+	var err error
+	clock := newFakeClock()
+	handler := WebhookHandler(func(event *WebhookEvent) error {
+		events = append(events, event)
+		return err
+	}, clock)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"object_type":"activity","object_id":1,"aspect_type":"create"}`)))
+	if w.Code != http.StatusOK || len(events) != 1 || events[0].ObjectId != 1 {
+		t.Errorf("event should be handled, got %d and %v", w.Code, events)
+	}
+
+	if !events[0].ReceivedAt.Equal(clock.Now()) {
+		t.Errorf("event should be received at the time of the clock, got %v", events[0].ReceivedAt)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/webhook", strings.NewReader(`{`)))
+	if w.Code != http.StatusBadRequest || len(events) != 1 {
+		t.Errorf("invalid event should be refused, got %d", w.Code)
+	}
+
+	err = errors.New("queue full")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"object_id":2}`)))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("failed event should be retried, got %d", w.Code)
+	}
+}
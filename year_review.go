@@ -0,0 +1,269 @@
+package strava
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// YearInReview summarizes the activities of a year for a yearly report, the totals by sport, the biggest days,
+// the most ridden or run segments and the totals by month, for charts. Activities are added as they are synced,
+// also those of other years, which are left out, and again when they are updated. Segments are only known
+// of the activities added with AddDetailed. Safe for concurrent use.
+type YearInReview struct {
+	Year int
+
+	lock       sync.Mutex
+	activities map[int64]*ActivitySummary
+	efforts    map[int64][]*SegmentEffortSummary
+}
+
+// NewYearInReview creates an empty review of the year.
+func NewYearInReview(year int) *YearInReview {
+	return &YearInReview{
+		Year:       year,
+		activities: make(map[int64]*ActivitySummary),
+		efforts:    make(map[int64][]*SegmentEffortSummary),
+	}
+}
+
+// Add adds the activities of the year, by their local start date, replacing them if they were already added.
+func (r *YearInReview) Add(activities ...*ActivitySummary) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, activity := range activities {
+		r.add(activity, nil)
+	}
+}
+
+// AddDetailed adds the activities of the year with their segment efforts.
+func (r *YearInReview) AddDetailed(activities ...*ActivityDetailed) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, activity := range activities {
+		r.add(&activity.ActivitySummary, activity.SegmentEfforts)
+	}
+}
+
+func (r *YearInReview) add(activity *ActivitySummary, efforts []*SegmentEffortSummary) {
+	delete(r.activities, activity.Id)
+	delete(r.efforts, activity.Id)
+
+	if activity.StartDateLocal.Year() != r.Year {
+		return
+	}
+
+	r.activities[activity.Id] = activity
+	if efforts != nil {
+		r.efforts[activity.Id] = efforts
+	}
+}
+
+// Remove removes the activity, e.g. after it was deleted.
+func (r *YearInReview) Remove(activityId int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.activities, activityId)
+	delete(r.efforts, activityId)
+}
+
+// ReviewTotals are the totals of the activities of a year, sport, day or month.
+type ReviewTotals struct {
+	Activities int       `json:"activities"`
+	Distance   Distance  `json:"distance"`
+	MovingTime int       `json:"moving_time"` // in seconds
+	Elevation  Elevation `json:"elevation"`
+}
+
+func (t *ReviewTotals) add(activity *ActivitySummary) {
+	t.Activities++
+	t.Distance += activity.Distance
+	t.MovingTime += activity.MovingTime
+	t.Elevation += activity.TotalElevationGain
+}
+
+// SportTotals are the totals of the activities of a sport.
+type SportTotals struct {
+	Sport ActivityType `json:"sport"`
+	ReviewTotals
+}
+
+// A ReviewDay is a day of the year with activities.
+type ReviewDay struct {
+	Date time.Time `json:"date"` // midnight UTC of the local date of the activities
+	ReviewTotals
+}
+
+// A ReviewMonth is a month of the year, with or without activities.
+type ReviewMonth struct {
+	Month time.Month `json:"month"`
+	ReviewTotals
+}
+
+// A ReviewSegment is a segment with efforts during the year.
+type ReviewSegment struct {
+	Id       int64  `json:"id"`
+	Name     string `json:"name"`
+	Efforts  int    `json:"efforts"`
+	BestTime int    `json:"best_time"` // elapsed time of the fastest effort, in seconds
+}
+
+// A YearReport is the summary of a year, see YearInReview.Report. It marshals to json for apps that render
+// it themselves, e.g. the Months for charts, Markdown renders it as text.
+type YearReport struct {
+	Year        int              `json:"year"`
+	Totals      ReviewTotals     `json:"totals"`
+	Sports      []*SportTotals   `json:"sports"`       // by moving time, longest first
+	BiggestDays []*ReviewDay     `json:"biggest_days"` // by moving time, longest first
+	TopSegments []*ReviewSegment `json:"top_segments"` // by number of efforts, most first
+	Months      []*ReviewMonth   `json:"months"`       // January to December
+}
+
+// reviewTopCount is the number of biggest days and top segments of a report.
+const reviewTopCount = 5
+
+// Report returns the summary of the activities added so far.
+func (r *YearInReview) Report() *YearReport {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	report := &YearReport{Year: r.Year}
+	sports := make(map[ActivityType]*SportTotals)
+	days := make(map[time.Time]*ReviewDay)
+	for month := time.January; month <= time.December; month++ {
+		report.Months = append(report.Months, &ReviewMonth{Month: month})
+	}
+
+	for _, activity := range r.activities {
+		report.Totals.add(activity)
+
+		sport := activity.SportType
+		if sport == "" {
+			sport = activity.Type
+		}
+		if sports[sport] == nil {
+			sports[sport] = &SportTotals{Sport: sport}
+			report.Sports = append(report.Sports, sports[sport])
+		}
+		sports[sport].add(activity)
+
+		date := localDate(activity.StartDateLocal)
+		if days[date] == nil {
+			days[date] = &ReviewDay{Date: date}
+			report.BiggestDays = append(report.BiggestDays, days[date])
+		}
+		days[date].add(activity)
+
+		report.Months[activity.StartDateLocal.Month()-1].add(activity)
+	}
+
+	sort.Slice(report.Sports, func(i, j int) bool {
+		a, b := report.Sports[i], report.Sports[j]
+		if a.MovingTime != b.MovingTime {
+			return a.MovingTime > b.MovingTime
+		}
+		return a.Sport < b.Sport
+	})
+
+	sort.Slice(report.BiggestDays, func(i, j int) bool {
+		a, b := report.BiggestDays[i], report.BiggestDays[j]
+		if a.MovingTime != b.MovingTime {
+			return a.MovingTime > b.MovingTime
+		}
+		return a.Date.Before(b.Date)
+	})
+	if len(report.BiggestDays) > reviewTopCount {
+		report.BiggestDays = report.BiggestDays[:reviewTopCount]
+	}
+
+	report.TopSegments = r.topSegments()
+	return report
+}
+
+// topSegments returns the segments with the most efforts.
+func (r *YearInReview) topSegments() []*ReviewSegment {
+	segments := make(map[int64]*ReviewSegment)
+	var top []*ReviewSegment
+	for _, efforts := range r.efforts {
+		for _, effort := range efforts {
+			segment := segments[effort.Segment.Id]
+			if segment == nil {
+				segment = &ReviewSegment{Id: effort.Segment.Id, Name: effort.Segment.Name}
+				segments[effort.Segment.Id] = segment
+				top = append(top, segment)
+			}
+
+			segment.Efforts++
+			if segment.BestTime == 0 || effort.ElapsedTime < segment.BestTime {
+				segment.BestTime = effort.ElapsedTime
+			}
+		}
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Efforts != top[j].Efforts {
+			return top[i].Efforts > top[j].Efforts
+		}
+		return top[i].Id < top[j].Id
+	})
+
+	if len(top) > reviewTopCount {
+		top = top[:reviewTopCount]
+	}
+
+	return top
+}
+
+// Markdown renders the report as markdown, with the units of the measurement preference, see Distance.Format.
+func (report *YearReport) Markdown(preference string) string {
+	var b strings.Builder
+	totals := func(t ReviewTotals) string {
+		return fmt.Sprintf("%d | %s | %s | %s", t.Activities, t.Distance.Format(preference), reviewDuration(t.MovingTime), t.Elevation.Format(preference))
+	}
+
+	fmt.Fprintf(&b, "# %d in review\n\n", report.Year)
+	fmt.Fprintf(&b, "%d activities, %s in %s, %s climbed\n", report.Totals.Activities, report.Totals.Distance.Format(preference),
+		reviewDuration(report.Totals.MovingTime), report.Totals.Elevation.Format(preference))
+
+	if len(report.Sports) > 0 {
+		b.WriteString("\n## Sports\n\n| Sport | Activities | Distance | Time | Elevation |\n| --- | --- | --- | --- | --- |\n")
+		for _, sport := range report.Sports {
+			fmt.Fprintf(&b, "| %s | %s |\n", sport.Sport, totals(sport.ReviewTotals))
+		}
+	}
+
+	if len(report.BiggestDays) > 0 {
+		b.WriteString("\n## Biggest days\n\n| Date | Activities | Distance | Time | Elevation |\n| --- | --- | --- | --- | --- |\n")
+		for _, day := range report.BiggestDays {
+			fmt.Fprintf(&b, "| %s | %s |\n", day.Date.Format("Jan 2"), totals(day.ReviewTotals))
+		}
+	}
+
+	if len(report.TopSegments) > 0 {
+		b.WriteString("\n## Top segments\n\n| Segment | Efforts | Best time |\n| --- | --- | --- |\n")
+		for _, segment := range report.TopSegments {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", segment.Name, segment.Efforts, reviewDuration(segment.BestTime))
+		}
+	}
+
+	b.WriteString("\n## Months\n\n| Month | Activities | Distance | Time | Elevation |\n| --- | --- | --- | --- | --- |\n")
+	for _, month := range report.Months {
+		fmt.Fprintf(&b, "| %s | %s |\n", month.Month, totals(month.ReviewTotals))
+	}
+
+	return b.String()
+}
+
+// reviewDuration formats the seconds as hours and minutes, e.g. 12h05m, or minutes and seconds below an hour.
+func reviewDuration(seconds int) string {
+	if seconds < 3600 {
+		return fmt.Sprintf("%dm%02ds", seconds/60, seconds%60)
+	}
+
+	return fmt.Sprintf("%dh%02dm", seconds/3600, seconds%3600/60)
+}
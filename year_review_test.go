@@ -0,0 +1,76 @@
+package strava
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestYearInReview(t *testing.T) {
+	newActivity := func(id int64, sport ActivityType, date time.Time, distance Distance, movingTime int) *ActivitySummary {
+		return &ActivitySummary{Id: id, SportType: sport, StartDateLocal: date, Distance: distance, MovingTime: movingTime, TotalElevationGain: 100}
+	}
+	effort := func(segmentId int64, name string, elapsedTime int) *SegmentEffortSummary {
+		e := &SegmentEffortSummary{Segment: SegmentSummary{Id: segmentId, Name: name}}
+		e.ElapsedTime = elapsedTime
+		return e
+	}
+
+	review := NewYearInReview(2024)
+	review.Add(
+		newActivity(1, ActivityTypes.Ride, time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC), 40000, 5400),
+		newActivity(2, ActivityTypes.Run, time.Date(2024, 3, 2, 18, 0, 0, 0, time.UTC), 10000, 3000),
+		newActivity(3, ActivityTypes.Run, time.Date(2024, 7, 14, 7, 0, 0, 0, time.UTC), 21100, 6300),
+		newActivity(4, ActivityTypes.Ride, time.Date(2023, 12, 31, 9, 0, 0, 0, time.UTC), 90000, 10800), // other year
+		newActivity(5, ActivityTypes.Hike, time.Date(2024, 9, 1, 9, 0, 0, 0, time.UTC), 5000, 1800),
+	)
+	review.AddDetailed(
+		&ActivityDetailed{
+			ActivitySummary: *newActivity(6, ActivityTypes.Ride, time.Date(2024, 7, 20, 9, 0, 0, 0, time.UTC), 60000, 7200),
+			SegmentEfforts:  []*SegmentEffortSummary{effort(10, "Hill", 300), effort(11, "Sprint", 60)},
+		},
+		&ActivityDetailed{
+			ActivitySummary: *newActivity(7, ActivityTypes.Ride, time.Date(2024, 8, 3, 9, 0, 0, 0, time.UTC), 50000, 6000),
+			SegmentEfforts:  []*SegmentEffortSummary{effort(10, "Hill", 280)},
+		},
+	)
+	review.Remove(5)
+
+	report := review.Report()
+	if report.Totals.Activities != 5 || report.Totals.Distance != 181100 || report.Totals.MovingTime != 27900 || report.Totals.Elevation != 500 {
+		t.Errorf("incorrect totals, got %+v", report.Totals)
+	}
+
+	if len(report.Sports) != 2 || report.Sports[0].Sport != ActivityTypes.Ride || report.Sports[0].Activities != 3 || report.Sports[1].MovingTime != 9300 {
+		t.Errorf("incorrect sports, got %+v %+v", report.Sports[0], report.Sports[1])
+	}
+
+	if len(report.BiggestDays) != 4 || !report.BiggestDays[0].Date.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) || report.BiggestDays[0].Activities != 2 {
+		t.Errorf("the day with two activities should be the biggest, got %+v", report.BiggestDays[0])
+	}
+
+	if len(report.TopSegments) != 2 || report.TopSegments[0].Id != 10 || report.TopSegments[0].Efforts != 2 || report.TopSegments[0].BestTime != 280 {
+		t.Errorf("incorrect top segments, got %+v", report.TopSegments)
+	}
+
+	if len(report.Months) != 12 || report.Months[2].Activities != 2 || report.Months[6].Activities != 2 || report.Months[0].Activities != 0 {
+		t.Errorf("incorrect months, got %+v %+v", report.Months[2], report.Months[6])
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil || !strings.Contains(string(data), `"biggest_days":[{"date":"2024-03-02T00:00:00Z","activities":2,"distance":50000`) {
+		t.Errorf("incorrect json, got %s %v", data, err)
+	}
+
+	markdown := report.Markdown(MeasurementPreferences.Meters)
+	for _, expected := range []string{"# 2024 in review", "| Ride | 3 | 150.00 km | 5h10m | 300 m |", "| Mar 2 | 2 | 50.00 km | 2h20m | 200 m |", "| Hill | 2 | 4m40s |", "| January | 0 | 0 m | 0m00s | 0 m |"} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("markdown should contain %q, got\n%s", expected, markdown)
+		}
+	}
+
+	if markdown := report.Markdown(MeasurementPreferences.Feet); !strings.Contains(markdown, "| Ride | 3 | 93.21 mi | 5h10m | 984 ft |") {
+		t.Errorf("markdown should be in imperial units, got\n%s", markdown)
+	}
+}
@@ -1,6 +1,7 @@
 package strava
 
 type ZonesSummary struct {
+	RawJSON
 	Score       int           `json:"score"`
 	Buckets     []*ZoneBucket `json:"distribution_buckets"`
 	Type        string        `json:"type"`         // power or heartrate
@@ -14,3 +15,21 @@ type ZoneBucket struct {
 	Max  int `json:"max"`
 	Time int `json:"time"`
 }
+
+// AthleteZones are the heart rate and power zones of the authenticated athlete, see Onboarder.
+// Requested from /athlete/zones, which needs the profile:read_all scope.
+type AthleteZones struct {
+	HeartRate *ZoneRanges `json:"heart_rate"`
+	Power     *ZoneRanges `json:"power"` // nil for athletes without a power meter
+}
+
+type ZoneRanges struct {
+	CustomZones bool        `json:"custom_zones"`
+	Zones       []ZoneRange `json:"zones"`
+}
+
+// ZoneRange is a zone of heart rate, in bpm, or power, in watts. The max of the last zone is -1.
+type ZoneRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}